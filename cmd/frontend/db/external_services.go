@@ -39,6 +39,7 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	extsvc.KindAWSCodeCommit:   {CodeHost: true, JSONSchema: schema.AWSCodeCommitSchemaJSON},
 	extsvc.KindBitbucketCloud:  {CodeHost: true, JSONSchema: schema.BitbucketCloudSchemaJSON},
 	extsvc.KindBitbucketServer: {CodeHost: true, JSONSchema: schema.BitbucketServerSchemaJSON},
+	extsvc.KindGerrit:          {CodeHost: true, JSONSchema: schema.GerritSchemaJSON},
 	extsvc.KindGitHub:          {CodeHost: true, JSONSchema: schema.GitHubSchemaJSON},
 	extsvc.KindGitLab:          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	extsvc.KindGitolite:        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
@@ -139,6 +140,13 @@ func (e *ExternalServicesStore) ValidateConfig(ctx context.Context, id int64, ki
 		}
 		err = e.validateBitbucketCloudConnection(ctx, id, &c)
 
+	case extsvc.KindGerrit:
+		var c schema.GerritConnection
+		if err = json.Unmarshal(normalized, &c); err != nil {
+			return err
+		}
+		err = e.validateGerritConnection(ctx, id, &c)
+
 	case extsvc.KindOther:
 		var c schema.OtherExternalServiceConnection
 		if err = json.Unmarshal(normalized, &c); err != nil {
@@ -224,6 +232,10 @@ func (e *ExternalServicesStore) validateBitbucketCloudConnection(ctx context.Con
 	return e.validateDuplicateRateLimits(ctx, id, extsvc.KindBitbucketCloud, c)
 }
 
+func (e *ExternalServicesStore) validateGerritConnection(ctx context.Context, id int64, c *schema.GerritConnection) error {
+	return e.validateDuplicateRateLimits(ctx, id, extsvc.KindGerrit, c)
+}
+
 func (e *ExternalServicesStore) validateDuplicateRateLimits(ctx context.Context, id int64, kind string, parsedConfig interface{}) error {
 	// Check if rate limit is already defined for this code host on another external service
 	rlc, err := extsvc.GetLimitFromConfig(kind, parsedConfig)
@@ -117,6 +117,53 @@ func (s *repos) GetByIDs(ctx context.Context, ids ...api.RepoID) ([]*types.Repo,
 	return s.getReposBySQL(ctx, true, q)
 }
 
+// GetStargazerCounts returns the number of stars of the given repositories on
+// their code host, as of the last time they were synced. Repositories whose
+// code host has no stars (or that are not found) are omitted. It does not
+// check permissions, so callers must only pass IDs of repositories the
+// current user can access.
+func (s *repos) GetStargazerCounts(ctx context.Context, ids ...api.RepoID) (map[api.RepoID]int, error) {
+	if Mocks.Repos.GetStargazerCounts != nil {
+		return Mocks.Repos.GetStargazerCounts(ctx, ids...)
+	}
+
+	counts := map[api.RepoID]int{}
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	items := make([]*sqlf.Query, len(ids))
+	for i := range ids {
+		items[i] = sqlf.Sprintf("%d", ids[i])
+	}
+	// The metadata of GitHub repositories is a github.Repository, and that of
+	// GitLab repositories is a gitlab.Project.
+	q := sqlf.Sprintf(`
+SELECT id, COALESCE((metadata->>'StargazerCount')::integer, (metadata->>'star_count')::integer, 0)
+FROM repo
+WHERE deleted_at IS NULL AND id IN (%s)`, sqlf.Join(items, ","))
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id    api.RepoID
+			count int
+		)
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			counts[id] = count
+		}
+	}
+	return counts, rows.Err()
+}
+
 func (s *repos) Count(ctx context.Context, opt ReposListOptions) (int, error) {
 	if Mocks.Repos.Count != nil {
 		return Mocks.Repos.Count(ctx, opt)
@@ -235,6 +235,32 @@ func TestRepos_GetByIDs(t *testing.T) {
 	}
 }
 
+func TestRepos_GetStargazerCounts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	repos := mustCreate(ctx, t, &types.Repo{Name: "github"}, &types.Repo{Name: "gitlab"}, &types.Repo{Name: "other"})
+	for i, metadata := range []string{`{"StargazerCount": 42}`, `{"star_count": 7}`, `{}`} {
+		q := sqlf.Sprintf("UPDATE repo SET metadata = %s WHERE id = %d", metadata, repos[i].ID)
+		if _, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts, err := Repos.GetStargazerCounts(ctx, repos[0].ID, repos[1].ID, repos[2].ID, 404)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[api.RepoID]int{repos[0].ID: 42, repos[1].ID: 7}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("got %v, want %v", counts, want)
+	}
+}
+
 func TestRepos_List(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
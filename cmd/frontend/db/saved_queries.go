@@ -3,9 +3,11 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 )
 
@@ -16,6 +18,9 @@ type SavedQueryInfo struct {
 	LastExecuted time.Time
 	LatestResult time.Time
 	ExecDuration time.Duration
+
+	// ResultFingerprints is nil if the matches of the query were never recorded.
+	ResultFingerprints []api.SavedQueryResultFingerprint
 }
 
 // Get gets the saved query information for the given query. nil
@@ -24,12 +29,15 @@ func (s *queryRunnerState) Get(ctx context.Context, query string) (*SavedQueryIn
 	info := &SavedQueryInfo{
 		Query: query,
 	}
-	var execDurationNs int64
+	var (
+		execDurationNs     int64
+		resultFingerprints []byte
+	)
 	err := dbconn.Global.QueryRowContext(
 		ctx,
-		"SELECT last_executed, latest_result, exec_duration_ns FROM query_runner_state WHERE query=$1",
+		"SELECT last_executed, latest_result, exec_duration_ns, result_fingerprints FROM query_runner_state WHERE query=$1",
 		query,
-	).Scan(&info.LastExecuted, &info.LatestResult, &execDurationNs)
+	).Scan(&info.LastExecuted, &info.LatestResult, &execDurationNs, &resultFingerprints)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -37,6 +45,11 @@ func (s *queryRunnerState) Get(ctx context.Context, query string) (*SavedQueryIn
 		return nil, errors.Wrap(err, "QueryRow")
 	}
 	info.ExecDuration = time.Duration(execDurationNs)
+	if resultFingerprints != nil {
+		if err := json.Unmarshal(resultFingerprints, &info.ResultFingerprints); err != nil {
+			return nil, errors.Wrap(err, "Unmarshal")
+		}
+	}
 	return info, nil
 }
 
@@ -45,12 +58,23 @@ func (s *queryRunnerState) Get(ctx context.Context, query string) (*SavedQueryIn
 // It is not safe to call concurrently for the same info.Query, as it uses a
 // poor man's upsert implementation.
 func (s *queryRunnerState) Set(ctx context.Context, info *SavedQueryInfo) error {
+	// A nil slice is stored as NULL to distinguish it from a query that had no matches.
+	var resultFingerprints interface{}
+	if info.ResultFingerprints != nil {
+		b, err := json.Marshal(info.ResultFingerprints)
+		if err != nil {
+			return errors.Wrap(err, "Marshal")
+		}
+		resultFingerprints = b
+	}
+
 	res, err := dbconn.Global.ExecContext(
 		ctx,
-		"UPDATE query_runner_state SET last_executed=$1, latest_result=$2, exec_duration_ns=$3 WHERE query=$4",
+		"UPDATE query_runner_state SET last_executed=$1, latest_result=$2, exec_duration_ns=$3, result_fingerprints=$4 WHERE query=$5",
 		info.LastExecuted,
 		info.LatestResult,
 		int64(info.ExecDuration),
+		resultFingerprints,
 		info.Query,
 	)
 	if err != nil {
@@ -64,11 +88,12 @@ func (s *queryRunnerState) Set(ctx context.Context, info *SavedQueryInfo) error
 		// Didn't update any row, so insert a new one.
 		_, err := dbconn.Global.ExecContext(
 			ctx,
-			"INSERT INTO query_runner_state(query, last_executed, latest_result, exec_duration_ns) VALUES($1, $2, $3, $4)",
+			"INSERT INTO query_runner_state(query, last_executed, latest_result, exec_duration_ns, result_fingerprints) VALUES($1, $2, $3, $4, $5)",
 			info.Query,
 			info.LastExecuted,
 			info.LatestResult,
 			int64(info.ExecDuration),
+			resultFingerprints,
 		)
 		if err != nil {
 			return errors.Wrap(err, "INSERT")
@@ -3,6 +3,9 @@ package db
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
@@ -12,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 type savedSearches struct{}
@@ -56,7 +60,8 @@ func (s *savedSearches) ListAll(ctx context.Context) (savedSearches []api.SavedQ
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url FROM saved_searches
+		slack_webhook_url,
+		webhooks FROM saved_searches
 	`)
 	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar))
 	if err != nil {
@@ -73,7 +78,8 @@ func (s *savedSearches) ListAll(ctx context.Context) (savedSearches []api.SavedQ
 			&sq.Config.NotifySlack,
 			&sq.Config.UserID,
 			&sq.Config.OrgID,
-			&sq.Config.SlackWebhookURL); err != nil {
+			&sq.Config.SlackWebhookURL,
+			&savedSearchWebhooks{&sq.Config.Webhooks}); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 		sq.Spec.Key = sq.Config.Key
@@ -106,7 +112,8 @@ func (s *savedSearches) GetByID(ctx context.Context, id int32) (*api.SavedQueryS
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		webhooks
 		FROM saved_searches WHERE id=$1`, id).Scan(
 		&sq.Config.Key,
 		&sq.Config.Description,
@@ -115,7 +122,8 @@ func (s *savedSearches) GetByID(ctx context.Context, id int32) (*api.SavedQueryS
 		&sq.Config.NotifySlack,
 		&sq.Config.UserID,
 		&sq.Config.OrgID,
-		&sq.Config.SlackWebhookURL)
+		&sq.Config.SlackWebhookURL,
+		&savedSearchWebhooks{&sq.Config.Webhooks})
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +174,8 @@ func (s *savedSearches) ListSavedSearchesByUserID(ctx context.Context, userID in
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		webhooks
 		FROM saved_searches %v`, conds)
 
 	rows, err := dbconn.Global.QueryContext(ctx, query.Query(sqlf.PostgresBindVar), query.Args()...)
@@ -175,7 +184,7 @@ func (s *savedSearches) ListSavedSearchesByUserID(ctx context.Context, userID in
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, &savedSearchWebhooks{&ss.Webhooks}); err != nil {
 			return nil, errors.Wrap(err, "Scan(2)")
 		}
 		savedSearches = append(savedSearches, &ss)
@@ -201,7 +210,8 @@ func (s *savedSearches) ListSavedSearchesByOrgID(ctx context.Context, orgID int3
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		webhooks
 		FROM saved_searches %v`, conds)
 
 	rows, err := dbconn.Global.QueryContext(ctx, query.Query(sqlf.PostgresBindVar), query.Args()...)
@@ -210,7 +220,7 @@ func (s *savedSearches) ListSavedSearchesByOrgID(ctx context.Context, orgID int3
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, &savedSearchWebhooks{&ss.Webhooks}); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 		savedSearches = append(savedSearches, &ss)
@@ -246,6 +256,7 @@ func (s *savedSearches) Create(ctx context.Context, newSavedSearch *types.SavedS
 		NotifySlack: newSavedSearch.NotifySlack,
 		UserID:      newSavedSearch.UserID,
 		OrgID:       newSavedSearch.OrgID,
+		Webhooks:    newSavedSearch.Webhooks,
 	}
 
 	err = dbconn.Global.QueryRowContext(ctx, `INSERT INTO saved_searches(
@@ -254,14 +265,16 @@ func (s *savedSearches) Create(ctx context.Context, newSavedSearch *types.SavedS
 			notify_owner,
 			notify_slack,
 			user_id,
-			org_id
-		) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+			org_id,
+			webhooks
+		) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
 		newSavedSearch.Description,
 		newSavedSearch.Query,
 		newSavedSearch.Notify,
 		newSavedSearch.NotifySlack,
 		newSavedSearch.UserID,
 		newSavedSearch.OrgID,
+		savedSearchWebhooks{&newSavedSearch.Webhooks},
 	).Scan(&savedQuery.ID)
 	if err != nil {
 		return nil, err
@@ -293,6 +306,7 @@ func (s *savedSearches) Update(ctx context.Context, savedSearch *types.SavedSear
 		UserID:          savedSearch.UserID,
 		OrgID:           savedSearch.OrgID,
 		SlackWebhookURL: savedSearch.SlackWebhookURL,
+		Webhooks:        savedSearch.Webhooks,
 	}
 
 	fieldUpdates := []*sqlf.Query{
@@ -305,9 +319,14 @@ func (s *savedSearches) Update(ctx context.Context, savedSearch *types.SavedSear
 		sqlf.Sprintf("org_id=%v", savedSearch.OrgID),
 		sqlf.Sprintf("slack_webhook_url=%v", savedSearch.SlackWebhookURL),
 	}
+	// Webhooks are only replaced when given, so that clients unaware of them
+	// do not drop the webhooks of a saved search when updating it.
+	if savedSearch.Webhooks != nil {
+		fieldUpdates = append(fieldUpdates, sqlf.Sprintf("webhooks=%v", savedSearchWebhooks{&savedSearch.Webhooks}))
+	}
 
-	updateQuery := sqlf.Sprintf(`UPDATE saved_searches SET %s WHERE ID=%v RETURNING id`, sqlf.Join(fieldUpdates, ", "), savedSearch.ID)
-	if err := dbconn.Global.QueryRowContext(ctx, updateQuery.Query(sqlf.PostgresBindVar), updateQuery.Args()...).Scan(&savedQuery.ID); err != nil {
+	updateQuery := sqlf.Sprintf(`UPDATE saved_searches SET %s WHERE ID=%v RETURNING id, webhooks`, sqlf.Join(fieldUpdates, ", "), savedSearch.ID)
+	if err := dbconn.Global.QueryRowContext(ctx, updateQuery.Query(sqlf.PostgresBindVar), updateQuery.Args()...).Scan(&savedQuery.ID, &savedSearchWebhooks{&savedQuery.Webhooks}); err != nil {
 		return nil, err
 	}
 	return savedQuery, nil
@@ -334,3 +353,37 @@ func (s *savedSearches) Delete(ctx context.Context, id int32) (err error) {
 	}
 	return nil
 }
+
+// savedSearchWebhooks represents the webhooks of a saved search stored as a
+// JSONB array. It implements the sql.Scanner and driver.Valuer interfaces. An
+// empty array is scanned as a nil slice.
+type savedSearchWebhooks struct {
+	webhooks *[]*schema.SavedQueryWebhook
+}
+
+// Scan implements the Scanner interface.
+func (w *savedSearchWebhooks) Scan(value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		var webhooks []*schema.SavedQueryWebhook
+		if err := json.Unmarshal(value, &webhooks); err != nil {
+			return err
+		}
+		if len(webhooks) > 0 {
+			*w.webhooks = webhooks
+		}
+		return nil
+	default:
+		return fmt.Errorf("value is not []byte: %T", value)
+	}
+}
+
+// Value implements the driver Valuer interface.
+func (w savedSearchWebhooks) Value() (driver.Value, error) {
+	if *w.webhooks == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(*w.webhooks)
+}
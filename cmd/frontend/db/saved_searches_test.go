@@ -8,6 +8,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func TestSavedSearchesIsEmpty(t *testing.T) {
@@ -388,3 +389,68 @@ func TestListSavedSearchesByUserID(t *testing.T) {
 		t.Errorf("got %v, want %v", savedSearches, want)
 	}
 }
+
+func TestSavedSearchesWebhooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+	_, err := Users.Create(ctx, NewUser{DisplayName: "test", Email: "test@test.com", Username: "test", Password: "test", EmailVerificationCode: "c2"})
+	if err != nil {
+		t.Fatal("can't create user", err)
+	}
+	userID := int32(1)
+	webhooks := []*schema.SavedQueryWebhook{
+		{Url: "https://example.com/hook", Format: "json", Secret: "s3cr3t"},
+		{Url: "https://example.com/teams", Format: "teams"},
+	}
+	fake := &types.SavedSearch{
+		Query:       "test",
+		Description: "test",
+		UserID:      &userID,
+		Webhooks:    webhooks,
+	}
+	ss, err := SavedSearches.Create(ctx, fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	savedSearch, err := SavedSearches.GetByID(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(savedSearch.Config.Webhooks, webhooks) {
+		t.Errorf("got webhooks %v, want %v", savedSearch.Config.Webhooks, webhooks)
+	}
+
+	// Updating without webhooks keeps the existing webhooks.
+	updatedSearch, err := SavedSearches.Update(ctx, &types.SavedSearch{
+		ID:          ss.ID,
+		Query:       "test2",
+		Description: "test2",
+		UserID:      &userID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(updatedSearch.Webhooks, webhooks) {
+		t.Errorf("got webhooks %v, want %v", updatedSearch.Webhooks, webhooks)
+	}
+
+	// Updating with an empty list removes all webhooks.
+	updatedSearch, err = SavedSearches.Update(ctx, &types.SavedSearch{
+		ID:          ss.ID,
+		Query:       "test2",
+		Description: "test2",
+		UserID:      &userID,
+		Webhooks:    []*schema.SavedQueryWebhook{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updatedSearch.Webhooks) != 0 {
+		t.Errorf("got webhooks %v, want none", updatedSearch.Webhooks)
+	}
+}
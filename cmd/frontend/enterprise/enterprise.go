@@ -11,6 +11,7 @@ import (
 // enterprise frontend setup hook.
 type Services struct {
 	GithubWebhook             http.Handler
+	GitLabWebhook             http.Handler
 	BitbucketServerWebhook    http.Handler
 	NewCodeIntelUploadHandler NewCodeIntelUploadHandler
 	AuthzResolver             graphqlbackend.AuthzResolver
@@ -26,6 +27,7 @@ type NewCodeIntelUploadHandler func(internal bool) http.Handler
 func DefaultServices() Services {
 	return Services{
 		GithubWebhook:             makeNotFoundHandler("github webhook"),
+		GitLabWebhook:             makeNotFoundHandler("gitlab webhook"),
 		BitbucketServerWebhook:    makeNotFoundHandler("bitbucket server webhook"),
 		NewCodeIntelUploadHandler: func(_ bool) http.Handler { return makeNotFoundHandler("code intel upload") },
 		AuthzResolver:             graphqlbackend.DefaultAuthzResolver,
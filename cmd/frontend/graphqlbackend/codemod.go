@@ -2,20 +2,23 @@ package graphqlbackend
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"sync"
 
-	"github.com/inconshreveable/log15"
 	"github.com/opentracing-contrib/go-stdlib/nethttp"
 	otlog "github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
@@ -286,27 +289,22 @@ func callCodemodInRepo(ctx context.Context, repoRevs *search.RepositoryRevisions
 	}
 
 	scanner := bufio.NewScanner(resp.Body)
-	// TODO(RVT): Remove buffer inefficiency introduced here. Results are
-	// line encoded JSON. Malformed JSON can happen, for extremely long
-	// lines. Unless we know where this and subsequent malformed lines end,
-	// we can't continue decoding the next one. As an inefficient but robust
-	// solution, we allow to buffer a very high maximum length line and can
-	// skip over very long malformed lines. It is set to 10 * 64K.
+	// Results are line encoded JSON. Allow very long lines, which hold the
+	// diffs of large files. It is set to 10 * 64K.
 	scanner.Buffer(make([]byte, 100), 10*bufio.MaxScanTokenSize)
 
 	repoResolver := &RepositoryResolver{repo: repoRevs.Repo}
 
 	for scanner.Scan() {
-		var raw *rawCodemodResult
 		b := scanner.Bytes()
-		if err := scanner.Err(); err != nil {
-			log15.Info(fmt.Sprintf("Skipping codemod scanner error (line too long?): %s", err.Error()))
+		if len(bytes.TrimSpace(b)) == 0 {
 			continue
 		}
+		// Skipping lines that can't be decoded would silently drop
+		// rewrites, e.g. when a patch set is created from them.
+		var raw *rawCodemodResult
 		if err := json.Unmarshal(b, &raw); err != nil {
-			// skip on other decode errors (including e.g., empty
-			// responses if dependencies are not installed)
-			continue
+			return nil, errors.Wrap(err, "invalid codemod output")
 		}
 		fileURL := fileMatchURI(repoRevs.Repo.Name, repoRevs.Revs[0].RevSpec, raw.URI)
 		matches, err := toMatchResolver(fileURL, raw)
@@ -325,6 +323,167 @@ func callCodemodInRepo(ctx context.Context, repoRevs *search.RepositoryRevisions
 			matches: matches,
 		})
 	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "reading codemod output")
+	}
 
 	return results, nil
 }
+
+type CreatePatchSetFromCodemodArgs struct {
+	Query string
+}
+
+// CreatePatchSetFromCodemod runs a codemod search query and creates a patch
+// set with one patch per repository, which contains the rewrites of all files
+// in the repository.
+func (r *schemaResolver) CreatePatchSetFromCodemod(ctx context.Context, args *CreatePatchSetFromCodemodArgs) (PatchSetResolver, error) {
+	if _, ok := r.CampaignsResolver.(defaultCampaignsResolver); ok {
+		return nil, campaignsOnlyInEnterprise
+	}
+	// 🚨 SECURITY: Only site admins may create patch sets for now. This is
+	// checked again when the patch set is created, but we avoid running the
+	// codemod for users who may not create it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	results, err := codemodSearch(ctx, args.Query)
+	if err != nil {
+		return nil, err
+	}
+	patches, err := codemodPatches(ctx, results)
+	if err != nil {
+		return nil, err
+	}
+	if len(patches) == 0 {
+		return nil, errors.New("the codemod query did not rewrite any files")
+	}
+	return r.CampaignsResolver.CreatePatchSetFromPatches(ctx, CreatePatchSetFromPatchesArgs{Patches: patches})
+}
+
+// codemodSearch runs a codemod search query (a query with a replace: field)
+// and returns its results. It fails if the search did not complete in all
+// repositories, because the rewrites would be incomplete.
+func codemodSearch(ctx context.Context, queryString string) ([]*codemodResultResolver, error) {
+	patternType := "structural"
+	impl, err := NewSearchImplementer(&SearchArgs{
+		Version:     "V2",
+		PatternType: &patternType,
+		Query:       queryString,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sr, ok := impl.(*searchResolver)
+	if !ok {
+		// The query is invalid, and impl is the alert describing why.
+		if alert, ok := impl.(*searchAlert); ok {
+			return nil, errors.New(alert.title)
+		}
+		return nil, errors.New("invalid search query")
+	}
+	if len(sr.query.Values(query.FieldReplace)) == 0 {
+		return nil, errors.New("the query must contain a replace: field")
+	}
+
+	srr, err := sr.doResults(ctx, "codemod")
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCodemodSearchComplete(srr); err != nil {
+		return nil, err
+	}
+
+	var results []*codemodResultResolver
+	for _, result := range srr.Results() {
+		if cm, ok := result.ToCodemodResult(); ok {
+			results = append(results, cm)
+		}
+	}
+	return results, nil
+}
+
+// checkCodemodSearchComplete returns an error if the given codemod search
+// results don't cover all repositories matched by the query or all of their
+// matches, or the search returned an alert (for example because it matched
+// too many repositories) instead of searching them.
+func checkCodemodSearchComplete(srr *SearchResultsResolver) error {
+	if alert := srr.Alert(); alert != nil {
+		if description := alert.Description(); description != nil {
+			return fmt.Errorf("the codemod search did not complete: %s. %s", alert.Title(), *description)
+		}
+		return fmt.Errorf("the codemod search did not complete: %s", alert.Title())
+	}
+	if n := len(srr.Timedout()); n > 0 {
+		return fmt.Errorf("the codemod timed out in %d repositories, try again with a larger timeout: value", n)
+	}
+	if n := len(srr.Cloning()); n > 0 {
+		return fmt.Errorf("the codemod could not run in %d repositories that are still cloning, try again later", n)
+	}
+	if n := len(srr.Missing()); n > 0 {
+		return fmt.Errorf("the codemod could not run in %d missing repositories", n)
+	}
+	if srr.LimitHit() {
+		return errors.New("the codemod search hit the result limit, try again with a larger count: value")
+	}
+	return nil
+}
+
+// codemodPatches combines the rewrites of the files of each repository
+// revision into a single unified diff, which can be applied to the base
+// revision with `git apply -p0`.
+func codemodPatches(ctx context.Context, results []*codemodResultResolver) ([]PatchInput, error) {
+	type repoRev struct {
+		repo   *RepositoryResolver
+		commit GitObjectID
+		rev    string
+	}
+	var (
+		keys  []repoRev
+		diffs = map[repoRev][]*codemodResultResolver{}
+	)
+	for _, result := range results {
+		key := repoRev{repo: result.commit.repoResolver, commit: result.commit.oid}
+		if result.commit.inputRev != nil {
+			key.rev = *result.commit.inputRev
+		}
+		if _, ok := diffs[key]; !ok {
+			keys = append(keys, key)
+		}
+		diffs[key] = append(diffs[key], result)
+	}
+
+	patches := make([]PatchInput, 0, len(keys))
+	for _, key := range keys {
+		baseRef := key.rev
+		if baseRef == "" {
+			defaultBranch, err := key.repo.DefaultBranch(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if defaultBranch == nil {
+				return nil, fmt.Errorf("unable to determine the default branch of %s", key.repo.Name())
+			}
+			baseRef = defaultBranch.Name()
+		}
+
+		files := diffs[key]
+		sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+		var patch strings.Builder
+		for _, file := range files {
+			patch.WriteString(file.diff)
+			if !strings.HasSuffix(file.diff, "\n") {
+				patch.WriteString("\n")
+			}
+		}
+
+		patches = append(patches, PatchInput{
+			Repository:   key.repo.ID(),
+			BaseRevision: api.CommitID(key.commit),
+			BaseRef:      git.EnsureRefPrefix(baseRef),
+			Patch:        patch.String(),
+		})
+	}
+	return patches, nil
+}
@@ -1,10 +1,18 @@
 package graphqlbackend
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 func TestCodemod_validateArgsNoRegex(t *testing.T) {
@@ -39,3 +47,125 @@ func TestCodemod_resolver(t *testing.T) {
 		t.Fatalf("Expected error %q", err)
 	}
 }
+
+func TestCodemod_patches(t *testing.T) {
+	repoA := &RepositoryResolver{repo: &types.Repo{ID: 1, Name: "github.com/a/a"}}
+	repoB := &RepositoryResolver{repo: &types.Repo{ID: 2, Name: "github.com/b/b"}}
+	result := func(repo *RepositoryResolver, rev, oid, path, diff string) *codemodResultResolver {
+		return &codemodResultResolver{
+			commit: &GitCommitResolver{repoResolver: repo, inputRev: &rev, oid: GitObjectID(oid)},
+			path:   path,
+			diff:   diff,
+		}
+	}
+	results := []*codemodResultResolver{
+		result(repoA, "master", "a1", "b.go", "--- b.go\n+++ b.go\n@@ -1 +1 @@\n-x\n+y"),
+		result(repoB, "refs/heads/dev", "b1", "c.go", "--- c.go\n+++ c.go\n@@ -1 +1 @@\n-x\n+y\n"),
+		result(repoA, "master", "a1", "a.go", "--- a.go\n+++ a.go\n@@ -1 +1 @@\n-x\n+y\n"),
+	}
+
+	patches, err := codemodPatches(context.Background(), results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []PatchInput{
+		{
+			Repository:   repoA.ID(),
+			BaseRevision: api.CommitID("a1"),
+			BaseRef:      "refs/heads/master",
+			Patch:        "--- a.go\n+++ a.go\n@@ -1 +1 @@\n-x\n+y\n--- b.go\n+++ b.go\n@@ -1 +1 @@\n-x\n+y\n",
+		},
+		{
+			Repository:   repoB.ID(),
+			BaseRevision: api.CommitID("b1"),
+			BaseRef:      "refs/heads/dev",
+			Patch:        "--- c.go\n+++ c.go\n@@ -1 +1 @@\n-x\n+y\n",
+		},
+	}
+	if !reflect.DeepEqual(patches, want) {
+		t.Errorf("got patches %+v, want %+v", patches, want)
+	}
+}
+
+func TestCodemod_searchComplete(t *testing.T) {
+	repo := &types.Repo{ID: 1, Name: "github.com/foo/bar"}
+	for name, tc := range map[string]struct {
+		srr     *SearchResultsResolver
+		wantErr string
+	}{
+		"complete": {
+			srr: &SearchResultsResolver{searchResultsCommon: searchResultsCommon{searched: []*types.Repo{repo}}},
+		},
+		"timed out": {
+			srr:     &SearchResultsResolver{searchResultsCommon: searchResultsCommon{timedout: []*types.Repo{repo}}},
+			wantErr: "the codemod timed out in 1 repositories",
+		},
+		"cloning": {
+			srr:     &SearchResultsResolver{searchResultsCommon: searchResultsCommon{cloning: []*types.Repo{repo}}},
+			wantErr: "the codemod could not run in 1 repositories that are still cloning",
+		},
+		"missing": {
+			srr:     &SearchResultsResolver{searchResultsCommon: searchResultsCommon{missing: []*types.Repo{repo}}},
+			wantErr: "the codemod could not run in 1 missing repositories",
+		},
+		"result limit hit": {
+			srr:     &SearchResultsResolver{searchResultsCommon: searchResultsCommon{searched: []*types.Repo{repo}, limitHit: true}},
+			wantErr: "the codemod search hit the result limit",
+		},
+		"over repo limit": {
+			srr:     &SearchResultsResolver{alert: &searchAlert{title: "Too many matching repositories", description: "Use a 'repo:' filter."}},
+			wantErr: "the codemod search did not complete: Too many matching repositories. Use a 'repo:' filter.",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := checkCodemodSearchComplete(tc.srr)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tc.wantErr) {
+				t.Fatalf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCallCodemodInRepo(t *testing.T) {
+	git.Mocks.ResolveRevision = func(spec string, opt git.ResolveRevisionOptions) (api.CommitID, error) {
+		return "a1", nil
+	}
+	defer git.ResetMocks()
+
+	var output string
+	replacer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(output))
+	}))
+	defer replacer.Close()
+	origReplacerURL := ReplacerURL
+	ReplacerURL = replacer.URL
+	defer func() { ReplacerURL = origReplacerURL }()
+
+	repoRevs := &search.RepositoryRevisions{
+		Repo: &types.Repo{ID: 1, Name: "github.com/foo/bar"},
+		Revs: []search.RevisionSpecifier{{RevSpec: "master"}},
+	}
+	result := `{"uri":"a.go","diff":"--- a.go\n+++ a.go\n@@ -1 +1 @@\n-x\n+y"}`
+
+	output = result + "\n\n"
+	results, err := callCodemodInRepo(context.Background(), repoRevs, &args{matchTemplate: "x", rewriteTemplate: "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].path != "a.go" {
+		t.Errorf("got results %+v, want one for a.go", results)
+	}
+
+	// Output that can't be parsed fails the search instead of dropping the
+	// rewrites in it.
+	output = result + "\n" + `{"uri":"b.go","diff":` + "\n"
+	if _, err := callCodemodInRepo(context.Background(), repoRevs, &args{matchTemplate: "x", rewriteTemplate: "y"}); err == nil || !strings.Contains(err.Error(), "invalid codemod output") {
+		t.Errorf("got error %v, want an invalid codemod output error", err)
+	}
+}
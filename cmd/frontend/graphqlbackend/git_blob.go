@@ -10,14 +10,28 @@ import (
 
 func (r *GitTreeEntryResolver) Blame(ctx context.Context,
 	args *struct {
-		StartLine int32
-		EndLine   int32
+		StartLine    int32
+		EndLine      int32
+		IgnoreRevs   bool
+		DetectMoves  bool
+		DetectCopies bool
 	}) ([]*hunkResolver, error) {
-	hunks, err := git.BlameFile(ctx, gitserver.Repo{Name: r.commit.repoResolver.repo.Name}, r.Path(), &git.BlameOptions{
+	repo := gitserver.Repo{Name: r.commit.repoResolver.repo.Name}
+	opt := &git.BlameOptions{
 		NewestCommit: api.CommitID(r.commit.OID()),
 		StartLine:    int(args.StartLine),
 		EndLine:      int(args.EndLine),
-	})
+		DetectMoves:  args.DetectMoves,
+		DetectCopies: args.DetectCopies,
+	}
+	if args.IgnoreRevs {
+		ignoreRevs, err := git.BlameIgnoreRevs(ctx, repo, opt.NewestCommit)
+		if err != nil {
+			return nil, err
+		}
+		opt.IgnoreRevs = ignoreRevs
+	}
+	hunks, err := git.BlameFile(ctx, repo, r.Path(), opt)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,478 @@
+package graphqlbackend
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"runtime"
+	"sort"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang/groupcache/lru"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// fuzzyFilesMaxFirst is the maximum number of matches that fuzzyFiles returns.
+const fuzzyFilesMaxFirst = 1000
+
+// fuzzyFilesCache caches the file paths of recently fuzzy-matched commits.
+// Listing the files of a large repository takes seconds, and the same commit
+// is queried on every keystroke in the file finder.
+var (
+	fuzzyFilesCacheMu sync.Mutex
+	fuzzyFilesCache   = lru.New(10)
+)
+
+type fuzzyFilesArgs struct {
+	Query string
+	First int32
+}
+
+func (r *GitCommitResolver) FuzzyFiles(ctx context.Context, args *fuzzyFilesArgs) ([]*fuzzyFileMatchResolver, error) {
+	if args.First < 0 {
+		return nil, errors.New("first must be non-negative")
+	}
+	first := int(args.First)
+	if first > fuzzyFilesMaxFirst {
+		first = fuzzyFilesMaxFirst
+	}
+
+	paths, err := r.filePaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matches := fuzzyMatchPaths(args.Query, paths, first)
+
+	resolvers := make([]*fuzzyFileMatchResolver, 0, len(matches))
+	for _, m := range matches {
+		resolvers = append(resolvers, &fuzzyFileMatchResolver{
+			file:      &GitTreeEntryResolver{commit: r, stat: CreateFileInfo(m.path, false)},
+			score:     m.score,
+			positions: m.positions,
+		})
+	}
+	return resolvers, nil
+}
+
+// filePaths returns the paths of all files in the tree of the commit.
+func (r *GitCommitResolver) filePaths(ctx context.Context) ([]string, error) {
+	key := string(r.repoResolver.Name()) + ":" + string(r.oid)
+	fuzzyFilesCacheMu.Lock()
+	v, ok := fuzzyFilesCache.Get(key)
+	fuzzyFilesCacheMu.Unlock()
+	if ok {
+		return v.([]string), nil
+	}
+
+	cachedRepo, err := backend.CachedGitRepo(ctx, r.repoResolver.repo)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := git.ListFiles(ctx, *cachedRepo, api.CommitID(r.oid))
+	if err != nil {
+		return nil, err
+	}
+
+	fuzzyFilesCacheMu.Lock()
+	fuzzyFilesCache.Add(key, paths)
+	fuzzyFilesCacheMu.Unlock()
+	return paths, nil
+}
+
+type fuzzyFileMatchResolver struct {
+	file      *GitTreeEntryResolver
+	score     int
+	positions []int
+}
+
+func (r *fuzzyFileMatchResolver) File() *GitTreeEntryResolver { return r.file }
+func (r *fuzzyFileMatchResolver) Score() int32                { return int32(r.score) }
+func (r *fuzzyFileMatchResolver) Positions() []int32 {
+	positions := make([]int32, len(r.positions))
+	for i, p := range r.positions {
+		positions[i] = int32(p)
+	}
+	return positions
+}
+
+type fuzzyMatch struct {
+	path      string
+	score     int
+	positions []int
+}
+
+// fuzzyMatchPaths returns the first n paths that match the query, ordered by
+// decreasing score. Paths with the same score are ordered by length and then
+// alphabetically. If the query is empty, all paths match with a score of 0.
+//
+// The paths are matched in parallel, because large repositories have hundreds
+// of thousands of files.
+func fuzzyMatchPaths(query string, paths []string, n int) []fuzzyMatch {
+	if n <= 0 {
+		return nil
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	chunkSize := (len(paths) + workers - 1) / workers
+	var (
+		wg   sync.WaitGroup
+		tops = make([]fuzzyMatchHeap, workers)
+	)
+	for w := 0; w < workers && w*chunkSize < len(paths); w++ {
+		chunk := paths[w*chunkSize:]
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		wg.Add(1)
+		go func(w int, chunk []string) {
+			defer wg.Done()
+			pattern := newFuzzyPattern(query)
+			for _, p := range chunk {
+				if score, ok := pattern.score(p, nil); ok {
+					tops[w].add(fuzzyMatch{path: p, score: score}, n)
+				}
+			}
+		}(w, chunk)
+	}
+	wg.Wait()
+
+	var matches []fuzzyMatch
+	for _, top := range tops {
+		matches = append(matches, top...)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ranksBefore(matches[j]) })
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+
+	// Only compute the positions of the matches that are returned.
+	pattern := newFuzzyPattern(query)
+	for i := range matches {
+		matches[i].positions = []int{}
+		pattern.score(matches[i].path, &matches[i].positions)
+	}
+	return matches
+}
+
+func (m fuzzyMatch) ranksBefore(other fuzzyMatch) bool {
+	if m.score != other.score {
+		return m.score > other.score
+	}
+	if len(m.path) != len(other.path) {
+		return len(m.path) < len(other.path)
+	}
+	return m.path < other.path
+}
+
+// fuzzyMatchHeap holds the best matches seen so far, with the worst of them
+// at the root.
+type fuzzyMatchHeap []fuzzyMatch
+
+func (h fuzzyMatchHeap) Len() int            { return len(h) }
+func (h fuzzyMatchHeap) Less(i, j int) bool  { return h[j].ranksBefore(h[i]) }
+func (h fuzzyMatchHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *fuzzyMatchHeap) Push(x interface{}) { *h = append(*h, x.(fuzzyMatch)) }
+func (h *fuzzyMatchHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// add adds m to the heap if it is among the best n matches seen so far.
+func (h *fuzzyMatchHeap) add(m fuzzyMatch, n int) {
+	if h.Len() < n {
+		heap.Push(h, m)
+	} else if m.ranksBefore((*h)[0]) {
+		(*h)[0] = m
+		heap.Fix(h, 0)
+	}
+}
+
+// The scores and bonuses of fuzzy matching, which are modeled after those of
+// fzf. Every matched character scores fuzzyScoreMatch plus the bonus of its
+// position, and every unmatched character between the first and last matched
+// character is penalized.
+const (
+	fuzzyScoreMatch        = 16
+	fuzzyScoreGapStart     = -3
+	fuzzyScoreGapExtension = -1
+
+	// fuzzyBonusSegment is the bonus of matching the first character of a
+	// path segment (a directory or file name).
+	fuzzyBonusSegment = 10
+	// fuzzyBonusBoundary is the bonus of matching the first character of a
+	// word, e.g. after "_", "-" or ".", and of matching a non-word character.
+	fuzzyBonusBoundary = 8
+	// fuzzyBonusCamel123 is the bonus of matching an uppercase letter after a
+	// lowercase letter, or a digit after a non-digit.
+	fuzzyBonusCamel123 = fuzzyBonusBoundary - 1
+	// fuzzyBonusConsecutive is the minimum bonus of a character matched right
+	// after another matched character. Consecutive characters also inherit
+	// the bonus of the first character of their run.
+	fuzzyBonusConsecutive = -(fuzzyScoreGapStart + fuzzyScoreGapExtension)
+	// fuzzyBonusFirstCharMultiplier multiplies the bonus of the first
+	// character of the query.
+	fuzzyBonusFirstCharMultiplier = 2
+	// fuzzyBonusBasename is the bonus of every character matched in the file
+	// name, because users usually type (parts of) the file name.
+	fuzzyBonusBasename = 2
+)
+
+type fuzzyCharClass int
+
+const (
+	fuzzyCharNonWord fuzzyCharClass = iota
+	fuzzyCharSlash
+	fuzzyCharLower
+	fuzzyCharUpper
+	fuzzyCharLetter
+	fuzzyCharNumber
+)
+
+func fuzzyCharClassOf(c rune) fuzzyCharClass {
+	switch {
+	case c == '/':
+		return fuzzyCharSlash
+	case unicode.IsLower(c):
+		return fuzzyCharLower
+	case unicode.IsUpper(c):
+		return fuzzyCharUpper
+	case unicode.IsLetter(c):
+		return fuzzyCharLetter
+	case unicode.IsNumber(c):
+		return fuzzyCharNumber
+	}
+	return fuzzyCharNonWord
+}
+
+// fuzzyBonus returns the bonus of matching a character of class cur that
+// follows a character of class prev.
+func fuzzyBonus(prev, cur fuzzyCharClass) int {
+	switch {
+	case cur == fuzzyCharNonWord || cur == fuzzyCharSlash:
+		return fuzzyBonusBoundary
+	case prev == fuzzyCharSlash:
+		return fuzzyBonusSegment
+	case prev == fuzzyCharNonWord:
+		return fuzzyBonusBoundary
+	case prev == fuzzyCharLower && cur == fuzzyCharUpper, prev != fuzzyCharNumber && cur == fuzzyCharNumber:
+		return fuzzyBonusCamel123
+	}
+	return 0
+}
+
+// fuzzyPattern is a query for fuzzy matching paths. A path matches if it
+// contains all characters of the query in the same order. The match is case
+// sensitive only if the query contains an uppercase character. Whitespace in
+// the query is ignored.
+type fuzzyPattern struct {
+	runes         []rune
+	caseSensitive bool
+
+	// Buffers reused by score.
+	text, folded                    []rune
+	bonus, scores, firstBonus, from []int
+}
+
+func newFuzzyPattern(query string) *fuzzyPattern {
+	p := &fuzzyPattern{}
+	for _, c := range query {
+		if unicode.IsSpace(c) {
+			continue
+		}
+		if unicode.IsUpper(c) {
+			p.caseSensitive = true
+		}
+		p.runes = append(p.runes, c)
+	}
+	if !p.caseSensitive {
+		for i, c := range p.runes {
+			p.runes[i] = unicode.ToLower(c)
+		}
+	}
+	return p
+}
+
+// fold returns the characters of filePath, and the characters to compare to
+// the pattern (which are lowercased unless the pattern is case-sensitive).
+func (p *fuzzyPattern) fold(filePath string) (text, folded []rune) {
+	p.text, p.folded = p.text[:0], p.folded[:0]
+	for _, c := range filePath {
+		p.text = append(p.text, c)
+		if !p.caseSensitive {
+			if c < utf8.RuneSelf {
+				if 'A' <= c && c <= 'Z' {
+					c += 'a' - 'A'
+				}
+			} else {
+				c = unicode.ToLower(c)
+			}
+		}
+		p.folded = append(p.folded, c)
+	}
+	return p.text, p.folded
+}
+
+// fuzzyNoMatch is the score of impossible matches in fuzzyPattern.score.
+const fuzzyNoMatch = -1 << 30
+
+// score returns the score of the best match of the pattern in path, and
+// whether it matches at all. If positions is non-nil, the character offsets of
+// the matched characters in path are appended to it.
+//
+// Like fzf's v2 algorithm, it finds the best match with dynamic programming,
+// which takes time proportional to the length of the path times the length of
+// the pattern. Paths that don't match are rejected with a linear scan first.
+// It is not safe for concurrent use, because it reuses its buffers.
+func (p *fuzzyPattern) score(filePath string, positions *[]int) (int, bool) {
+	m := len(p.runes)
+	if m == 0 {
+		return 0, true
+	}
+	text, folded := p.fold(filePath)
+
+	// Reject paths that don't contain the pattern, and narrow the range of
+	// the path that can contain matched characters: from the first
+	// occurrence of the first character to the last occurrence of the last
+	// character.
+	lo, pidx := 0, 0
+	for i, c := range folded {
+		if c == p.runes[pidx] {
+			if pidx == 0 {
+				lo = i
+			}
+			pidx++
+			if pidx == m {
+				break
+			}
+		}
+	}
+	if pidx < m {
+		return 0, false
+	}
+	hi := len(text)
+	for hi > lo && folded[hi-1] != p.runes[m-1] {
+		hi--
+	}
+
+	basenameStart := 0
+	for i, c := range text {
+		if c == '/' {
+			basenameStart = i + 1
+		}
+	}
+	n := hi - lo
+	if cap(p.bonus) < n {
+		p.bonus = make([]int, n)
+	}
+	bonus := p.bonus[:n]
+	prevClass := fuzzyCharSlash
+	if lo > 0 {
+		prevClass = fuzzyCharClassOf(text[lo-1])
+	}
+	for j := 0; j < n; j++ {
+		class := fuzzyCharClassOf(text[lo+j])
+		bonus[j] = fuzzyBonus(prevClass, class)
+		prevClass = class
+	}
+
+	// scores[i*n+j] is the best score of matching the first i+1 characters
+	// of the pattern such that character i is matched at offset lo+j.
+	// firstBonus is the bonus of the first character of the consecutive run
+	// that ends there, and from is the offset (relative to lo) at which
+	// character i-1 is matched.
+	if cap(p.scores) < m*n {
+		p.scores = make([]int, m*n)
+		p.firstBonus = make([]int, m*n)
+		p.from = make([]int, m*n)
+	}
+	scores, firstBonus, from := p.scores[:m*n], p.firstBonus[:m*n], p.from[:m*n]
+	for i := 0; i < m; i++ {
+		// gapScore is the best score of matching character i-1 before
+		// offset j-1, including the penalty for the gap up to offset j.
+		gapScore, gapFrom := fuzzyNoMatch, -1
+		for j := 0; j < n; j++ {
+			idx := i*n + j
+			scores[idx] = fuzzyNoMatch
+			if i > 0 && j >= 2 {
+				if gapScore != fuzzyNoMatch {
+					gapScore += fuzzyScoreGapExtension
+				}
+				if s := scores[idx-n-2]; s != fuzzyNoMatch && s+fuzzyScoreGapStart > gapScore {
+					gapScore, gapFrom = s+fuzzyScoreGapStart, j-2
+				}
+			}
+			if folded[lo+j] != p.runes[i] {
+				continue
+			}
+
+			var basename int
+			if lo+j >= basenameStart {
+				basename = fuzzyBonusBasename
+			}
+			b := bonus[j]
+			if i == 0 {
+				scores[idx] = fuzzyScoreMatch + b*fuzzyBonusFirstCharMultiplier + basename
+				firstBonus[idx] = b
+				from[idx] = -1
+				continue
+			}
+
+			best, bestFrom, bestFirstBonus := fuzzyNoMatch, -1, b
+			if j >= 1 && scores[idx-n-1] != fuzzyNoMatch {
+				fb := firstBonus[idx-n-1]
+				if b >= fuzzyBonusBoundary && b > fb {
+					fb = b
+				}
+				best = scores[idx-n-1] + fuzzyScoreMatch + maxInt(b, fb, fuzzyBonusConsecutive)
+				bestFrom, bestFirstBonus = j-1, fb
+			}
+			if gapScore != fuzzyNoMatch {
+				if s := gapScore + fuzzyScoreMatch + b; s > best {
+					best, bestFrom, bestFirstBonus = s, gapFrom, b
+				}
+			}
+			if best == fuzzyNoMatch {
+				continue
+			}
+			scores[idx] = best + basename
+			firstBonus[idx] = bestFirstBonus
+			from[idx] = bestFrom
+		}
+	}
+
+	// Prefer the last of equally scored matches, which is closer to the
+	// file name.
+	score, end := fuzzyNoMatch, -1
+	for j := 0; j < n; j++ {
+		if s := scores[(m-1)*n+j]; s != fuzzyNoMatch && s >= score {
+			score, end = s, j
+		}
+	}
+	if end == -1 {
+		return 0, false
+	}
+	if positions != nil {
+		matched := make([]int, m)
+		for i, j := m-1, end; i >= 0; i-- {
+			matched[i] = lo + j
+			j = from[i*n+j]
+		}
+		*positions = append(*positions, matched...)
+	}
+	return score, true
+}
+
+func maxInt(a int, bs ...int) int {
+	for _, b := range bs {
+		if b > a {
+			a = b
+		}
+	}
+	return a
+}
@@ -0,0 +1,109 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestFuzzyMatchPaths(t *testing.T) {
+	paths := []string{
+		"README.md",
+		"cmd/frontend/graphqlbackend/git_tree.go",
+		"cmd/frontend/graphqlbackend/git_tree_entry.go",
+		"cmd/frontend/graphqlbackend/schema.graphql",
+		"cmd/gitserver/server/server.go",
+		"internal/vcs/git/tree.go",
+		"web/src/search/SearchResults.tsx",
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Matches in the file name rank before matches spread across
+		// directories, and shorter paths rank first.
+		{query: "tree.go", want: []string{"internal/vcs/git/tree.go", "cmd/frontend/graphqlbackend/git_tree.go", "cmd/frontend/graphqlbackend/git_tree_entry.go"}},
+		// Word boundaries (after "_" or "/") score higher than other
+		// characters.
+		{query: "gte", want: []string{"cmd/frontend/graphqlbackend/git_tree_entry.go", "internal/vcs/git/tree.go", "cmd/frontend/graphqlbackend/git_tree.go"}},
+		// Path segment starts score higher.
+		{query: "cfgs", want: []string{"cmd/frontend/graphqlbackend/schema.graphql"}},
+		// Uppercase characters make the match case-sensitive.
+		{query: "SR", want: []string{"web/src/search/SearchResults.tsx"}},
+		{query: "readme", want: []string{"README.md"}},
+		{query: "Readme", want: nil},
+		// Whitespace is ignored.
+		{query: "server go", want: []string{"cmd/gitserver/server/server.go"}},
+		{query: "xyz", want: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			var got []string
+			for _, m := range fuzzyMatchPaths(test.query, paths, 3) {
+				got = append(got, m.path)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFuzzyPattern_positions(t *testing.T) {
+	tests := []struct {
+		query, path string
+		want        []int
+	}{
+		// The last occurrence is matched.
+		{query: "main", path: "cmd/main/main.go", want: []int{9, 10, 11, 12}},
+		{query: "gte", path: "a/git_tree_entry.go", want: []int{2, 6, 11}},
+		{query: "", path: "a.go", want: []int{}},
+	}
+	for _, test := range tests {
+		got := []int{}
+		if _, ok := newFuzzyPattern(test.query).score(test.path, &got); !ok {
+			t.Errorf("%q does not match %q", test.query, test.path)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q in %q: got positions %v, want %v", test.query, test.path, got, test.want)
+		}
+	}
+}
+
+func TestGitCommitResolver_FuzzyFiles(t *testing.T) {
+	defer git.ResetMocks()
+	calls := 0
+	git.Mocks.ListFiles = func(commit api.CommitID) ([]string, error) {
+		calls++
+		return []string{"a/foo.go", "b/bar.go", "foo_test.go"}, nil
+	}
+
+	commit := &GitCommitResolver{
+		repoResolver: &RepositoryResolver{repo: &types.Repo{Name: "github.com/gorilla/mux"}},
+		oid:          "1234567890123456789012345678901234567890",
+	}
+	for i := 0; i < 2; i++ {
+		matches, err := commit.FuzzyFiles(context.Background(), &fuzzyFilesArgs{Query: "foo", First: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) != 1 {
+			t.Fatalf("got %d matches, want 1", len(matches))
+		}
+		if got, want := matches[0].File().Path(), "a/foo.go"; got != want {
+			t.Errorf("got path %q, want %q", got, want)
+		}
+		if got, want := matches[0].Positions(), []int32{2, 3, 4}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got positions %v, want %v", got, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("got %d calls to ListFiles, want 1 (the file paths should be cached)", calls)
+	}
+}
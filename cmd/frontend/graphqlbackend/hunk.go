@@ -57,3 +57,29 @@ func (r *hunkResolver) Commit(ctx context.Context) (*GitCommitResolver, error) {
 	}
 	return toGitCommitResolver(r.repo, commit), nil
 }
+
+func (r *hunkResolver) Filename() string {
+	return r.hunk.Filename
+}
+
+func (r *hunkResolver) PreviousCommit(ctx context.Context) (*GitCommitResolver, error) {
+	if r.hunk.PreviousCommit == "" {
+		return nil, nil
+	}
+	cachedRepo, err := backend.CachedGitRepo(ctx, r.repo.repo)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := git.GetCommit(ctx, *cachedRepo, nil, r.hunk.PreviousCommit, git.ResolveRevisionOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return toGitCommitResolver(r.repo, commit), nil
+}
+
+func (r *hunkResolver) PreviousFilename() *string {
+	if r.hunk.PreviousFilename == "" {
+		return nil
+	}
+	return &r.hunk.PreviousFilename
+}
@@ -3,6 +3,9 @@ package graphqlbackend
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"strings"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -11,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/cmd/query-runner/queryrunnerapi"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 type savedSearchResolver struct {
@@ -59,6 +63,7 @@ func savedSearchByID(ctx context.Context, id graphql.ID) (*savedSearchResolver,
 			UserID:          ss.Config.UserID,
 			OrgID:           ss.Config.OrgID,
 			SlackWebhookURL: ss.Config.SlackWebhookURL,
+			Webhooks:        ss.Config.Webhooks,
 		},
 	}
 	return savedSearch, nil
@@ -100,6 +105,80 @@ func (r savedSearchResolver) Namespace(ctx context.Context) (*NamespaceResolver,
 
 func (r savedSearchResolver) SlackWebhookURL() *string { return r.s.SlackWebhookURL }
 
+func (r savedSearchResolver) Webhooks() []*savedSearchWebhookResolver {
+	webhooks := make([]*savedSearchWebhookResolver, 0, len(r.s.Webhooks))
+	for _, webhook := range r.s.Webhooks {
+		webhooks = append(webhooks, &savedSearchWebhookResolver{webhook})
+	}
+	return webhooks
+}
+
+type savedSearchWebhookResolver struct {
+	w *schema.SavedQueryWebhook
+}
+
+// URL returns the webhook URL with everything but its scheme and host redacted. Webhook URLs
+// usually embed a token in their path, query or user info, which makes the full URL a credential.
+func (r *savedSearchWebhookResolver) URL() string { return redactWebhookURL(r.w.Url) }
+
+// redactWebhookURL returns the scheme and host of the given webhook URL followed by a redacted
+// path.
+func redactWebhookURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "<redacted>"
+	}
+	return fmt.Sprintf("%s://%s/<redacted>", u.Scheme, u.Host)
+}
+
+func (r *savedSearchWebhookResolver) Format() string {
+	if r.w.Format == "" {
+		return "json"
+	}
+	return r.w.Format
+}
+
+type savedSearchWebhookInput struct {
+	URL    string
+	Format *string
+	Secret *string
+}
+
+// toSavedQueryWebhooks validates the given webhook inputs and converts them to their settings
+// representation. A nil input is returned as a nil slice, which leaves the webhooks of an existing
+// saved search unchanged.
+func toSavedQueryWebhooks(inputs *[]*savedSearchWebhookInput) ([]*schema.SavedQueryWebhook, error) {
+	if inputs == nil {
+		return nil, nil
+	}
+
+	webhooks := make([]*schema.SavedQueryWebhook, 0, len(*inputs))
+	for _, input := range *inputs {
+		if strings.Contains(input.URL, "<redacted>") {
+			return nil, fmt.Errorf("invalid webhook URL %q: webhook URLs are redacted when read, provide the full URL", input.URL)
+		}
+		u, err := url.Parse(input.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q: must be an absolute http or https URL", input.URL)
+		}
+
+		webhook := &schema.SavedQueryWebhook{Url: input.URL}
+		if input.Format != nil {
+			switch *input.Format {
+			case "json", "teams", "mattermost":
+				webhook.Format = *input.Format
+			default:
+				return nil, fmt.Errorf("invalid webhook format %q: must be one of json, teams or mattermost", *input.Format)
+			}
+		}
+		if input.Secret != nil {
+			webhook.Secret = *input.Secret
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, nil
+}
+
 func toSavedSearchResolver(entry types.SavedSearch) *savedSearchResolver {
 	return &savedSearchResolver{entry}
 }
@@ -151,6 +230,7 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 	NotifySlack bool
 	OrgID       *graphql.ID
 	UserID      *graphql.ID
+	Webhooks    *[]*savedSearchWebhookInput
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to create a saved search for the specified user or org.
@@ -180,6 +260,11 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 		return nil, errMissingPatternType
 	}
 
+	webhooks, err := toSavedQueryWebhooks(args.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+
 	ss, err := db.SavedSearches.Create(ctx, &types.SavedSearch{
 		Description: args.Description,
 		Query:       args.Query,
@@ -187,6 +272,7 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 		NotifySlack: args.NotifySlack,
 		UserID:      userID,
 		OrgID:       orgID,
+		Webhooks:    webhooks,
 	})
 	if err != nil {
 		return nil, err
@@ -203,6 +289,7 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 	NotifySlack bool
 	OrgID       *graphql.ID
 	UserID      *graphql.ID
+	Webhooks    *[]*savedSearchWebhookInput
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to update a saved search for the specified user or org.
@@ -237,6 +324,11 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 		return nil, errMissingPatternType
 	}
 
+	webhooks, err := toSavedQueryWebhooks(args.Webhooks)
+	if err != nil {
+		return nil, err
+	}
+
 	ss, err := db.SavedSearches.Update(ctx, &types.SavedSearch{
 		ID:          id,
 		Description: args.Description,
@@ -245,6 +337,7 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 		NotifySlack: args.NotifySlack,
 		UserID:      userID,
 		OrgID:       orgID,
+		Webhooks:    webhooks,
 	})
 	if err != nil {
 		return nil, err
@@ -47,6 +47,18 @@ type Mutation {
         # created from this PatchSet.
         patches: [PatchInput!]!
     ): PatchSet!
+    # Create a patchset from the rewrites of a codemod search query (a structural search query
+    # with a replace: field). The rewritten files of each repository become one patch against the
+    # revision that was searched. Run the query as a search first to preview the rewrites. This
+    # fails if the search did not run in all matching repositories, for example because some
+    # timed out or are still cloning, or the query matched too many repositories.
+    #
+    # To create the campaign, call createCampaign with the returned PatchSet.id in the
+    # CreateCampaignInput.patchSet field.
+    createPatchSetFromCodemod(
+        # The codemod search query, for example "repo:foo errors.New(fmt.Sprintf(:[args])) replace:fmt.Errorf(:[args])".
+        query: String!
+    ): PatchSet!
     # Updates a campaign. Updating is not allowed when any of the following are true:
     #
     # - The campaign has been closed.
@@ -376,6 +388,9 @@ type Mutation {
         notifySlack: Boolean!
         orgID: ID
         userID: ID
+        # HTTP webhooks to notify when the saved search has new results. When updating a saved
+        # search, its existing webhooks are kept if this is omitted.
+        webhooks: [SavedSearchWebhookInput!]
     ): SavedSearch!
     # Updates a saved search
     updateSavedSearch(
@@ -386,6 +401,9 @@ type Mutation {
         notifySlack: Boolean!
         orgID: ID
         userID: ID
+        # HTTP webhooks to notify when the saved search has new results. When updating a saved
+        # search, its existing webhooks are kept if this is omitted.
+        webhooks: [SavedSearchWebhookInput!]
     ): SavedSearch!
     # Deletes a saved search
     deleteSavedSearch(id: ID!): EmptyResponse
@@ -1222,6 +1240,41 @@ type Query {
     clientConfiguration: ClientConfigurationDetails!
     # Fetch search filter suggestions for autocompletion.
     searchFilterSuggestions: SearchFilterSuggestions!
+    # Completions for the token of a search query under the cursor: field names, or the values of the field
+    # whose value is under the cursor. Values are completed for the fields type:, patterntype:, fork:,
+    # archived:, visibility:, repogroup:, lang: and repo:.
+    searchQueryCompletions(
+        # The search query (such as "repo:myrepo foo").
+        query: String!
+        # The character offset (zero-based) of the cursor in the query. Defaults to the end of the query.
+        cursor: Int
+        # Returns the first n completions.
+        first: Int = 50
+    ): [SearchQueryCompletion!]!
+    # (experimental) Runs a search and counts its matches grouped by the given dimension, such as the repository
+    # or the author of the matches.
+    #
+    # Unless the query specifies count:, the search fetches up to 100,000 results to approximate an exhaustive
+    # search. SearchAggregations.limitHit is true if the counts do not include all matches.
+    searchAggregations(
+        # The version of the search syntax being used.
+        version: SearchVersion = V2
+        # PatternType controls the search pattern type, if and only if it is not specified in the query string using
+        # the patternType: field.
+        patternType: SearchPatternType
+        # The search query (such as "foo" or "repo:myrepo foo").
+        query: String!
+        # (experimental) Optionally specify the versionContext. If not specified the
+        # default version context is used (all repositories on the default branch).
+        versionContext: String
+        # The dimension to group the matches by.
+        groupBy: SearchAggregationGroupBy!
+        # When grouping by PATH_PREFIX, the number of leading directories of the file paths to group by.
+        pathPrefixDepth: Int = 1
+        # When grouping by CAPTURE_GROUP, the index of the capture group of the regular expression search pattern
+        # to group by.
+        captureGroup: Int = 1
+    ): SearchAggregations!
     # Runs a search.
     search(
         # The version of the search syntax being used.
@@ -1413,6 +1466,25 @@ type SearchFilterSuggestions {
     repo: [String!]!
 }
 
+# A completion of a search query token.
+type SearchQueryCompletion {
+    # Whether the completion is a field name (including its colon) or a field value.
+    kind: SearchQueryCompletionKind!
+    # The text to replace the range of the query with.
+    text: String!
+    # The range of the query that the text replaces. The range is on line 0, and its characters are offsets in
+    # the query.
+    range: Range!
+}
+
+# The kind of a search query completion.
+enum SearchQueryCompletionKind {
+    # A field name, such as "repo:".
+    FIELD
+    # A field value, such as "go" for "lang:".
+    VALUE
+}
+
 # A search result.
 union SearchResult = FileMatch | CommitSearchResult | Repository | CodemodResult
 
@@ -1544,6 +1616,42 @@ type SearchResultsStats {
     languages: [LanguageStatistics!]!
 }
 
+# The dimension that search aggregations group matches by.
+enum SearchAggregationGroupBy {
+    # The repository of the match.
+    REPOSITORY
+    # The owner of the repository of the match, i.e. the repository name without its last path component (such as
+    # "github.com/sourcegraph" for "github.com/sourcegraph/sourcegraph").
+    OWNER
+    # The leading directories of the path of the file containing the match (see the pathPrefixDepth argument).
+    # Only file matches are counted.
+    PATH_PREFIX
+    # The author of the commit. Only diff and commit search results are counted.
+    AUTHOR
+    # The text matched by a capture group of the regular expression search pattern (see the captureGroup
+    # argument). Only file content matches are counted.
+    CAPTURE_GROUP
+}
+
+# Counts of the matches of a search, grouped by a dimension.
+type SearchAggregations {
+    # The groups of matches, ordered by decreasing count.
+    buckets: [SearchAggregationBucket!]!
+    # The total number of matches that were counted.
+    matchCount: Int!
+    # Whether the counts are incomplete because the search hit a limit, timed out or could not search some
+    # repositories (e.g. because they are still cloning).
+    limitHit: Boolean!
+}
+
+# A group of matches of a search aggregation.
+type SearchAggregationBucket {
+    # The value of the dimension that the matches are grouped by, such as the repository name.
+    key: String!
+    # The number of matches in the group.
+    count: Int!
+}
+
 # A search filter.
 type SearchFilter {
     # The value.
@@ -1594,6 +1702,28 @@ type SavedSearch implements Node {
     namespace: Namespace!
     # The Slack webhook URL associated with this saved search, if any.
     slackWebhookURL: String
+    # The HTTP webhooks notified when this saved search has new results.
+    webhooks: [SavedSearchWebhook!]!
+}
+
+# An HTTP webhook that is notified when a saved search has new results.
+type SavedSearchWebhook {
+    # The scheme and host of the URL that notifications are posted to. The rest of the URL is
+    # redacted because webhook URLs usually embed a secret token; it can only be set, not read.
+    url: String!
+    # The payload format: "json", "teams" (Microsoft Teams) or "mattermost".
+    format: String!
+}
+
+# An HTTP webhook that is notified when a saved search has new results.
+input SavedSearchWebhookInput {
+    # The URL that notifications are posted to.
+    url: String!
+    # The payload format: "json" (default), "teams" (Microsoft Teams) or "mattermost".
+    format: String
+    # If set, each request carries an X-Sourcegraph-Signature header with the hex-encoded
+    # HMAC-SHA256 of the request body keyed by this secret.
+    secret: String
 }
 
 # A search query description.
@@ -1705,6 +1835,7 @@ enum ExternalServiceKind {
     AWSCODECOMMIT
     BITBUCKETCLOUD
     BITBUCKETSERVER
+    GERRIT
     GITHUB
     GITLAB
     GITOLITE
@@ -2641,6 +2772,27 @@ type GitCommit implements Node {
         # file paths returned in the list.
         includePatterns: [String!]
     ): SymbolConnection!
+    # Fuzzy-matches the paths of all files in the tree at this commit against a query, like the
+    # "Go to file" finder of editors. The matches are ordered by decreasing score.
+    fuzzyFiles(
+        # The query. A path matches if it contains all characters of the query in the same order,
+        # but not necessarily consecutively. Whitespace in the query is ignored. The match is
+        # case-sensitive only if the query contains an uppercase character.
+        query: String!
+        # Returns the first n matches (at most 1000).
+        first: Int = 50
+    ): [FuzzyFileMatch!]!
+}
+
+# A file whose path fuzzy-matches a query.
+type FuzzyFileMatch {
+    # The file.
+    file: GitBlob!
+    # The score of the match. Matches of consecutive characters and of characters at the start of
+    # file names, directory names and words score higher.
+    score: Int!
+    # The 0-based character offsets of the characters in the file path that matched the query.
+    positions: [Int!]!
 }
 
 # A set of Git behind/ahead counts for one commit relative to another.
@@ -2929,7 +3081,20 @@ type GitBlob implements TreeEntry & File2 {
     # The URLs to this blob on its repository's external services.
     externalURLs: [ExternalLink!]!
     # Blame the blob.
-    blame(startLine: Int!, endLine: Int!): [Hunk!]!
+    blame(
+        startLine: Int!
+        endLine: Int!
+        # Whether to ignore the changes of the commits listed in the .git-blame-ignore-revs file at
+        # the root of the repository at this commit, such as commits that only reformat code. Lines
+        # they changed are blamed on the commit that previously changed them.
+        ignoreRevs: Boolean = false
+        # Whether to blame lines that were moved or copied within the file on the commit that
+        # originally added them (git blame -M).
+        detectMoves: Boolean = false
+        # Whether to blame lines that were moved or copied from other files modified in the same
+        # commit on the commit that originally added them (git blame -C).
+        detectCopies: Boolean = false
+    ): [Hunk!]!
     # Highlight the blob contents.
     highlight(disableTimeout: Boolean!, isLightTheme: Boolean!, highlightLongLines: Boolean = false): HighlightedFile!
     # Submodule metadata if this tree points to a submodule
@@ -2994,6 +3159,30 @@ type GitBlobLSIFData implements TreeEntryLSIFData {
         character: Int!
     ): LocationConnection!
 
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # A list of type definitions of the symbol under the given document position.
+    typeDefinitions(
+        # The line on which the symbol occurs (zero-based, inclusive).
+        line: Int!
+
+        # The character (not byte) of the start line on which the symbol occurs (zero-based, inclusive).
+        character: Int!
+    ): LocationConnection!
+
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # A list of implementations of the symbol under the given document position.
+    implementations(
+        # The line on which the symbol occurs (zero-based, inclusive).
+        line: Int!
+
+        # The character (not byte) of the start line on which the symbol occurs (zero-based, inclusive).
+        character: Int!
+    ): LocationConnection!
+
     # (experimental) The LSIF API may change substantially in the near future as we
     # continue to adjust it for our use cases. Changes will not be documented in the
     # CHANGELOG during this time.
@@ -3099,6 +3288,15 @@ type Hunk {
     message: String!
     # The commit that contains the hunk.
     commit: GitCommit!
+    # The path of the file in the commit that contains the hunk. It differs from the path of the
+    # blamed file if the file was renamed, or if the hunk was moved or copied from another file.
+    filename: String!
+    # The parent of the commit that contains the hunk, in which previousFilename can be blamed to
+    # see the history before the hunk was changed. Null if the commit added the file.
+    previousCommit: GitCommit
+    # The path of the file in previousCommit. Null if the commit that contains the hunk added the
+    # file.
+    previousFilename: String
 }
 
 # A namespace is a container for certain types of data and settings, such as a user or organization.
@@ -252,6 +252,10 @@ type searchResolver struct {
 
 	zoekt        *searchbackend.Zoekt
 	searcherURLs *endpoint.Map
+
+	// stream, if set, receives results as soon as a search backend produced
+	// them. See StreamSearch.
+	stream chan<- SearchEvent
 }
 
 // rawQuery returns the original query string input.
@@ -475,6 +479,8 @@ func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoF
 	visibility := query.ParseVisibility(visibilityStr)
 
 	commitAfter, _ := r.query.StringValue(query.FieldRepoHasCommitAfter)
+	rev, _ := r.query.StringValue(query.FieldRev)
+	revAt, _ := r.query.StringValue(query.FieldRevAt)
 
 	var versionContextName string
 	if r.versionContext != nil {
@@ -494,6 +500,8 @@ func (r *searchResolver) resolveRepositories(ctx context.Context, effectiveRepoF
 		onlyPrivate:        visibility == query.Private,
 		onlyPublic:         visibility == query.Public,
 		commitAfter:        commitAfter,
+		rev:                rev,
+		revAt:              revAt,
 		query:              r.query,
 	}
 	repoRevs, missingRepoRevs, overLimit, excludedRepos, err = resolveRepositories(ctx, options)
@@ -517,8 +525,10 @@ type patternRevspec struct {
 }
 
 // given a repo name, determine whether it matched any patterns for which we have
-// revspecs (or ref globs), and if so, return the matching/allowed ones.
-func getRevsForMatchedRepo(repo api.RepoName, pats []patternRevspec) (matched []search.RevisionSpecifier, clashing []search.RevisionSpecifier) {
+// revspecs (or ref globs), and if so, return the matching/allowed ones. If it
+// matched none, return defaultRevs (the revisions of the rev: field), or the
+// default branch if there are none.
+func getRevsForMatchedRepo(repo api.RepoName, pats []patternRevspec, defaultRevs []search.RevisionSpecifier) (matched []search.RevisionSpecifier, clashing []search.RevisionSpecifier) {
 	revLists := make([][]search.RevisionSpecifier, 0, len(pats))
 	for _, rev := range pats {
 		if rev.includePattern.MatchString(string(repo)) {
@@ -530,8 +540,14 @@ func getRevsForMatchedRepo(repo api.RepoName, pats []patternRevspec) (matched []
 		matched = revLists[0]
 		return
 	}
-	// no matches: we generate a dummy list containing only master
+	// no matches: we use the default revs, or generate a dummy list containing
+	// only master
 	if len(revLists) == 0 {
+		if len(defaultRevs) > 0 {
+			// Copy because the caller filters the revs in place.
+			matched = append([]search.RevisionSpecifier(nil), defaultRevs...)
+			return
+		}
 		matched = []search.RevisionSpecifier{{RevSpec: ""}}
 		return
 	}
@@ -611,6 +627,8 @@ type resolveRepoOp struct {
 	noArchived         bool
 	onlyArchived       bool
 	commitAfter        string
+	rev                string
+	revAt              string
 	onlyPrivate        bool
 	onlyPublic         bool
 	query              query.QueryInfo
@@ -667,7 +685,7 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 	var versionContextRepositories []string
 	var versionContext *schema.VersionContext
 	// If a ref is specified we skip using version contexts.
-	if len(includePatternRevs) == 0 && op.versionContextName != "" {
+	if len(includePatternRevs) == 0 && op.rev == "" && op.versionContextName != "" {
 		versionContext, err = resolveVersionContext(op.versionContextName)
 		if err != nil {
 			return nil, nil, false, nil, err
@@ -722,6 +740,13 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 	}
 	overLimit = len(repos) >= maxRepoListSize
 
+	// The revisions of the rev: field are searched in repositories whose
+	// repo: filters don't specify revisions.
+	var defaultRevs []search.RevisionSpecifier
+	if op.rev != "" {
+		defaultRevs = search.ParseRevisionSpecifiers(op.rev)
+	}
+
 	repoRevisions = make([]*search.RepositoryRevisions, 0, len(repos))
 	tr.LazyPrintf("Associate/validate revs - start")
 
@@ -733,12 +758,12 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 			for _, vcRepoRev := range versionContext.Revisions {
 				if vcRepoRev.Repo == string(repo.Name) {
 					repoRev.Repo = repo
-					revs = append(revs, search.RevisionSpecifier{RevSpec: vcRepoRev.Rev})
+					revs = append(revs, search.RevisionSpecifier{RevSpec: vcRepoRev.Rev, RevAt: vcRepoRev.RevAt})
 				}
 			}
 		} else {
 			var clashingRevs []search.RevisionSpecifier
-			revs, clashingRevs = getRevsForMatchedRepo(repo.Name, includePatternRevs)
+			revs, clashingRevs = getRevsForMatchedRepo(repo.Name, includePatternRevs, defaultRevs)
 			repoRev.Repo = repo
 			// if multiple specified revisions clash, report this usefully:
 			if len(revs) == 0 && clashingRevs != nil {
@@ -749,6 +774,10 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 			}
 		}
 
+		if op.revAt != "" {
+			revs = withRevAt(revs, op.revAt)
+		}
+
 		// We do in place filtering to reduce allocations. Common path is no
 		// filtering of revs.
 		if len(revs) > 0 {
@@ -800,6 +829,10 @@ func resolveRepositories(ctx context.Context, op resolveRepoOp) (repoRevisions,
 		repoRevisions, err = filterRepoHasCommitAfter(ctx, repoRevisions, op.commitAfter)
 	}
 
+	if err == nil {
+		repoRevisions, err = resolveRevAt(ctx, repoRevisions)
+	}
+
 	return repoRevisions, missingRepoRevisions, overLimit, excludedRepos, err
 }
 
@@ -892,6 +925,95 @@ func filterRepoHasCommitAfter(ctx context.Context, revisions []*search.Repositor
 	return pass, err
 }
 
+// withRevAt returns revs with their RevAt set to date, which is the value of
+// the rev.at: field. Revisions that already specify a date (from a version
+// context) are left unchanged. If revs is empty, it returns the default
+// branch at date.
+func withRevAt(revs []search.RevisionSpecifier, date string) []search.RevisionSpecifier {
+	if len(revs) == 0 {
+		return []search.RevisionSpecifier{{RevAt: date}}
+	}
+	dated := make([]search.RevisionSpecifier, 0, len(revs))
+	for _, rev := range revs {
+		if rev.RevAt == "" {
+			rev.RevAt = date
+		}
+		dated = append(dated, rev)
+	}
+	return dated
+}
+
+// resolveRevAt replaces revisions with a RevAt date by the last commit before
+// that date, so that they are searched like an explicit commit. Revisions
+// that have no commit before the date are removed, as are repositories that
+// have no revisions left. The order of the repositories is preserved.
+func resolveRevAt(ctx context.Context, revisions []*search.RepositoryRevisions) ([]*search.RepositoryRevisions, error) {
+	hasRevAt := func(revs *search.RepositoryRevisions) bool {
+		for _, rev := range revs.Revs {
+			if rev.RevAt != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	var (
+		resolved = make([]*search.RepositoryRevisions, len(revisions))
+		run      = parallel.NewRun(128)
+		found    bool
+	)
+	for i, revs := range revisions {
+		if !hasRevAt(revs) {
+			resolved[i] = revs
+			continue
+		}
+		found = true
+
+		run.Acquire()
+		i, revs := i, revs
+		goroutine.Go(func() {
+			defer run.Release()
+
+			var specifiers []search.RevisionSpecifier
+			for _, rev := range revs.Revs {
+				if rev.RevAt == "" || rev.RefGlob != "" || rev.ExcludeRefGlob != "" {
+					// Ref globs can't be resolved to a single commit, so
+					// they are searched as usual.
+					rev.RevAt = ""
+					specifiers = append(specifiers, rev)
+					continue
+				}
+				commitID, err := git.LastCommitBefore(ctx, revs.GitserverRepo(), rev.RevAt, rev.RevSpec)
+				if err != nil {
+					if gitserver.IsRevisionNotFound(err) || vcs.IsRepoNotExist(err) {
+						continue
+					}
+
+					run.Error(err)
+					continue
+				}
+				specifiers = append(specifiers, search.RevisionSpecifier{RevSpec: string(commitID)})
+			}
+			if len(specifiers) > 0 {
+				resolved[i] = &search.RepositoryRevisions{Repo: revs.Repo, Revs: specifiers}
+			}
+		})
+	}
+	if !found {
+		return revisions, nil
+	}
+
+	err := run.Wait()
+
+	pass := resolved[:0]
+	for _, revs := range resolved {
+		if revs != nil {
+			pass = append(pass, revs)
+		}
+	}
+	return pass, err
+}
+
 func optimizeRepoPatternWithHeuristics(repoPattern string) string {
 	if envvar.SourcegraphDotComMode() && strings.HasPrefix(string(repoPattern), "github.com") {
 		repoPattern = "^" + repoPattern
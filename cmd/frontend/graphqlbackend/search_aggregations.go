@@ -0,0 +1,253 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// searchAggregationsMaxResults is the number of results that a search
+// aggregation fetches when the query does not specify count:. It approximates
+// an exhaustive search while bounding the memory used by a single request.
+const searchAggregationsMaxResults = 100000
+
+type searchAggregationsArgs struct {
+	Version         string
+	Query           string
+	PatternType     *string
+	VersionContext  *string
+	GroupBy         string
+	PathPrefixDepth int32
+	CaptureGroup    int32
+}
+
+func (r *schemaResolver) SearchAggregations(ctx context.Context, args *searchAggregationsArgs) (*searchAggregationsResolver, error) {
+	grouper, err := newSearchResultGrouper(args)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, err := newSearchAggregationsSearchResolver(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.GroupBy == "CAPTURE_GROUP" {
+		p, err := sr.getPatternInfo(nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := grouper.setPattern(p.Pattern, p.IsRegExp, p.IsCaseSensitive); err != nil {
+			return nil, err
+		}
+	}
+
+	srr, err := sr.doResults(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range srr.Results() {
+		grouper.add(result)
+	}
+	return &searchAggregationsResolver{
+		buckets:    grouper.buckets(),
+		matchCount: grouper.matchCount,
+		limitHit:   srr.LimitHit() || len(srr.Cloning()) > 0 || len(srr.Missing()) > 0 || len(srr.Timedout()) > 0,
+	}, nil
+}
+
+// newSearchAggregationsSearchResolver returns the resolver for the search
+// query to aggregate. Unless the query specifies count:, it fetches up to
+// searchAggregationsMaxResults results.
+func newSearchAggregationsSearchResolver(args *searchAggregationsArgs) (*searchResolver, error) {
+	searchArgs := &SearchArgs{
+		Version:        args.Version,
+		PatternType:    args.PatternType,
+		Query:          args.Query,
+		VersionContext: args.VersionContext,
+	}
+	for i := 0; i < 2; i++ {
+		impl, err := NewSearchImplementer(searchArgs)
+		if err != nil {
+			return nil, err
+		}
+		sr, ok := impl.(*searchResolver)
+		if !ok {
+			// The query is invalid, and impl is the alert describing why.
+			if alert, ok := impl.(*searchAlert); ok {
+				return nil, errors.New(alert.title)
+			}
+			return nil, errors.New("invalid search query")
+		}
+		if sr.countIsSet() {
+			return sr, nil
+		}
+		searchArgs.Query = fmt.Sprintf("%s count:%d", args.Query, searchAggregationsMaxResults)
+	}
+	return nil, errors.New("unable to set the result count of the search query")
+}
+
+type searchAggregationsResolver struct {
+	buckets    []*searchAggregationBucketResolver
+	matchCount int32
+	limitHit   bool
+}
+
+func (r *searchAggregationsResolver) Buckets() []*searchAggregationBucketResolver { return r.buckets }
+func (r *searchAggregationsResolver) MatchCount() int32                           { return r.matchCount }
+func (r *searchAggregationsResolver) LimitHit() bool                              { return r.limitHit }
+
+type searchAggregationBucketResolver struct {
+	key   string
+	count int32
+}
+
+func (r *searchAggregationBucketResolver) Key() string  { return r.key }
+func (r *searchAggregationBucketResolver) Count() int32 { return r.count }
+
+// searchResultGrouper counts the matches of search results by the dimension
+// they are grouped by.
+type searchResultGrouper struct {
+	groupBy         string
+	pathPrefixDepth int
+	captureGroup    int
+	pattern         *regexp.Regexp
+
+	counts     map[string]int32
+	matchCount int32
+}
+
+func newSearchResultGrouper(args *searchAggregationsArgs) (*searchResultGrouper, error) {
+	switch args.GroupBy {
+	case "REPOSITORY", "OWNER", "AUTHOR", "CAPTURE_GROUP":
+	case "PATH_PREFIX":
+		if args.PathPrefixDepth < 1 {
+			return nil, errors.New("pathPrefixDepth must be at least 1")
+		}
+	default:
+		return nil, fmt.Errorf("unknown groupBy value %q", args.GroupBy)
+	}
+	return &searchResultGrouper{
+		groupBy:         args.GroupBy,
+		pathPrefixDepth: int(args.PathPrefixDepth),
+		captureGroup:    int(args.CaptureGroup),
+		counts:          map[string]int32{},
+	}, nil
+}
+
+// setPattern sets the pattern whose capture group the matches are grouped by.
+func (g *searchResultGrouper) setPattern(pattern string, isRegExp, isCaseSensitive bool) error {
+	if !isRegExp {
+		return errors.New("grouping by capture group requires a regular expression search pattern")
+	}
+	if !isCaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	if g.captureGroup < 1 || g.captureGroup > re.NumSubexp() {
+		return fmt.Errorf("the search pattern has no capture group %d", g.captureGroup)
+	}
+	g.pattern = re
+	return nil
+}
+
+// add counts the matches of the given search result. Results that do not
+// have a value for the dimension (such as file matches when grouping by
+// commit author) are not counted.
+func (g *searchResultGrouper) add(result SearchResultResolver) {
+	if fm, ok := result.ToFileMatch(); ok {
+		switch g.groupBy {
+		case "REPOSITORY":
+			g.count(fm.Repo.Name(), fm.resultCount())
+		case "OWNER":
+			g.count(repoOwner(fm.Repo.Name()), fm.resultCount())
+		case "PATH_PREFIX":
+			g.count(pathPrefix(fm.JPath, g.pathPrefixDepth), fm.resultCount())
+		case "CAPTURE_GROUP":
+			for _, lm := range fm.JLineMatches {
+				preview := []rune(lm.JPreview)
+				for _, ol := range lm.JOffsetAndLengths {
+					start, end := int(ol[0]), int(ol[0]+ol[1])
+					if start < 0 || end > len(preview) || start > end {
+						continue
+					}
+					if m := g.pattern.FindStringSubmatch(string(preview[start:end])); m != nil {
+						g.count(m[g.captureGroup], 1)
+					}
+				}
+			}
+		}
+		return
+	}
+
+	if commit, ok := result.ToCommitSearchResult(); ok {
+		switch g.groupBy {
+		case "REPOSITORY":
+			g.count(commit.commit.repoResolver.Name(), 1)
+		case "OWNER":
+			g.count(repoOwner(commit.commit.repoResolver.Name()), 1)
+		case "AUTHOR":
+			if author := commit.commit.author.person; author != nil {
+				g.count(fmt.Sprintf("%s <%s>", author.name, author.email), 1)
+			}
+		}
+		return
+	}
+
+	if repo, ok := result.ToRepository(); ok {
+		switch g.groupBy {
+		case "REPOSITORY":
+			g.count(repo.Name(), 1)
+		case "OWNER":
+			g.count(repoOwner(repo.Name()), 1)
+		}
+	}
+}
+
+func (g *searchResultGrouper) count(key string, n int32) {
+	g.counts[key] += n
+	g.matchCount += n
+}
+
+// buckets returns the counts by key, ordered by decreasing count.
+func (g *searchResultGrouper) buckets() []*searchAggregationBucketResolver {
+	buckets := make([]*searchAggregationBucketResolver, 0, len(g.counts))
+	for key, count := range g.counts {
+		buckets = append(buckets, &searchAggregationBucketResolver{key: key, count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].count != buckets[j].count {
+			return buckets[i].count > buckets[j].count
+		}
+		return buckets[i].key < buckets[j].key
+	})
+	return buckets
+}
+
+// repoOwner returns the owner of a repository, which is its name without the
+// last path component (e.g. "github.com/sourcegraph" for
+// "github.com/sourcegraph/sourcegraph").
+func repoOwner(repoName string) string {
+	return path.Dir(repoName)
+}
+
+// pathPrefix returns the first depth directories of the path of a file,
+// followed by a slash. Files in the root directory have the prefix "/".
+func pathPrefix(filePath string, depth int) string {
+	dirs := strings.Split(path.Dir(filePath), "/")
+	if dirs[0] == "." {
+		return "/"
+	}
+	if len(dirs) > depth {
+		dirs = dirs[:depth]
+	}
+	return strings.Join(dirs, "/") + "/"
+}
@@ -0,0 +1,132 @@
+package graphqlbackend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestSearchResultGrouper(t *testing.T) {
+	repo := func(name string) *RepositoryResolver {
+		return &RepositoryResolver{repo: &types.Repo{Name: api.RepoName(name)}}
+	}
+	commit := func(repoName, authorName, authorEmail string) *commitSearchResultResolver {
+		return &commitSearchResultResolver{commit: &GitCommitResolver{
+			repoResolver: repo(repoName),
+			author:       signatureResolver{person: &personResolver{name: authorName, email: authorEmail}},
+		}}
+	}
+	results := []SearchResultResolver{
+		&FileMatchResolver{Repo: repo("github.com/a/x"), JPath: "cmd/foo/main.go", MatchCount: 2, JLineMatches: []*lineMatch{
+			{JPreview: "log.Printf(\"a\") // log.Fatalf", JOffsetAndLengths: [][2]int32{{0, 10}, {19, 10}}},
+		}},
+		&FileMatchResolver{Repo: repo("github.com/a/y"), JPath: "README.md", MatchCount: 1, JLineMatches: []*lineMatch{
+			{JPreview: "ünï log.Printf", JOffsetAndLengths: [][2]int32{{4, 10}}},
+		}},
+		&FileMatchResolver{Repo: repo("github.com/b/z"), JPath: "cmd/bar/main.go", MatchCount: 1},
+		commit("github.com/a/x", "Alice", "alice@example.com"),
+		commit("github.com/b/z", "Alice", "alice@example.com"),
+		repo("github.com/b/w"),
+	}
+
+	tests := []struct {
+		args    searchAggregationsArgs
+		pattern string
+		want    map[string]int32
+	}{
+		{
+			args: searchAggregationsArgs{GroupBy: "REPOSITORY"},
+			want: map[string]int32{"github.com/a/x": 3, "github.com/a/y": 1, "github.com/b/z": 2, "github.com/b/w": 1},
+		},
+		{
+			args: searchAggregationsArgs{GroupBy: "OWNER"},
+			want: map[string]int32{"github.com/a": 4, "github.com/b": 3},
+		},
+		{
+			args: searchAggregationsArgs{GroupBy: "PATH_PREFIX", PathPrefixDepth: 1},
+			want: map[string]int32{"cmd/": 3, "/": 1},
+		},
+		{
+			args: searchAggregationsArgs{GroupBy: "PATH_PREFIX", PathPrefixDepth: 2},
+			want: map[string]int32{"cmd/foo/": 2, "cmd/bar/": 1, "/": 1},
+		},
+		{
+			args: searchAggregationsArgs{GroupBy: "AUTHOR"},
+			want: map[string]int32{"Alice <alice@example.com>": 2},
+		},
+		{
+			args:    searchAggregationsArgs{GroupBy: "CAPTURE_GROUP", CaptureGroup: 1},
+			pattern: `log\.(\w+)`,
+			want:    map[string]int32{"Printf": 2, "Fatalf": 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.args.GroupBy, func(t *testing.T) {
+			g, err := newSearchResultGrouper(&test.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.pattern != "" {
+				if err := g.setPattern(test.pattern, true, true); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, result := range results {
+				g.add(result)
+			}
+
+			got := map[string]int32{}
+			var total int32
+			for _, b := range g.buckets() {
+				got[b.Key()] = b.Count()
+				total += b.Count()
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			if g.matchCount != total {
+				t.Errorf("got match count %d, want %d", g.matchCount, total)
+			}
+		})
+	}
+}
+
+func TestSearchResultGrouper_Errors(t *testing.T) {
+	if _, err := newSearchResultGrouper(&searchAggregationsArgs{GroupBy: "LANGUAGE"}); err == nil {
+		t.Error("expected error for unknown groupBy value")
+	}
+	if _, err := newSearchResultGrouper(&searchAggregationsArgs{GroupBy: "PATH_PREFIX"}); err == nil {
+		t.Error("expected error for missing path prefix depth")
+	}
+
+	g, err := newSearchResultGrouper(&searchAggregationsArgs{GroupBy: "CAPTURE_GROUP", CaptureGroup: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.setPattern(`log\.(\w+)`, true, true); err == nil {
+		t.Error("expected error for missing capture group")
+	}
+	if err := g.setPattern(`log.Printf`, false, true); err == nil {
+		t.Error("expected error for literal pattern")
+	}
+}
+
+func TestSearchAggregationsBucketsOrder(t *testing.T) {
+	g, err := newSearchResultGrouper(&searchAggregationsArgs{GroupBy: "REPOSITORY"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.count("b", 1)
+	g.count("a", 1)
+	g.count("c", 2)
+
+	var keys []string
+	for _, b := range g.buckets() {
+		keys = append(keys, b.Key())
+	}
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("got %v, want %v", keys, want)
+	}
+}
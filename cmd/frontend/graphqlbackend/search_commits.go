@@ -17,6 +17,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
@@ -124,6 +125,7 @@ func searchCommitsInRepo(ctx context.Context, op search.CommitParameters) (resul
 		args = append(args, "--regexp-ignore-case")
 	}
 
+	var refGlobArgs []string
 	for _, rev := range op.RepoRevs.Revs {
 		switch {
 		case rev.RevSpec != "":
@@ -138,12 +140,13 @@ func searchCommitsInRepo(ctx context.Context, op search.CommitParameters) (resul
 			args = append(args, rev.RevSpec)
 
 		case rev.RefGlob != "":
-			args = append(args, "--glob="+rev.RefGlob)
+			refGlobArgs = append(refGlobArgs, "--glob="+rev.RefGlob)
 
 		case rev.ExcludeRefGlob != "":
-			args = append(args, "--exclude="+rev.ExcludeRefGlob)
+			refGlobArgs = append(refGlobArgs, "--exclude="+rev.ExcludeRefGlob)
 		}
 	}
+	args = append(args, refGlobArgs...)
 
 	beforeValues, _ := op.Query.StringValues(query.FieldBefore)
 	for _, s := range beforeValues {
@@ -242,6 +245,16 @@ func searchCommitsInRepo(ctx context.Context, op search.CommitParameters) (resul
 		rawResults = rawResults[:maxResults]
 	}
 
+	if !op.RepoRevs.OnlyExplicit() {
+		if err := addRefGlobSourceRefs(ctx, op.RepoRevs, refGlobArgs, rawResults); err != nil {
+			if ctx.Err() == nil {
+				return nil, false, false, err
+			}
+			// Return the results with only the source refs reported by `git log --source`.
+			timedOut = true
+		}
+	}
+
 	repoResolver := &RepositoryResolver{repo: repo}
 	results = make([]*commitSearchResultResolver, len(rawResults))
 	for i, rawResult := range rawResults {
@@ -315,6 +328,52 @@ func searchCommitsInRepo(ctx context.Context, op search.CommitParameters) (resul
 	return results, limitHit, timedOut, nil
 }
 
+// addRefGlobSourceRefs adds the refs that match the ref globs of repoRevs and
+// contain the commit to the source refs of each result. `git log --source`
+// only reports the first of them by which it reached the commit. The refs of
+// all results are looked up at once by walking the history of refGlobArgs.
+func addRefGlobSourceRefs(ctx context.Context, repoRevs *search.RepositoryRevisions, refGlobArgs []string, results []*git.LogCommitSearchResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	globs, err := repoRevs.RefGlobs()
+	if err != nil {
+		return err
+	}
+
+	commits := make([]api.CommitID, len(results))
+	for i, result := range results {
+		commits[i] = result.Commit.ID
+	}
+	refs, err := git.RefsContaining(ctx, repoRevs.GitserverRepo(), refGlobArgs, commits)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		sourceRefs := result.SourceRefs
+		for _, ref := range refs[result.Commit.ID] {
+			if globs.Match(ref) {
+				sourceRefs = append(sourceRefs, ref)
+			}
+		}
+		result.SourceRefs = uniqueSortedStrings(sourceRefs)
+	}
+	return nil
+}
+
+// uniqueSortedStrings returns the sorted distinct strings of s, reusing its
+// storage.
+func uniqueSortedStrings(s []string) []string {
+	sort.Strings(s)
+	unique := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
 func cleanDiffPreview(highlights []*highlightedRange, rawDiffResult string) (string, []*highlightedRange) {
 	// A map of line number to number of lines that have been ignored before the particular line number.
 	lineByCountIgnored := make(map[int]int32)
@@ -14,6 +14,7 @@ import (
 	//"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
@@ -101,6 +102,63 @@ func (r *commitSearchResultResolver) String() string {
 	return fmt.Sprintf("{commit: %+v diffPreview: %+v messagePreview: %+v}", r.commit, r.diffPreview, r.messagePreview)
 }
 
+func TestSearchCommitsInRepo_refGlobSourceRefs(t *testing.T) {
+	ctx := context.Background()
+
+	git.Mocks.RawLogDiffSearch = func(opt git.RawLogDiffSearchOptions) ([]*git.LogCommitSearchResult, bool, error) {
+		if want := []string{"--glob=refs/heads/release-*", "--exclude=refs/heads/release-old"}; !reflect.DeepEqual(opt.Args[len(opt.Args)-2:], want) {
+			t.Errorf("got args %v, want them to end with %v", opt.Args, want)
+		}
+		return []*git.LogCommitSearchResult{
+			{Commit: git.Commit{ID: "c1"}, SourceRefs: []string{"refs/heads/release-2"}},
+			{Commit: git.Commit{ID: "c2"}, SourceRefs: []string{"refs/heads/release-2"}},
+		}, true, nil
+	}
+	git.Mocks.RefsContaining = func(revArgs []string, commits []api.CommitID) (map[api.CommitID][]string, error) {
+		if want := []string{"--glob=refs/heads/release-*", "--exclude=refs/heads/release-old"}; !reflect.DeepEqual(revArgs, want) {
+			t.Errorf("got rev args %v, want %v", revArgs, want)
+		}
+		if want := []api.CommitID{"c1", "c2"}; !reflect.DeepEqual(commits, want) {
+			t.Errorf("got commits %v, want %v", commits, want)
+		}
+		return map[api.CommitID][]string{
+			"c1": {"refs/heads/master", "refs/heads/release-1", "refs/heads/release-2", "refs/heads/release-old"},
+			"c2": {"refs/heads/release-2"},
+		}, nil
+	}
+	defer git.ResetMocks()
+
+	q, err := query.ParseAndCheck("type:commit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, _, _, err := searchCommitsInRepo(ctx, search.CommitParameters{
+		RepoRevs: &search.RepositoryRevisions{
+			Repo: &types.Repo{ID: 1, Name: "repo"},
+			Revs: []search.RevisionSpecifier{{RefGlob: "refs/heads/release-*"}, {ExcludeRefGlob: "refs/heads/release-old"}},
+		},
+		PatternInfo: &search.CommitPatternInfo{FileMatchLimit: int32(defaultMaxSearchResults)},
+		Query:       q,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"c1": {"refs/heads/release-1", "refs/heads/release-2"},
+		"c2": {"refs/heads/release-2"},
+	}
+	got := map[string][]string{}
+	for _, result := range results {
+		for _, ref := range result.SourceRefs() {
+			got[string(result.Commit().OID())] = append(got[string(result.Commit().OID())], ref.Name())
+		}
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got source refs %v, want %v", got, want)
+	}
+}
+
 func TestExpandUsernamesToEmails(t *testing.T) {
 	resetMocks()
 	db.Mocks.Users.GetByUsername = func(ctx context.Context, username string) (*types.User, error) {
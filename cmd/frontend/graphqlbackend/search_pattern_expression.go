@@ -0,0 +1,121 @@
+package graphqlbackend
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// isTextSearchPatternExpression returns true if the search pattern of an
+// and/or query is a boolean expression that should be evaluated by the text
+// search backends (searcher and zoekt) in a single search, rather than by
+// intersecting and merging the results of its operands. This is the case if
+// the query only asks for file results, or if the expression negates a
+// pattern, which only the text search backends support.
+func (r *searchResolver) isTextSearchPatternExpression(scopeParameters []query.Node, pattern query.Node) bool {
+	if r.patternType == query.SearchTypeStructural || !isBooleanPatternExpression(pattern) {
+		return false
+	}
+
+	var resultTypes []string
+	query.VisitField(scopeParameters, query.FieldType, func(value string, negated bool) {
+		if !negated {
+			resultTypes = append(resultTypes, value)
+		}
+	})
+	if len(resultTypes) == 1 && resultTypes[0] == "file" {
+		return true
+	}
+	return len(resultTypes) == 0 && containsNegatedPattern(pattern)
+}
+
+// isBooleanPatternExpression returns true if the search pattern node is an
+// and/or expression or contains a negated pattern.
+func isBooleanPatternExpression(node query.Node) bool {
+	if operator, ok := node.(query.Operator); ok && (operator.Kind == query.And || operator.Kind == query.Or) {
+		return true
+	}
+	return containsNegatedPattern(node)
+}
+
+func containsNegatedPattern(node query.Node) bool {
+	var negated bool
+	query.VisitPattern([]query.Node{node}, func(_ string, patternNegated bool, _ query.Annotation) {
+		negated = negated || patternNegated
+	})
+	return negated
+}
+
+// patternExpression returns the pattern expression for the search pattern of
+// q, or nil if q is not an and/or query whose pattern is a boolean
+// expression.
+func patternExpression(q query.QueryInfo, opts *getPatternInfoOptions) *search.PatternExpression {
+	andOrQuery, ok := q.(*query.AndOrQuery)
+	if !ok || opts.forceFileSearch || opts.performStructuralSearch {
+		return nil
+	}
+	_, pattern, err := query.PartitionSearchPattern(andOrQuery.Query)
+	if err != nil || pattern == nil || !isBooleanPatternExpression(pattern) {
+		return nil
+	}
+	return toPatternExpression(pattern, opts)
+}
+
+// toPatternExpression converts a search pattern node to a pattern expression.
+// Its patterns are interpreted like the pattern of a query with only that
+// pattern (see processSearchPattern).
+func toPatternExpression(node query.Node, opts *getPatternInfoOptions) *search.PatternExpression {
+	switch n := node.(type) {
+	case query.Pattern:
+		if n.Negated {
+			n.Negated = false
+			return &search.PatternExpression{
+				Kind:     search.PatternExpressionNot,
+				Operands: []*search.PatternExpression{toPatternExpression(n, opts)},
+			}
+		}
+
+	case query.Operator:
+		switch n.Kind {
+		case query.And, query.Or:
+			kind := search.PatternExpressionAnd
+			if n.Kind == query.Or {
+				kind = search.PatternExpressionOr
+			}
+			expr := &search.PatternExpression{Kind: kind}
+			for _, operand := range n.Operands {
+				expr.Operands = append(expr.Operands, toPatternExpression(operand, opts))
+			}
+			return expr
+
+		case query.Concat:
+			// A concatenation with negated patterns, like "foo not bar",
+			// matches the concatenation of the other patterns and none of
+			// the negated patterns.
+			var positive, negated []query.Node
+			for _, operand := range n.Operands {
+				if p, ok := operand.(query.Pattern); ok && p.Negated {
+					negated = append(negated, p)
+				} else {
+					positive = append(positive, operand)
+				}
+			}
+			if len(negated) == 0 {
+				break
+			}
+			expr := &search.PatternExpression{Kind: search.PatternExpressionAnd}
+			if len(positive) > 0 {
+				expr.Operands = append(expr.Operands, toPatternExpression(query.Operator{Kind: query.Concat, Operands: positive}, opts))
+			}
+			for _, p := range negated {
+				expr.Operands = append(expr.Operands, toPatternExpression(p, opts))
+			}
+			if len(expr.Operands) == 1 {
+				return expr.Operands[0]
+			}
+			return expr
+		}
+	}
+
+	pattern, _, _ := processSearchPattern(&query.AndOrQuery{Query: []query.Node{node}}, opts)
+	return &search.PatternExpression{Kind: search.PatternExpressionPattern, Pattern: pattern}
+}
@@ -0,0 +1,69 @@
+package graphqlbackend
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+func TestPatternExpression(t *testing.T) {
+	cases := []struct {
+		query      string
+		searchType query.SearchType
+		want       string
+	}{
+		{query: "foo", searchType: query.SearchTypeRegex, want: "<nil>"},
+		{query: "foo bar", searchType: query.SearchTypeRegex, want: "<nil>"},
+		{query: "not foo", searchType: query.SearchTypeRegex, want: "<nil>"},
+		{query: "foo and not bar", searchType: query.SearchTypeRegex, want: `(and "foo" (not "bar"))`},
+		{query: "repo:r a.* or b and not c", searchType: query.SearchTypeRegex, want: `(or "a.*" (and "b" (not "c")))`},
+		{query: "foo bar and not baz", searchType: query.SearchTypeRegex, want: `(and "(foo).*?(bar)" (not "baz"))`},
+		{query: "error not found", searchType: query.SearchTypeLiteral, want: "<nil>"},
+		{query: "f(x) and not a.b", searchType: query.SearchTypeLiteral, want: `(and "f\\(x\\)" (not "a\\.b"))`},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			q, err := query.ProcessAndOr(c.query, c.searchType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := "<nil>"
+			if expr := patternExpression(q, &getPatternInfoOptions{}); expr != nil {
+				got = expr.String()
+			}
+			if got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsTextSearchPatternExpression(t *testing.T) {
+	cases := []struct {
+		query string
+		want  bool
+	}{
+		{query: "a and b", want: false},
+		{query: "type:file a and b", want: true},
+		{query: "type:commit a and b", want: false},
+		{query: "a and not b", want: true},
+		{query: "type:diff a and not b", want: false},
+		{query: "type:file a", want: false},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			q, err := query.ProcessAndOr(c.query, query.SearchTypeRegex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			scopeParameters, pattern, err := query.PartitionSearchPattern(q.(*query.AndOrQuery).Query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := &searchResolver{patternType: query.SearchTypeRegex}
+			if got := r.isTextSearchPatternExpression(scopeParameters, pattern); got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/src-d/enry/v2/data"
+)
+
+// maxSearchQueryCompletions is the maximum number of completions returned by
+// searchQueryCompletions.
+const maxSearchQueryCompletions = 1000
+
+// searchQueryFieldValues are the values completed for fields that accept a
+// fixed set of values.
+var searchQueryFieldValues = map[string][]string{
+	query.FieldType:        {"commit", "diff", "file", "path", "repo", "symbol"},
+	query.FieldPatternType: {"literal", "regexp", "structural"},
+	query.FieldFork:        {string(Yes), string(No), string(Only)},
+	query.FieldArchived:    {string(Yes), string(No), string(Only)},
+	query.FieldVisibility:  {"any", "private", "public"},
+}
+
+type searchQueryCompletionsArgs struct {
+	Query  string
+	Cursor *int32
+	First  int32
+}
+
+func (r *schemaResolver) SearchQueryCompletions(ctx context.Context, args *searchQueryCompletionsArgs) ([]*searchQueryCompletionResolver, error) {
+	// The cursor and ranges are character offsets, and the parser uses byte
+	// offsets.
+	cursor := len(args.Query)
+	if args.Cursor != nil {
+		if *args.Cursor < 0 || int(*args.Cursor) > utf8.RuneCountInString(args.Query) {
+			return nil, errors.New("cursor must be between 0 and the length of the query")
+		}
+		cursor = len(string([]rune(args.Query)[:*args.Cursor]))
+	}
+	first := int(args.First)
+	if first < 0 || first > maxSearchQueryCompletions {
+		first = maxSearchQueryCompletions
+	}
+
+	target, err := query.CompletionTargetAt(args.Query, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := "VALUE"
+	var texts []string
+	if target.Field == "" {
+		kind = "FIELD"
+		prefix := strings.ToLower(target.Prefix)
+		for _, field := range query.FieldNames() {
+			if strings.HasPrefix(field, prefix) {
+				texts = append(texts, field+":")
+			}
+		}
+	} else {
+		texts, err = searchQueryFieldValueCompletions(ctx, target.Field, target.Prefix, first)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(texts) > first {
+		texts = texts[:first]
+	}
+
+	rng := lsp.Range{
+		Start: lsp.Position{Character: utf8.RuneCountInString(args.Query[:target.Range.Start.Column])},
+		End:   lsp.Position{Character: utf8.RuneCountInString(args.Query[:target.Range.End.Column])},
+	}
+	completions := make([]*searchQueryCompletionResolver, len(texts))
+	for i, text := range texts {
+		completions[i] = &searchQueryCompletionResolver{kind: kind, text: text, rng: rng}
+	}
+	return completions, nil
+}
+
+// searchQueryFieldValueCompletions returns the completions of the value of
+// field that start with prefix (case-insensitively), or match it for repo:.
+func searchQueryFieldValueCompletions(ctx context.Context, field, prefix string, first int) ([]string, error) {
+	var values []string
+	switch field {
+	case query.FieldRepoGroup:
+		groups, err := resolveRepoGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for name := range groups {
+			values = append(values, name)
+		}
+		sort.Strings(values)
+
+	case query.FieldLang:
+		for lang := range data.LanguagesType {
+			// Language names are matched like this by lang:, and it avoids
+			// quoting names containing spaces.
+			values = append(values, strings.ToLower(strings.Replace(lang, " ", "_", -1)))
+		}
+		sort.Strings(values)
+
+	case query.FieldRepo:
+		pattern := prefix
+		if _, err := regexp.Compile(pattern); err != nil {
+			pattern = regexp.QuoteMeta(pattern)
+		}
+		repos, err := backend.Repos.List(ctx, db.ReposListOptions{
+			IncludePatterns: []string{pattern},
+			OrderBy:         db.RepoListOrderBy{{Field: db.RepoListName}},
+			LimitOffset:     &db.LimitOffset{Limit: first},
+		})
+		if err != nil {
+			return nil, err
+		}
+		values = make([]string, len(repos))
+		for i, repo := range repos {
+			values[i] = "^" + regexp.QuoteMeta(string(repo.Name)) + "$"
+		}
+		return values, nil
+
+	default:
+		values = searchQueryFieldValues[field]
+	}
+
+	prefix = strings.ToLower(prefix)
+	var completions []string
+	for _, value := range values {
+		if strings.HasPrefix(strings.ToLower(value), prefix) {
+			completions = append(completions, value)
+		}
+	}
+	return completions, nil
+}
+
+type searchQueryCompletionResolver struct {
+	kind string
+	text string
+	rng  lsp.Range
+}
+
+func (r *searchQueryCompletionResolver) Kind() string { return r.kind }
+func (r *searchQueryCompletionResolver) Text() string { return r.text }
+func (r *searchQueryCompletionResolver) Range() RangeResolver {
+	return NewRangeResolver(r.rng)
+}
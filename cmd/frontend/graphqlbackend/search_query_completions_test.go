@@ -0,0 +1,104 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestSearchQueryCompletions(t *testing.T) {
+	mockResolveRepoGroups = func() (map[string][]*types.Repo, error) {
+		return map[string][]*types.Repo{"go": {}, "javascript": {}, "golang-tools": {}}, nil
+	}
+	defer func() { mockResolveRepoGroups = nil }()
+
+	db.Mocks.Repos.List = func(_ context.Context, opt db.ReposListOptions) ([]*types.Repo, error) {
+		if want := []string{`github\.com/foo\(`}; !reflect.DeepEqual(opt.IncludePatterns, want) {
+			t.Errorf("got include patterns %q, want %q", opt.IncludePatterns, want)
+		}
+		return []*types.Repo{{Name: "github.com/foo/bar"}}, nil
+	}
+	defer func() { db.Mocks.Repos.List = nil }()
+
+	type completion struct {
+		Kind       string
+		Text       string
+		Start, End int32
+	}
+	tests := []struct {
+		// The cursor is at the position of "|" in the query, or at the end
+		// if there is none.
+		query string
+		first int32
+		want  []completion
+	}{
+		{query: "foo rep", want: []completion{
+			{Kind: "FIELD", Text: "replace:", Start: 4, End: 7},
+			{Kind: "FIELD", Text: "repo:", Start: 4, End: 7},
+			{Kind: "FIELD", Text: "repogroup:", Start: 4, End: 7},
+			{Kind: "FIELD", Text: "repohascommitafter:", Start: 4, End: 7},
+			{Kind: "FIELD", Text: "repohasfile:", Start: 4, End: 7},
+		}},
+		{query: "-fi|le:x", want: []completion{
+			{Kind: "FIELD", Text: "file:", Start: 1, End: 6},
+		}},
+		{query: "foo ", first: 2, want: []completion{
+			{Kind: "FIELD", Text: "after:", Start: 4, End: 4},
+			{Kind: "FIELD", Text: "archived:", Start: 4, End: 4},
+		}},
+		{query: "type:c| foo", want: []completion{
+			{Kind: "VALUE", Text: "commit", Start: 5, End: 6},
+		}},
+		{query: "fork:", want: []completion{
+			{Kind: "VALUE", Text: "yes", Start: 5, End: 5},
+			{Kind: "VALUE", Text: "no", Start: 5, End: 5},
+			{Kind: "VALUE", Text: "only", Start: 5, End: 5},
+		}},
+		{query: "visibility:PU", want: []completion{
+			{Kind: "VALUE", Text: "public", Start: 11, End: 13},
+		}},
+		{query: "patterntype:re", want: []completion{
+			{Kind: "VALUE", Text: "regexp", Start: 12, End: 14},
+		}},
+		{query: "g:go", want: []completion{
+			{Kind: "VALUE", Text: "go", Start: 2, End: 4},
+			{Kind: "VALUE", Text: "golang-tools", Start: 2, End: 4},
+		}},
+		{query: "lang:visual_b", want: []completion{
+			{Kind: "VALUE", Text: "visual_basic", Start: 5, End: 13},
+		}},
+		// Ranges are character offsets.
+		{query: "ü r:github.com/foo(", want: []completion{
+			{Kind: "VALUE", Text: `^github\.com/foo/bar$`, Start: 4, End: 19},
+		}},
+		{query: "archived:x", want: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			args := &searchQueryCompletionsArgs{Query: test.query, First: 50}
+			if test.first != 0 {
+				args.First = test.first
+			}
+			if i := strings.Index(test.query, "|"); i != -1 {
+				args.Query = strings.Replace(test.query, "|", "", 1)
+				cursor := int32(i)
+				args.Cursor = &cursor
+			}
+			completions, err := (&schemaResolver{}).SearchQueryCompletions(context.Background(), args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []completion
+			for _, c := range completions {
+				got = append(got, completion{Kind: c.Kind(), Text: c.Text(), Start: c.Range().Start().Character(), End: c.Range().End().Character()})
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
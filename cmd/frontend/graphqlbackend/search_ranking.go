@@ -0,0 +1,232 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// defaultLowValuePathPatterns match the paths of tests, vendored dependencies
+// and generated code. They are used unless the site configuration specifies
+// search.ranking.lowValuePathPatterns.
+var defaultLowValuePathPatterns = []string{
+	`(^|/)(tests?|__tests__|testdata|spec|fixtures?|mocks?)/`,
+	`(^|/)(vendor|third_party|node_modules)/`,
+	`_test\.go$`,
+	`\.(test|spec)\.[jt]sx?$`,
+	`(^|/)test_[^/]*\.py$`,
+	`(_generated\.go|\.pb\.go|_pb2\.py|\.min\.js)$`,
+	`(^|/)(package-lock\.json|yarn\.lock|go\.sum)$`,
+}
+
+func init() {
+	conf.ContributeValidator(func(c conf.Unified) (problems conf.Problems) {
+		if c.SearchRanking == nil {
+			return nil
+		}
+		for _, pattern := range c.SearchRanking.LowValuePathPatterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				problems = append(problems, conf.NewSiteProblem(fmt.Sprintf("search.ranking.lowValuePathPatterns: not a valid regexp: %s. See the valid syntax: https://golang.org/pkg/regexp/", pattern)))
+			}
+		}
+		return problems
+	})
+}
+
+// resultRankerFromConfig returns the result ranker for the current site
+// configuration, or nil if ranking is disabled.
+var resultRankerFromConfig = conf.Cached(func() interface{} {
+	return newResultRanker(conf.Get().SearchRanking)
+})
+
+// resultRanker orders search results by a score computed from signals of
+// their repository and file. See the search.ranking site configuration.
+type resultRanker struct {
+	repositoryStarsWeight    float64
+	repositoryActivityWeight float64
+	matchCountWeight         float64
+	lowValuePathWeight       float64
+	lowValuePaths            []*regexp.Regexp
+}
+
+// repositoryActivityHalfLife is the time after which a repository that hasn't
+// changed gets half of the score for activity of one that changed just now.
+const repositoryActivityHalfLife = 90 * 24 * time.Hour
+
+func newResultRanker(c *schema.SearchRanking) *resultRanker {
+	if c == nil || !c.Enabled {
+		return nil
+	}
+	weight := func(w *float64, defaultWeight float64) float64 {
+		if w == nil {
+			return defaultWeight
+		}
+		return *w
+	}
+	r := &resultRanker{
+		repositoryStarsWeight:    weight(c.RepositoryStarsWeight, 1),
+		repositoryActivityWeight: weight(c.RepositoryActivityWeight, 1),
+		matchCountWeight:         weight(c.MatchCountWeight, 1),
+		lowValuePathWeight:       weight(c.LowValuePathWeight, 2),
+	}
+
+	patterns := c.LowValuePathPatterns
+	if patterns == nil {
+		patterns = defaultLowValuePathPatterns
+	}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			// Skip if there's an error. A user-visible validation error will appear due to the ContributeValidator call above.
+			log15.Error("Site config: unable to compile search.ranking.lowValuePathPatterns regexp", "regexp", pattern)
+			continue
+		}
+		r.lowValuePaths = append(r.lowValuePaths, re)
+	}
+	return r
+}
+
+// rankResults orders results by decreasing score if ranking is enabled in the
+// site configuration. Results with the same score keep their order.
+func (r *searchResolver) rankResults(ctx context.Context, results []SearchResultResolver) {
+	if ranker, _ := resultRankerFromConfig().(*resultRanker); ranker != nil {
+		ranker.rank(ctx, results)
+	}
+}
+
+// rank orders results by decreasing score. Results with the same score keep
+// their order.
+func (r *resultRanker) rank(ctx context.Context, results []SearchResultResolver) {
+	if len(results) < 2 {
+		return
+	}
+
+	seen := map[api.RepoID]struct{}{}
+	var repos []*types.Repo
+	for _, result := range results {
+		if repo := searchResultRepo(result); repo != nil {
+			if _, ok := seen[repo.repo.ID]; !ok {
+				seen[repo.repo.ID] = struct{}{}
+				repos = append(repos, repo.repo)
+			}
+		}
+	}
+
+	var stars map[api.RepoID]int
+	if r.repositoryStarsWeight != 0 && len(repos) > 0 {
+		ids := make([]api.RepoID, 0, len(repos))
+		for _, repo := range repos {
+			ids = append(ids, repo.ID)
+		}
+		// The results only contain repositories that the user can access.
+		var err error
+		stars, err = db.Repos.GetStargazerCounts(ctx, ids...)
+		if err != nil {
+			// Rank without the number of stars rather than failing the search.
+			log15.Warn("search ranking: unable to get the number of stars of repositories", "error", err)
+		}
+	}
+
+	var lastChanged map[api.RepoName]time.Time
+	if r.repositoryActivityWeight != 0 && len(repos) > 0 {
+		names := make([]api.RepoName, 0, len(repos))
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		var err error
+		lastChanged, err = repoLastChanged(ctx, names)
+		if err != nil {
+			// Rank without the activity of repositories rather than failing the search.
+			log15.Warn("search ranking: unable to get when repositories last changed", "error", err)
+		}
+	}
+
+	now := time.Now()
+	scores := make(map[SearchResultResolver]float64, len(results))
+	for _, result := range results {
+		scores[result] = r.score(result, stars, lastChanged, now)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return scores[results[i]] > scores[results[j]] })
+}
+
+// score returns the score of a search result. stars is the number of stars by
+// repository, and lastChanged is when each repository last changed.
+func (r *resultRanker) score(result SearchResultResolver, stars map[api.RepoID]int, lastChanged map[api.RepoName]time.Time, now time.Time) float64 {
+	var score float64
+	if repo := searchResultRepo(result); repo != nil {
+		score += r.repositoryStarsWeight * math.Log10(1+float64(stars[repo.repo.ID]))
+		if t, ok := lastChanged[repo.repo.Name]; ok {
+			age := now.Sub(t)
+			if age < 0 {
+				age = 0
+			}
+			score += r.repositoryActivityWeight * math.Exp2(-float64(age)/float64(repositoryActivityHalfLife))
+		}
+	}
+	if fm, ok := result.ToFileMatch(); ok {
+		score += r.matchCountWeight * math.Log2(1+float64(fm.resultCount()))
+		if r.isLowValuePath(fm.JPath) {
+			score -= r.lowValuePathWeight
+		}
+	}
+	return score
+}
+
+func (r *resultRanker) isLowValuePath(path string) bool {
+	for _, re := range r.lowValuePaths {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+var mockRepoLastChanged func(names []api.RepoName) (map[api.RepoName]time.Time, error)
+
+// repoLastChanged returns when the given repositories last changed according to
+// gitserver. Repositories that aren't cloned are omitted.
+func repoLastChanged(ctx context.Context, names []api.RepoName) (map[api.RepoName]time.Time, error) {
+	if mockRepoLastChanged != nil {
+		return mockRepoLastChanged(names)
+	}
+
+	resp, err := gitserver.DefaultClient.RepoInfo(ctx, names...)
+	if err != nil {
+		return nil, err
+	}
+	lastChanged := make(map[api.RepoName]time.Time, len(resp.Results))
+	for name, info := range resp.Results {
+		if info != nil && info.LastChanged != nil {
+			lastChanged[name] = *info.LastChanged
+		}
+	}
+	return lastChanged, nil
+}
+
+// searchResultRepo returns the repository of a search result.
+func searchResultRepo(result SearchResultResolver) *RepositoryResolver {
+	if repo, ok := result.ToRepository(); ok {
+		return repo
+	}
+	if fm, ok := result.ToFileMatch(); ok {
+		return fm.Repo
+	}
+	if commit, ok := result.ToCommitSearchResult(); ok {
+		return commit.commit.repoResolver
+	}
+	if codemod, ok := result.ToCodemodResult(); ok {
+		return codemod.commit.repoResolver
+	}
+	return nil
+}
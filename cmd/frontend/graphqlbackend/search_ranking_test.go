@@ -0,0 +1,123 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestResultRanker(t *testing.T) {
+	defer func() { db.Mocks.Repos.GetStargazerCounts = nil }()
+	db.Mocks.Repos.GetStargazerCounts = func(ctx context.Context, ids ...api.RepoID) (map[api.RepoID]int, error) {
+		return map[api.RepoID]int{2: 999}, nil
+	}
+	defer func() { mockRepoLastChanged = nil }()
+
+	repo := func(id api.RepoID, name string) *RepositoryResolver {
+		return &RepositoryResolver{repo: &types.Repo{ID: id, Name: api.RepoName(name)}}
+	}
+	small, popular := repo(1, "github.com/a/small"), repo(2, "github.com/b/popular")
+	fileMatch := func(repo *RepositoryResolver, path string, matches int) *FileMatchResolver {
+		return &FileMatchResolver{Repo: repo, JPath: path, MatchCount: matches}
+	}
+	results := []SearchResultResolver{
+		fileMatch(small, "main.go", 1),
+		fileMatch(small, "main_test.go", 1),
+		fileMatch(small, "util.go", 7),
+		fileMatch(popular, "vendor/lib/lib.go", 1),
+		small,
+		popular,
+	}
+
+	key := func(result SearchResultResolver) string {
+		repoName, path := result.searchResultURIs()
+		return repoName + " " + path
+	}
+	tests := []struct {
+		name        string
+		config      *schema.SearchRanking
+		lastChanged map[api.RepoName]time.Time
+		want        []string
+	}{
+		{
+			name:   "defaults",
+			config: &schema.SearchRanking{Enabled: true},
+			want: []string{
+				// log2(1 + 7) = 3, ranked first among equal scores
+				// because it was first before ranking.
+				"github.com/a/small util.go",
+				// log10(1 + 999) = 3
+				"github.com/b/popular ",
+				// log10(1 + 999) + log2(1 + 1) - 2 = 2
+				"github.com/b/popular vendor/lib/lib.go",
+				// log2(1 + 1) = 1
+				"github.com/a/small main.go",
+				"github.com/a/small ",
+				// log2(1 + 1) - 2 = -1
+				"github.com/a/small main_test.go",
+			},
+		},
+		{
+			name: "only activity",
+			config: &schema.SearchRanking{
+				Enabled:               true,
+				RepositoryStarsWeight: new(float64),
+				MatchCountWeight:      new(float64),
+				LowValuePathWeight:    new(float64),
+			},
+			lastChanged: map[api.RepoName]time.Time{
+				"github.com/a/small":   time.Now().Add(-time.Hour),
+				"github.com/b/popular": time.Now().Add(-365 * 24 * time.Hour),
+			},
+			want: []string{
+				// 2^(-1h/90d) ≈ 1
+				"github.com/a/small main.go",
+				"github.com/a/small main_test.go",
+				"github.com/a/small util.go",
+				"github.com/a/small ",
+				// 2^(-365d/90d) ≈ 0.06
+				"github.com/b/popular vendor/lib/lib.go",
+				"github.com/b/popular ",
+			},
+		},
+		{
+			name:   "only match count",
+			config: &schema.SearchRanking{Enabled: true, RepositoryStarsWeight: new(float64), LowValuePathWeight: new(float64)},
+			want: []string{
+				"github.com/a/small util.go",
+				"github.com/a/small main.go",
+				"github.com/a/small main_test.go",
+				"github.com/b/popular vendor/lib/lib.go",
+				"github.com/a/small ",
+				"github.com/b/popular ",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepoLastChanged = func(names []api.RepoName) (map[api.RepoName]time.Time, error) {
+				return test.lastChanged, nil
+			}
+			ranked := append([]SearchResultResolver(nil), results...)
+			newResultRanker(test.config).rank(context.Background(), ranked)
+
+			var got []string
+			for _, result := range ranked {
+				got = append(got, key(result))
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	if newResultRanker(&schema.SearchRanking{}) != nil {
+		t.Error("expected no ranker when ranking is disabled")
+	}
+}
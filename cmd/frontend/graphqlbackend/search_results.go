@@ -945,6 +945,11 @@ func (r *searchResolver) evaluate(ctx context.Context, q []query.Node) (*SearchR
 		r.query.(*query.AndOrQuery).Query = scopeParameters
 		return r.evaluateLeaf(ctx)
 	}
+	if r.isTextSearchPatternExpression(scopeParameters, pattern) {
+		// Searcher and zoekt evaluate the expression for each file.
+		r.query.(*query.AndOrQuery).Query = append(scopeParameters, pattern)
+		return r.evaluateLeaf(ctx)
+	}
 	result, err := r.evaluatePatternExpression(ctx, scopeParameters, pattern)
 	if err != nil {
 		return nil, err
@@ -954,6 +959,15 @@ func (r *searchResolver) evaluate(ctx context.Context, q []query.Node) (*SearchR
 }
 
 func (r *searchResolver) Results(ctx context.Context) (*SearchResultsResolver, error) {
+	rr, err := r.results(ctx)
+	// Stable and paginated searches return results in a deterministic order.
+	if rr != nil && !r.query.BoolValue("stable") && r.pagination == nil {
+		r.rankResults(ctx, rr.SearchResults)
+	}
+	return rr, err
+}
+
+func (r *searchResolver) results(ctx context.Context) (*SearchResultsResolver, error) {
 	switch q := r.query.(type) {
 	case *query.OrdinaryQuery:
 		return r.evaluateLeaf(ctx)
@@ -992,7 +1006,15 @@ func (r *searchResolver) resultsWithTimeoutSuggestion(ctx context.Context) (*Sea
 	if shouldShowAlert {
 		usedTime := time.Since(start)
 		suggestTime := longer(2, usedTime)
-		return &SearchResultsResolver{alert: alertForTimeout(usedTime, suggestTime, r)}, nil
+		alert := alertForTimeout(usedTime, suggestTime, r)
+		if r.stream != nil && rr != nil {
+			// The partial results were already streamed, so we keep them
+			// instead of replacing them with the alert. Otherwise the
+			// returned results would not match the streamed ones.
+			rr.alert = alert
+			return rr, nil
+		}
+		return &SearchResultsResolver{alert: alert}, nil
 	}
 	return rr, err
 }
@@ -1253,6 +1275,19 @@ func getPatternInfo(q query.QueryInfo, opts *getPatternInfoOptions) (*search.Tex
 
 	languages, _ := q.StringValues(query.FieldLang)
 
+	// Handle symbolkind:, -symbolkind: and select:symbol.<kind> filters.
+	symbolKinds, excludeSymbolKinds := q.StringValues(query.FieldSymbolKind)
+	if selected, _ := q.StringValue(query.FieldSelect); selected != "" {
+		kind, err := query.SelectedSymbolKind(selected)
+		if err != nil {
+			return nil, err
+		}
+		if kind != "" {
+			symbolKinds = append(symbolKinds, kind)
+		}
+	}
+	symbolParentPatterns, excludeSymbolParentPatterns := q.RegexpPatterns(query.FieldSymbolParent)
+
 	patternInfo := &search.TextPatternInfo{
 		IsRegExp:                     isRegExp,
 		IsStructuralPat:              isStructuralPat,
@@ -1265,10 +1300,17 @@ func getPatternInfo(q query.QueryInfo, opts *getPatternInfoOptions) (*search.Tex
 		Languages:                    languages,
 		PathPatternsAreCaseSensitive: q.IsCaseSensitive(),
 		CombyRule:                    strings.Join(combyRule, ""),
+		SymbolKinds:                  ctagsKindsForSymbolKinds(symbolKinds),
+		ExcludeSymbolKinds:           ctagsKindsForSymbolKinds(excludeSymbolKinds),
+		SymbolParentPatterns:         symbolParentPatterns,
+		PatternExpression:            patternExpression(q, opts),
 	}
 	if len(excludePatterns) > 0 {
 		patternInfo.ExcludePattern = unionRegExps(excludePatterns)
 	}
+	if len(excludeSymbolParentPatterns) > 0 {
+		patternInfo.ExcludeSymbolParentPattern = unionRegExps(excludeSymbolParentPatterns)
+	}
 	return patternInfo, nil
 }
 
@@ -1341,8 +1383,19 @@ func (r *searchResolver) determineResultTypes(args search.TextParameters, forceO
 		resultTypes = []string{forceOnlyResultType}
 	} else if len(r.query.Values(query.FieldReplace)) > 0 {
 		resultTypes = []string{"codemod"}
+	} else if len(r.query.Values(query.FieldSelect)) > 0 {
+		// Only selecting symbols is supported, which getPatternInfo
+		// validates.
+		resultTypes = []string{"symbol"}
 	} else {
 		resultTypes, _ = r.query.StringValues(query.FieldType)
+		if args.PatternInfo.PatternExpression != nil {
+			// Only file content search supports pattern expressions.
+			resultTypes = []string{"file"}
+		}
+		if len(resultTypes) == 0 && hasSymbolFilters(r.query) {
+			resultTypes = []string{"symbol"}
+		}
 		if len(resultTypes) == 0 {
 			resultTypes = []string{"file", "path", "repo"}
 		}
@@ -1423,6 +1476,11 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 
 	start := time.Now()
 
+	// Optional searches are cancelled once the required ones are done, but
+	// the results they produced until then are still returned. Hence we
+	// stream with the parent context.
+	streamCtx := ctx
+
 	ctx, cancel, err := r.withTimeout(ctx)
 	if err != nil {
 		return nil, err
@@ -1496,6 +1554,39 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 		seenResultTypes = make(map[string]struct{})
 	)
 
+	// stream sends the results of a search backend to r.stream, if set,
+	// together with the progress of the search so far. It must be called
+	// after common was updated with the backend's searchResultsCommon.
+	var (
+		streamedMatchCount int32
+		// streamedFileMatchCounts are the match counts of the file matches
+		// streamed so far, by URI. A file match is streamed again when the
+		// results of another backend are merged into it.
+		streamedFileMatchCounts = make(map[string]int32)
+	)
+	stream := func(results []SearchResultResolver) {
+		if r.stream == nil {
+			return
+		}
+
+		commonMu.Lock()
+		for _, res := range results {
+			if fm, ok := res.ToFileMatch(); ok {
+				streamedMatchCount -= streamedFileMatchCounts[fm.uri]
+				streamedFileMatchCounts[fm.uri] = fm.resultCount()
+			}
+			streamedMatchCount += res.resultCount()
+		}
+		progress := common.progress()
+		progress.MatchCount = streamedMatchCount
+		commonMu.Unlock()
+
+		select {
+		case r.stream <- SearchEvent{Results: results, Progress: progress}:
+		case <-streamCtx.Done():
+		}
+	}
+
 	waitGroup := func(required bool) *sync.WaitGroup {
 		if args.UseFullDeadline {
 			// When a custom timeout is specified, all searches are required and get the full timeout.
@@ -1548,6 +1639,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					common.update(*repoCommon)
 					commonMu.Unlock()
 				}
+				stream(repoResults)
 			})
 		case "symbol":
 			wg := waitGroup(len(resultTypes) == 1)
@@ -1562,12 +1654,15 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					multiErr = multierror.Append(multiErr, errors.Wrap(err, "symbol search failed"))
 					multiErrMu.Unlock()
 				}
+				var streamed []SearchResultResolver
 				for _, symbolFileMatch := range symbolFileMatches {
 					key := symbolFileMatch.uri
 					fileMatchesMu.Lock()
 					if m, ok := fileMatches[key]; ok {
 						m.symbols = symbolFileMatch.symbols
+						streamed = append(streamed, copyFileMatch(m))
 					} else {
+						streamed = append(streamed, copyFileMatch(symbolFileMatch))
 						fileMatches[key] = symbolFileMatch
 						resultsMu.Lock()
 						results = append(results, symbolFileMatch)
@@ -1580,6 +1675,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					common.update(*symbolsCommon)
 					commonMu.Unlock()
 				}
+				stream(streamed)
 			})
 		case "file", "path":
 			if searchedFileContentsOrPaths {
@@ -1615,6 +1711,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 						fileCommon.limitHit = false // Ensure we don't display "Show more".
 					}
 				}
+				var streamed []SearchResultResolver
 				for _, r := range fileResults {
 					key := r.uri
 					fileMatchesMu.Lock()
@@ -1623,7 +1720,9 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 						// merge line match results with an existing symbol result
 						m.JLimitHit = m.JLimitHit || r.JLimitHit
 						m.JLineMatches = r.JLineMatches
+						streamed = append(streamed, copyFileMatch(m))
 					} else {
+						streamed = append(streamed, copyFileMatch(r))
 						fileMatches[key] = r
 						resultsMu.Lock()
 						results = append(results, r)
@@ -1636,6 +1735,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					common.update(*fileCommon)
 					commonMu.Unlock()
 				}
+				stream(streamed)
 			})
 		case "diff":
 			wg := waitGroup(len(resultTypes) == 1)
@@ -1675,6 +1775,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					common.update(*diffCommon)
 					commonMu.Unlock()
 				}
+				stream(diffResults)
 			})
 		case "commit":
 			wg := waitGroup(len(resultTypes) == 1)
@@ -1715,6 +1816,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					common.update(*commitCommon)
 					commonMu.Unlock()
 				}
+				stream(commitResults)
 			})
 		case "codemod":
 			wg := waitGroup(true)
@@ -1739,6 +1841,7 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 					common.update(*codemodCommon)
 					commonMu.Unlock()
 				}
+				stream(codemodResults)
 			})
 		}
 	}
@@ -1802,6 +1905,14 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 	return &resultsResolver, multiErr.ErrorOrNil()
 }
 
+// copyFileMatch returns a shallow copy of fm. File matches are merged with
+// the results of other backends while they are streamed, so we only stream
+// copies. The caller must hold the lock protecting fm.
+func copyFileMatch(fm *FileMatchResolver) *FileMatchResolver {
+	c := *fm
+	return &c
+}
+
 // isContextError returns true if ctx.Err() is not nil or if err
 // is an error caused by context cancelation or timeout.
 func isContextError(ctx context.Context, err error) bool {
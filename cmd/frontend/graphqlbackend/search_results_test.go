@@ -403,6 +403,23 @@ func TestSearchResolver_getPatternInfo(t *testing.T) {
 			IsRegExp:       true,
 			ExcludePattern: `f|(\.graphql$|\.gql$|\.graphqls$)`,
 		},
+		"p symbolkind:method -symbolkind:func": {
+			Pattern:            "p",
+			IsRegExp:           true,
+			SymbolKinds:        []string{"method", "methodspec"},
+			ExcludeSymbolKinds: []string{"func"},
+		},
+		"p select:symbol.method": {
+			Pattern:     "p",
+			IsRegExp:    true,
+			SymbolKinds: []string{"method", "methodspec"},
+		},
+		"p symbolparent:a -symbolparent:b -symbolparent:c": {
+			Pattern:                    "p",
+			IsRegExp:                   true,
+			SymbolParentPatterns:       []string{"a"},
+			ExcludeSymbolParentPattern: "b|c",
+		},
 	}
 	for queryStr, want := range tests {
 		t.Run(queryStr, func(t *testing.T) {
@@ -546,12 +563,13 @@ func TestSearchResolver_DynamicFilters(t *testing.T) {
 // of clashing revspecs (if no matching rev specs were found)
 func TestSearchRevspecs(t *testing.T) {
 	type testCase struct {
-		descr    string
-		specs    []string
-		repo     string
-		err      error
-		matched  []search.RevisionSpecifier
-		clashing []search.RevisionSpecifier
+		descr       string
+		specs       []string
+		defaultRevs []search.RevisionSpecifier
+		repo        string
+		err         error
+		matched     []search.RevisionSpecifier
+		clashing    []search.RevisionSpecifier
 	}
 
 	tests := []testCase{
@@ -611,6 +629,20 @@ func TestSearchRevspecs(t *testing.T) {
 			matched:  []search.RevisionSpecifier{{RevSpec: "b"}, {RevSpec: "c"}},
 			clashing: nil,
 		},
+		{
+			descr:       "default revs",
+			specs:       []string{"foo"},
+			defaultRevs: []search.RevisionSpecifier{{RefGlob: "refs/heads/release-*"}},
+			repo:        "foo",
+			matched:     []search.RevisionSpecifier{{RefGlob: "refs/heads/release-*"}},
+		},
+		{
+			descr:       "revspec overrides default revs",
+			specs:       []string{"foo@b"},
+			defaultRevs: []search.RevisionSpecifier{{RefGlob: "refs/heads/release-*"}},
+			repo:        "foo",
+			matched:     []search.RevisionSpecifier{{RevSpec: "b"}},
+		},
 		{
 			descr:    "invalid regexp",
 			specs:    []string{"*o@a:b"},
@@ -636,7 +668,7 @@ func TestSearchRevspecs(t *testing.T) {
 			if test.err != nil {
 				t.Errorf("missing expected error: wanted '%s'", test.err.Error())
 			}
-			matched, clashing := getRevsForMatchedRepo(api.RepoName(test.repo), pats)
+			matched, clashing := getRevsForMatchedRepo(api.RepoName(test.repo), pats, test.defaultRevs)
 			if !reflect.DeepEqual(matched, test.matched) {
 				t.Errorf("matched repo mismatch: actual: %#v, expected: %#v", matched, test.matched)
 			}
@@ -0,0 +1,255 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+)
+
+// SearchEvent is sent to the stream of a streaming search whenever one of the
+// search backends (Zoekt, searcher, symbols, commit search, ...) completes.
+type SearchEvent struct {
+	// Results are the results produced by the backend. A file match may be
+	// sent more than once if several backends matched the same file, in
+	// which case the later one supersedes the earlier one.
+	Results []SearchResultResolver
+
+	// Progress is the progress of the overall search so far.
+	Progress SearchProgress
+}
+
+// SearchProgress describes which repositories a search covered.
+type SearchProgress struct {
+	MatchCount int32 `json:"matchCount"`
+	LimitHit   bool  `json:"limitHit"`
+
+	RepositoriesCount    int `json:"repositoriesCount"`
+	RepositoriesSearched int `json:"repositoriesSearched"`
+	RepositoriesIndexed  int `json:"repositoriesIndexed"`
+
+	// The repositories below could not be searched (skipped) or only
+	// partially (timed out).
+	Cloning  []api.RepoName `json:"cloning,omitempty"`
+	Missing  []api.RepoName `json:"missing,omitempty"`
+	Timedout []api.RepoName `json:"timedout,omitempty"`
+
+	ExcludedForks    int `json:"excludedForks"`
+	ExcludedArchived int `json:"excludedArchived"`
+
+	IndexUnavailable bool `json:"indexUnavailable"`
+}
+
+// progress returns the progress described by c. It does not modify c.
+func (c *searchResultsCommon) progress() SearchProgress {
+	return SearchProgress{
+		MatchCount:           c.resultCount,
+		LimitHit:             c.LimitHit(),
+		RepositoriesCount:    len(repoNames(c.repos)),
+		RepositoriesSearched: len(repoNames(c.searched)),
+		RepositoriesIndexed:  len(repoNames(c.indexed)),
+		Cloning:              repoNames(c.cloning),
+		Missing:              repoNames(c.missing),
+		Timedout:             repoNames(c.timedout),
+		ExcludedForks:        c.excluded.forks,
+		ExcludedArchived:     c.excluded.archived,
+		IndexUnavailable:     c.indexUnavailable,
+	}
+}
+
+// repoNames returns the sorted, deduplicated names of repos.
+func repoNames(repos []*types.Repo) []api.RepoName {
+	if len(repos) == 0 {
+		return nil
+	}
+
+	seen := make(map[api.RepoName]struct{}, len(repos))
+	names := make([]api.RepoName, 0, len(repos))
+	for _, r := range repos {
+		if _, ok := seen[r.Name]; ok {
+			continue
+		}
+		seen[r.Name] = struct{}{}
+		names = append(names, r.Name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// Progress returns the progress of the completed search.
+func (sr *SearchResultsResolver) Progress() SearchProgress {
+	p := sr.searchResultsCommon.progress()
+	p.MatchCount = sr.MatchCount()
+	return p
+}
+
+// StreamSearch runs the search described by args exactly like the GraphQL
+// search field does, but additionally sends results to stream as soon as the
+// search backends produce them. The complete (sorted) results are returned
+// once the search finished.
+//
+// The streamed results differ from the GraphQL search field in two ways:
+//
+//   - They are sent in the order the search backends produce them. Ranking
+//     only applies to the returned results, which are the same results in
+//     ranked order.
+//   - If the search timed out in all repositories, the GraphQL search field
+//     drops the partial results in favor of the timeout alert. The partial
+//     results were already streamed, so they are returned together with the
+//     alert instead.
+//
+// Searches that need all results before returning any of them (and/or
+// queries, stable and paginated searches) are not streamed incrementally:
+// their results are sent to stream in a single event once they are
+// complete.
+//
+// StreamSearch does not close stream.
+func StreamSearch(ctx context.Context, args *SearchArgs, stream chan<- SearchEvent) (*SearchResultsResolver, error) {
+	impl, err := NewSearchImplementer(args)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := impl.(*searchResolver)
+	if ok && r.canStream() {
+		r.stream = stream
+	}
+
+	results, err := impl.Results(ctx)
+	if err != nil || results == nil {
+		return results, err
+	}
+
+	if !ok || r.stream == nil {
+		select {
+		case stream <- SearchEvent{Results: results.SearchResults, Progress: results.Progress()}:
+		case <-ctx.Done():
+		}
+	}
+
+	return results, nil
+}
+
+// canStream reports whether the results of r can be sent to a stream while
+// the search backends produce them.
+func (r *searchResolver) canStream() bool {
+	if _, ok := r.query.(*query.OrdinaryQuery); !ok {
+		return false
+	}
+	return r.pagination == nil && !r.query.BoolValue(query.FieldStable)
+}
+
+// StreamMatch is the JSON representation of a search result sent by the
+// streaming search API. Only the fields relevant to Type are set.
+type StreamMatch struct {
+	Type       string `json:"type"` // "repo", "file", "commit" or "codemod"
+	Repository string `json:"repository"`
+	URL        string `json:"url,omitempty"`
+
+	// File and codemod matches.
+	Path string `json:"path,omitempty"`
+
+	// File matches.
+	Revision    string               `json:"revision,omitempty"`
+	CommitID    api.CommitID         `json:"commit,omitempty"`
+	LineMatches []*StreamLineMatch   `json:"lineMatches,omitempty"`
+	Symbols     []*StreamSymbolMatch `json:"symbols,omitempty"`
+	LimitHit    bool                 `json:"limitHit,omitempty"`
+
+	// Commit and diff matches.
+	OID     GitObjectID `json:"oid,omitempty"`
+	Label   string      `json:"label,omitempty"`
+	Detail  string      `json:"detail,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Diff    string      `json:"diff,omitempty"`
+}
+
+// StreamLineMatch is a line of a file matched by a search.
+type StreamLineMatch struct {
+	Line             string     `json:"line"`
+	LineNumber       int32      `json:"lineNumber"`
+	OffsetAndLengths [][2]int32 `json:"offsetAndLengths"`
+}
+
+// StreamSymbolMatch is a symbol matched by a search.
+type StreamSymbolMatch struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Line       int    `json:"line"`
+	Parent     string `json:"parent,omitempty"`
+	ParentKind string `json:"parentKind,omitempty"`
+}
+
+// NewStreamMatch returns the JSON representation of res.
+func NewStreamMatch(res SearchResultResolver) *StreamMatch {
+	if repo, ok := res.ToRepository(); ok {
+		return &StreamMatch{
+			Type:       "repo",
+			Repository: repo.Name(),
+			URL:        repo.URL(),
+		}
+	}
+
+	if fm, ok := res.ToFileMatch(); ok {
+		m := &StreamMatch{
+			Type:       "file",
+			Repository: fm.Repo.Name(),
+			Path:       fm.JPath,
+			CommitID:   fm.CommitID,
+			LimitHit:   fm.JLimitHit,
+		}
+		if fm.InputRev != nil {
+			m.Revision = *fm.InputRev
+		}
+		for _, lm := range fm.JLineMatches {
+			m.LineMatches = append(m.LineMatches, &StreamLineMatch{
+				Line:             lm.JPreview,
+				LineNumber:       lm.JLineNumber,
+				OffsetAndLengths: lm.JOffsetAndLengths,
+			})
+		}
+		for _, s := range fm.symbols {
+			m.Symbols = append(m.Symbols, &StreamSymbolMatch{
+				Name:       s.symbol.Name,
+				Kind:       s.symbol.Kind,
+				Line:       s.symbol.Line,
+				Parent:     s.symbol.Parent,
+				ParentKind: s.symbol.ParentKind,
+			})
+		}
+		return m
+	}
+
+	if cr, ok := res.ToCommitSearchResult(); ok {
+		m := &StreamMatch{
+			Type:       "commit",
+			Repository: cr.commit.repoResolver.Name(),
+			URL:        cr.url,
+			OID:        cr.commit.oid,
+			Label:      cr.label,
+			Detail:     cr.detail,
+		}
+		if cr.messagePreview != nil {
+			m.Message = cr.messagePreview.value
+		}
+		if cr.diffPreview != nil {
+			m.Diff = cr.diffPreview.value
+		}
+		return m
+	}
+
+	if cm, ok := res.ToCodemodResult(); ok {
+		return &StreamMatch{
+			Type:       "codemod",
+			Repository: cm.commit.repoResolver.Name(),
+			URL:        cm.fileURL,
+			Path:       cm.path,
+			OID:        cm.commit.oid,
+			Diff:       cm.diff,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSearchResultsCommon_progress(t *testing.T) {
+	foo := &types.Repo{ID: 1, Name: "foo"}
+	bar := &types.Repo{ID: 2, Name: "bar"}
+	baz := &types.Repo{ID: 3, Name: "baz"}
+
+	c := searchResultsCommon{
+		repos:    []*types.Repo{foo, bar, baz},
+		searched: []*types.Repo{foo, foo, bar},
+		indexed:  []*types.Repo{foo},
+		cloning:  []*types.Repo{baz},
+		timedout: []*types.Repo{bar, foo},
+		excluded: excludedRepos{forks: 2, archived: 1},
+	}
+
+	want := SearchProgress{
+		RepositoriesCount:    3,
+		RepositoriesSearched: 2,
+		RepositoriesIndexed:  1,
+		Cloning:              []api.RepoName{"baz"},
+		Timedout:             []api.RepoName{"bar", "foo"},
+		ExcludedForks:        2,
+		ExcludedArchived:     1,
+	}
+	if diff := cmp.Diff(want, c.progress()); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestNewStreamMatch(t *testing.T) {
+	rev := "develop"
+	fm := &FileMatchResolver{
+		JPath: "main.go",
+		JLineMatches: []*lineMatch{
+			{JPreview: "func main() {", JLineNumber: 3, JOffsetAndLengths: [][2]int32{{5, 4}}},
+		},
+		Repo:     NewRepositoryResolver(&types.Repo{Name: "foo"}),
+		CommitID: "deadbeef",
+		InputRev: &rev,
+	}
+
+	want := &StreamMatch{
+		Type:       "file",
+		Repository: "foo",
+		Path:       "main.go",
+		Revision:   "develop",
+		CommitID:   "deadbeef",
+		LineMatches: []*StreamLineMatch{
+			{Line: "func main() {", LineNumber: 3, OffsetAndLengths: [][2]int32{{5, 4}}},
+		},
+	}
+	if diff := cmp.Diff(want, NewStreamMatch(fm)); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestStreamSearch_timeout(t *testing.T) {
+	mockDecodedViewerFinalSettings = &schema.Settings{}
+	defer func() { mockDecodedViewerFinalSettings = nil }()
+
+	repo := &types.Repo{ID: 1, Name: "repo"}
+	db.Mocks.Repos.List = func(context.Context, db.ReposListOptions) ([]*types.Repo, error) {
+		return []*types.Repo{repo}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+	db.Mocks.Repos.Count = mockCount
+
+	mockSearchRepositories = func(args *search.TextParameters) ([]SearchResultResolver, *searchResultsCommon, error) {
+		return nil, &searchResultsCommon{}, nil
+	}
+	defer func() { mockSearchRepositories = nil }()
+
+	// Every repo timed out, but not before a file match was found.
+	mockSearchFilesInRepos = func(args *search.TextParameters) ([]*FileMatchResolver, *searchResultsCommon, error) {
+		return []*FileMatchResolver{{
+			uri:          "git://repo?rev#dir/file",
+			JPath:        "dir/file",
+			JLineMatches: []*lineMatch{{JLineNumber: 123}},
+			Repo:         &RepositoryResolver{repo: repo},
+		}}, &searchResultsCommon{repos: []*types.Repo{repo}, timedout: []*types.Repo{repo}}, nil
+	}
+	defer func() { mockSearchFilesInRepos = nil }()
+
+	args := &SearchArgs{Query: "foo", Version: "V2"}
+
+	// The GraphQL search field only returns the timeout alert.
+	r, err := (&schemaResolver{}).Search(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := r.Results(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results.SearchResults) != 0 || results.alert == nil {
+		t.Fatalf("got %d results and alert %v, want only the timeout alert", len(results.SearchResults), results.alert)
+	}
+
+	// A streaming search already sent the partial results, so it returns
+	// them together with the alert.
+	stream := make(chan SearchEvent)
+	streamed := make(chan int)
+	go func() {
+		n := 0
+		for e := range stream {
+			n += len(e.Results)
+		}
+		streamed <- n
+	}()
+	results, err = StreamSearch(context.Background(), args, stream)
+	close(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := <-streamed; n != 1 {
+		t.Fatalf("got %d streamed results, want 1", n)
+	}
+	if len(results.SearchResults) != 1 || results.alert == nil {
+		t.Fatalf("got %d results and alert %v, want the streamed result and the timeout alert", len(results.SearchResults), results.alert)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -22,6 +23,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gituri"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
@@ -61,6 +63,11 @@ func searchSymbols(ctx context.Context, args *search.TextParameters, limit int)
 		return nil, nil, nil
 	}
 
+	filter, err := newSymbolFilter(args.PatternInfo)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	ctx, cancelAll := context.WithCancel(ctx)
 	defer cancelAll()
 
@@ -124,7 +131,7 @@ func searchSymbols(ctx context.Context, args *search.TextParameters, limit int)
 	run.Acquire()
 	goroutine.Go(func() {
 		defer run.Release()
-		matches, limitHit, reposLimitHit, searchErr := indexed.Search(ctx)
+		matches, limitHit, reposLimitHit, searchErr := searchIndexedSymbols(ctx, indexed, filter, limit)
 		mu.Lock()
 		defer mu.Unlock()
 		if ctx.Err() == nil {
@@ -186,6 +193,40 @@ func searchSymbols(ctx context.Context, args *search.TextParameters, limit int)
 	return res2, common, err
 }
 
+// maxSymbolFilterOverFetch is the largest factor by which indexed symbol
+// search raises the file match limit when symbols are filtered by kind or
+// parent.
+const maxSymbolFilterOverFetch = 64
+
+// searchIndexedSymbols runs the indexed symbol search s and applies filter to
+// its results. Zoekt doesn't support the symbol filters, so they can only be
+// applied after Zoekt applied the file match limit, which could leave too few
+// or no results. Hence, while the filtered results don't fill the limits and
+// Zoekt skipped matches, the search is repeated with a larger file match
+// limit.
+func searchIndexedSymbols(ctx context.Context, s *indexedSearchRequest, filter *symbolFilter, limit int) (matches []*FileMatchResolver, limitHit bool, reposLimitHit map[string]struct{}, err error) {
+	if filter == nil || len(s.Repos()) == 0 {
+		return s.Search(ctx)
+	}
+
+	fileMatchLimit := s.args.PatternInfo.FileMatchLimit
+	for factor := int32(4); ; factor *= 4 {
+		args := *s.args
+		patternInfo := *args.PatternInfo
+		patternInfo.FileMatchLimit = fileMatchLimit * factor
+		args.PatternInfo = &patternInfo
+		req := *s
+		req.args = &args
+
+		matches, limitHit, reposLimitHit, err = req.Search(ctx)
+		matches = filter.filterFileMatches(matches)
+		filled := symbolCount(matches) > limit || len(matches) > int(fileMatchLimit)
+		if err != nil || !limitHit || filled || factor >= maxSymbolFilterOverFetch {
+			return matches, limitHit, reposLimitHit, err
+		}
+	}
+}
+
 // limitSymbolResults returns a new version of res containing no more than limit symbol matches.
 func limitSymbolResults(res []*FileMatchResolver, limit int) []*FileMatchResolver {
 	res2 := make([]*FileMatchResolver, 0, len(res))
@@ -251,13 +292,17 @@ func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisio
 	}
 
 	symbols, err := backend.Symbols.ListTags(ctx, search.SymbolsParameters{
-		Repo:            repoRevs.Repo.Name,
-		CommitID:        commitID,
-		Query:           patternInfo.Pattern,
-		IsCaseSensitive: patternInfo.IsCaseSensitive,
-		IsRegExp:        patternInfo.IsRegExp,
-		IncludePatterns: patternInfo.IncludePatterns,
-		ExcludePattern:  patternInfo.ExcludePattern,
+		Repo:                 repoRevs.Repo.Name,
+		CommitID:             commitID,
+		Query:                patternInfo.Pattern,
+		IsCaseSensitive:      patternInfo.IsCaseSensitive,
+		IsRegExp:             patternInfo.IsRegExp,
+		IncludePatterns:      patternInfo.IncludePatterns,
+		ExcludePattern:       patternInfo.ExcludePattern,
+		Kinds:                patternInfo.SymbolKinds,
+		ExcludeKinds:         patternInfo.ExcludeSymbolKinds,
+		ParentPatterns:       patternInfo.SymbolParentPatterns,
+		ExcludeParentPattern: patternInfo.ExcludeSymbolParentPattern,
 		// Ask for limit + 1 so we can detect whether there are more results than the limit.
 		First: limit + 1,
 	})
@@ -291,6 +336,110 @@ func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisio
 	return fileMatches, err
 }
 
+// hasSymbolFilters reports whether q filters symbols by kind or parent.
+func hasSymbolFilters(q query.QueryInfo) bool {
+	kinds, excludeKinds := q.StringValues(query.FieldSymbolKind)
+	parents, excludeParents := q.RegexpPatterns(query.FieldSymbolParent)
+	return len(kinds)+len(excludeKinds)+len(parents)+len(excludeParents) > 0
+}
+
+// symbolFilter filters symbols by kind and parent. The symbols service
+// applies these filters itself when searching unindexed repositories, but
+// indexed search doesn't support them, so its results are filtered
+// afterwards (see searchIndexedSymbols).
+type symbolFilter struct {
+	kinds         map[string]struct{}
+	excludeKinds  map[string]struct{}
+	parents       []*regexp.Regexp
+	excludeParent *regexp.Regexp
+}
+
+// newSymbolFilter returns a filter for the symbol filters of p, or nil if p
+// has none.
+func newSymbolFilter(p *search.TextPatternInfo) (*symbolFilter, error) {
+	if len(p.SymbolKinds) == 0 && len(p.ExcludeSymbolKinds) == 0 && len(p.SymbolParentPatterns) == 0 && p.ExcludeSymbolParentPattern == "" {
+		return nil, nil
+	}
+
+	compile := func(pattern string) (*regexp.Regexp, error) {
+		if !p.IsCaseSensitive {
+			pattern = "(?i:" + pattern + ")"
+		}
+		return regexp.Compile(pattern)
+	}
+
+	toSet := func(kinds []string) map[string]struct{} {
+		set := make(map[string]struct{}, len(kinds))
+		for _, kind := range kinds {
+			set[strings.ToLower(kind)] = struct{}{}
+		}
+		return set
+	}
+
+	f := &symbolFilter{
+		kinds:        toSet(p.SymbolKinds),
+		excludeKinds: toSet(p.ExcludeSymbolKinds),
+	}
+	for _, pattern := range p.SymbolParentPatterns {
+		re, err := compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.parents = append(f.parents, re)
+	}
+	if p.ExcludeSymbolParentPattern != "" {
+		re, err := compile(p.ExcludeSymbolParentPattern)
+		if err != nil {
+			return nil, err
+		}
+		f.excludeParent = re
+	}
+	return f, nil
+}
+
+func (f *symbolFilter) match(s *protocol.Symbol) bool {
+	kind := strings.ToLower(s.Kind)
+	if _, ok := f.kinds[kind]; len(f.kinds) > 0 && !ok {
+		return false
+	}
+	if _, ok := f.excludeKinds[kind]; ok {
+		return false
+	}
+	for _, re := range f.parents {
+		if !re.MatchString(s.Parent) {
+			return false
+		}
+	}
+	if f.excludeParent != nil && f.excludeParent.MatchString(s.Parent) {
+		return false
+	}
+	return true
+}
+
+// filterFileMatches removes the symbols not matched by f from fileMatches,
+// and then the file matches without any symbols left. A nil filter matches
+// all symbols.
+func (f *symbolFilter) filterFileMatches(fileMatches []*FileMatchResolver) []*FileMatchResolver {
+	if f == nil {
+		return fileMatches
+	}
+
+	filtered := fileMatches[:0]
+	for _, fm := range fileMatches {
+		symbols := fm.symbols[:0]
+		for _, s := range fm.symbols {
+			if f.match(&s.symbol) {
+				symbols = append(symbols, s)
+			}
+		}
+		if len(symbols) > 0 {
+			fm.symbols = symbols
+			filtered = append(filtered, fm)
+		}
+	}
+	return filtered
+}
+
 // makeFileMatchURIFromSymbol makes a git://repo?rev#path URI from a symbol
 // search result to use in a fileMatchResolver
 func makeFileMatchURIFromSymbol(symbolResult *searchSymbolResult, inputRev string) string {
@@ -324,62 +473,71 @@ func ctagsSymbolCharacter(s protocol.Symbol) int {
 	return 0
 }
 
+// lspSymbolKinds maps ctags kinds to LSP symbol kinds. It is the inverse of
+// query.CtagsKinds.
+var lspSymbolKinds = func() map[string]lsp.SymbolKind {
+	m := make(map[string]lsp.SymbolKind)
+	for name, kinds := range query.CtagsKinds {
+		for _, kind := range kinds {
+			m[kind] = symbolKindNames[name]
+		}
+	}
+	return m
+}()
+
 func ctagsKindToLSPSymbolKind(kind string) lsp.SymbolKind {
-	// Ctags kinds are determined by the parser and do not (in general) match LSP symbol kinds.
-	switch strings.ToLower(kind) {
-	case "file":
-		return lsp.SKFile
-	case "module":
-		return lsp.SKModule
-	case "namespace":
-		return lsp.SKNamespace
-	case "package", "packagename", "subprogspec":
-		return lsp.SKPackage
-	case "class", "type", "service", "typedef", "union", "section", "subtype", "component":
-		return lsp.SKClass
-	case "method", "methodspec":
-		return lsp.SKMethod
-	case "property":
-		return lsp.SKProperty
-	case "field", "member", "anonmember", "recordfield":
-		return lsp.SKField
-	case "constructor":
-		return lsp.SKConstructor
-	case "enum", "enumerator":
-		return lsp.SKEnum
-	case "interface":
-		return lsp.SKInterface
-	case "function", "func", "subroutine", "macro", "subprogram", "procedure", "command", "singletonmethod":
-		return lsp.SKFunction
-	case "variable", "var", "functionvar", "define", "alias", "val":
-		return lsp.SKVariable
-	case "constant", "const":
-		return lsp.SKConstant
-	case "string", "message", "heredoc":
-		return lsp.SKString
-	case "number":
-		return lsp.SKNumber
-	case "bool", "boolean":
-		return lsp.SKBoolean
-	case "array":
-		return lsp.SKArray
-	case "object", "literal", "map":
-		return lsp.SKObject
-	case "key", "label", "target", "selector", "id", "tag":
-		return lsp.SKKey
-	case "null":
-		return lsp.SKNull
-	case "enum member", "enumconstant":
-		return lsp.SKEnumMember
-	case "struct":
-		return lsp.SKStruct
-	case "event":
-		return lsp.SKEvent
-	case "operator":
-		return lsp.SKOperator
-	case "type parameter", "annotation":
-		return lsp.SKTypeParameter
+	if lspKind, ok := lspSymbolKinds[strings.ToLower(kind)]; ok {
+		return lspKind
 	}
 	log15.Debug("Unknown ctags kind", "kind", kind)
 	return 0
 }
+
+// symbolKindNames maps the symbol kinds users can filter on in queries
+// (symbolkind:function, select:symbol.function), which are the keys of
+// query.CtagsKinds, to LSP symbol kinds.
+var symbolKindNames = map[string]lsp.SymbolKind{
+	"file":          lsp.SKFile,
+	"module":        lsp.SKModule,
+	"namespace":     lsp.SKNamespace,
+	"package":       lsp.SKPackage,
+	"class":         lsp.SKClass,
+	"method":        lsp.SKMethod,
+	"property":      lsp.SKProperty,
+	"field":         lsp.SKField,
+	"constructor":   lsp.SKConstructor,
+	"enum":          lsp.SKEnum,
+	"interface":     lsp.SKInterface,
+	"function":      lsp.SKFunction,
+	"variable":      lsp.SKVariable,
+	"constant":      lsp.SKConstant,
+	"string":        lsp.SKString,
+	"number":        lsp.SKNumber,
+	"boolean":       lsp.SKBoolean,
+	"array":         lsp.SKArray,
+	"object":        lsp.SKObject,
+	"key":           lsp.SKKey,
+	"null":          lsp.SKNull,
+	"enummember":    lsp.SKEnumMember,
+	"struct":        lsp.SKStruct,
+	"event":         lsp.SKEvent,
+	"operator":      lsp.SKOperator,
+	"typeparameter": lsp.SKTypeParameter,
+}
+
+// ctagsKindsForSymbolKinds returns the ctags kinds matched by the given
+// symbol kinds from a query. Kinds that aren't in query.CtagsKinds are
+// assumed to be ctags kinds (e.g. "func"), so that users can filter on
+// kinds specific to a language.
+func ctagsKindsForSymbolKinds(names []string) []string {
+	var kinds []string
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if ctagsKinds, ok := query.CtagsKinds[name]; ok {
+			kinds = append(kinds, ctagsKinds...)
+		} else {
+			kinds = append(kinds, name)
+		}
+	}
+	return kinds
+}
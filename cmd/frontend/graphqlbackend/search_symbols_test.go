@@ -1,14 +1,21 @@
 package graphqlbackend
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/zoekt"
+	zoektquery "github.com/google/zoekt/query"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
@@ -257,3 +264,181 @@ func TestLimitingSymbolResults(t *testing.T) {
 		}
 	})
 }
+
+func TestCtagsKindsForSymbolKinds(t *testing.T) {
+	got := ctagsKindsForSymbolKinds([]string{"Method", "singletonmethod"})
+	want := []string{"method", "methodspec", "singletonmethod"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestSymbolKindNames(t *testing.T) {
+	for name := range query.CtagsKinds {
+		if _, ok := symbolKindNames[name]; !ok {
+			t.Errorf("symbol kind %q has no LSP symbol kind", name)
+		}
+	}
+	if len(symbolKindNames) != len(query.CtagsKinds) {
+		t.Errorf("unexpected number of symbol kinds. want=%d have=%d", len(query.CtagsKinds), len(symbolKindNames))
+	}
+}
+
+func TestSymbolFilter(t *testing.T) {
+	newFileMatch := func(symbols ...protocol.Symbol) *FileMatchResolver {
+		fm := &FileMatchResolver{}
+		for _, s := range symbols {
+			fm.symbols = append(fm.symbols, &searchSymbolResult{symbol: s})
+		}
+		return fm
+	}
+	names := func(fms []*FileMatchResolver) (names [][]string) {
+		for _, fm := range fms {
+			var n []string
+			for _, s := range fm.symbols {
+				n = append(n, s.symbol.Name)
+			}
+			names = append(names, n)
+		}
+		return names
+	}
+
+	newFileMatches := func() []*FileMatchResolver {
+		return []*FileMatchResolver{
+			newFileMatch(
+				protocol.Symbol{Name: "x", Kind: "variable"},
+				protocol.Symbol{Name: "Close", Kind: "method", Parent: "File"},
+			),
+			newFileMatch(
+				protocol.Symbol{Name: "Open", Kind: "func"},
+			),
+		}
+	}
+
+	tests := []struct {
+		name string
+		p    search.TextPatternInfo
+		want [][]string
+	}{
+		{
+			name: "no filters",
+			want: [][]string{{"x", "Close"}, {"Open"}},
+		},
+		{
+			name: "kinds",
+			p:    search.TextPatternInfo{SymbolKinds: []string{"method", "func"}},
+			want: [][]string{{"Close"}, {"Open"}},
+		},
+		{
+			name: "exclude kinds",
+			p:    search.TextPatternInfo{ExcludeSymbolKinds: []string{"func"}},
+			want: [][]string{{"x", "Close"}},
+		},
+		{
+			name: "parent",
+			p:    search.TextPatternInfo{SymbolParentPatterns: []string{"^file$"}},
+			want: [][]string{{"Close"}},
+		},
+		{
+			name: "case sensitive parent",
+			p:    search.TextPatternInfo{SymbolParentPatterns: []string{"^file$"}, IsCaseSensitive: true},
+		},
+		{
+			name: "exclude parent",
+			p:    search.TextPatternInfo{ExcludeSymbolParentPattern: "File"},
+			want: [][]string{{"x"}, {"Open"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newSymbolFilter(&tt.p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := names(f.filterFileMatches(newFileMatches()))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+// countingSearcher counts the searches sent to a Zoekt searcher, and returns
+// a copy of the fake result to each.
+type countingSearcher struct {
+	*fakeSearcher
+	searches int
+}
+
+func (c *countingSearcher) Search(ctx context.Context, q zoektquery.Q, opts *zoekt.SearchOptions) (*zoekt.SearchResult, error) {
+	c.searches++
+	res, err := c.fakeSearcher.Search(ctx, q, opts)
+	if err != nil {
+		return nil, err
+	}
+	// zoektSearch truncates the files of the result it gets.
+	r := *res
+	return &r, nil
+}
+
+func TestSearchSymbols_filterIndexed(t *testing.T) {
+	// Only the last of many files has a method, so the filter removes all
+	// the files within the file match limit.
+	var files []zoekt.FileMatch
+	for i := 0; i < 20; i++ {
+		kind := "variable"
+		if i == 19 {
+			kind = "method"
+		}
+		files = append(files, zoekt.FileMatch{
+			Repository: "repo",
+			Branches:   []string{"HEAD"},
+			FileName:   fmt.Sprintf("file%d.go", i),
+			LineMatches: []zoekt.LineMatch{{
+				LineNumber:    1,
+				LineFragments: []zoekt.LineFragmentMatch{{SymbolInfo: &zoekt.Symbol{Sym: "Foo", Kind: kind}}},
+			}},
+		})
+	}
+	searcher := &countingSearcher{fakeSearcher: &fakeSearcher{
+		result: &zoekt.SearchResult{Files: files},
+		repos: []*zoekt.RepoListEntry{{Repository: zoekt.Repository{
+			Name:       "repo",
+			Branches:   []zoekt.RepositoryBranch{{Name: "HEAD", Version: "deadbeef"}},
+			HasSymbols: true,
+		}}},
+	}}
+
+	q, err := query.ParseAndCheck("index:only type:symbol symbolkind:method Foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := &search.TextParameters{
+		PatternInfo: &search.TextPatternInfo{
+			Pattern:        "Foo",
+			FileMatchLimit: 2,
+			SymbolKinds:    []string{"method"},
+		},
+		Repos: []*search.RepositoryRevisions{{
+			Repo: &types.Repo{ID: 1, Name: "repo"},
+			Revs: []search.RevisionSpecifier{{RevSpec: ""}},
+		}},
+		Query: q,
+		Zoekt: &searchbackend.Zoekt{Client: searcher, DisableCache: true},
+	}
+
+	res, _, err := searchSymbols(context.Background(), args, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].JPath != "file19.go" {
+		var paths []string
+		for _, fm := range res {
+			paths = append(paths, fm.JPath)
+		}
+		t.Fatalf("got file matches %v, want [file19.go]", paths)
+	}
+	if searcher.searches != 2 {
+		t.Errorf("got %d searches, want 2", searcher.searches)
+	}
+}
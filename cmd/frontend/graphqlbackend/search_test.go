@@ -16,6 +16,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	querytypes "github.com/sourcegraph/sourcegraph/internal/search/query/types"
@@ -756,3 +758,36 @@ func TestComputeExcludedRepositories(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveRevAt(t *testing.T) {
+	git.Mocks.LastCommitBefore = func(repo gitserver.Repo, date, revspec string) (api.CommitID, error) {
+		if repo.Name == "new" {
+			return "", &gitserver.RevisionNotFoundError{Repo: repo.Name, Spec: revspec}
+		}
+		return api.CommitID(fmt.Sprintf("%s-%s-%s", repo.Name, revspec, date)), nil
+	}
+	defer git.ResetMocks()
+
+	repoRevs := func(name string, revs ...search.RevisionSpecifier) *search.RepositoryRevisions {
+		return &search.RepositoryRevisions{Repo: &types.Repo{Name: api.RepoName(name)}, Revs: revs}
+	}
+	revisions := []*search.RepositoryRevisions{
+		repoRevs("a", withRevAt(nil, "2020-01-01")...),
+		repoRevs("b", withRevAt([]search.RevisionSpecifier{{RevSpec: "dev", RevAt: "2019-01-01"}, {RefGlob: "refs/tags/"}}, "2020-01-01")...),
+		repoRevs("c"),
+		repoRevs("new", withRevAt(nil, "2020-01-01")...),
+	}
+
+	got, err := resolveRevAt(context.Background(), revisions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*search.RepositoryRevisions{
+		repoRevs("a", search.RevisionSpecifier{RevSpec: "a--2020-01-01"}),
+		repoRevs("b", search.RevisionSpecifier{RevSpec: "b-dev-2019-01-01"}, search.RevisionSpecifier{RefGlob: "refs/tags/"}),
+		repoRevs("c"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -205,6 +205,7 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 		"Pattern":         []string{p.Pattern},
 		"ExcludePattern":  []string{p.ExcludePattern},
 		"IncludePatterns": p.IncludePatterns,
+		"IncludePaths":    p.IncludePaths,
 		"FetchTimeout":    []string{fetchTimeout.String()},
 		"Languages":       p.Languages,
 		"CombyRule":       []string{p.CombyRule},
@@ -219,6 +220,13 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 		q.Set("Deadline", string(t))
 	}
 	q.Set("FileMatchLimit", strconv.FormatInt(int64(p.FileMatchLimit), 10))
+	if p.PatternExpression != nil {
+		expr, err := json.Marshal(p.PatternExpression)
+		if err != nil {
+			return nil, false, err
+		}
+		q.Set("PatternExpression", string(expr))
+	}
 	if p.IsRegExp {
 		q.Set("IsRegExp", "true")
 	}
@@ -620,8 +628,7 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 					if v, ok := searcherReposFilteredFiles[string(repoRev.Repo.Name)]; ok {
 						patternCopy := *args.PatternInfo
 						args.PatternInfo = &patternCopy
-						includePatternsCopy := []string{}
-						args.PatternInfo.IncludePatterns = append(includePatternsCopy, v...)
+						args.PatternInfo.IncludePaths = append([]string{}, v...)
 					}
 				}
 
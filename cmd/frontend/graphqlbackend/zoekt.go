@@ -477,25 +477,110 @@ func fileRe(pattern string, queryIsCaseSensitive bool) (zoektquery.Q, error) {
 	return parseRe(pattern, true, queryIsCaseSensitive)
 }
 
-func queryToZoektQuery(query *search.TextPatternInfo, typ indexedRequestType) (zoektquery.Q, error) {
-	var and []zoektquery.Q
-
-	var q zoektquery.Q
-	var err error
+// patternToZoektQuery returns the zoekt query for pattern, interpreted
+// according to the options in query.
+func patternToZoektQuery(query *search.TextPatternInfo, pattern string) (zoektquery.Q, error) {
 	if query.IsRegExp {
 		fileNameOnly := query.PatternMatchesPath && !query.PatternMatchesContent
-		q, err = parseRe(query.Pattern, fileNameOnly, query.IsCaseSensitive)
+		return parseRe(pattern, fileNameOnly, query.IsCaseSensitive)
+	}
+	return &zoektquery.Substring{
+		Pattern:       pattern,
+		CaseSensitive: query.IsCaseSensitive,
+
+		FileName: true,
+		Content:  true,
+	}, nil
+}
+
+// patternExpressionToZoektQuery translates expr to the equivalent zoekt
+// query, so that zoekt matches the same files as searcher does for expr.
+// Like searcher, the whole expression is matched against file contents and,
+// if the pattern matches paths, separately against file names. So "foo and
+// not bar" matches a file containing foo whose name contains bar.
+func patternExpressionToZoektQuery(query *search.TextPatternInfo, expr *search.PatternExpression) (zoektquery.Q, error) {
+	var or []zoektquery.Q
+	if query.PatternMatchesContent || !query.PatternMatchesPath {
+		q, err := patternExpressionToZoektQueryOn(query, expr, false)
 		if err != nil {
 			return nil, err
 		}
-	} else {
-		q = &zoektquery.Substring{
-			Pattern:       query.Pattern,
-			CaseSensitive: query.IsCaseSensitive,
+		or = append(or, q)
+	}
+	if query.PatternMatchesPath {
+		q, err := patternExpressionToZoektQueryOn(query, expr, true)
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, q)
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return zoektquery.NewOr(or...), nil
+}
+
+// patternExpressionToZoektQueryOn translates expr to a zoekt query whose
+// patterns only match file names if fileName is true, and only file contents
+// otherwise.
+func patternExpressionToZoektQueryOn(query *search.TextPatternInfo, expr *search.PatternExpression, fileName bool) (zoektquery.Q, error) {
+	if expr.Kind == search.PatternExpressionPattern {
+		if !query.IsRegExp {
+			return &zoektquery.Substring{
+				Pattern:       expr.Pattern,
+				CaseSensitive: query.IsCaseSensitive,
+
+				FileName: fileName,
+				Content:  !fileName,
+			}, nil
+		}
+		q, err := parseRe(expr.Pattern, fileName, query.IsCaseSensitive)
+		if err != nil {
+			return nil, err
+		}
+		switch q := q.(type) {
+		case *zoektquery.Substring:
+			q.Content = !fileName
+		case *zoektquery.Regexp:
+			q.Content = !fileName
+		}
+		return q, nil
+	}
 
-			FileName: true,
-			Content:  true,
+	operands := make([]zoektquery.Q, 0, len(expr.Operands))
+	for _, operand := range expr.Operands {
+		q, err := patternExpressionToZoektQueryOn(query, operand, fileName)
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, q)
+	}
+	switch expr.Kind {
+	case search.PatternExpressionAnd:
+		return zoektquery.NewAnd(operands...), nil
+	case search.PatternExpressionOr:
+		return zoektquery.NewOr(operands...), nil
+	case search.PatternExpressionNot:
+		if len(operands) != 1 {
+			return nil, fmt.Errorf("not expression has %d operands, want 1", len(operands))
 		}
+		return &zoektquery.Not{Child: operands[0]}, nil
+	}
+	return nil, fmt.Errorf("unknown pattern expression kind %q", expr.Kind)
+}
+
+func queryToZoektQuery(query *search.TextPatternInfo, typ indexedRequestType) (zoektquery.Q, error) {
+	var and []zoektquery.Q
+
+	var q zoektquery.Q
+	var err error
+	if query.PatternExpression != nil {
+		q, err = patternExpressionToZoektQuery(query, query.PatternExpression)
+	} else {
+		q, err = patternToZoektQuery(query, query.Pattern)
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	if typ == symbolRequest {
@@ -1,10 +1,15 @@
 package graphqlbackend
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"sort"
 	"strings"
 	"testing"
@@ -18,13 +23,17 @@ import (
 	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	searcher "github.com/sourcegraph/sourcegraph/cmd/searcher/search"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/endpoint"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	searchbackend "github.com/sourcegraph/sourcegraph/internal/search/backend"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/store"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -533,6 +542,47 @@ func TestQueryToZoektQuery(t *testing.T) {
 			},
 			Query: `foo (type:repo file:\.go$) (type:repo file:\.yaml$) -(type:repo file:\.java$) -(type:repo file:\.xml$)`,
 		},
+		{
+			Name: "pattern expression",
+			Type: textRequest,
+			Pattern: &search.TextPatternInfo{
+				IsRegExp: true,
+				Pattern:  "foo",
+				PatternExpression: &search.PatternExpression{
+					Kind: search.PatternExpressionAnd,
+					Operands: []*search.PatternExpression{
+						{Kind: search.PatternExpressionPattern, Pattern: "foo"},
+						{Kind: search.PatternExpressionNot, Operands: []*search.PatternExpression{
+							{Kind: search.PatternExpressionOr, Operands: []*search.PatternExpression{
+								{Kind: search.PatternExpressionPattern, Pattern: "bar"},
+								{Kind: search.PatternExpressionPattern, Pattern: "ba+z"},
+							}},
+						}},
+					},
+				},
+				IncludePatterns: []string{`\.go$`},
+			},
+			Query: `content:foo -(content:bar or content:ba+z) case:no f:\.go$`,
+		},
+		{
+			Name: "pattern expression matching paths",
+			Type: textRequest,
+			Pattern: &search.TextPatternInfo{
+				Pattern: "foo",
+				PatternExpression: &search.PatternExpression{
+					Kind: search.PatternExpressionAnd,
+					Operands: []*search.PatternExpression{
+						{Kind: search.PatternExpressionPattern, Pattern: "foo"},
+						{Kind: search.PatternExpressionNot, Operands: []*search.PatternExpression{
+							{Kind: search.PatternExpressionPattern, Pattern: "bar"},
+						}},
+					},
+				},
+				PatternMatchesContent: true,
+				PatternMatchesPath:    true,
+			},
+			Query: `(content:foo -content:bar) or (f:foo -f:bar)`,
+		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.Name, func(t *testing.T) {
@@ -931,3 +981,146 @@ func repoRevsSliceToMap(rs []*search.RepositoryRevisions) map[string]*search.Rep
 	}
 	return m
 }
+
+// TestPatternExpressionBackendsAgree checks that zoekt and searcher match the
+// same files for a pattern expression with a negated operand.
+func TestPatternExpressionBackendsAgree(t *testing.T) {
+	files := map[string]string{
+		"bar.go":  "foo",
+		"baz.go":  "foo bar",
+		"foo.txt": "qux",
+		"qux.go":  "qux",
+	}
+	expr := &search.PatternExpression{
+		Kind: search.PatternExpressionAnd,
+		Operands: []*search.PatternExpression{
+			{Kind: search.PatternExpressionPattern, Pattern: "foo"},
+			{Kind: search.PatternExpressionNot, Operands: []*search.PatternExpression{
+				{Kind: search.PatternExpressionPattern, Pattern: "bar"},
+			}},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		pattern *search.TextPatternInfo
+		want    []string
+	}{
+		{
+			name:    "content",
+			pattern: &search.TextPatternInfo{PatternExpression: expr, PatternMatchesContent: true},
+			want:    []string{"bar.go"},
+		},
+		{
+			name:    "content and path",
+			pattern: &search.TextPatternInfo{PatternExpression: expr, PatternMatchesContent: true, PatternMatchesPath: true},
+			want:    []string{"bar.go", "foo.txt"},
+		},
+	}
+
+	zoektSearcher := newMemZoektSearcher(t, files)
+	defer zoektSearcher.Close()
+
+	searcherURL, cleanup := newTestSearcher(t, files)
+	defer cleanup()
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := queryToZoektQuery(tt.pattern, textRequest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := zoektSearcher.Search(context.Background(), q, &zoekt.SearchOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var zoektPaths []string
+			for _, file := range res.Files {
+				zoektPaths = append(zoektPaths, file.FileName)
+			}
+			sort.Strings(zoektPaths)
+			if diff := cmp.Diff(tt.want, zoektPaths); diff != "" {
+				t.Errorf("unexpected zoekt matches (-want +got):\n%s", diff)
+			}
+
+			matches, _, err := textSearch(context.Background(), endpoint.Static(searcherURL), gitserver.Repo{Name: "repo"}, "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef", tt.pattern, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var searcherPaths []string
+			for _, match := range matches {
+				searcherPaths = append(searcherPaths, match.JPath)
+			}
+			sort.Strings(searcherPaths)
+			if diff := cmp.Diff(tt.want, searcherPaths); diff != "" {
+				t.Errorf("unexpected searcher matches (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// memIndexFile is a zoekt.IndexFile backed by a byte slice.
+type memIndexFile struct {
+	data []byte
+}
+
+func (f *memIndexFile) Read(off, sz uint32) ([]byte, error) { return f.data[off : off+sz], nil }
+func (f *memIndexFile) Size() (uint32, error)               { return uint32(len(f.data)), nil }
+func (f *memIndexFile) Close()                              {}
+func (f *memIndexFile) Name() string                        { return "mem" }
+
+// newMemZoektSearcher returns a zoekt.Searcher over an in-memory shard
+// containing files.
+func newMemZoektSearcher(t *testing.T, files map[string]string) zoekt.Searcher {
+	b, err := zoekt.NewIndexBuilder(&zoekt.Repository{Name: "repo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := b.Add(zoekt.Document{Name: name, Content: []byte(content)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s, err := zoekt.NewSearcher(&memIndexFile{data: buf.Bytes()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// newTestSearcher starts a searcher service which serves files for every
+// repository and commit, and returns its URL.
+func newTestSearcher(t *testing.T, files map[string]string) (string, func()) {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for name, content := range files {
+		if err := w.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "searcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(&searcher.Service{Store: &store.Store{
+		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil
+		},
+		Path: dir,
+	}})
+	return ts.URL, func() {
+		ts.Close()
+		os.RemoveAll(dir)
+	}
+}
@@ -19,6 +19,7 @@ func newTest() *httptestutil.Client {
 		router.New(mux.NewRouter()),
 		nil,
 		enterpriseServices.GithubWebhook,
+		enterpriseServices.GitLabWebhook,
 		enterpriseServices.BitbucketServerWebhook,
 		enterpriseServices.NewCodeIntelUploadHandler,
 	))
@@ -306,10 +306,11 @@ func serveSavedQueriesSetInfo(w http.ResponseWriter, r *http.Request) error {
 		return errors.Wrap(err, "Decode")
 	}
 	err = db.QueryRunnerState.Set(r.Context(), &db.SavedQueryInfo{
-		Query:        info.Query,
-		LastExecuted: info.LastExecuted,
-		LatestResult: info.LatestResult,
-		ExecDuration: info.ExecDuration,
+		Query:              info.Query,
+		LastExecuted:       info.LastExecuted,
+		LatestResult:       info.LatestResult,
+		ExecDuration:       info.ExecDuration,
+		ResultFingerprints: info.ResultFingerprints,
 	})
 	if err != nil {
 		return errors.Wrap(err, "SavedQueries.Set")
@@ -511,7 +512,7 @@ func serveGitExec(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	// Find the correct shard to query
-	addr := gitserver.DefaultClient.AddrForRepo(r.Context(), repo.Name)
+	addr := gitserver.DefaultClient.ReadAddrForRepo(r.Context(), repo.Name)
 
 	director := func(req *http.Request) {
 		req.URL.Scheme = "http"
@@ -529,7 +530,7 @@ func serveGitExec(w http.ResponseWriter, r *http.Request) error {
 // gitserver for the repo.
 type gitServiceHandler struct {
 	Gitserver interface {
-		AddrForRepo(context.Context, api.RepoName) string
+		ReadAddrForRepo(context.Context, api.RepoName) string
 	}
 }
 
@@ -546,7 +547,7 @@ func (s *gitServiceHandler) redirectToGitServer(w http.ResponseWriter, r *http.R
 
 	u := &url.URL{
 		Scheme:   "http",
-		Host:     s.Gitserver.AddrForRepo(r.Context(), api.RepoName(repo)),
+		Host:     s.Gitserver.ReadAddrForRepo(r.Context(), api.RepoName(repo)),
 		Path:     path.Join("/git", repo, gitPath),
 		RawQuery: r.URL.RawQuery,
 	}
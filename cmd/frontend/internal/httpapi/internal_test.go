@@ -55,7 +55,7 @@ func TestGitServiceHandlers(t *testing.T) {
 
 type mockAddrForRepo struct{}
 
-func (mockAddrForRepo) AddrForRepo(_ context.Context, name api.RepoName) string {
+func (mockAddrForRepo) ReadAddrForRepo(_ context.Context, name api.RepoName) string {
 	return strings.ReplaceAll(string(name), "/", ".") + ".gitserver"
 }
 
@@ -7,8 +7,9 @@ import (
 )
 
 const (
-	LSIFUpload = "lsif.upload"
-	GraphQL    = "graphql"
+	LSIFUpload   = "lsif.upload"
+	GraphQL      = "graphql"
+	SearchStream = "search.stream"
 
 	SrcCliVersion  = "src-cli.version"
 	SrcCliDownload = "src-cli.download"
@@ -20,6 +21,7 @@ const (
 	Telemetry   = "telemetry"
 
 	GitHubWebhooks          = "github.webhooks"
+	GitLabWebhooks          = "gitlab.webhooks"
 	BitbucketServerWebhooks = "bitbucketServer.webhooks"
 
 	SavedQueriesListAll    = "internal.saved-queries.list-all"
@@ -64,7 +66,9 @@ func New(base *mux.Router) *mux.Router {
 
 	addRegistryRoute(base)
 	addGraphQLRoute(base)
+	base.Path("/search/stream").Methods("GET").Name(SearchStream)
 	base.Path("/github-webhooks").Methods("POST").Name(GitHubWebhooks)
+	base.Path("/gitlab-webhooks").Methods("POST").Name(GitLabWebhooks)
 	base.Path("/bitbucket-server-webhooks").Methods("POST").Name(BitbucketServerWebhooks)
 	base.Path("/lsif/upload").Methods("POST").Name(LSIFUpload)
 	base.Path("/src-cli/version").Methods("GET").Name(SrcCliVersion)
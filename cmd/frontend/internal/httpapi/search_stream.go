@@ -0,0 +1,191 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+// searchStreamHandler streams the results of a search as Server-Sent Events
+// (https://html.spec.whatwg.org/multipage/server-sent-events.html) while the
+// search backends produce them.
+//
+// The following events are sent:
+//
+//   - "matches": a JSON array of graphqlbackend.StreamMatch.
+//   - "progress": a graphqlbackend.SearchProgress, sent whenever a search
+//     backend completes.
+//   - "alert": the alert of the search, if any.
+//   - "error": sent instead of "done" if the search failed.
+//   - "done": the final summary of the search. It is always the last event.
+type searchStreamHandler struct {
+	// search runs the search. It is graphqlbackend.StreamSearch outside of
+	// tests.
+	search func(context.Context, *graphqlbackend.SearchArgs, chan<- graphqlbackend.SearchEvent) (*graphqlbackend.SearchResultsResolver, error)
+}
+
+type searchStreamAlert struct {
+	Title           string                      `json:"title"`
+	Description     string                      `json:"description,omitempty"`
+	ProposedQueries []searchStreamProposedQuery `json:"proposedQueries,omitempty"`
+}
+
+type searchStreamProposedQuery struct {
+	Description string `json:"description,omitempty"`
+	Query       string `json:"query"`
+}
+
+type searchStreamDone struct {
+	graphqlbackend.SearchProgress
+	ElapsedMilliseconds int32 `json:"elapsedMilliseconds"`
+}
+
+type searchStreamError struct {
+	Message string `json:"message"`
+}
+
+func (h *searchStreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	args, err := parseSearchStreamArgs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	ctx = trace.WithRequestSource(ctx, guessSource(r))
+
+	ew := &eventWriter{w: w, flusher: flusher}
+
+	var (
+		results   *graphqlbackend.SearchResultsResolver
+		searchErr error
+		events    = make(chan graphqlbackend.SearchEvent)
+	)
+	go func() {
+		defer close(events)
+		results, searchErr = h.search(ctx, args, events)
+	}()
+
+	for event := range events {
+		if len(event.Results) > 0 {
+			matches := make([]*graphqlbackend.StreamMatch, 0, len(event.Results))
+			for _, res := range event.Results {
+				if m := graphqlbackend.NewStreamMatch(res); m != nil {
+					matches = append(matches, m)
+				}
+			}
+			ew.event("matches", matches)
+		}
+		ew.event("progress", event.Progress)
+
+		if ew.err != nil {
+			// The client went away. Stop the search, but keep draining
+			// events until it returned.
+			cancel()
+		}
+	}
+
+	if searchErr != nil {
+		ew.event("error", searchStreamError{Message: searchErr.Error()})
+		return
+	}
+
+	if alert := results.Alert(); alert != nil {
+		a := searchStreamAlert{Title: alert.Title()}
+		if d := alert.Description(); d != nil {
+			a.Description = *d
+		}
+		if pqs := alert.ProposedQueries(); pqs != nil {
+			for _, pq := range *pqs {
+				q := searchStreamProposedQuery{Query: pq.Query()}
+				if d := pq.Description(); d != nil {
+					q.Description = *d
+				}
+				a.ProposedQueries = append(a.ProposedQueries, q)
+			}
+		}
+		ew.event("alert", a)
+	}
+
+	ew.event("done", searchStreamDone{
+		SearchProgress:      results.Progress(),
+		ElapsedMilliseconds: results.ElapsedMilliseconds(),
+	})
+
+	if ew.err != nil && ctx.Err() == nil {
+		log15.Warn("streaming search: failed to write events", "error", ew.err)
+	}
+}
+
+// parseSearchStreamArgs parses the search arguments from the URL query of
+// r. The parameters are named like the arguments of the GraphQL search
+// field: q (the query), v (the version), t (the pattern type) and vc (the
+// version context).
+func parseSearchStreamArgs(r *http.Request) (*graphqlbackend.SearchArgs, error) {
+	values := r.URL.Query()
+
+	args := &graphqlbackend.SearchArgs{
+		Query:   values.Get("q"),
+		Version: values.Get("v"),
+	}
+	if args.Query == "" {
+		return nil, fmt.Errorf("missing query parameter q")
+	}
+	if args.Version == "" {
+		args.Version = "V2"
+	}
+	if t := values.Get("t"); t != "" {
+		args.PatternType = &t
+	}
+	if vc := values.Get("vc"); vc != "" {
+		args.VersionContext = &vc
+	}
+
+	return args, nil
+}
+
+// eventWriter writes Server-Sent Events. After the first error, all writes
+// are skipped and err is set.
+type eventWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+	err     error
+}
+
+func (e *eventWriter) event(name string, data interface{}) {
+	if e.err != nil {
+		return
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		e.err = err
+		return
+	}
+
+	// json.Marshal never produces newlines, so the payload fits on a
+	// single data line.
+	if _, err := fmt.Fprintf(e.w, "event: %s\ndata: %s\n\n", name, b); err != nil {
+		e.err = err
+		return
+	}
+	e.flusher.Flush()
+}
@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestSearchStreamHandler(t *testing.T) {
+	t.Run("missing query", func(t *testing.T) {
+		h := &searchStreamHandler{}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/search/stream", nil))
+
+		if have, want := rec.Code, http.StatusBadRequest; have != want {
+			t.Fatalf("wrong status code: have %d, want %d", have, want)
+		}
+	})
+
+	t.Run("results", func(t *testing.T) {
+		var args *graphqlbackend.SearchArgs
+		h := &searchStreamHandler{
+			search: func(ctx context.Context, a *graphqlbackend.SearchArgs, stream chan<- graphqlbackend.SearchEvent) (*graphqlbackend.SearchResultsResolver, error) {
+				args = a
+				results := []graphqlbackend.SearchResultResolver{
+					graphqlbackend.NewRepositoryResolver(&types.Repo{Name: "github.com/foo/bar"}),
+				}
+				stream <- graphqlbackend.SearchEvent{
+					Results:  results,
+					Progress: graphqlbackend.SearchProgress{MatchCount: 1, RepositoriesSearched: 1},
+				}
+				stream <- graphqlbackend.SearchEvent{
+					Progress: graphqlbackend.SearchProgress{MatchCount: 1, RepositoriesSearched: 2},
+				}
+				return &graphqlbackend.SearchResultsResolver{SearchResults: results}, nil
+			},
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/search/stream?q=foo&t=regexp", nil))
+
+		if have, want := rec.Code, http.StatusOK; have != want {
+			t.Fatalf("wrong status code: have %d, want %d", have, want)
+		}
+		if have, want := rec.Header().Get("Content-Type"), "text/event-stream"; have != want {
+			t.Errorf("wrong content type: have %q, want %q", have, want)
+		}
+
+		if args.Query != "foo" || args.Version != "V2" || args.PatternType == nil || *args.PatternType != "regexp" {
+			t.Errorf("wrong search args: %+v", args)
+		}
+
+		events := parseEventNames(rec.Body.String())
+		if diff := cmp.Diff([]string{"matches", "progress", "progress", "done"}, events); diff != "" {
+			t.Errorf("wrong events: %s", diff)
+		}
+
+		if want := `data: [{"type":"repo","repository":"github.com/foo/bar","url":"/github.com/foo/bar"}]`; !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("matches not found in body:\n%s", rec.Body.String())
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		h := &searchStreamHandler{
+			search: func(ctx context.Context, a *graphqlbackend.SearchArgs, stream chan<- graphqlbackend.SearchEvent) (*graphqlbackend.SearchResultsResolver, error) {
+				return nil, errors.New("boom")
+			},
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/search/stream?q=foo", nil))
+
+		if have, want := rec.Body.String(), "event: error\ndata: {\"message\":\"boom\"}\n\n"; have != want {
+			t.Errorf("wrong body: have %q, want %q", have, want)
+		}
+	})
+}
+
+func parseEventNames(body string) []string {
+	var names []string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "event: ") {
+			names = append(names, strings.TrimPrefix(line, "event: "))
+		}
+	}
+	return names
+}
@@ -1,5 +1,7 @@
 package types
 
+import "github.com/sourcegraph/sourcegraph/schema"
+
 // SavedSearch represents a saved search
 type SavedSearch struct {
 	ID              int32 // the globally unique DB ID
@@ -10,4 +12,7 @@ type SavedSearch struct {
 	UserID          *int32  // if non-nil, the owner is this user. UserID/OrgID are mutually exclusive.
 	OrgID           *int32  // if non-nil, the owner is this organization. UserID/OrgID are mutually exclusive.
 	SlackWebhookURL *string // if non-nil && NotifySlack == true, indicates that this Slack webhook URL should be used instead of the owners default Slack webhook.
+
+	// Webhooks are the HTTP endpoints that are notified of new results in addition to the owner(s).
+	Webhooks []*schema.SavedQueryWebhook
 }
@@ -28,6 +28,9 @@ var (
 	runRepoCleanup, _ = strconv.ParseBool(env.Get("SRC_RUN_REPO_CLEANUP", "", "Periodically remove inactive repositories."))
 	wantPctFree       = env.Get("SRC_REPOS_DESIRED_PERCENT_FREE", "10", "Target percentage of free space on disk.")
 	janitorInterval   = env.Get("SRC_REPOS_JANITOR_INTERVAL", "1m", "Interval between cleanup runs")
+
+	maintenanceInterval    = env.Get("SRC_REPOS_MAINTENANCE_INTERVAL", "10m", "Interval between runs of git maintenance (gc, repack and commit-graph) on the repos due for it. Set to 0 to disable git maintenance.")
+	maintenanceConcurrency = env.Get("SRC_REPOS_MAINTENANCE_CONCURRENCY", "1", "Maximum number of repos to run git maintenance on at the same time.")
 )
 
 func main() {
@@ -46,10 +49,20 @@ func main() {
 	if err != nil {
 		log.Fatalf("parsing $SRC_REPOS_DESIRED_PERCENT_FREE: %v", err)
 	}
+	maintenanceConcurrency2, err := strconv.Atoi(maintenanceConcurrency)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_MAINTENANCE_CONCURRENCY: %v", err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Fatalf("failed to get hostname: %s", err)
+	}
 	gitserver := server.Server{
 		ReposDir:                reposDir,
 		DeleteStaleRepositories: runRepoCleanup,
 		DesiredPercentFree:      wantPctFree2,
+		Hostname:                hostname,
+		MaintenanceConcurrency:  maintenanceConcurrency2,
 	}
 	gitserver.RegisterMetrics()
 
@@ -77,6 +90,19 @@ func main() {
 		}
 	}()
 
+	maintenanceInterval2, err := time.ParseDuration(maintenanceInterval)
+	if err != nil {
+		log.Fatalf("parsing $SRC_REPOS_MAINTENANCE_INTERVAL: %v", err)
+	}
+	if maintenanceInterval2 > 0 {
+		go func() {
+			for {
+				time.Sleep(maintenanceInterval2)
+				gitserver.Maintenance()
+			}
+		}()
+	}
+
 	port := "3178"
 	host := ""
 	if env.InsecureDev {
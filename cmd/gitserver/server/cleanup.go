@@ -56,6 +56,7 @@ var (
 // 2. Remove stale lock files.
 // 3. Remove inactive repos on sourcegraph.com
 // 4. Reclone repos after a while. (simulate git gc)
+// 5. Remove repos assigned to other gitservers once they have cloned them.
 func (s *Server) cleanupRepos() {
 	bCtx, bCancel := s.serverContext()
 	defer bCancel()
@@ -76,6 +77,12 @@ func (s *Server) cleanupRepos() {
 		return true, nil
 	}
 
+	maybeRemoveWrongShard := func(dir GitDir) (done bool, err error) {
+		ctx, cancel := context.WithTimeout(bCtx, time.Minute)
+		defer cancel()
+		return s.maybeRemoveWrongShard(ctx, dir)
+	}
+
 	ensureGitAttributes := func(dir GitDir) (done bool, err error) {
 		return false, setGitAttributes(dir)
 	}
@@ -173,6 +180,9 @@ func (s *Server) cleanupRepos() {
 	cleanups := []cleanupFn{
 		// Do some sanity checks on the repository.
 		{"maybe remove corrupt", maybeRemoveCorrupt},
+		// Repos assigned to another gitserver are kept until it has cloned
+		// them, possibly from this gitserver.
+		{"maybe remove wrong shard", maybeRemoveWrongShard},
 		// If git is interrupted it can leave lock files lying around. It does
 		// not clean these up, and instead fails commands.
 		{"remove stale locks", removeStaleLocks},
@@ -597,6 +607,12 @@ func checkMaybeCorruptRepo(repo api.RepoName, dir GitDir, stderr string) {
 		return
 	}
 
+	// Objects missing from a partial clone that couldn't be fetched from the
+	// code host don't mean that the repo is corrupt.
+	if strings.Contains(stderr, "promisor remote") {
+		return
+	}
+
 	log15.Warn("marking repo for recloning due to stderr output indicating repo corruption", "repo", repo, "stderr", stderr)
 
 	// We set a flag in the config for the cleanup janitor job to fix. The
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"golang.org/x/sync/singleflight"
+)
+
+// cloneOptionsKinds are the kinds of code host connections with the
+// gitCloneOptions setting.
+var cloneOptionsKinds = []string{
+	extsvc.KindGitHub,
+	extsvc.KindGitLab,
+	extsvc.KindBitbucketServer,
+	extsvc.KindBitbucketCloud,
+	extsvc.KindGerrit,
+	extsvc.KindOther,
+}
+
+// cloneOptionsTTL is how long the clone options of the code host connections
+// are cached.
+const cloneOptionsTTL = time.Minute
+
+// codeHostCloneOptions are the clone options of a code host connection,
+// which apply to the repos with clone URLs under baseURL.
+type codeHostCloneOptions struct {
+	baseURL *url.URL
+	opts    *schema.GitCloneOptions
+}
+
+// listCodeHostCloneOptions returns the clone options of all code host
+// connections that set them.
+var listCodeHostCloneOptions = func(ctx context.Context) ([]codeHostCloneOptions, error) {
+	svcs, err := api.InternalClient.ExternalServicesList(ctx, api.ExternalServicesListRequest{Kinds: cloneOptionsKinds})
+	if err != nil {
+		return nil, err
+	}
+
+	var all []codeHostCloneOptions
+	for _, svc := range svcs {
+		// The connections of every kind have these settings.
+		var c struct {
+			URL             string                  `json:"url"`
+			GitCloneOptions *schema.GitCloneOptions `json:"gitCloneOptions"`
+		}
+		if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+			log15.Warn("failed to parse code host connection config", "id", svc.ID, "error", err)
+			continue
+		}
+		if c.GitCloneOptions == nil || c.URL == "" {
+			continue
+		}
+		baseURL, err := parseRemoteURL(c.URL)
+		if err != nil {
+			log15.Warn("failed to parse code host connection URL", "id", svc.ID, "error", err)
+			continue
+		}
+		all = append(all, codeHostCloneOptions{baseURL: baseURL, opts: c.GitCloneOptions})
+	}
+	return all, nil
+}
+
+// cloneOptionsSnapshot is the cached clone options of the code host
+// connections. A snapshot is never modified; refreshing the cache replaces it.
+type cloneOptionsSnapshot struct {
+	all     []codeHostCloneOptions
+	fetched time.Time
+}
+
+var (
+	cloneOptionsCache   atomic.Value // *cloneOptionsSnapshot
+	cloneOptionsRefresh singleflight.Group
+)
+
+// cachedCloneOptions returns the clone options of all code host connections,
+// and refreshes them first if they are older than cloneOptionsTTL. Concurrent
+// callers share a single refresh, and no lock is held while it lists the code
+// host connections.
+func cachedCloneOptions(ctx context.Context) []codeHostCloneOptions {
+	if s, _ := cloneOptionsCache.Load().(*cloneOptionsSnapshot); s != nil && time.Since(s.fetched) <= cloneOptionsTTL {
+		return s.all
+	}
+
+	v, _, _ := cloneOptionsRefresh.Do("", func() (interface{}, error) {
+		prev, _ := cloneOptionsCache.Load().(*cloneOptionsSnapshot)
+		if prev != nil && time.Since(prev.fetched) <= cloneOptionsTTL {
+			// Another refresh finished since we checked.
+			return prev, nil
+		}
+
+		next := &cloneOptionsSnapshot{fetched: time.Now()}
+		all, err := listCodeHostCloneOptions(ctx)
+		if err != nil {
+			// Keep using the options we have until the next attempt.
+			log15.Warn("failed to list clone options of code host connections", "error", err)
+			if prev != nil {
+				next.all = prev.all
+			}
+		} else {
+			next.all = all
+		}
+		cloneOptionsCache.Store(next)
+		return next, nil
+	})
+	return v.(*cloneOptionsSnapshot).all
+}
+
+// gitCloneOptions returns the clone options of the code host connection that
+// the repo with the given clone URL belongs to, or nil if there are none. If
+// several connections match, the one with the longest URL wins.
+func gitCloneOptions(ctx context.Context, remoteURL string) *schema.GitCloneOptions {
+	all := cachedCloneOptions(ctx)
+
+	u, err := parseRemoteURL(remoteURL)
+	if err != nil {
+		return nil
+	}
+
+	var (
+		best    *schema.GitCloneOptions
+		bestLen = -1
+	)
+	for _, c := range all {
+		if !strings.EqualFold(c.baseURL.Hostname(), u.Hostname()) {
+			continue
+		}
+		base := strings.TrimSuffix(c.baseURL.Path, "/")
+		if u.Path != base && !strings.HasPrefix(u.Path, base+"/") {
+			continue
+		}
+		if len(base) > bestLen {
+			best, bestLen = c.opts, len(base)
+		}
+	}
+	return best
+}
+
+var scpURLRe = lazyregexp.New(`^(?:[^@/]+@)?([^:/]+):(.*)$`)
+
+// parseRemoteURL parses a Git remote URL, including SCP-like URLs such as
+// "git@github.com:foo/bar.git".
+func parseRemoteURL(remoteURL string) (*url.URL, error) {
+	if !strings.Contains(remoteURL, "://") {
+		if m := scpURLRe.FindStringSubmatch(remoteURL); m != nil {
+			return &url.URL{Scheme: "ssh", Host: m[1], Path: "/" + strings.TrimPrefix(m[2], "/")}, nil
+		}
+	}
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("no host in remote URL %q", remoteURL)
+	}
+	return u, nil
+}
+
+// branchesAndTagsRefspecs are the refspecs fetched when the
+// branchesAndTagsOnly clone option is set.
+var branchesAndTagsRefspecs = []string{"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"}
+
+// cloneOptionsCloneCmd returns the command that clones the repo at url into
+// tmpPath with the given clone options.
+//
+// Like refspecOverridesCloneCmd, it inits a bare repo with the refspecs to
+// fetch before fetching them. For a partial clone, the origin remote is also
+// configured as the promisor remote, from which git fetches the blobs left
+// out of the clone when a command such as "git show" or "git archive" needs
+// them.
+func cloneOptionsCloneCmd(ctx context.Context, url, tmpPath string, opts *schema.GitCloneOptions) (*exec.Cmd, error) {
+	if err := os.MkdirAll(tmpPath, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "clone failed to create tmp dir")
+	}
+	cmds := [][]string{
+		{"init", "--bare", "."},
+		{"config", "--add", "remote.origin.url", url},
+		{"config", "--add", "remote.origin.mirror", "true"},
+	}
+	refspecs := []string{"+refs/*:refs/*"}
+	if opts.BranchesAndTagsOnly {
+		refspecs = branchesAndTagsRefspecs
+	}
+	for _, refspec := range refspecs {
+		cmds = append(cmds, []string{"config", "--add", "remote.origin.fetch", refspec})
+	}
+	if opts.BlobSizeLimit != "" {
+		cmds = append(cmds,
+			[]string{"config", "core.repositoryformatversion", "1"},
+			[]string{"config", "extensions.partialClone", "origin"},
+			[]string{"config", "remote.origin.promisor", "true"},
+			[]string{"config", "remote.origin.partialclonefilter", "blob:limit=" + opts.BlobSizeLimit},
+		)
+	}
+	for _, args := range cmds {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = tmpPath
+		if err := cmd.Run(); err != nil {
+			return nil, errors.Wrapf(err, "clone setup failed")
+		}
+	}
+	cmd := exec.CommandContext(ctx, "git", "fetch", "--progress", "origin")
+	cmd.Dir = tmpPath
+	return cmd, nil
+}
+
+// cloneOptionsFetchCmd returns the command that fetches the repo in dir with
+// the given clone options, or nil if the default fetch command should be used.
+//
+// Partial clones are fetched from the origin remote, which is kept up to date
+// with url, because git only applies the filter of a partial clone when
+// fetching from its promisor remote.
+func cloneOptionsFetchCmd(ctx context.Context, dir GitDir, url string, opts *schema.GitCloneOptions) *exec.Cmd {
+	partial := isPartialClone(dir)
+	if (opts == nil || !opts.BranchesAndTagsOnly) && !partial {
+		return nil
+	}
+
+	remote := url
+	if partial {
+		remote = "origin"
+	}
+	refspecs := defaultRefspecs
+	if opts != nil && opts.BranchesAndTagsOnly {
+		refspecs = branchesAndTagsRefspecs
+	}
+	return exec.CommandContext(ctx, "git", append([]string{"fetch", "--prune", remote}, refspecs...)...)
+}
+
+// isPartialClone reports whether the repo in dir is a partial clone, which is
+// missing blobs that git fetches from the code host when they are needed.
+func isPartialClone(dir GitDir) bool {
+	promisor, _ := gitConfigGet(dir, "remote.origin.promisor")
+	return strings.TrimSpace(promisor) == "true"
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGitCloneOptions(t *testing.T) {
+	github := &schema.GitCloneOptions{BranchesAndTagsOnly: true}
+	gitlab := &schema.GitCloneOptions{BlobSizeLimit: "1m"}
+	gitlabGroup := &schema.GitCloneOptions{BlobSizeLimit: "1k"}
+
+	orig := listCodeHostCloneOptions
+	listCodeHostCloneOptions = func(context.Context) ([]codeHostCloneOptions, error) {
+		return []codeHostCloneOptions{
+			{baseURL: mustParseURL(t, "https://github.com"), opts: github},
+			{baseURL: mustParseURL(t, "https://gitlab.example.com/"), opts: gitlab},
+			{baseURL: mustParseURL(t, "https://gitlab.example.com/big"), opts: gitlabGroup},
+		}, nil
+	}
+	defer func() {
+		listCodeHostCloneOptions = orig
+		cloneOptionsCache.Store(&cloneOptionsSnapshot{})
+	}()
+	cloneOptionsCache.Store(&cloneOptionsSnapshot{})
+
+	for remoteURL, want := range map[string]*schema.GitCloneOptions{
+		"https://token@github.com/foo/bar":          github,
+		"git@github.com:foo/bar.git":                github,
+		"https://GitHub.com/foo/bar":                github,
+		"https://gitlab.example.com/foo/bar":        gitlab,
+		"https://gitlab.example.com/big/bar":        gitlabGroup,
+		"ssh://git@gitlab.example.com:22/big/bar":   gitlabGroup,
+		"https://gitlab.example.com/bigger/bar":     gitlab,
+		"https://bitbucket.example.com/scm/foo/bar": nil,
+		"/tmp/foo/bar":                              nil,
+	} {
+		if got := gitCloneOptions(context.Background(), remoteURL); got != want {
+			t.Errorf("%s: got %+v, want %+v", remoteURL, got, want)
+		}
+	}
+}
+
+func TestGitCloneOptions_concurrentRefresh(t *testing.T) {
+	opts := &schema.GitCloneOptions{BranchesAndTagsOnly: true}
+
+	var calls int32
+	release := make(chan struct{})
+	orig := listCodeHostCloneOptions
+	listCodeHostCloneOptions = func(context.Context) ([]codeHostCloneOptions, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return []codeHostCloneOptions{{baseURL: mustParseURL(t, "https://github.com"), opts: opts}}, nil
+	}
+	defer func() {
+		listCodeHostCloneOptions = orig
+		cloneOptionsCache.Store(&cloneOptionsSnapshot{})
+	}()
+	cloneOptionsCache.Store(&cloneOptionsSnapshot{})
+
+	const n = 10
+	var wg sync.WaitGroup
+	got := make([]*schema.GitCloneOptions, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = gitCloneOptions(context.Background(), "https://github.com/foo/bar")
+		}(i)
+	}
+	// Give the callers time to wait on the refresh before it completes.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("got %d calls to list the clone options, want 1", n)
+	}
+	for i, o := range got {
+		if o != opts {
+			t.Errorf("caller %d: got %+v, want %+v", i, o, opts)
+		}
+	}
+}
+
+func mustParseURL(t *testing.T, rawurl string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func TestCloneOptions_partialClone(t *testing.T) {
+	remote := tmpDir(t)
+	runCmd(t, remote, "git", "init", ".")
+	runCmd(t, remote, "git", "config", "uploadpack.allowFilter", "true")
+	runCmd(t, remote, "git", "config", "uploadpack.allowAnySHA1InWant", "true")
+	writeFile(t, filepath.Join(remote, "small.txt"), []byte("small\n"))
+	writeFile(t, filepath.Join(remote, "big.txt"), bytes.Repeat([]byte("big\n"), 1024))
+	runCmd(t, remote, "git", "add", ".")
+	runCmd(t, remote, "git", "commit", "-m", "hello")
+	runCmd(t, remote, "git", "update-ref", "refs/pull/1/head", "HEAD")
+	remoteURL := "file://" + remote
+
+	s := &Server{ReposDir: tmpDir(t)}
+	h := s.Handler()
+	repo := api.RepoName("example.com/foo/bar")
+	dir := s.dir(repo)
+
+	ctx := context.Background()
+	opts := &schema.GitCloneOptions{BlobSizeLimit: "1k", BranchesAndTagsOnly: true}
+	cmd, err := cloneOptionsCloneCmd(ctx, remoteURL, string(dir), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("clone failed: %s\n%s", err, out)
+	}
+
+	if !isPartialClone(dir) {
+		t.Fatal("expected a partial clone")
+	}
+	if refs := runCmd(t, string(dir), "git", "show-ref"); strings.Contains(refs, "refs/pull/") {
+		t.Errorf("expected only branches and tags to be cloned, got refs:\n%s", refs)
+	}
+	missing := runCmd(t, string(dir), "git", "rev-list", "--objects", "--missing=print", "HEAD")
+	if n := strings.Count(missing, "\n?"); n != 1 {
+		t.Errorf("got %d missing objects, want the big blob to be missing:\n%s", n, missing)
+	}
+
+	body, _ := json.Marshal(protocol.RepoInfoRequest{Repos: []api.RepoName{repo}})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/repos", bytes.NewReader(body)))
+	var info protocol.RepoInfoResponse
+	if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Results[repo]; got == nil || !got.PartialClone || got.DiskUsage == 0 {
+		t.Errorf("got repo info %+v, want a partial clone with its disk usage", got)
+	}
+
+	// The big blob is fetched when it is needed.
+	body, _ = json.Marshal(protocol.ExecRequest{Repo: repo, Args: []string{"show", "HEAD:big.txt"}})
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/exec", bytes.NewReader(body)))
+	if rec.Code != http.StatusOK || rec.Result().Trailer.Get("X-Exec-Exit-Status") != "0" {
+		t.Fatalf("show failed with status %d, trailers %v: %s", rec.Code, rec.Result().Trailer, rec.Body.String())
+	}
+	if rec.Body.Len() != 4*1024 {
+		t.Errorf("got %d bytes of big.txt, want %d", rec.Body.Len(), 4*1024)
+	}
+
+	// Fetches are filtered too.
+	writeFile(t, filepath.Join(remote, "big2.txt"), bytes.Repeat([]byte("big2\n"), 1024))
+	runCmd(t, remote, "git", "add", ".")
+	runCmd(t, remote, "git", "commit", "-m", "bigger")
+	runCmd(t, remote, "git", "update-ref", "refs/pull/2/head", "HEAD")
+
+	cmd = cloneOptionsFetchCmd(ctx, dir, remoteURL, opts)
+	if cmd == nil {
+		t.Fatal("expected a fetch command")
+	}
+	dir.Set(cmd)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("fetch failed: %s\n%s", err, out)
+	}
+	if got, want := runCmd(t, string(dir), "git", "rev-parse", "HEAD"), runCmd(t, remote, "git", "rev-parse", "HEAD"); got != want {
+		t.Errorf("got HEAD %s after fetch, want %s", got, want)
+	}
+	if refs := runCmd(t, string(dir), "git", "show-ref"); strings.Contains(refs, "refs/pull/") {
+		t.Errorf("expected only branches and tags to be fetched, got refs:\n%s", refs)
+	}
+	missing = runCmd(t, string(dir), "git", "rev-list", "--objects", "--missing=print", "HEAD")
+	if !strings.Contains(missing, "\n?") {
+		t.Errorf("expected the new big blob to be missing:\n%s", missing)
+	}
+
+	// Repos without clone options use the default fetch command.
+	full := GitDir(tmpDir(t))
+	runCmd(t, string(full), "git", "init", "--bare", ".")
+	if cmd := cloneOptionsFetchCmd(ctx, full, remoteURL, nil); cmd != nil {
+		t.Errorf("got fetch command %v, want the default", cmd.Args)
+	}
+}
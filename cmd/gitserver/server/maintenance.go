@@ -0,0 +1,240 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"math/bits"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// repoMaintenanceInterval is how often git maintenance runs on a repo.
+	repoMaintenanceInterval = 24 * time.Hour
+
+	// maxReposPerMaintenance is the maximum number of repos maintained by a
+	// single run of Maintenance. The stalest and largest repos go first.
+	maxReposPerMaintenance = 50
+)
+
+var (
+	maintenanceTasks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_maintenance_tasks_total",
+		Help: "number of git maintenance tasks run on repos",
+	}, []string{"task", "status"})
+	maintenanceTaskDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "src_gitserver_maintenance_task_duration_seconds",
+		Help:    "duration of git maintenance tasks run on repos",
+		Buckets: []float64{0.1, 1, 10, 60, 300, 900, 1800, 3600},
+	}, []string{"task"})
+	maintenanceReposDue = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_maintenance_repos_due",
+		Help: "number of repos due for git maintenance at the start of the last run",
+	})
+)
+
+// maintenanceCandidate is a repo due for git maintenance.
+type maintenanceCandidate struct {
+	dir       GitDir
+	staleness time.Duration // time since the last maintenance, or clone
+	size      int64         // size of the repo's objects in bytes
+}
+
+// priority orders candidates by staleness and, on a log scale, size, so that
+// large repos that haven't been maintained in a while go first.
+func (c maintenanceCandidate) priority() float64 {
+	return c.staleness.Hours() * float64(1+bits.Len64(uint64(c.size>>20)))
+}
+
+// Maintenance runs git maintenance on the repos in s.ReposDir that are due
+// for it, at most s.MaintenanceConcurrency at a time:
+//
+//  1. git gc --auto packs loose objects and refs, and prunes garbage. It runs
+//     in the foreground, so that it is bounded by the task timeout and the
+//     concurrency limit, and doesn't race the repack.
+//  2. git repack -adb packs all objects into a single pack with a bitmap
+//     index, which speeds up fetches and commands that walk many objects.
+//  3. git commit-graph write --reachable speeds up commands that walk the
+//     commit history, such as git log for commit and diff searches.
+//
+// Repos are picked by staleness and size. A repo is due once a day.
+func (s *Server) Maintenance() {
+	ctx, cancel := s.serverContext()
+	defer cancel()
+
+	candidates, err := s.maintenanceCandidates()
+	if err != nil {
+		log15.Error("maintenance: error iterating over repositories", "error", err)
+	}
+	maintenanceReposDue.Set(float64(len(candidates)))
+	if len(candidates) > maxReposPerMaintenance {
+		candidates = candidates[:maxReposPerMaintenance]
+	}
+
+	concurrency := s.MaintenanceConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, c := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(dir GitDir) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := s.maintainRepo(ctx, dir); err != nil {
+				log15.Error("maintenance: failed to maintain repo", "repo", s.name(dir), "error", err)
+			}
+		}(c.dir)
+	}
+	wg.Wait()
+}
+
+// maintenanceCandidates returns the repos due for maintenance, ordered by
+// priority.
+func (s *Server) maintenanceCandidates() ([]maintenanceCandidate, error) {
+	var candidates []maintenanceCandidate
+	err := bestEffortWalk(s.ReposDir, func(dir string, fi os.FileInfo) error {
+		if s.ignorePath(dir) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Look for $GIT_DIR
+		if !fi.IsDir() || fi.Name() != ".git" {
+			return nil
+		}
+		gitDir := GitDir(dir)
+
+		last, err := lastMaintenanceTime(gitDir)
+		if err != nil {
+			log15.Warn("maintenance: failed to get last maintenance time", "repo", gitDir, "error", err)
+			return filepath.SkipDir
+		}
+		staleness := time.Since(last)
+		if staleness < repoMaintenanceInterval+jitterDuration(dir, repoMaintenanceInterval/4) {
+			return filepath.SkipDir
+		}
+
+		size, err := dirSize(gitDir.Path("objects"))
+		if err != nil {
+			log15.Warn("maintenance: failed to get repo size", "repo", gitDir, "error", err)
+		}
+		candidates = append(candidates, maintenanceCandidate{dir: gitDir, staleness: staleness, size: size})
+		return filepath.SkipDir
+	})
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].priority() > candidates[j].priority()
+	})
+	return candidates, err
+}
+
+// maintainRepo runs the git maintenance tasks on the repo in dir, unless it
+// is being cloned.
+func (s *Server) maintainRepo(ctx context.Context, dir GitDir) error {
+	if _, cloning := s.locker.Status(dir); cloning {
+		return nil
+	}
+
+	if err := runMaintenanceTask(ctx, dir, "gc", "-c", "gc.autoDetach=false", "gc", "--auto"); err != nil {
+		return err
+	}
+
+	if needsRepack(dir) {
+		args := []string{"repack", "-a", "-d", "-b"}
+		if isPartialClone(dir) {
+			// Bitmaps aren't written for partial clones, since they don't
+			// have all of the objects.
+			args = args[:3]
+		}
+		if err := runMaintenanceTask(ctx, dir, "repack", args...); err != nil {
+			return err
+		}
+	}
+
+	if err := runMaintenanceTask(ctx, dir, "commit-graph", "commit-graph", "write", "--reachable"); err != nil {
+		return err
+	}
+
+	return setLastMaintenanceTime(dir, time.Now())
+}
+
+func runMaintenanceTask(ctx context.Context, dir GitDir, task string, args ...string) error {
+	ctx, cancel := context.WithTimeout(ctx, longGitCommandTimeout)
+	defer cancel()
+
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	dir.Set(cmd)
+	output, err := cmd.CombinedOutput()
+	maintenanceTaskDuration.WithLabelValues(task).Observe(time.Since(start).Seconds())
+	if err != nil {
+		maintenanceTasks.WithLabelValues(task, "failed").Inc()
+		return errors.Wrapf(wrapCmdError(cmd, err), "git %s failed. Output: %s", task, string(output))
+	}
+	maintenanceTasks.WithLabelValues(task, "succeeded").Inc()
+	return nil
+}
+
+// needsRepack reports whether the objects of the repo in dir are spread over
+// more than one pack, or aren't indexed by a bitmap.
+func needsRepack(dir GitDir) bool {
+	infos, err := ioutil.ReadDir(dir.Path("objects", "pack"))
+	if err != nil {
+		return true
+	}
+	var packs, bitmaps int
+	for _, fi := range infos {
+		switch filepath.Ext(fi.Name()) {
+		case ".pack":
+			packs++
+		case ".bitmap":
+			bitmaps++
+		}
+	}
+	return packs > 1 || bitmaps == 0
+}
+
+// lastMaintenanceTime returns the time git maintenance last ran on the repo
+// in dir. Repos that were never maintained count from when they were cloned,
+// since a clone is fully packed.
+func lastMaintenanceTime(dir GitDir) (time.Time, error) {
+	value, err := gitConfigGet(dir, "sourcegraph.maintenanceTimestamp")
+	if err != nil {
+		return time.Unix(0, 0), errors.Wrap(err, "failed to determine maintenance timestamp")
+	}
+	if sec, err := strconv.ParseInt(strings.TrimSpace(value), 10, 0); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	return getRecloneTime(dir)
+}
+
+func setLastMaintenanceTime(dir GitDir, now time.Time) error {
+	err := gitConfigSet(dir, "sourcegraph.maintenanceTimestamp", strconv.FormatInt(now.Unix(), 10))
+	if err != nil {
+		return errors.Wrap(err, "failed to update maintenanceTimestamp")
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMaintainRepo(t *testing.T) {
+	remote := tmpDir(t)
+	runCmd(t, remote, "git", "init", ".")
+	writeFile(t, filepath.Join(remote, "a.txt"), []byte("a\n"))
+	runCmd(t, remote, "git", "add", ".")
+	runCmd(t, remote, "git", "commit", "-m", "a")
+
+	s := &Server{ReposDir: tmpDir(t)}
+	s.Handler() // Handler sets up the locker.
+	dir := GitDir(filepath.Join(s.ReposDir, "example.com/foo/bar/.git"))
+	runCmd(t, s.ReposDir, "git", "clone", "--mirror", remote, string(dir))
+
+	// A fetch adds a second pack.
+	writeFile(t, filepath.Join(remote, "b.txt"), []byte("b\n"))
+	runCmd(t, remote, "git", "add", ".")
+	runCmd(t, remote, "git", "commit", "-m", "b")
+	runCmd(t, string(dir), "git", "-c", "fetch.unpackLimit=1", "fetch", remote, "+refs/heads/*:refs/heads/*")
+	if !needsRepack(dir) {
+		t.Fatal("expected the repo to need a repack")
+	}
+
+	if err := s.maintainRepo(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+
+	if needsRepack(dir) {
+		t.Error("expected the repo to be repacked into a single pack with a bitmap")
+	}
+	if _, err := os.Stat(dir.Path("objects", "info", "commit-graph")); err != nil {
+		t.Errorf("expected a commit-graph: %s", err)
+	}
+	last, err := lastMaintenanceTime(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(last) > time.Minute {
+		t.Errorf("got last maintenance time %s, want now", last)
+	}
+}
+
+func TestMaintainRepo_autoGC(t *testing.T) {
+	remote := tmpDir(t)
+	runCmd(t, remote, "git", "init", ".")
+	writeFile(t, filepath.Join(remote, "a.txt"), []byte("a\n"))
+	runCmd(t, remote, "git", "add", ".")
+	runCmd(t, remote, "git", "commit", "-m", "a")
+
+	s := &Server{ReposDir: tmpDir(t)}
+	s.Handler() // Handler sets up the locker.
+	dir := GitDir(filepath.Join(s.ReposDir, "example.com/foo/bar/.git"))
+	runCmd(t, s.ReposDir, "git", "clone", "--mirror", remote, string(dir))
+	if err := s.maintainRepo(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fetch enough loose objects and a loose ref to trigger auto gc, without
+	// adding a pack, so that only gc can pack them.
+	if err := os.MkdirAll(filepath.Join(remote, "files"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2000; i++ {
+		writeFile(t, filepath.Join(remote, "files", strconv.Itoa(i)), []byte(strconv.Itoa(i)+"\n"))
+	}
+	runCmd(t, remote, "git", "add", ".")
+	runCmd(t, remote, "git", "commit", "-m", "b")
+	runCmd(t, remote, "git", "branch", "b")
+	runCmd(t, string(dir), "git", "-c", "fetch.unpackLimit=100000", "fetch", remote, "+refs/heads/*:refs/heads/*")
+	runCmd(t, string(dir), "git", "config", "gc.auto", "256")
+	// git gc --auto estimates the number of loose objects from the objects/17
+	// directory, and needs more than gc.auto/256 objects in it.
+	if objects, _ := ioutil.ReadDir(dir.Path("objects", "17")); len(objects) < 2 {
+		t.Fatalf("got %d loose objects in objects/17, want enough to trigger auto gc", len(objects))
+	}
+	if needsRepack(dir) {
+		t.Fatal("expected the repo not to need a repack")
+	}
+
+	if err := s.maintainRepo(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Auto gc packs refs, which repack doesn't, and must have finished.
+	if _, err := os.Stat(dir.Path("refs", "heads", "b")); !os.IsNotExist(err) {
+		t.Errorf("expected auto gc to have packed the loose ref refs/heads/b: %v", err)
+	}
+	if _, err := os.Stat(dir.Path("gc.pid")); !os.IsNotExist(err) {
+		t.Errorf("expected auto gc to have finished: %v", err)
+	}
+	if objects, _ := ioutil.ReadDir(dir.Path("objects", "17")); len(objects) != 0 {
+		t.Errorf("got %d loose objects in objects/17, want 0", len(objects))
+	}
+}
+
+func TestMaintenanceCandidates(t *testing.T) {
+	s := &Server{ReposDir: tmpDir(t)}
+
+	now := time.Now()
+	for name, last := range map[string]time.Time{
+		"fresh":  now.Add(-time.Hour),
+		"stale":  now.Add(-3 * repoMaintenanceInterval),
+		"staler": now.Add(-5 * repoMaintenanceInterval),
+	} {
+		dir := filepath.Join(s.ReposDir, "example.com", name, ".git")
+		runCmd(t, s.ReposDir, "git", "init", "--bare", dir)
+		if err := setLastMaintenanceTime(GitDir(dir), last); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	candidates, err := s.maintenanceCandidates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range candidates {
+		got = append(got, filepath.Base(filepath.Dir(string(c.dir))))
+	}
+	if want := []string{"staler", "stale"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got candidates %v, want %v", got, want)
+	}
+}
+
+func TestMaintenanceCandidate_priority(t *testing.T) {
+	small := maintenanceCandidate{staleness: 48 * time.Hour, size: 1 << 10}
+	large := maintenanceCandidate{staleness: 48 * time.Hour, size: 1 << 30}
+	stale := maintenanceCandidate{staleness: 30 * 24 * time.Hour, size: 1 << 10}
+	if small.priority() >= large.priority() {
+		t.Errorf("expected a large repo to go before a small one")
+	}
+	if large.priority() >= stale.priority() {
+		t.Errorf("expected a much staler repo to go before a large one")
+	}
+}
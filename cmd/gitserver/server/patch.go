@@ -261,6 +261,10 @@ func (s *Server) createCommitFromPatch(ctx context.Context, req protocol.CreateC
 		return http.StatusInternalServerError, resp
 	}
 
+	// Reads of the repo may be sent to its replicas, which don't fetch the ref
+	// from the code host.
+	s.updateReplicaRefs(ctx, protocol.NormalizeRepo(req.Repo), ref, cmtHash)
+
 	return http.StatusOK, resp
 }
 
@@ -47,6 +47,14 @@ func (s *Server) repoInfo(ctx context.Context, repo api.RepoName) (*protocol.Rep
 		} else {
 			resp.LastChanged = &lastChanged
 		}
+
+		if size, err := dirSize(string(dir)); err != nil {
+			log15.Warn("error computing disk usage", "repo", repo, "err", err)
+		} else {
+			resp.DiskUsage = size
+		}
+
+		resp.PartialClone = isPartialClone(dir)
 	}
 	return &resp, nil
 }
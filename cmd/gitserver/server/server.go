@@ -116,6 +116,15 @@ type Server struct {
 	// DiskSizer tells how much disk is free and how large the disk is.
 	DiskSizer DiskSizer
 
+	// Hostname is the hostname of this gitserver, which identifies its
+	// address among the addresses of all gitservers. If it matches none of
+	// them, repos are neither cloned from nor removed for other gitservers.
+	Hostname string
+
+	// MaintenanceConcurrency is the maximum number of repos that Maintenance
+	// runs git maintenance on at the same time. It defaults to 1.
+	MaintenanceConcurrency int
+
 	// skipCloneForTests is set by tests to avoid clones.
 	skipCloneForTests bool
 
@@ -235,6 +244,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-update", s.handleRepoUpdate)
 	mux.HandleFunc("/getGitolitePhabricatorMetadata", s.handleGetGitolitePhabricatorMetadata)
 	mux.HandleFunc("/create-commit-from-patch", s.handleCreateCommitFromPatch)
+	mux.HandleFunc("/replica-ref-update", s.handleReplicaRefUpdate)
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -693,6 +703,11 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	cmdStart = time.Now()
 	cmd := exec.CommandContext(ctx, "git", req.Args...)
 	dir.Set(cmd)
+	if isPartialClone(dir) {
+		// git fetches the blobs left out of a partial clone from the code
+		// host when the command needs them, within the command's timeout.
+		configureRemoteGitCommand(cmd, tlsExternal().(*tlsConfig))
+	}
 	cmd.Stdout = stdoutW
 	cmd.Stderr = stderrW
 
@@ -826,25 +841,54 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		tmpPath = filepath.Join(tmpPath, ".git")
 		tmp := GitDir(tmpPath)
 
-		var cmd *exec.Cmd
-		if useRefspecOverrides() {
-			cmd, err = refspecOverridesCloneCmd(ctx, url, tmpPath)
-			if err != nil {
-				return err
+		// Clone from the gitserver that previously stored the repo, if any,
+		// rather than from the code host. Repos with clone options are
+		// cloned from the code host, since the repo on the other gitserver
+		// may be a partial clone.
+		cloneOpts := gitCloneOptions(ctx, url)
+		var peerURLs []string
+		if cloneOpts == nil {
+			peerURLs = s.peerCloneURLs(repo)
+		}
+		clonedFromPeer := false
+		for _, peerURL := range peerURLs {
+			lock.SetStatus("cloning from " + peerURL)
+			if err := cloneFromPeer(ctx, peerURL, url, tmpPath); err != nil {
+				log15.Debug("failed to clone repo from peer", "repo", repo, "peer", peerURL, "error", err)
+				continue
 			}
-		} else {
-			cmd = exec.CommandContext(ctx, "git", "clone", "--mirror", "--progress", url, tmpPath)
+			log15.Info("cloned repo from peer", "repo", repo, "peer", peerURL)
+			reposClonedFromPeer.Inc()
+			clonedFromPeer = true
+			break
 		}
-		// see issue #7322: skip LFS content in repositories with Git LFS configured
-		cmd.Env = append(os.Environ(), "GIT_LFS_SKIP_SMUDGE=1")
-		log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath)
 
-		pr, pw := io.Pipe()
-		defer pw.Close()
-		go readCloneProgress(redactor, lock, pr)
+		if !clonedFromPeer {
+			var cmd *exec.Cmd
+			if useRefspecOverrides() {
+				cmd, err = refspecOverridesCloneCmd(ctx, url, tmpPath)
+				if err != nil {
+					return err
+				}
+			} else if cloneOpts != nil {
+				cmd, err = cloneOptionsCloneCmd(ctx, url, tmpPath, cloneOpts)
+				if err != nil {
+					return err
+				}
+			} else {
+				cmd = exec.CommandContext(ctx, "git", "clone", "--mirror", "--progress", url, tmpPath)
+			}
+			// see issue #7322: skip LFS content in repositories with Git LFS configured
+			cmd.Env = append(os.Environ(), "GIT_LFS_SKIP_SMUDGE=1")
+			log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath)
+
+			pr, pw := io.Pipe()
+			defer pw.Close()
+			go readCloneProgress(redactor, lock, pr)
 
-		if output, err := runWithRemoteOpts(ctx, cmd, pw); err != nil {
-			return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+			if output, err := runWithRemoteOpts(ctx, cmd, pw); err != nil {
+				return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+			}
 		}
 
 		removeBadRefs(ctx, tmp)
@@ -1091,6 +1135,15 @@ func (s *Server) doRepoUpdate(ctx context.Context, repo api.RepoName, url string
 			s.repoUpdateLocksMu.Unlock()
 
 			err = s.doRepoUpdate2(repo, url)
+			if err == nil {
+				go func() {
+					ctx, cancel1 := s.serverContext()
+					defer cancel1()
+					ctx, cancel2 := context.WithTimeout(ctx, longGitCommandTimeout)
+					defer cancel2()
+					s.updateReplicas(ctx, repo, url)
+				}()
+			}
 		})
 	}()
 
@@ -1263,6 +1316,20 @@ func computeRefHash(dir GitDir) ([]byte, error) {
 	return hash, nil
 }
 
+// defaultRefspecs are the refspecs fetched when updating a repo.
+var defaultRefspecs = []string{
+	// Normal git refs
+	"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
+	// GitHub pull requests
+	"+refs/pull/*:refs/pull/*",
+	// GitLab merge requests
+	"+refs/merge-requests/*:refs/merge-requests/*",
+	// Bitbucket pull requests
+	"+refs/pull-requests/*:refs/pull-requests/*",
+	// Possibly deprecated refs for sourcegraph zap experiment?
+	"+refs/sourcegraph/*:refs/sourcegraph/*",
+}
+
 func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 	// background context.
 	ctx, cancel1 := s.serverContext()
@@ -1318,18 +1385,10 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		configRemoteOpts = false
 	} else if useRefspecOverrides() {
 		cmd = refspecOverridesFetchCmd(ctx, url)
+	} else if optsCmd := cloneOptionsFetchCmd(ctx, dir, url, gitCloneOptions(ctx, url)); optsCmd != nil {
+		cmd = optsCmd
 	} else {
-		cmd = exec.CommandContext(ctx, "git", "fetch", "--prune", url,
-			// Normal git refs
-			"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*",
-			// GitHub pull requests
-			"+refs/pull/*:refs/pull/*",
-			// GitLab merge requests
-			"+refs/merge-requests/*:refs/merge-requests/*",
-			// Bitbucket pull requests
-			"+refs/pull-requests/*:refs/pull-requests/*",
-			// Possibly deprecated refs for sourcegraph zap experiment?
-			"+refs/sourcegraph/*:refs/sourcegraph/*")
+		cmd = exec.CommandContext(ctx, "git", append([]string{"fetch", "--prune", url}, defaultRefspecs...)...)
 	}
 	dir.Set(cmd)
 
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+var (
+	reposClonedFromPeer = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_repos_cloned_from_peer",
+		Help: "number of repos cloned from the gitserver that previously stored them",
+	})
+	reposRemovedWrongShard = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_repos_removed_wrong_shard",
+		Help: "number of repos removed because they were cloned by the gitserver they are now assigned to",
+	})
+	replicaRepoUpdates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_replica_repo_updates_total",
+		Help: "number of repo updates forwarded to the gitservers storing replicas of the repo",
+	}, []string{"status"})
+	replicaRefUpdates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_replica_ref_updates_total",
+		Help: "number of refs created by this gitserver forwarded to the gitservers storing replicas of the repo",
+	}, []string{"status"})
+)
+
+// gitserverAddrs returns the addresses of all gitservers.
+var gitserverAddrs = func() []string {
+	return conf.Get().ServiceConnections.GitServers
+}
+
+// replicationFactor returns the number of gitservers that store each repo.
+var replicationFactor = func() int {
+	return conf.Get().GitserverReplicationFactor
+}
+
+// requestReplicaRepoUpdate requests an update of the replica of the repo on
+// the gitserver at addr.
+var requestReplicaRepoUpdate = func(ctx context.Context, addr string, repo gitserver.Repo) error {
+	resp, err := gitserver.DefaultClient.RequestReplicaRepoUpdate(ctx, addr, repo, 0)
+	if err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// requestReplicaRefUpdate requests the gitserver at addr to set a ref of its
+// replica of the repo.
+var requestReplicaRefUpdate = func(ctx context.Context, addr string, req protocol.ReplicaRefUpdateRequest) error {
+	return gitserver.DefaultClient.RequestReplicaRefUpdate(ctx, addr, req)
+}
+
+// isRepoClonedOnOwner reports whether the repo is cloned on the gitserver it
+// is assigned to. It asks that gitserver directly, as reads of a repo may be
+// sent to the gitserver that stored it before until it is cloned there.
+var isRepoClonedOnOwner = func(ctx context.Context, repo api.RepoName) (bool, error) {
+	return gitserver.DefaultClient.IsRepoClonedOn(ctx, gitserver.DefaultClient.AddrForRepo(ctx, repo), repo)
+}
+
+// hostnameMatch reports whether addr is the address of this gitserver, e.g.
+// "gitserver-0.gitserver:3178" or "gitserver-0:3178" for the hostname
+// "gitserver-0".
+func (s *Server) hostnameMatch(addr string) bool {
+	if s.Hostname == "" {
+		return false
+	}
+	return addr == s.Hostname ||
+		strings.HasPrefix(addr, s.Hostname+".") ||
+		strings.HasPrefix(addr, s.Hostname+":")
+}
+
+// addr returns the address of this gitserver among addrs, or "" if it isn't
+// one of them.
+func (s *Server) addr(addrs []string) string {
+	for _, addr := range addrs {
+		if s.hostnameMatch(addr) {
+			return addr
+		}
+	}
+	return ""
+}
+
+// repoReplicas returns the addresses of the gitservers that store the repo,
+// starting with the one it is assigned to, and the address of this gitserver.
+// self is "" if the address of this gitserver is unknown, in which case repos
+// are neither cloned from nor left to other gitservers.
+func (s *Server) repoReplicas(repo api.RepoName) (replicas []string, self string) {
+	addrs := gitserverAddrs()
+	self = s.addr(addrs)
+	if self == "" {
+		return nil, ""
+	}
+	return gitserver.AddrsForRepo(repo, addrs, replicationFactor()), self
+}
+
+// peerCloneURLs returns the URLs of the repo on the gitservers that may have
+// stored it before it was assigned to this gitserver, and on the other
+// gitservers storing replicas of it. They are served by the /git/ handler of
+// those gitservers.
+func (s *Server) peerCloneURLs(repo api.RepoName) []string {
+	replicas, self := s.repoReplicas(repo)
+	if !contains(replicas, self) {
+		return nil
+	}
+
+	var peers []string
+	if replicas[0] == self {
+		peers = gitserver.PreviousAddrsForRepo(repo, gitserverAddrs(), self)
+	}
+	for _, addr := range replicas {
+		if addr != self && !contains(peers, addr) {
+			peers = append(peers, addr)
+		}
+	}
+
+	var urls []string
+	for _, peer := range peers {
+		urls = append(urls, peerURL(peer, repo))
+	}
+	return urls
+}
+
+// peerURL returns the URL of the repo on the gitserver at addr.
+func peerURL(addr string, repo api.RepoName) string {
+	return "http://" + addr + "/git/" + string(protocol.NormalizeRepo(repo))
+}
+
+func contains(addrs []string, addr string) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// updateReplicas forwards an update of the repo to the gitservers storing its
+// replicas, if this is the gitserver it is assigned to. Replicas that aren't
+// cloned yet are cloned, preferably from this gitserver.
+func (s *Server) updateReplicas(ctx context.Context, repo api.RepoName, url string) {
+	s.forwardToReplicas(repo, replicaRepoUpdates, func(addr, _ string) error {
+		return requestReplicaRepoUpdate(ctx, addr, gitserver.Repo{Name: repo, URL: url})
+	})
+}
+
+// updateReplicaRefs forwards a ref that this gitserver set to commit, and
+// which isn't fetched from the code host, to the gitservers storing replicas
+// of the repo, if this is the gitserver it is assigned to. Reads of the repo
+// may be sent to the replicas.
+func (s *Server) updateReplicaRefs(ctx context.Context, repo api.RepoName, ref, commit string) {
+	s.forwardToReplicas(repo, replicaRefUpdates, func(addr, self string) error {
+		return requestReplicaRefUpdate(ctx, addr, protocol.ReplicaRefUpdateRequest{
+			Repo:   repo,
+			Ref:    ref,
+			Commit: commit,
+			Peer:   self,
+		})
+	})
+}
+
+// forwardToReplicas calls forward concurrently with the address of each
+// gitserver storing a replica of the repo and the address of this gitserver,
+// if this is the gitserver it is assigned to, and waits for them to return.
+func (s *Server) forwardToReplicas(repo api.RepoName, counter *prometheus.CounterVec, forward func(addr, self string) error) {
+	replicas, self := s.repoReplicas(repo)
+	if len(replicas) < 2 || replicas[0] != self {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, addr := range replicas[1:] {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			if err := forward(addr, self); err != nil {
+				log15.Warn("failed to update replica", "repo", repo, "gitserver", addr, "err", err)
+				counter.WithLabelValues("failed").Inc()
+				return
+			}
+			counter.WithLabelValues("succeeded").Inc()
+		}(addr)
+	}
+	wg.Wait()
+}
+
+// handleReplicaRefUpdate sets a ref of the replica of a repo, fetching the
+// commit from the gitserver that forwarded it.
+func (s *Server) handleReplicaRefUpdate(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ReplicaRefUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// A replica that isn't cloned yet gets the ref when it is cloned from the
+	// peer.
+	dir := s.dir(protocol.NormalizeRepo(req.Repo))
+	if !repoCloned(dir) {
+		return
+	}
+
+	if err := updateRefFromPeer(r.Context(), dir, req); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// updateRefFromPeer fetches req.Ref from the repo on the gitserver at
+// req.Peer into dir, and sets it to req.Commit.
+func updateRefFromPeer(ctx context.Context, dir GitDir, req protocol.ReplicaRefUpdateRequest) error {
+	cmd := exec.CommandContext(ctx, "git", "fetch", "--no-tags", "--", peerURL(req.Peer, req.Repo), req.Ref)
+	dir.Set(cmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "fetch failed. Output: %s", string(output))
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "update-ref", "--", req.Ref, req.Commit)
+	dir.Set(cmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "failed to update ref. Output: %s", string(output))
+	}
+	return nil
+}
+
+// cloneFromPeer mirrors the repo at peerURL, the URL of the repo on another
+// gitserver, into tmpPath. The origin remote of the clone is set to the code
+// host URL, so that it is updated from the code host afterwards.
+func cloneFromPeer(ctx context.Context, peerURL, url, tmpPath string) error {
+	cmd := exec.CommandContext(ctx, "git", "clone", "--mirror", peerURL, tmpPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		// Leave tmpPath empty for a clone from the code host.
+		os.RemoveAll(tmpPath)
+		return errors.Wrapf(err, "clone failed. Output: %s", string(output))
+	}
+
+	cmd = exec.CommandContext(ctx, "git", "remote", "set-url", "origin", "--", url)
+	GitDir(tmpPath).Set(cmd)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.RemoveAll(tmpPath)
+		return errors.Wrapf(err, "failed to set remote URL. Output: %s", string(output))
+	}
+	return nil
+}
+
+// maybeRemoveWrongShard removes the repo in dir if it is assigned to another
+// gitserver which has cloned it, and this gitserver doesn't store a replica of
+// it. Until then, the other gitserver can clone it from this one.
+func (s *Server) maybeRemoveWrongShard(ctx context.Context, dir GitDir) (done bool, err error) {
+	repo := s.name(dir)
+	replicas, self := s.repoReplicas(repo)
+	if self == "" || contains(replicas, self) {
+		return false, nil
+	}
+	owner := replicas[0]
+
+	cloned, err := isRepoClonedOnOwner(ctx, repo)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check if repo is cloned on its gitserver")
+	}
+	if !cloned {
+		return false, nil
+	}
+
+	log15.Info("removing repo cloned on the gitserver it is assigned to", "repo", repo, "gitserver", owner)
+	if err := s.removeRepoDirectory(dir); err != nil {
+		return true, err
+	}
+	reposRemovedWrongShard.Inc()
+	return true, nil
+}
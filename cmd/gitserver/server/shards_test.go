@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+)
+
+func TestServer_hostnameMatch(t *testing.T) {
+	s := &Server{Hostname: "gitserver-1"}
+	for addr, want := range map[string]bool{
+		"gitserver-1":                    true,
+		"gitserver-1:3178":               true,
+		"gitserver-1.gitserver:3178":     true,
+		"gitserver-10:3178":              false,
+		"gitserver-10.gitserver:3178":    false,
+		"gitserver-0.gitserver-1:3178":   false,
+		"gitserver-1-canary.gitserver:1": false,
+	} {
+		if got := s.hostnameMatch(addr); got != want {
+			t.Errorf("hostnameMatch(%q) = %t, want %t", addr, got, want)
+		}
+	}
+
+	if (&Server{}).hostnameMatch("") {
+		t.Error("an empty hostname matches the empty address")
+	}
+}
+
+// repoAssignedTo returns the name of a repo that is assigned to addr.
+func repoAssignedTo(t *testing.T, addrs []string, addr string) api.RepoName {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		repo := api.RepoName(fmt.Sprintf("example.com/foo/repo%d", i))
+		if gitserver.AddrForRepo(repo, addrs) == addr {
+			return repo
+		}
+	}
+	t.Fatalf("no repo is assigned to %s", addr)
+	return ""
+}
+
+func mockGitserverAddrs(t *testing.T, addrs []string) {
+	orig := gitserverAddrs
+	gitserverAddrs = func() []string { return addrs }
+	t.Cleanup(func() { gitserverAddrs = orig })
+}
+
+func TestCloneRepo_fromPeer(t *testing.T) {
+	remote := tmpDir(t)
+	runCmd(t, remote, "git", "init", ".")
+	runCmd(t, remote, "git", "commit", "--allow-empty", "-m", "hello")
+
+	// The peer stores the repo with a branch that isn't on the code host, so
+	// that we can tell where the repo was cloned from.
+	peer := &Server{ReposDir: tmpDir(t)}
+	srv := httptest.NewServer(peer.Handler())
+	defer srv.Close()
+	peerAddr := strings.TrimPrefix(srv.URL, "http://")
+
+	addrs := []string{peerAddr, "gitserver-new:3178"}
+	mockGitserverAddrs(t, addrs)
+	repo := repoAssignedTo(t, addrs, "gitserver-new:3178")
+
+	peerDir := peer.dir(repo)
+	runCmd(t, remote, "git", "clone", "--mirror", remote, string(peerDir))
+	runCmd(t, string(peerDir), "git", "branch", "peer-only", "HEAD")
+
+	s := &Server{
+		ReposDir:         tmpDir(t),
+		Hostname:         "gitserver-new",
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+	}
+	if _, err := s.cloneRepo(context.Background(), repo, remote, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Dir(string(s.dir(repo)))
+	runCmd(t, dir, "git", "rev-parse", "--verify", "refs/heads/peer-only")
+	if got := strings.TrimSpace(runCmd(t, dir, "git", "remote", "get-url", "origin")); got != remote {
+		t.Errorf("got origin %q, want the code host URL %q", got, remote)
+	}
+
+	// Repos assigned to the peer are cloned from the code host.
+	other := repoAssignedTo(t, addrs, peerAddr)
+	runCmd(t, remote, "git", "clone", "--mirror", remote, string(peer.dir(other)))
+	runCmd(t, string(peer.dir(other)), "git", "branch", "peer-only", "HEAD")
+	if _, err := s.cloneRepo(context.Background(), other, remote, &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.dir(other).Path("refs", "heads", "peer-only")); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be cloned from the code host: %v", other, err)
+	}
+}
+
+func TestCleanupWrongShard(t *testing.T) {
+	addrs := []string{"gitserver-0.gitserver:3178", "gitserver-1.gitserver:3178"}
+	mockGitserverAddrs(t, addrs)
+
+	owned := repoAssignedTo(t, addrs, addrs[0])
+	moved := repoAssignedTo(t, addrs, addrs[1])
+
+	cloned := false
+	orig := isRepoClonedOnOwner
+	isRepoClonedOnOwner = func(_ context.Context, repo api.RepoName) (bool, error) {
+		if repo != moved {
+			t.Errorf("unexpected check of %s", repo)
+		}
+		return cloned, nil
+	}
+	defer func() { isRepoClonedOnOwner = orig }()
+
+	root := tmpDir(t)
+	for _, repo := range []api.RepoName{owned, moved} {
+		runCmd(t, root, "git", "--bare", "init", filepath.Join(root, string(repo), ".git"))
+	}
+
+	s := &Server{ReposDir: root, Hostname: "gitserver-0"}
+	s.Handler() // Handler as a side-effect sets up Server
+
+	// The repo is kept until its new gitserver has cloned it.
+	s.cleanupRepos()
+	for _, repo := range []api.RepoName{owned, moved} {
+		if _, err := os.Stat(filepath.Join(root, string(repo), ".git", "HEAD")); err != nil {
+			t.Errorf("expected %s to be kept: %v", repo, err)
+		}
+	}
+
+	cloned = true
+	s.cleanupRepos()
+	if _, err := os.Stat(filepath.Join(root, string(moved))); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed: %v", moved, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, string(owned), ".git", "HEAD")); err != nil {
+		t.Errorf("expected %s to be kept: %v", owned, err)
+	}
+
+	// Nothing is removed if this gitserver isn't one of the gitservers.
+	runCmd(t, root, "git", "--bare", "init", filepath.Join(root, string(moved), ".git"))
+	s.Hostname = "gitserver-2"
+	s.cleanupRepos()
+	if _, err := os.Stat(filepath.Join(root, string(moved), ".git", "HEAD")); err != nil {
+		t.Errorf("expected %s to be kept: %v", moved, err)
+	}
+}
+
+func mockReplicationFactor(t *testing.T, n int) {
+	orig := replicationFactor
+	replicationFactor = func() int { return n }
+	t.Cleanup(func() { replicationFactor = orig })
+}
+
+func TestServer_peerCloneURLs_replicas(t *testing.T) {
+	addrs := []string{"gitserver-0:3178", "gitserver-1:3178", "gitserver-2:3178"}
+	mockGitserverAddrs(t, addrs)
+	mockReplicationFactor(t, 2)
+
+	repo := api.RepoName("github.com/foo/bar")
+	replicas := gitserver.AddrsForRepo(repo, addrs, 2)
+	url := func(addr string) string { return "http://" + addr + "/git/" + string(repo) }
+
+	// The replica clones from the gitserver the repo is assigned to.
+	s := &Server{Hostname: strings.TrimSuffix(replicas[1], ":3178")}
+	if got, want := s.peerCloneURLs(repo), []string{url(replicas[0])}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// The gitserver the repo is assigned to can clone from the replica.
+	s.Hostname = strings.TrimSuffix(replicas[0], ":3178")
+	if got := s.peerCloneURLs(repo); !contains(got, url(replicas[1])) {
+		t.Errorf("got %v, want %s included", got, url(replicas[1]))
+	}
+}
+
+func TestServer_updateReplicas(t *testing.T) {
+	addrs := []string{"gitserver-0:3178", "gitserver-1:3178", "gitserver-2:3178"}
+	mockGitserverAddrs(t, addrs)
+	mockReplicationFactor(t, 3)
+
+	repo := api.RepoName("github.com/foo/bar")
+	replicas := gitserver.AddrsForRepo(repo, addrs, 3)
+
+	var (
+		mu      sync.Mutex
+		updated []string
+	)
+	orig := requestReplicaRepoUpdate
+	requestReplicaRepoUpdate = func(_ context.Context, addr string, r gitserver.Repo) error {
+		if r.Name != repo || r.URL != "https://github.com/foo/bar" {
+			t.Errorf("unexpected update of %+v", r)
+		}
+		mu.Lock()
+		updated = append(updated, addr)
+		mu.Unlock()
+		return nil
+	}
+	defer func() { requestReplicaRepoUpdate = orig }()
+
+	// Replicas don't forward updates.
+	s := &Server{Hostname: strings.TrimSuffix(replicas[1], ":3178")}
+	s.updateReplicas(context.Background(), repo, "https://github.com/foo/bar")
+	if len(updated) != 0 {
+		t.Fatalf("replica updated %v", updated)
+	}
+
+	s.Hostname = strings.TrimSuffix(replicas[0], ":3178")
+	s.updateReplicas(context.Background(), repo, "https://github.com/foo/bar")
+	want := []string{replicas[1], replicas[2]}
+	sort.Strings(updated)
+	sort.Strings(want)
+	if !cmp.Equal(updated, want) {
+		t.Errorf("got updates of %v, want %v", updated, want)
+	}
+}
+
+func TestServer_updateReplicaRefs(t *testing.T) {
+	remote := tmpDir(t)
+	runCmd(t, remote, "git", "init", ".")
+	runCmd(t, remote, "git", "commit", "--allow-empty", "-m", "hello")
+
+	primary := &Server{ReposDir: tmpDir(t)}
+	primarySrv := httptest.NewServer(primary.Handler())
+	defer primarySrv.Close()
+	replica := &Server{ReposDir: tmpDir(t)}
+	replicaSrv := httptest.NewServer(replica.Handler())
+	defer replicaSrv.Close()
+
+	addrs := []string{strings.TrimPrefix(primarySrv.URL, "http://"), strings.TrimPrefix(replicaSrv.URL, "http://")}
+	mockGitserverAddrs(t, addrs)
+	mockReplicationFactor(t, 2)
+	primary.Hostname = addrs[0]
+	repo := repoAssignedTo(t, addrs, addrs[0])
+
+	// The primary has a commit that isn't on the code host, like the ones
+	// created from patches.
+	for _, s := range []*Server{primary, replica} {
+		runCmd(t, remote, "git", "clone", "--mirror", remote, string(s.dir(repo)))
+	}
+	primaryDir := string(primary.dir(repo))
+	commit := strings.TrimSpace(runCmd(t, primaryDir, "git", "commit-tree", "-p", "HEAD", "-m", "patch", "HEAD^{tree}"))
+	runCmd(t, primaryDir, "git", "update-ref", "refs/heads/patch", commit)
+
+	primary.updateReplicaRefs(context.Background(), repo, "refs/heads/patch", commit)
+	if got := strings.TrimSpace(runCmd(t, string(replica.dir(repo)), "git", "rev-parse", "refs/heads/patch")); got != commit {
+		t.Errorf("got replica ref at %q, want %q", got, commit)
+	}
+}
+
+func TestCleanupWrongShard_replica(t *testing.T) {
+	addrs := []string{"gitserver-0.gitserver:3178", "gitserver-1.gitserver:3178"}
+	mockGitserverAddrs(t, addrs)
+	mockReplicationFactor(t, 2)
+
+	orig := isRepoClonedOnOwner
+	isRepoClonedOnOwner = func(_ context.Context, repo api.RepoName) (bool, error) {
+		t.Errorf("unexpected check of %s", repo)
+		return true, nil
+	}
+	defer func() { isRepoClonedOnOwner = orig }()
+
+	replica := repoAssignedTo(t, addrs, addrs[1])
+	root := tmpDir(t)
+	runCmd(t, root, "git", "--bare", "init", filepath.Join(root, string(replica), ".git"))
+
+	s := &Server{ReposDir: root, Hostname: "gitserver-0"}
+	s.Handler() // Handler as a side-effect sets up Server
+
+	s.cleanupRepos()
+	if _, err := os.Stat(filepath.Join(root, string(replica), ".git", "HEAD")); err != nil {
+		t.Errorf("expected the replica of %s to be kept: %v", replica, err)
+	}
+}
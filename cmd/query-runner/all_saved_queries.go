@@ -14,16 +14,22 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
+// savedQueryChange describes the old and new value of a saved query that was
+// created, updated or deleted.
+type savedQueryChange struct {
+	oldValue, newValue api.SavedQuerySpecAndConfig
+}
+
 // diffSavedQueryConfigs takes the old and new saved queries configurations.
 //
-// It returns maps whose keys represent the old value and value represent the
-// new value, i.e. a map of the saved query in the oldList and what its new
-// value is in the newList for each respective category. For deleted, the new
-// value will be an empty struct.
-func diffSavedQueryConfigs(oldList, newList map[api.SavedQueryIDSpec]api.ConfigSavedQuery) (deleted, updated, created map[api.SavedQuerySpecAndConfig]api.SavedQuerySpecAndConfig) {
-	deleted = map[api.SavedQuerySpecAndConfig]api.SavedQuerySpecAndConfig{}
-	updated = map[api.SavedQuerySpecAndConfig]api.SavedQuerySpecAndConfig{}
-	created = map[api.SavedQuerySpecAndConfig]api.SavedQuerySpecAndConfig{}
+// It returns maps keyed by the saved query's unique key whose values hold the
+// old value of the saved query in the oldList and its new value in the newList
+// for each respective category. For created, the old value will be an empty
+// struct. For deleted, the new value will be an empty struct.
+func diffSavedQueryConfigs(oldList, newList map[api.SavedQueryIDSpec]api.ConfigSavedQuery) (deleted, updated, created map[string]savedQueryChange) {
+	deleted = map[string]savedQueryChange{}
+	updated = map[string]savedQueryChange{}
+	created = map[string]savedQueryChange{}
 
 	// Because the api.SavedqueryIDSpec contains pointers, we should use its
 	// unique string key.
@@ -42,20 +48,20 @@ func diffSavedQueryConfigs(oldList, newList map[api.SavedQueryIDSpec]api.ConfigS
 	// Detect deleted entries
 	for k, oldVal := range oldByKey {
 		if _, ok := newByKey[k]; !ok {
-			deleted[oldVal] = api.SavedQuerySpecAndConfig{}
+			deleted[k] = savedQueryChange{oldValue: oldVal}
 		}
 	}
 
 	for k, newVal := range newByKey {
 		// Detect created entries
 		if oldVal, ok := oldByKey[k]; !ok {
-			created[oldVal] = newVal
+			created[k] = savedQueryChange{oldValue: oldVal, newValue: newVal}
 			continue
 		}
 		// Detect updated entries
 		oldVal := oldByKey[k]
 		if ok := reflect.DeepEqual(newVal, oldVal); !ok {
-			updated[oldVal] = newVal
+			updated[k] = savedQueryChange{oldValue: oldVal, newValue: newVal}
 		}
 	}
 	return deleted, updated, created
@@ -63,30 +69,27 @@ func diffSavedQueryConfigs(oldList, newList map[api.SavedQueryIDSpec]api.ConfigS
 
 func sendNotificationsForCreatedOrUpdatedOrDeleted(oldList, newList map[api.SavedQueryIDSpec]api.ConfigSavedQuery) {
 	deleted, updated, created := diffSavedQueryConfigs(oldList, newList)
-	for oldVal, newVal := range deleted {
-		oldVal := oldVal
-		newVal := newVal
+	for _, change := range deleted {
+		change := change
 		go func() {
-			if err := notifySavedQueryWasCreatedOrUpdated(oldVal, newVal); err != nil {
-				log15.Error("Failed to handle deleted saved search.", "query", oldVal.Config.Query, "error", err)
+			if err := notifySavedQueryWasCreatedOrUpdated(change.oldValue, change.newValue); err != nil {
+				log15.Error("Failed to handle deleted saved search.", "query", change.oldValue.Config.Query, "error", err)
 			}
 		}()
 	}
-	for oldVal, newVal := range created {
-		oldVal := oldVal
-		newVal := newVal
+	for _, change := range created {
+		change := change
 		go func() {
-			if err := notifySavedQueryWasCreatedOrUpdated(oldVal, newVal); err != nil {
-				log15.Error("Failed to handle created saved search.", "query", oldVal.Config.Query, "error", err)
+			if err := notifySavedQueryWasCreatedOrUpdated(change.oldValue, change.newValue); err != nil {
+				log15.Error("Failed to handle created saved search.", "query", change.oldValue.Config.Query, "error", err)
 			}
 		}()
 	}
-	for oldVal, newVal := range updated {
-		oldVal := oldVal
-		newVal := newVal
+	for _, change := range updated {
+		change := change
 		go func() {
-			if err := notifySavedQueryWasCreatedOrUpdated(oldVal, newVal); err != nil {
-				log15.Error("Failed to handle updated saved search.", "query", oldVal.Config.Query, "error", err)
+			if err := notifySavedQueryWasCreatedOrUpdated(change.oldValue, change.newValue); err != nil {
+				log15.Error("Failed to handle updated saved search.", "query", change.oldValue.Config.Query, "error", err)
 			}
 		}()
 	}
@@ -164,5 +167,10 @@ func serveTestNotification(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if err := webhookNotifyTest(r.Context(), args.SavedSearch.Config); err != nil {
+		writeError(w, fmt.Errorf("error sending webhook notifications: %s", err))
+		return
+	}
+
 	log15.Info("saved query test notification sent", "spec", args.SavedSearch.Spec, "key", args.SavedSearch.Spec.Key)
 }
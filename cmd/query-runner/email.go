@@ -52,6 +52,7 @@ func (n *notifier) emailNotify(ctx context.Context) {
 				Description            string
 				Query                  string
 				ApproximateResultCount string
+				RemovedResultCount     int
 				Ownership              string
 				PluralResults          string
 			}{
@@ -59,6 +60,7 @@ func (n *notifier) emailNotify(ctx context.Context) {
 				Description:            n.query.Description,
 				Query:                  n.query.Query,
 				ApproximateResultCount: n.results.Data.Search.Results.ApproximateResultCount,
+				RemovedResultCount:     len(n.removed),
 				Ownership:              ownership,
 				PluralResults:          plural,
 			}); err != nil {
@@ -74,14 +76,18 @@ var newSearchResultsEmailTemplates = txemail.MustValidate(txtypes.Templates{
 {{.ApproximateResultCount}} new search result{{.PluralResults}} found for {{.Ownership}} saved search:
 
   "{{.Description}}"
-
+{{if .RemovedResultCount}}
+{{.RemovedResultCount}} previously found result(s) no longer match.
+{{end}}
 View the new result{{.PluralResults}} on Sourcegraph: {{.URL}}
 `,
 	HTML: `
 <strong>{{.ApproximateResultCount}}</strong> new search result{{.PluralResults}} found for {{.Ownership}} saved search:
 
 <p style="padding-left: 16px">&quot;{{.Description}}&quot;</p>
-
+{{if .RemovedResultCount}}
+<p>{{.RemovedResultCount}} previously found result(s) no longer match.</p>
+{{end}}
 <p><a href="{{.URL}}">View the new result{{.PluralResults}} on Sourcegraph</a></p>
 `,
 })
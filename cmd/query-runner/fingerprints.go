@@ -0,0 +1,123 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// isContentQuery reports whether the saved query searches file contents, as
+// opposed to diffs or commits. Content queries do not support the after:
+// operator, so their new matches are determined by comparing fingerprints of
+// the matches of consecutive executions.
+func isContentQuery(query string) bool {
+	return !strings.Contains(query, "type:diff") && !strings.Contains(query, "type:commit")
+}
+
+// diffSearchResults compares the file matches of the given search results
+// against the fingerprints of the matches of the previous execution.
+//
+// It returns the search results restricted to the matches that were not
+// previously found, the fingerprints of the previous matches that are no longer
+// found, and the fingerprints to record for the next execution.
+func diffSearchResults(prev []api.SavedQueryResultFingerprint, v *gqlSearchResponse) (added *gqlSearchResponse, removed, current []api.SavedQueryResultFingerprint) {
+	prevSet := make(map[api.SavedQueryResultFingerprint]struct{}, len(prev))
+	for _, fingerprint := range prev {
+		prevSet[fingerprint] = struct{}{}
+	}
+
+	current = []api.SavedQueryResultFingerprint{}
+	currentSet := map[api.SavedQueryResultFingerprint]struct{}{}
+	record := func(fingerprint api.SavedQueryResultFingerprint) bool {
+		if _, ok := currentSet[fingerprint]; !ok {
+			currentSet[fingerprint] = struct{}{}
+			current = append(current, fingerprint)
+		}
+		_, ok := prevSet[fingerprint]
+		return !ok
+	}
+
+	var (
+		addedResults []interface{}
+		addedCount   int
+	)
+	for _, result := range v.Data.Search.Results.Results {
+		m, _ := result.(map[string]interface{})
+		if m["__typename"] != "FileMatch" {
+			continue
+		}
+
+		repo, _, path := parseFileMatchResource(stringAt(m, "resource"))
+		lineMatches, _ := m["lineMatches"].([]interface{})
+		if len(lineMatches) == 0 {
+			// The path of the file matched, but not its contents.
+			if record(api.SavedQueryResultFingerprint{Repo: repo, Path: path}) {
+				addedResults = append(addedResults, m)
+				addedCount++
+			}
+			continue
+		}
+
+		var addedLineMatches []interface{}
+		for _, lineMatch := range lineMatches {
+			if record(api.SavedQueryResultFingerprint{Repo: repo, Path: path, LineHash: hashLine(stringAt(lineMatch, "preview"))}) {
+				addedLineMatches = append(addedLineMatches, lineMatch)
+			}
+		}
+		if len(addedLineMatches) > 0 {
+			fileMatch := make(map[string]interface{}, len(m))
+			for k, v := range m {
+				fileMatch[k] = v
+			}
+			fileMatch["lineMatches"] = addedLineMatches
+			addedResults = append(addedResults, fileMatch)
+			addedCount += len(addedLineMatches)
+		}
+	}
+
+	results := v.Data.Search.Results
+	if results.LimitHit || len(results.Cloning) > 0 || len(results.Timedout) > 0 {
+		// The results are incomplete, so a previous match that is missing may
+		// still exist. Keep remembering it instead of reporting it as removed,
+		// which would also report it as new once it is found again.
+		for _, fingerprint := range prev {
+			record(fingerprint)
+		}
+	} else {
+		for _, fingerprint := range prev {
+			if _, ok := currentSet[fingerprint]; !ok {
+				removed = append(removed, fingerprint)
+			}
+		}
+	}
+
+	added = &gqlSearchResponse{}
+	added.Data.Search.Results.ApproximateResultCount = strconv.Itoa(addedCount)
+	added.Data.Search.Results.Results = addedResults
+	return added, removed, current
+}
+
+// hashLine returns a short hash of the content of a matched line. Storing the
+// hash instead of the line keeps the recorded fingerprints small.
+func hashLine(line string) string {
+	sum := sha256.Sum256([]byte(line))
+	return hex.EncodeToString(sum[:8])
+}
+
+// parseFileMatchResource splits the resource of a file match, which has the form
+// git://<repository>?<commit>#<path>, into its components.
+func parseFileMatchResource(resource string) (repo, commit, path string) {
+	resource = strings.TrimPrefix(resource, "git://")
+	if i := strings.Index(resource, "#"); i >= 0 {
+		path = resource[i+1:]
+		resource = resource[:i]
+	}
+	if i := strings.Index(resource, "?"); i >= 0 {
+		commit = resource[i+1:]
+		resource = resource[:i]
+	}
+	return resource, commit, path
+}
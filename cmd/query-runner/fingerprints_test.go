@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestDiffSearchResults(t *testing.T) {
+	fileMatch := func(resource string, previews ...string) interface{} {
+		lineMatches := []interface{}{}
+		for _, preview := range previews {
+			lineMatches = append(lineMatches, map[string]interface{}{"preview": preview})
+		}
+		return map[string]interface{}{"__typename": "FileMatch", "resource": resource, "lineMatches": lineMatches}
+	}
+	response := func(limitHit bool, results ...interface{}) *gqlSearchResponse {
+		v := &gqlSearchResponse{}
+		v.Data.Search.Results.LimitHit = limitHit
+		v.Data.Search.Results.Results = results
+		return v
+	}
+
+	kept := api.SavedQueryResultFingerprint{Repo: "r", Path: "a.go", LineHash: hashLine("kept")}
+	gone := api.SavedQueryResultFingerprint{Repo: "r", Path: "a.go", LineHash: hashLine("gone")}
+	path := api.SavedQueryResultFingerprint{Repo: "r", Path: "b.go"}
+	prev := []api.SavedQueryResultFingerprint{kept, gone, path}
+
+	t.Run("added and removed", func(t *testing.T) {
+		added, removed, current := diffSearchResults(prev, response(false,
+			fileMatch("git://r?c2#a.go", "kept", "new"),
+			map[string]interface{}{"__typename": "FileMatch", "resource": "git://r?c2#b.go"},
+			fileMatch("git://r?c2#c.go", "kept"),
+		))
+
+		wantAdded := []interface{}{
+			fileMatch("git://r?c2#a.go", "new"),
+			fileMatch("git://r?c2#c.go", "kept"),
+		}
+		if !reflect.DeepEqual(added.Data.Search.Results.Results, wantAdded) {
+			t.Errorf("got added %+v, want %+v", added.Data.Search.Results.Results, wantAdded)
+		}
+		if got, want := added.Data.Search.Results.ApproximateResultCount, "2"; got != want {
+			t.Errorf("got approximate result count %q, want %q", got, want)
+		}
+		if want := []api.SavedQueryResultFingerprint{gone}; !reflect.DeepEqual(removed, want) {
+			t.Errorf("got removed %+v, want %+v", removed, want)
+		}
+		wantCurrent := []api.SavedQueryResultFingerprint{
+			kept,
+			{Repo: "r", Path: "a.go", LineHash: hashLine("new")},
+			path,
+			{Repo: "r", Path: "c.go", LineHash: hashLine("kept")},
+		}
+		if !reflect.DeepEqual(current, wantCurrent) {
+			t.Errorf("got current %+v, want %+v", current, wantCurrent)
+		}
+	})
+
+	t.Run("incomplete results", func(t *testing.T) {
+		added, removed, current := diffSearchResults(prev, response(true, fileMatch("git://r?c2#a.go", "kept")))
+		if len(added.Data.Search.Results.Results) != 0 {
+			t.Errorf("got added %+v, want none", added.Data.Search.Results.Results)
+		}
+		if len(removed) != 0 {
+			t.Errorf("got removed %+v, want none", removed)
+		}
+		if !reflect.DeepEqual(current, prev) {
+			t.Errorf("got current %+v, want %+v", current, prev)
+		}
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		_, removed, current := diffSearchResults(nil, response(false))
+		if removed != nil {
+			t.Errorf("got removed %+v, want none", removed)
+		}
+		if current == nil || len(current) != 0 {
+			t.Errorf("got current %#v, want empty non-nil slice", current)
+		}
+	})
+}
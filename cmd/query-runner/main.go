@@ -138,16 +138,10 @@ func (e *executorT) run(ctx context.Context) error {
 // runQuery runs the given query if an appropriate amount of time has elapsed
 // since it last ran.
 func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery) error {
-	if !query.Notify && !query.NotifySlack {
+	if !query.Notify && !query.NotifySlack && len(query.Webhooks) == 0 {
 		// No need to run this query because there will be nobody to notify.
 		return nil
 	}
-	if !strings.Contains(query.Query, "type:diff") && !strings.Contains(query.Query, "type:commit") {
-		// TODO(slimsag): we temporarily do not support non-commit search
-		// queries, since those do not support the after:"time" operator.
-		return nil
-	}
-
 	info, err := api.InternalClient.SavedQueriesGetInfo(ctx, query.Query)
 	if err != nil {
 		return errors.Wrap(err, "SavedQueriesGetInfo")
@@ -175,21 +169,29 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 		}
 	}
 
+	// Content queries do not support the after:"time" operator, so they are
+	// run as-is and their new matches are determined by comparing them to the
+	// matches of the previous execution.
+	contentQuery := isContentQuery(query.Query)
+
 	// Construct a new query which finds search results introduced after the
 	// last time we queried.
-	var latestKnownResult time.Time
-	if info != nil {
-		latestKnownResult = info.LatestResult
-	} else {
-		// We've never executed this search query before, so use the current
-		// time. We'll most certainly find nothing, which is okay.
-		latestKnownResult = time.Now()
-	}
-	afterTime := latestKnownResult.UTC().Format(time.RFC3339)
-	newQuery := strings.Join([]string{query.Query, fmt.Sprintf(`after:"%s"`, afterTime)}, " ")
-	if debugPretendSavedQueryResultsExist {
-		debugPretendSavedQueryResultsExist = false
-		newQuery = query.Query
+	newQuery := query.Query
+	if !contentQuery {
+		var latestKnownResult time.Time
+		if info != nil {
+			latestKnownResult = info.LatestResult
+		} else {
+			// We've never executed this search query before, so use the current
+			// time. We'll most certainly find nothing, which is okay.
+			latestKnownResult = time.Now()
+		}
+		afterTime := latestKnownResult.UTC().Format(time.RFC3339)
+		newQuery = strings.Join([]string{query.Query, fmt.Sprintf(`after:"%s"`, afterTime)}, " ")
+		if debugPretendSavedQueryResultsExist {
+			debugPretendSavedQueryResultsExist = false
+			newQuery = query.Query
+		}
 	}
 
 	// Perform the search and mark the saved query as having been executed in
@@ -198,24 +200,45 @@ func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, que
 	// constantly and potentially causing harm to the system. We'll retry at
 	// our normal interval, regardless of errors.
 	v, execDuration, searchErr := performSearch(ctx, newQuery)
-	if err := api.InternalClient.SavedQueriesSetInfo(ctx, &api.SavedQueryInfo{
+	newInfo := &api.SavedQueryInfo{
 		Query:        query.Query,
 		LastExecuted: time.Now(),
-		LatestResult: latestResultTime(info, v, searchErr),
 		ExecDuration: execDuration,
-	}); err != nil {
+	}
+	var (
+		prevFingerprints []api.SavedQueryResultFingerprint
+		removed          []api.SavedQueryResultFingerprint
+	)
+	if contentQuery {
+		if info != nil {
+			prevFingerprints = info.ResultFingerprints
+		}
+		newInfo.LatestResult = newInfo.LastExecuted
+		newInfo.ResultFingerprints = prevFingerprints
+		if searchErr == nil {
+			v, removed, newInfo.ResultFingerprints = diffSearchResults(prevFingerprints, v)
+		}
+	} else {
+		newInfo.LatestResult = latestResultTime(info, v, searchErr)
+	}
+	if err := api.InternalClient.SavedQueriesSetInfo(ctx, newInfo); err != nil {
 		return errors.Wrap(err, "SavedQueriesSetInfo")
 	}
 
 	if searchErr != nil {
 		return searchErr
 	}
+	if contentQuery && prevFingerprints == nil {
+		// This is the first execution that recorded the matches of the query,
+		// so every match would be new. Only notify about later changes.
+		return nil
+	}
 
 	// Send notifications for new search results in a separate goroutine, so
 	// that we don't block other search queries from running in sequence (which
 	// is done intentionally, to ensure no overloading of searcher/gitserver).
 	go func() {
-		if err := notify(context.Background(), spec, query, newQuery, v); err != nil {
+		if err := notify(context.Background(), spec, query, newQuery, v, removed); err != nil {
 			log15.Error("executor: failed to send notifications", "error", err)
 		}
 	}()
@@ -275,12 +298,13 @@ func latestResultTime(prevInfo *api.SavedQueryInfo, v *gqlSearchResponse, search
 
 var externalURL *url.URL
 
-// notify handles sending notifications for new search results.
-func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, newQuery string, results *gqlSearchResponse) error {
-	if len(results.Data.Search.Results.Results) == 0 {
+// notify handles sending notifications for new search results, and for the
+// matches of a content query that were removed since its last execution.
+func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery, newQuery string, results *gqlSearchResponse, removed []api.SavedQueryResultFingerprint) error {
+	if len(results.Data.Search.Results.Results) == 0 && len(removed) == 0 {
 		return nil
 	}
-	log15.Info("sending notifications", "new_results", len(results.Data.Search.Results.Results), "description", query.Description)
+	log15.Info("sending notifications", "new_results", len(results.Data.Search.Results.Results), "removed_results", len(removed), "description", query.Description)
 
 	// Determine which users to notify.
 	recipients, err := getNotificationRecipients(ctx, spec, query)
@@ -294,12 +318,14 @@ func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSave
 		query:      query,
 		newQuery:   newQuery,
 		results:    results,
+		removed:    removed,
 		recipients: recipients,
 	}
 
-	// Send Slack and email notifications.
+	// Send Slack, email and webhook notifications.
 	n.slackNotify(ctx)
 	n.emailNotify(ctx)
+	n.webhookNotify(ctx)
 	return nil
 }
 
@@ -308,12 +334,14 @@ type notifier struct {
 	query      api.ConfigSavedQuery
 	newQuery   string
 	results    *gqlSearchResponse
+	removed    []api.SavedQueryResultFingerprint
 	recipients recipients
 }
 
 const (
-	utmSourceEmail = "saved-search-email"
-	utmSourceSlack = "saved-search-slack"
+	utmSourceEmail   = "saved-search-email"
+	utmSourceSlack   = "saved-search-slack"
+	utmSourceWebhook = "saved-search-webhook"
 )
 
 func searchURL(query, utmSource string) string {
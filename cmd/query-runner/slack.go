@@ -22,6 +22,9 @@ func (n *notifier) slackNotify(ctx context.Context) {
 		searchURL(n.newQuery, utmSourceSlack),
 		n.query.Description,
 	)
+	if len(n.removed) > 0 {
+		text += fmt.Sprintf(" (%d removed)", len(n.removed))
+	}
 	for _, recipient := range n.recipients {
 		if err := slackNotify(ctx, recipient, text, n.query.SlackWebhookURL); err != nil {
 			log15.Error("Failed to post Slack notification message.", "recipient", recipient, "text", text, "error", err)
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context/ctxhttp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// webhookSampleSize is the maximum number of search results included in a
+// webhook notification.
+const webhookSampleSize = 10
+
+// webhookSignatureHeader is the header carrying the hex-encoded HMAC-SHA256 of
+// the request body when the webhook is configured with a secret.
+const webhookSignatureHeader = "X-Sourcegraph-Signature"
+
+// webhookPayload is the body posted to webhooks with the "json" format.
+type webhookPayload struct {
+	Description            string          `json:"description"`
+	Query                  string          `json:"query"`
+	SearchURL              string          `json:"searchURL"`
+	ResultCount            int             `json:"resultCount"`
+	ApproximateResultCount string          `json:"approximateResultCount"`
+	Results                []webhookResult `json:"results"`
+	RemovedResultCount     int             `json:"removedResultCount,omitempty"`
+	RemovedResults         []webhookResult `json:"removedResults,omitempty"`
+	Test                   bool            `json:"test,omitempty"`
+}
+
+// webhookResult is a single search result in a webhook payload.
+type webhookResult struct {
+	Repository string `json:"repository"`
+	Commit     string `json:"commit,omitempty"`
+	Path       string `json:"path,omitempty"`
+	Preview    string `json:"preview,omitempty"`
+}
+
+func (n *notifier) webhookNotify(ctx context.Context) {
+	if len(n.query.Webhooks) == 0 {
+		return
+	}
+
+	payload := newWebhookPayload(n.query, searchURL(n.newQuery, utmSourceWebhook), n.results, n.removed)
+	for _, webhook := range n.query.Webhooks {
+		// Do not log the URL of the webhook, as incoming webhook URLs of chat services embed credentials.
+		if err := postWebhook(ctx, webhook, payload); err != nil {
+			log15.Error("Failed to post webhook notification.", "description", n.query.Description, "format", webhook.Format, "error", err)
+		}
+	}
+	logEvent(0, "SavedSearchWebhookNotificationSent", "results")
+}
+
+func webhookNotifyTest(ctx context.Context, query api.ConfigSavedQuery) error {
+	payload := &webhookPayload{
+		Description: query.Description,
+		Query:       query.Query,
+		SearchURL:   searchURL(query.Query, utmSourceWebhook),
+		Results:     []webhookResult{},
+		Test:        true,
+	}
+	for _, webhook := range query.Webhooks {
+		if err := postWebhook(ctx, webhook, payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newWebhookPayload creates the payload describing the new results of the given saved query, and
+// the matches of a content query that were removed since its last execution.
+func newWebhookPayload(query api.ConfigSavedQuery, searchURL string, results *gqlSearchResponse, removed []api.SavedQueryResultFingerprint) *webhookPayload {
+	payload := &webhookPayload{
+		Description:            query.Description,
+		Query:                  query.Query,
+		SearchURL:              searchURL,
+		ResultCount:            len(results.Data.Search.Results.Results),
+		ApproximateResultCount: results.Data.Search.Results.ApproximateResultCount,
+		Results:                []webhookResult{},
+	}
+	for _, result := range results.Data.Search.Results.Results {
+		if len(payload.Results) >= webhookSampleSize {
+			break
+		}
+		if r, ok := toWebhookResult(result); ok {
+			payload.Results = append(payload.Results, r)
+		}
+	}
+	payload.RemovedResultCount = len(removed)
+	for _, fingerprint := range removed {
+		if len(payload.RemovedResults) >= webhookSampleSize {
+			break
+		}
+		payload.RemovedResults = append(payload.RemovedResults, webhookResult{Repository: fingerprint.Repo, Path: fingerprint.Path})
+	}
+	return payload
+}
+
+// toWebhookResult converts a search result as returned by the GraphQL API to a webhook result.
+func toWebhookResult(result interface{}) (webhookResult, bool) {
+	m, _ := result.(map[string]interface{})
+
+	switch m["__typename"] {
+	case "CommitSearchResult":
+		commit, _ := m["commit"].(map[string]interface{})
+		r := webhookResult{
+			Repository: stringAt(commit, "repository", "name"),
+			Commit:     stringAt(commit, "oid"),
+			Preview:    stringAt(m, "diffPreview", "value"),
+		}
+		if r.Preview == "" {
+			r.Preview = stringAt(m, "messagePreview", "value")
+		}
+		return r, true
+
+	case "FileMatch":
+		r := webhookResult{}
+		r.Repository, r.Commit, r.Path = parseFileMatchResource(stringAt(m, "resource"))
+		if lineMatches, _ := m["lineMatches"].([]interface{}); len(lineMatches) > 0 {
+			r.Preview = stringAt(lineMatches[0], "preview")
+		}
+		return r, true
+	}
+
+	return webhookResult{}, false
+}
+
+// stringAt returns the string found by following the given keys through nested JSON objects, or
+// the empty string if there is none.
+func stringAt(v interface{}, keys ...string) string {
+	for _, key := range keys {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		v = m[key]
+	}
+	s, _ := v.(string)
+	return s
+}
+
+// webhookBody renders the payload in the format expected by the webhook.
+func webhookBody(format string, payload *webhookPayload) ([]byte, error) {
+	switch format {
+	case "", "json":
+		return json.Marshal(payload)
+
+	case "teams":
+		// See https://docs.microsoft.com/en-us/outlook/actionable-messages/message-card-reference.
+		return json.Marshal(map[string]interface{}{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  webhookTitle(payload),
+			"title":    webhookTitle(payload),
+			"text":     webhookMarkdown(payload),
+			"potentialAction": []interface{}{
+				map[string]interface{}{
+					"@type":   "OpenUri",
+					"name":    "View on Sourcegraph",
+					"targets": []interface{}{map[string]string{"os": "default", "uri": payload.SearchURL}},
+				},
+			},
+		})
+
+	case "mattermost":
+		// See https://docs.mattermost.com/developer/webhooks-incoming.html.
+		return json.Marshal(map[string]string{
+			"username": "saved-search-bot",
+			"text":     fmt.Sprintf("#### %s\n%s\n[View on Sourcegraph](%s)", webhookTitle(payload), webhookMarkdown(payload), payload.SearchURL),
+		})
+	}
+
+	return nil, fmt.Errorf("unknown webhook format %q", format)
+}
+
+func webhookTitle(payload *webhookPayload) string {
+	if payload.Test {
+		return fmt.Sprintf(`Test notification for saved search "%s"`, payload.Description)
+	}
+
+	plural := ""
+	if payload.ApproximateResultCount != "1" {
+		plural = "s"
+	}
+	title := fmt.Sprintf(`%s new result%s found for saved search "%s"`, payload.ApproximateResultCount, plural, payload.Description)
+	if payload.RemovedResultCount > 0 {
+		title += fmt.Sprintf(" (%d removed)", payload.RemovedResultCount)
+	}
+	return title
+}
+
+func webhookMarkdown(payload *webhookPayload) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Query: `%s`\n", payload.Query)
+	for _, r := range payload.Results {
+		fmt.Fprintf(&b, "\n- **%s**", r.Repository)
+		if r.Path != "" {
+			fmt.Fprintf(&b, " %s", r.Path)
+		}
+		if r.Commit != "" {
+			fmt.Fprintf(&b, " `%.10s`", r.Commit)
+		}
+		if preview := strings.TrimSpace(strings.SplitN(r.Preview, "\n", 2)[0]); preview != "" {
+			fmt.Fprintf(&b, ": %s", preview)
+		}
+	}
+	if len(payload.RemovedResults) > 0 {
+		b.WriteString("\n\nNo longer matching:\n")
+		for _, r := range payload.RemovedResults {
+			fmt.Fprintf(&b, "\n- **%s** %s", r.Repository, r.Path)
+		}
+	}
+	return b.String()
+}
+
+// signWebhookBody returns the hex-encoded HMAC-SHA256 of body keyed by secret.
+func signWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func postWebhook(ctx context.Context, webhook *schema.SavedQueryWebhook, payload *webhookPayload) error {
+	body, err := webhookBody(webhook.Format, payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", webhook.Url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "webhook: create post request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		req.Header.Set(webhookSignatureHeader, signWebhookBody(webhook.Secret, body))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	resp, err := ctxhttp.Do(ctx, nil, req)
+	if err != nil {
+		return errors.Wrap(err, "webhook: post request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		content, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook: unexpected status %d: %s", resp.StatusCode, content)
+	}
+	return nil
+}
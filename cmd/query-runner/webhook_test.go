@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestNewWebhookPayload(t *testing.T) {
+	var results gqlSearchResponse
+	results.Data.Search.Results.ApproximateResultCount = "2"
+	results.Data.Search.Results.Results = []interface{}{
+		map[string]interface{}{
+			"__typename":     "CommitSearchResult",
+			"commit":         map[string]interface{}{"oid": "deadbeef", "repository": map[string]interface{}{"name": "github.com/foo/bar"}},
+			"messagePreview": map[string]interface{}{"value": "fix bug"},
+			"diffPreview":    nil,
+		},
+		map[string]interface{}{
+			"__typename":  "FileMatch",
+			"resource":    "git://github.com/foo/baz?cafebabe#main.go",
+			"lineMatches": []interface{}{map[string]interface{}{"preview": "func main() {"}},
+		},
+		map[string]interface{}{"__typename": "Repository"},
+	}
+
+	payload := newWebhookPayload(api.ConfigSavedQuery{Description: "d", Query: "q"}, "https://example.com/search", &results, []api.SavedQueryResultFingerprint{{Repo: "github.com/foo/qux", Path: "old.go", LineHash: "0123456789abcdef"}})
+	want := &webhookPayload{
+		Description:            "d",
+		Query:                  "q",
+		SearchURL:              "https://example.com/search",
+		ResultCount:            3,
+		ApproximateResultCount: "2",
+		Results: []webhookResult{
+			{Repository: "github.com/foo/bar", Commit: "deadbeef", Preview: "fix bug"},
+			{Repository: "github.com/foo/baz", Commit: "cafebabe", Path: "main.go", Preview: "func main() {"},
+		},
+		RemovedResultCount: 1,
+		RemovedResults:     []webhookResult{{Repository: "github.com/foo/qux", Path: "old.go"}},
+	}
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("got %+v, want %+v", payload, want)
+	}
+}
+
+func TestPostWebhook(t *testing.T) {
+	payload := &webhookPayload{
+		Description:            "d",
+		Query:                  "q",
+		SearchURL:              "https://example.com/search",
+		ResultCount:            1,
+		ApproximateResultCount: "1",
+		Results:                []webhookResult{{Repository: "github.com/foo/bar", Commit: "deadbeef", Preview: "fix bug"}},
+	}
+
+	var (
+		body      []byte
+		signature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+		signature = r.Header.Get(webhookSignatureHeader)
+	}))
+	defer server.Close()
+
+	t.Run("json", func(t *testing.T) {
+		if err := postWebhook(context.Background(), &schema.SavedQueryWebhook{Url: server.URL, Secret: "s3cr3t"}, payload); err != nil {
+			t.Fatal(err)
+		}
+		var got webhookPayload
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(&got, payload) {
+			t.Errorf("got %+v, want %+v", got, payload)
+		}
+		if want := signWebhookBody("s3cr3t", body); signature != want {
+			t.Errorf("got signature %q, want %q", signature, want)
+		}
+	})
+
+	t.Run("teams", func(t *testing.T) {
+		if err := postWebhook(context.Background(), &schema.SavedQueryWebhook{Url: server.URL, Format: "teams"}, payload); err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got["@type"] != "MessageCard" {
+			t.Errorf("got @type %v, want MessageCard", got["@type"])
+		}
+		if want := `1 new result found for saved search "d"`; got["title"] != want {
+			t.Errorf("got title %v, want %q", got["title"], want)
+		}
+		if signature != "" {
+			t.Errorf("got signature %q, want none", signature)
+		}
+	})
+
+	t.Run("mattermost", func(t *testing.T) {
+		if err := postWebhook(context.Background(), &schema.SavedQueryWebhook{Url: server.URL, Format: "mattermost"}, payload); err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]string
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatal(err)
+		}
+		if text := got["text"]; !strings.Contains(text, "**github.com/foo/bar** `deadbeef`: fix bug") || !strings.Contains(text, payload.SearchURL) {
+			t.Errorf("unexpected text %q", text)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if err := postWebhook(context.Background(), &schema.SavedQueryWebhook{Url: server.URL, Format: "irc"}, payload); err == nil {
+			t.Error("expected error for unknown format")
+		}
+	})
+}
+
+func TestPostWebhookErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	if err := postWebhook(context.Background(), &schema.SavedQueryWebhook{Url: server.URL}, &webhookPayload{}); err == nil {
+		t.Error("expected error for non-2xx status")
+	}
+}
@@ -0,0 +1,175 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A GerritSource yields repositories from a single Gerrit connection configured
+// in Sourcegraph via the external services configuration.
+type GerritSource struct {
+	svc     *ExternalService
+	config  *schema.GerritConnection
+	baseURL *url.URL
+	exclude excludeFunc
+	client  *gerrit.Client
+}
+
+// NewGerritSource returns a new GerritSource from the given external service.
+func NewGerritSource(svc *ExternalService, cf *httpcli.Factory) (*GerritSource, error) {
+	var c schema.GerritConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
+	}
+	return newGerritSource(svc, &c, cf)
+}
+
+func newGerritSource(svc *ExternalService, c *schema.GerritConnection, cf *httpcli.Factory) (*GerritSource, error) {
+	baseURL, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	baseURL = extsvc.NormalizeBaseURL(baseURL)
+
+	if cf == nil {
+		cf = httpcli.NewExternalHTTPClientFactory()
+	}
+
+	cli, err := cf.Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	var eb excludeBuilder
+	for _, r := range c.Exclude {
+		eb.Exact(r.Name)
+		eb.Pattern(r.Pattern)
+	}
+	exclude, err := eb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	client := gerrit.NewClient(baseURL, cli)
+	client.Username = c.Username
+	client.Password = c.Password
+
+	return &GerritSource{
+		svc:     svc,
+		config:  c,
+		baseURL: baseURL,
+		exclude: exclude,
+		client:  client,
+	}, nil
+}
+
+// ListRepos returns all Gerrit projects accessible to all connections configured
+// in Sourcegraph via the external services configuration.
+func (s GerritSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	if len(s.config.Projects) > 0 {
+		s.listConfiguredProjects(ctx, results)
+		return
+	}
+	s.listAllProjects(ctx, results)
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s GerritSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func (s GerritSource) makeRepo(p *gerrit.Project) *Repo {
+	urn := s.svc.URN()
+	return &Repo{
+		Name: string(reposource.GerritRepoName(
+			s.config.RepositoryPathPattern,
+			s.baseURL.Hostname(),
+			p.Name,
+		)),
+		URI: string(reposource.GerritRepoName(
+			"",
+			s.baseURL.Hostname(),
+			p.Name,
+		)),
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          p.ID,
+			ServiceType: extsvc.TypeGerrit,
+			ServiceID:   s.baseURL.String(),
+		},
+		Description: p.Description,
+		Archived:    p.State == gerrit.ProjectStateReadOnly,
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: s.authenticatedRemoteURL(p),
+			},
+		},
+		Metadata: p,
+	}
+}
+
+// authenticatedRemoteURL returns the project's Git remote URL with the configured
+// Gerrit HTTP credentials inserted in the URL userinfo. Gerrit only accepts
+// authenticated Git requests under the "/a/" path prefix.
+func (s *GerritSource) authenticatedRemoteURL(p *gerrit.Project) string {
+	u := *s.baseURL
+	u.Path += "a/" + p.Name
+	u.User = url.UserPassword(s.config.Username, s.config.Password)
+	return u.String()
+}
+
+func (s *GerritSource) excludes(p *gerrit.Project) bool {
+	// Projects in the HIDDEN state are only visible to administrators and
+	// can't be cloned by regular users. All-Projects and All-Users only
+	// hold Gerrit's own configuration and account data, not code.
+	return p.State == gerrit.ProjectStateHidden ||
+		p.Name == "All-Projects" ||
+		p.Name == "All-Users" ||
+		s.exclude(p.Name)
+}
+
+func (s *GerritSource) listConfiguredProjects(ctx context.Context, results chan SourceResult) {
+	for _, name := range s.config.Projects {
+		p, err := s.client.GetProject(ctx, name)
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.project: item=%q", name)}
+			continue
+		}
+
+		if !s.excludes(p) {
+			results <- SourceResult{Source: s, Repo: s.makeRepo(p)}
+		}
+	}
+}
+
+func (s *GerritSource) listAllProjects(ctx context.Context, results chan SourceResult) {
+	cursor := &gerrit.Pagination{PerPage: 100, Page: 1}
+	for {
+		projects, nextPage, err := s.client.ListProjects(ctx, gerrit.ListProjectsArgs{Cursor: cursor})
+		if err != nil {
+			results <- SourceResult{Source: s, Err: errors.Wrapf(err, "gerrit.projects: page=%+v", cursor)}
+			return
+		}
+
+		for _, p := range projects {
+			if !s.excludes(p) {
+				results <- SourceResult{Source: s, Repo: s.makeRepo(p)}
+			}
+		}
+
+		if !nextPage {
+			return
+		}
+		cursor.Page++
+	}
+}
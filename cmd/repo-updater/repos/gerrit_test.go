@@ -0,0 +1,232 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
+	"github.com/sourcegraph/sourcegraph/internal/testutil"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGerritSource_ListRepos(t *testing.T) {
+	assertAllReposListed := func(want []string) ReposAssertion {
+		return func(t testing.TB, rs Repos) {
+			t.Helper()
+
+			have := rs.Names()
+			sort.Strings(have)
+			sort.Strings(want)
+
+			if !reflect.DeepEqual(have, want) {
+				t.Error(cmp.Diff(have, want))
+			}
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		assert ReposAssertion
+		conf   *schema.GerritConnection
+		err    string
+	}{
+		{
+			name: "found",
+			assert: assertAllReposListed([]string{
+				"gerrit.sgdev.org/experimental/sandbox",
+				"gerrit.sgdev.org/platform/build",
+				"gerrit.sgdev.org/tools/repo",
+			}),
+			conf: &schema.GerritConnection{
+				Url:      "https://gerrit.sgdev.org",
+				Username: gerrit.GetenvTestGerritUsername(),
+				Password: "secret",
+			},
+			err: "<nil>",
+		},
+		{
+			name: "with projects",
+			assert: assertAllReposListed([]string{
+				"gerrit.sgdev.org/platform/build",
+				"gerrit.sgdev.org/tools/repo",
+			}),
+			conf: &schema.GerritConnection{
+				Url:      "https://gerrit.sgdev.org",
+				Username: gerrit.GetenvTestGerritUsername(),
+				Password: "secret",
+				Projects: []string{
+					"platform/build",
+					"tools/repo",
+				},
+			},
+			err: "<nil>",
+		},
+		{
+			name: "excluded",
+			assert: assertAllReposListed([]string{
+				"gerrit/platform/build",
+			}),
+			conf: &schema.GerritConnection{
+				Url:                   "https://gerrit.sgdev.org",
+				Username:              gerrit.GetenvTestGerritUsername(),
+				Password:              "secret",
+				RepositoryPathPattern: "gerrit/{name}",
+				Exclude: []*schema.ExcludedGerritProject{
+					{Name: "tools/repo"},
+					{Pattern: "^experimental/"},
+				},
+			},
+			err: "<nil>",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		tc.name = "GERRIT-LIST-REPOS/" + tc.name
+		t.Run(tc.name, func(t *testing.T) {
+			cf, save := newClientFactory(t, tc.name)
+			defer save(t)
+
+			svc := &ExternalService{
+				Kind:   extsvc.KindGerrit,
+				Config: marshalJSON(t, tc.conf),
+			}
+
+			src, err := newGerritSource(svc, tc.conf, cf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			repos, err := listAll(context.Background(), src)
+
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Errorf("error:\nhave: %q\nwant: %q", have, want)
+			}
+
+			if tc.assert != nil {
+				tc.assert(t, repos)
+			}
+		})
+	}
+}
+
+func TestGerritSource_makeRepo(t *testing.T) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "gerrit-projects.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var projects []*gerrit.Project
+	if err := json.Unmarshal(b, &projects); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := ExternalService{ID: 1, Kind: extsvc.KindGerrit}
+
+	tests := []struct {
+		name   string
+		schema *schema.GerritConnection
+	}{
+		{
+			name: "simple",
+			schema: &schema.GerritConnection{
+				Url:      "https://gerrit.example.com",
+				Username: "alice",
+				Password: "secret",
+			},
+		}, {
+			name: "path-pattern",
+			schema: &schema.GerritConnection{
+				Url:                   "https://gerrit.example.com",
+				Username:              "alice",
+				Password:              "secret",
+				RepositoryPathPattern: "gerrit/{name}",
+			},
+		},
+	}
+	for _, test := range tests {
+		test.name = "GerritSource_makeRepo_" + test.name
+		t.Run(test.name, func(t *testing.T) {
+			s, err := newGerritSource(&svc, test.schema, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []*Repo
+			for _, p := range projects {
+				got = append(got, s.makeRepo(p))
+			}
+
+			testutil.AssertGolden(t, "testdata/golden/"+test.name, update(test.name), got)
+		})
+	}
+}
+
+func TestGerritSource_Exclude(t *testing.T) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "gerrit-projects.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var projects []*gerrit.Project
+	if err := json.Unmarshal(b, &projects); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]*schema.GerritConnection{
+		"none": {
+			Url:      "https://gerrit.example.com",
+			Username: "alice",
+			Password: "secret",
+		},
+		"name": {
+			Url:      "https://gerrit.example.com",
+			Username: "alice",
+			Password: "secret",
+			Exclude: []*schema.ExcludedGerritProject{
+				{Name: "tools/repo"},
+			},
+		},
+		"pattern": {
+			Url:      "https://gerrit.example.com",
+			Username: "alice",
+			Password: "secret",
+			Exclude: []*schema.ExcludedGerritProject{
+				{Pattern: "^experimental/"},
+			},
+		},
+	}
+
+	svc := ExternalService{ID: 1, Kind: extsvc.KindGerrit}
+
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := newGerritSource(&svc, config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			type output struct {
+				Include []string
+				Exclude []string
+			}
+			var got output
+			for _, p := range projects {
+				if s.excludes(p) {
+					got.Exclude = append(got.Exclude, p.Name)
+				} else {
+					got.Include = append(got.Include, p.Name)
+				}
+			}
+
+			path := filepath.Join("testdata", "gerrit-projects-exclude-"+name+".golden")
+			testutil.AssertGolden(t, path, update(name), got)
+		})
+	}
+}
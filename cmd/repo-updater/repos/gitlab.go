@@ -16,6 +16,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -106,6 +107,158 @@ func (s GitLabSource) GetRepo(ctx context.Context, pathWithNamespace string) (*R
 	return s.makeRepo(proj), nil
 }
 
+var _ ChangesetSource = GitLabSource{}
+
+// CreateChangeset creates a GitLab merge request. If it already exists,
+// *Changeset will be populated and the return value will be true.
+func (s GitLabSource) CreateChangeset(ctx context.Context, c *Changeset) (bool, error) {
+	project, ok := c.Repo.Metadata.(*gitlab.Project)
+	if !ok {
+		return false, errors.New("Repo is not a GitLab project")
+	}
+
+	exists := false
+	source := git.AbbreviateRef(c.HeadRef)
+	target := git.AbbreviateRef(c.BaseRef)
+
+	mr, err := s.client.CreateMergeRequest(ctx, project, gitlab.CreateMergeRequestOpts{
+		SourceBranch: source,
+		TargetBranch: target,
+		Title:        c.Title,
+		Description:  c.Body,
+	})
+	if err != nil {
+		if err != gitlab.ErrMergeRequestAlreadyExists {
+			return exists, errors.Wrap(err, "creating the merge request")
+		}
+		exists = true
+
+		mr, err = s.client.GetOpenMergeRequestByRefs(ctx, project, source, target)
+		if err != nil {
+			return exists, errors.Wrap(err, "retrieving an extant merge request")
+		}
+	}
+
+	if err := s.loadMergeRequestData(ctx, project, mr); err != nil {
+		return exists, errors.Wrap(err, "loading extra metadata")
+	}
+	if err := c.SetMetadata(mr); err != nil {
+		return exists, errors.Wrap(err, "setting changeset metadata")
+	}
+
+	return exists, nil
+}
+
+// CloseChangeset closes the merge request on GitLab and updates the
+// Metadata column in the *campaigns.Changeset to the newly closed merge
+// request.
+func (s GitLabSource) CloseChangeset(ctx context.Context, c *Changeset) error {
+	mr, ok := c.Changeset.Metadata.(*gitlab.MergeRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitLab merge request")
+	}
+
+	project, ok := c.Repo.Metadata.(*gitlab.Project)
+	if !ok {
+		return errors.New("Repo is not a GitLab project")
+	}
+
+	updated, err := s.client.UpdateMergeRequest(ctx, project, mr, gitlab.UpdateMergeRequestOpts{
+		StateEvent: gitlab.UpdateMergeRequestStateEventClose,
+	})
+	if err != nil {
+		return errors.Wrap(err, "closing the merge request")
+	}
+
+	if err := s.loadMergeRequestData(ctx, project, updated); err != nil {
+		return errors.Wrap(err, "loading extra metadata")
+	}
+	c.Changeset.Metadata = updated
+
+	return nil
+}
+
+// LoadChangesets loads the latest state of the given Changesets from GitLab.
+func (s GitLabSource) LoadChangesets(ctx context.Context, cs ...*Changeset) error {
+	var notFound []*Changeset
+
+	for _, c := range cs {
+		project, ok := c.Repo.Metadata.(*gitlab.Project)
+		if !ok {
+			return errors.New("Repo is not a GitLab project")
+		}
+
+		iid, err := strconv.Atoi(c.ExternalID)
+		if err != nil {
+			return errors.Wrapf(err, "parsing changeset external id %q", c.ExternalID)
+		}
+
+		mr, err := s.client.GetMergeRequest(ctx, project, iid)
+		if err != nil {
+			if gitlab.IsNotFound(err) {
+				notFound = append(notFound, c)
+				continue
+			}
+			return errors.Wrapf(err, "retrieving merge request %d", iid)
+		}
+
+		if err := s.loadMergeRequestData(ctx, project, mr); err != nil {
+			return errors.Wrapf(err, "loading merge request %d data", iid)
+		}
+		if err := c.SetMetadata(mr); err != nil {
+			return errors.Wrapf(err, "setting changeset metadata for merge request %d", iid)
+		}
+	}
+
+	if len(notFound) > 0 {
+		return ChangesetsNotFoundError{Changesets: notFound}
+	}
+
+	return nil
+}
+
+func (s GitLabSource) loadMergeRequestData(ctx context.Context, project *gitlab.Project, mr *gitlab.MergeRequest) error {
+	if err := s.client.LoadMergeRequestNotes(ctx, project, mr); err != nil {
+		return errors.Wrap(err, "loading mr notes")
+	}
+
+	if err := s.client.LoadMergeRequestPipelines(ctx, project, mr); err != nil {
+		return errors.Wrap(err, "loading mr pipelines")
+	}
+
+	return nil
+}
+
+// UpdateChangeset updates the title, description and target branch of the
+// merge request on GitLab.
+func (s GitLabSource) UpdateChangeset(ctx context.Context, c *Changeset) error {
+	mr, ok := c.Changeset.Metadata.(*gitlab.MergeRequest)
+	if !ok {
+		return errors.New("Changeset is not a GitLab merge request")
+	}
+
+	project, ok := c.Repo.Metadata.(*gitlab.Project)
+	if !ok {
+		return errors.New("Repo is not a GitLab project")
+	}
+
+	updated, err := s.client.UpdateMergeRequest(ctx, project, mr, gitlab.UpdateMergeRequestOpts{
+		Title:        c.Title,
+		Description:  c.Body,
+		TargetBranch: git.AbbreviateRef(c.BaseRef),
+	})
+	if err != nil {
+		return errors.Wrap(err, "updating the merge request")
+	}
+
+	if err := s.loadMergeRequestData(ctx, project, updated); err != nil {
+		return errors.Wrap(err, "loading extra metadata")
+	}
+	c.Changeset.Metadata = updated
+
+	return nil
+}
+
 // ExternalServices returns a singleton slice containing the external service.
 func (s GitLabSource) ExternalServices() ExternalServices {
 	return ExternalServices{s.svc}
@@ -7,11 +7,14 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
@@ -213,3 +216,195 @@ func TestGitLabSource_makeRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestGitLabSource_ChangesetSource(t *testing.T) {
+	ctx := context.Background()
+	project := &gitlab.Project{ProjectCommon: gitlab.ProjectCommon{ID: 42, PathWithNamespace: "group/project"}}
+
+	newSource := func(t *testing.T) *GitLabSource {
+		svc := ExternalService{Kind: extsvc.KindGitLab}
+		s, err := newGitLabSource(&svc, &schema.GitLabConnection{Url: "https://gitlab.com"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	newChangeset := func(mr *gitlab.MergeRequest) *Changeset {
+		c := &Changeset{
+			Title:     "Fix all the things",
+			Body:      "This fixes all the things",
+			HeadRef:   "refs/heads/campaigns/fix",
+			BaseRef:   "refs/heads/master",
+			Repo:      &Repo{Metadata: project},
+			Changeset: &campaigns.Changeset{},
+		}
+		if mr != nil {
+			c.Changeset.Metadata = mr
+			c.Changeset.ExternalID = strconv.Itoa(mr.IID)
+		}
+		return c
+	}
+
+	mockLoadData := func(t *testing.T) {
+		gitlab.MockLoadMergeRequestNotes = func(_ *gitlab.Client, _ context.Context, _ *gitlab.Project, mr *gitlab.MergeRequest) error {
+			mr.Notes = []*gitlab.Note{{ID: 1, Body: "approved this merge request", System: true}}
+			return nil
+		}
+		gitlab.MockLoadMergeRequestPipelines = func(_ *gitlab.Client, _ context.Context, _ *gitlab.Project, mr *gitlab.MergeRequest) error {
+			mr.Pipelines = []*gitlab.Pipeline{{ID: 2, Status: gitlab.PipelineStatusSuccess}}
+			return nil
+		}
+		t.Cleanup(func() {
+			gitlab.MockLoadMergeRequestNotes = nil
+			gitlab.MockLoadMergeRequestPipelines = nil
+		})
+	}
+
+	t.Run("CreateChangeset", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			createErr error
+			exists    bool
+		}{
+			"new":             {createErr: nil, exists: false},
+			"already exists":  {createErr: gitlab.ErrMergeRequestAlreadyExists, exists: true},
+			"creation failed": {createErr: errors.New("boom")},
+		} {
+			tc := tc
+			t.Run(name, func(t *testing.T) {
+				mockLoadData(t)
+				mr := &gitlab.MergeRequest{IID: 3, SourceBranch: "campaigns/fix", State: gitlab.MergeRequestStateOpened}
+
+				gitlab.MockCreateMergeRequest = func(_ *gitlab.Client, _ context.Context, p *gitlab.Project, opts gitlab.CreateMergeRequestOpts) (*gitlab.MergeRequest, error) {
+					if p != project {
+						t.Errorf("unexpected project %+v", p)
+					}
+					want := gitlab.CreateMergeRequestOpts{
+						SourceBranch: "campaigns/fix",
+						TargetBranch: "master",
+						Title:        "Fix all the things",
+						Description:  "This fixes all the things",
+					}
+					if diff := cmp.Diff(want, opts); diff != "" {
+						t.Errorf("unexpected options: %s", diff)
+					}
+					if tc.createErr != nil {
+						return nil, tc.createErr
+					}
+					return mr, nil
+				}
+				gitlab.MockGetOpenMergeRequestByRefs = func(_ *gitlab.Client, _ context.Context, _ *gitlab.Project, source, target string) (*gitlab.MergeRequest, error) {
+					if source != "campaigns/fix" || target != "master" {
+						t.Errorf("unexpected refs %q %q", source, target)
+					}
+					return mr, nil
+				}
+				t.Cleanup(func() {
+					gitlab.MockCreateMergeRequest = nil
+					gitlab.MockGetOpenMergeRequestByRefs = nil
+				})
+
+				c := newChangeset(nil)
+				exists, err := newSource(t).CreateChangeset(ctx, c)
+				if tc.createErr != nil && tc.createErr != gitlab.ErrMergeRequestAlreadyExists {
+					if err == nil {
+						t.Fatal("expected error")
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if exists != tc.exists {
+					t.Errorf("unexpected exists: have %v, want %v", exists, tc.exists)
+				}
+				if c.Changeset.Metadata != mr {
+					t.Errorf("unexpected metadata %+v", c.Changeset.Metadata)
+				}
+				if have, want := c.Changeset.ExternalID, "3"; have != want {
+					t.Errorf("unexpected external id: have %q, want %q", have, want)
+				}
+				if have, want := c.Changeset.ExternalServiceType, extsvc.TypeGitLab; have != want {
+					t.Errorf("unexpected external service type: have %q, want %q", have, want)
+				}
+				if len(mr.Notes) != 1 || len(mr.Pipelines) != 1 {
+					t.Errorf("extra metadata not loaded: %+v", mr)
+				}
+			})
+		}
+	})
+
+	t.Run("CloseChangeset", func(t *testing.T) {
+		mockLoadData(t)
+		closed := &gitlab.MergeRequest{IID: 3, State: gitlab.MergeRequestStateClosed}
+		gitlab.MockUpdateMergeRequest = func(_ *gitlab.Client, _ context.Context, _ *gitlab.Project, mr *gitlab.MergeRequest, opts gitlab.UpdateMergeRequestOpts) (*gitlab.MergeRequest, error) {
+			if opts.StateEvent != gitlab.UpdateMergeRequestStateEventClose {
+				t.Errorf("unexpected state event %q", opts.StateEvent)
+			}
+			return closed, nil
+		}
+		t.Cleanup(func() { gitlab.MockUpdateMergeRequest = nil })
+
+		c := newChangeset(&gitlab.MergeRequest{IID: 3, State: gitlab.MergeRequestStateOpened})
+		if err := newSource(t).CloseChangeset(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Changeset.Metadata != closed {
+			t.Errorf("unexpected metadata %+v", c.Changeset.Metadata)
+		}
+	})
+
+	t.Run("UpdateChangeset", func(t *testing.T) {
+		mockLoadData(t)
+		updated := &gitlab.MergeRequest{IID: 3, Title: "Fix all the things"}
+		gitlab.MockUpdateMergeRequest = func(_ *gitlab.Client, _ context.Context, _ *gitlab.Project, mr *gitlab.MergeRequest, opts gitlab.UpdateMergeRequestOpts) (*gitlab.MergeRequest, error) {
+			want := gitlab.UpdateMergeRequestOpts{
+				Title:        "Fix all the things",
+				Description:  "This fixes all the things",
+				TargetBranch: "master",
+			}
+			if diff := cmp.Diff(want, opts); diff != "" {
+				t.Errorf("unexpected options: %s", diff)
+			}
+			return updated, nil
+		}
+		t.Cleanup(func() { gitlab.MockUpdateMergeRequest = nil })
+
+		c := newChangeset(&gitlab.MergeRequest{IID: 3})
+		if err := newSource(t).UpdateChangeset(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Changeset.Metadata != updated {
+			t.Errorf("unexpected metadata %+v", c.Changeset.Metadata)
+		}
+	})
+
+	t.Run("LoadChangesets", func(t *testing.T) {
+		mockLoadData(t)
+		gitlab.MockGetMergeRequest = func(_ *gitlab.Client, _ context.Context, _ *gitlab.Project, iid int) (*gitlab.MergeRequest, error) {
+			if iid == 404 {
+				return nil, gitlab.ErrNotFound
+			}
+			return &gitlab.MergeRequest{IID: iid}, nil
+		}
+		t.Cleanup(func() { gitlab.MockGetMergeRequest = nil })
+
+		found := newChangeset(&gitlab.MergeRequest{IID: 3})
+		missing := newChangeset(&gitlab.MergeRequest{IID: 404})
+
+		err := newSource(t).LoadChangesets(ctx, found, missing)
+		notFound, ok := err.(ChangesetsNotFoundError)
+		if !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(notFound.Changesets) != 1 || notFound.Changesets[0] != missing {
+			t.Errorf("unexpected not found changesets: %+v", notFound.Changesets)
+		}
+
+		mr, ok := found.Changeset.Metadata.(*gitlab.MergeRequest)
+		if !ok || mr.IID != 3 || len(mr.Notes) != 1 {
+			t.Errorf("unexpected metadata %+v", found.Changeset.Metadata)
+		}
+	})
+}
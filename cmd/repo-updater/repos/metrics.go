@@ -56,6 +56,10 @@ var (
 		Name: "src_repoupdater_sched_manual_fetch",
 		Help: "Incremented each time the scheduler updates a repository due to user traffic.",
 	})
+	schedPushes = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "src_repoupdater_sched_pushes",
+		Help: "Incremented each time the scheduler is notified of a push webhook delivery for a repository.",
+	})
 	schedKnownRepos = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "src_repoupdater_sched_known_repos",
 		Help: "The number of repositories that are managed by the scheduler.",
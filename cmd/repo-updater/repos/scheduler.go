@@ -78,6 +78,10 @@ const (
 
 	// maxDelay is the maximum amount of time between scheduled updates for a single repository.
 	maxDelay = 8 * time.Hour
+
+	// pushExpiry is how long after the last push webhook delivery for a
+	// repository it is assumed to be kept up to date by push webhooks.
+	pushExpiry = 24 * time.Hour
 )
 
 // updateScheduler schedules repo update (or clone) requests to gitserver.
@@ -90,6 +94,11 @@ const (
 // then the next update will be scheduled 6 hours from then.
 // This heuristic is simple to compute and has nice backoff properties.
 //
+// Repos for which the code host delivers push webhooks are updated as soon as
+// a push is delivered (see SetPushed). Until pushExpiry has elapsed since the
+// last delivery, they are only scheduled every maxDelay, in case deliveries
+// are lost.
+//
 // When it is time for a repo to update, the scheduler inserts the repo into a queue.
 //
 // A worker continuously dequeues repos and sends updates to gitserver, but its concurrency
@@ -334,6 +343,14 @@ func (s *updateScheduler) UpdateOnce(id api.RepoID, name api.RepoName, url strin
 	s.updateQueue.enqueue(repo, priorityHigh)
 }
 
+// SetPushed records that the code host delivered a push webhook for the given
+// repository, which is then scheduled less frequently. It doesn't update the
+// repo: call UpdateOnce for that.
+func (s *updateScheduler) SetPushed(id api.RepoID) {
+	schedPushes.Inc()
+	s.schedule.setPushed(id)
+}
+
 // DebugDump returns the state of the update scheduler for debugging.
 func (s *updateScheduler) DebugDump() interface{} {
 	data := struct {
@@ -606,6 +623,7 @@ type scheduledRepoUpdate struct {
 	Repo     configuredRepo // the repo to update
 	Interval time.Duration  // how regularly the repo is updated
 	Due      time.Time      // the next time that the repo will be enqueued for a update
+	LastPush time.Time      // the last time that a push webhook was delivered for the repo
 	Index    int            `json:"-"` // the index in the heap
 }
 
@@ -678,6 +696,10 @@ func (s *schedule) updateInterval(repo configuredRepo, interval time.Duration) {
 
 	s.mu.Lock()
 	if update := s.index[repo.ID]; update != nil {
+		if timeNow().Sub(update.LastPush) < pushExpiry {
+			// The repo is kept up to date by push webhooks.
+			interval = maxDelay
+		}
 		switch {
 		case interval > maxDelay:
 			update.Interval = maxDelay
@@ -694,6 +716,25 @@ func (s *schedule) updateInterval(repo configuredRepo, interval time.Duration) {
 	s.mu.Unlock()
 }
 
+// setPushed records that a push webhook was delivered for a repo and schedules
+// its next update after maxDelay. It does nothing if the repo is not in the
+// schedule.
+func (s *schedule) setPushed(id api.RepoID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update := s.index[id]
+	if update == nil {
+		return
+	}
+
+	update.LastPush = timeNow()
+	update.Interval = maxDelay
+	update.Due = timeNow().Add(maxDelay)
+	heap.Fix(s, update.Index)
+	s.rescheduleTimer()
+}
+
 // remove removes a repo from the schedule.
 func (s *schedule) remove(repo configuredRepo) (removed bool) {
 	if repo.ID == 0 {
@@ -999,6 +999,62 @@ func TestSchedule_updateInterval(t *testing.T) {
 			timeAfterFuncDelays: []time.Duration{time.Minute, time.Minute, time.Minute, time.Minute, time.Minute},
 			wakeupNotifications: 5,
 		},
+		{
+			name: "recently pushed",
+			initialSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:     a,
+					Interval: maxDelay,
+					Due:      defaultTime.Add(maxDelay),
+					LastPush: defaultTime,
+				},
+			},
+			updateCalls: []*updateCall{
+				{
+					repo:     a,
+					time:     defaultTime.Add(time.Minute),
+					interval: time.Minute,
+				},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:     a,
+					Interval: maxDelay,
+					Due:      defaultTime.Add(time.Minute + maxDelay),
+					LastPush: defaultTime,
+				},
+			},
+			timeAfterFuncDelays: []time.Duration{maxDelay},
+			wakeupNotifications: 1,
+		},
+		{
+			name: "push expired",
+			initialSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:     a,
+					Interval: maxDelay,
+					Due:      defaultTime.Add(pushExpiry),
+					LastPush: defaultTime,
+				},
+			},
+			updateCalls: []*updateCall{
+				{
+					repo:     a,
+					time:     defaultTime.Add(pushExpiry),
+					interval: time.Minute,
+				},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:     a,
+					Interval: time.Minute,
+					Due:      defaultTime.Add(pushExpiry + time.Minute),
+					LastPush: defaultTime,
+				},
+			},
+			timeAfterFuncDelays: []time.Duration{time.Minute},
+			wakeupNotifications: 1,
+		},
 	}
 
 	for _, test := range tests {
@@ -1020,6 +1076,57 @@ func TestSchedule_updateInterval(t *testing.T) {
 	}
 }
 
+func TestSchedule_setPushed(t *testing.T) {
+	a := configuredRepo{ID: 1, Name: "a", URL: "a.com"}
+	b := configuredRepo{ID: 2, Name: "b", URL: "b.com"}
+
+	tests := []struct {
+		name                string
+		initialSchedule     []*scheduledRepoUpdate
+		pushed              []api.RepoID
+		finalSchedule       []*scheduledRepoUpdate
+		timeAfterFuncDelays []time.Duration
+		wakeupNotifications int
+	}{
+		{
+			name:   "push has no effect if repo isn't in schedule",
+			pushed: []api.RepoID{a.ID},
+		},
+		{
+			name: "push reschedules repo",
+			initialSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: minDelay, Due: defaultTime.Add(time.Minute)},
+				{Repo: b, Interval: minDelay, Due: defaultTime.Add(2 * time.Minute)},
+			},
+			pushed: []api.RepoID{a.ID},
+			finalSchedule: []*scheduledRepoUpdate{
+				{Repo: b, Interval: minDelay, Due: defaultTime.Add(2 * time.Minute)},
+				{Repo: a, Interval: maxDelay, Due: defaultTime.Add(maxDelay), LastPush: defaultTime},
+			},
+			timeAfterFuncDelays: []time.Duration{2 * time.Minute},
+			wakeupNotifications: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, stop := startRecording()
+			defer stop()
+
+			s := NewUpdateScheduler()
+			setupInitialSchedule(s, test.initialSchedule)
+
+			mockTime(defaultTime)
+			for _, id := range test.pushed {
+				s.SetPushed(id)
+			}
+
+			verifySchedule(t, s, test.finalSchedule)
+			verifyScheduleRecording(t, s, test.timeAfterFuncDelays, test.wakeupNotifications, r)
+		})
+	}
+}
+
 func TestSchedule_remove(t *testing.T) {
 	a := configuredRepo{ID: 1, Name: "a", URL: "a.com"}
 	b := configuredRepo{ID: 2, Name: "b", URL: "b.com"}
@@ -61,6 +61,8 @@ func NewSource(svc *ExternalService, cf *httpcli.Factory) (Source, error) {
 		return NewBitbucketServerSource(svc, cf)
 	case extsvc.KindBitbucketCloud:
 		return NewBitbucketCloudSource(svc, cf)
+	case extsvc.KindGerrit:
+		return NewGerritSource(svc, cf)
 	case extsvc.KindGitolite:
 		return NewGitoliteSource(svc, cf)
 	case extsvc.KindPhabricator:
@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -105,6 +106,8 @@ func (e *ExternalService) Exclude(rs ...*Repo) error {
 		return e.excludeBitbucketServerRepos(rs...)
 	case extsvc.KindAWSCodeCommit:
 		return e.excludeAWSCodeCommitRepos(rs...)
+	case extsvc.KindGerrit:
+		return e.excludeGerritRepos(rs...)
 	case extsvc.KindGitolite:
 		return e.excludeGitoliteRepos(rs...)
 	case extsvc.KindOther:
@@ -309,6 +312,34 @@ func (e *ExternalService) excludeGitoliteRepos(rs ...*Repo) error {
 	})
 }
 
+// excludeGerritRepos changes the configuration of a Gerrit external service to exclude the
+// given repos from being synced.
+func (e *ExternalService) excludeGerritRepos(rs ...*Repo) error {
+	if len(rs) == 0 {
+		return nil
+	}
+
+	return e.config(extsvc.KindGerrit, func(v interface{}) (string, interface{}, error) {
+		c := v.(*schema.GerritConnection)
+		set := make(map[string]bool, len(c.Exclude))
+		for _, ex := range c.Exclude {
+			if ex.Name != "" {
+				set[ex.Name] = true
+			}
+		}
+
+		for _, r := range rs {
+			p, ok := r.Metadata.(*gerrit.Project)
+			if ok && p.Name != "" && !set[p.Name] {
+				c.Exclude = append(c.Exclude, &schema.ExcludedGerritProject{Name: p.Name})
+				set[p.Name] = true
+			}
+		}
+
+		return "exclude", c.Exclude, nil
+	})
+}
+
 // excludeGithubRepos changes the configuration of a Github external service to exclude the
 // given repos from being synced.
 func (e *ExternalService) excludeGithubRepos(rs ...*Repo) error {
@@ -447,6 +478,8 @@ func (e ExternalService) schema() string {
 		return schema.AWSCodeCommitSchemaJSON
 	case extsvc.KindBitbucketServer:
 		return schema.BitbucketServerSchemaJSON
+	case extsvc.KindGerrit:
+		return schema.GerritSchemaJSON
 	case extsvc.KindGitHub:
 		return schema.GitHubSchemaJSON
 	case extsvc.KindGitLab:
@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
@@ -96,6 +97,19 @@ func TestExternalService_Exclude(t *testing.T) {
 		UpdatedAt: now,
 	}
 
+	gerritService := ExternalService{
+		Kind:        extsvc.KindGerrit,
+		DisplayName: "Gerrit",
+		Config: `{
+			// Some comment
+			"url": "https://gerrit.mycorp.com",
+			"username": "admin",
+			"password": "secret"
+		}`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
 	otherService := ExternalService{
 		Kind:        extsvc.KindOther,
 		DisplayName: "Other code hosts",
@@ -181,6 +195,9 @@ func TestExternalService_Exclude(t *testing.T) {
 		{
 			Metadata: &gitolite.Repo{Name: "foo"},
 		},
+		{
+			Metadata: &gerrit.Project{ID: "org%2Ffoo", Name: "org/foo"},
+		},
 	}
 
 	var testCases []testCase
@@ -251,6 +268,18 @@ func TestExternalService_Exclude(t *testing.T) {
 					]
 				}`)
 			}),
+			gerritService.With(func(e *ExternalService) {
+				e.Config = formatJSON(t, `
+				{
+					// Some comment
+					"url": "https://gerrit.mycorp.com",
+					"username": "admin",
+					"password": "secret",
+					"exclude": [
+						{"name": "org/foo"}
+					]
+				}`)
+			}),
 			&otherService,
 		}
 
@@ -324,6 +353,18 @@ func TestExternalService_Exclude(t *testing.T) {
 					]
 				}`)
 			}),
+			gerritService.With(func(e *ExternalService) {
+				e.Config = formatJSON(t, `
+				{
+					// Some comment
+					"url": "https://gerrit.mycorp.com",
+					"username": "admin",
+					"password": "secret",
+					"exclude": [
+						{"pattern": "^experimental/.*"}
+					]
+				}`)
+			}),
 			otherService.With(func(e *ExternalService) {
 				e.Config = formatJSON(t, `
 				{
@@ -412,6 +453,19 @@ func TestExternalService_Exclude(t *testing.T) {
 						]
 					}`)
 				}),
+				gerritService.With(func(e *ExternalService) {
+					e.Config = formatJSON(t, `
+					{
+						// Some comment
+						"url": "https://gerrit.mycorp.com",
+						"username": "admin",
+						"password": "secret",
+						"exclude": [
+							{"pattern": "^experimental/.*"},
+							{"name": "org/foo"}
+						]
+					}`)
+				}),
 				otherService.With(func(e *ExternalService) {
 					e.Config = formatJSON(t, `
 					{
@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/awscodecommit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gerrit"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
@@ -38,6 +39,7 @@ type Server struct {
 	}
 	Scheduler interface {
 		UpdateOnce(id api.RepoID, name api.RepoName, url string)
+		SetPushed(id api.RepoID)
 		ScheduleInfo(id api.RepoID) *protocol.RepoUpdateSchedulerInfoResult
 	}
 	GitserverClient interface {
@@ -304,6 +306,9 @@ func (s *Server) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdate
 		}
 	}
 	s.Scheduler.UpdateOnce(repo.ID, req.Repo, req.URL)
+	if req.Pushed {
+		s.Scheduler.SetPushed(repo.ID)
+	}
 
 	return &protocol.RepoUpdateResponse{
 		ID:   repo.ID,
@@ -708,6 +713,22 @@ func newRepoInfo(r *repos.Repo) (*protocol.RepoInfo, error) {
 			Blob:   webURL + "/browse/{rev}/--/{path}",
 			Commit: webURL + "/commit/{commit}",
 		}
+	case extsvc.TypeGerrit:
+		proj := r.Metadata.(*gerrit.Project)
+		for _, l := range proj.WebLinks {
+			// Gerrit doesn't serve repository contents itself, but the gitiles
+			// plugin registers a "browse" web link for every project.
+			if l.Name != "browse" {
+				continue
+			}
+			info.Links = &protocol.RepoLinks{
+				Root:   l.URL,
+				Tree:   pathAppend(l.URL, "/+/{rev}/{path}"),
+				Blob:   pathAppend(l.URL, "/+/{rev}/{path}"),
+				Commit: pathAppend(l.URL, "/+/{commit}"),
+			}
+			break
+		}
 	}
 
 	return &info, nil
@@ -351,11 +351,12 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 	ctx := context.Background()
 
 	type testCase struct {
-		name  string
-		store repos.Store
-		repo  gitserver.Repo
-		res   *protocol.RepoUpdateResponse
-		err   string
+		name   string
+		store  repos.Store
+		repo   gitserver.Repo
+		pushed bool
+		res    *protocol.RepoUpdateResponse
+		err    string
 	}
 
 	var testCases []testCase
@@ -422,6 +423,22 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 				},
 			}
 		}(),
+		func() testCase {
+			store := new(repos.FakeStore)
+			repo := repo.Clone()
+			must(store.UpsertRepos(ctx, repo))
+			return testCase{
+				name:   "pushed",
+				store:  store,
+				repo:   gitserver.Repo{Name: api.RepoName(repo.Name)},
+				pushed: true,
+				res: &protocol.RepoUpdateResponse{
+					ID:   repo.ID,
+					Name: repo.Name,
+					URL:  repo.CloneURLs()[0],
+				},
+			}
+		}(),
 	)
 
 	for _, tc := range testCases {
@@ -429,7 +446,8 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 		ctx := context.Background()
 
 		t.Run(tc.name, func(t *testing.T) {
-			s := &Server{Store: tc.store, Scheduler: &fakeScheduler{}}
+			scheduler := &fakeScheduler{}
+			s := &Server{Store: tc.store, Scheduler: scheduler}
 			srv := httptest.NewServer(s.Handler())
 			defer srv.Close()
 			cli := repoupdater.Client{URL: srv.URL}
@@ -438,7 +456,11 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 				tc.err = "<nil>"
 			}
 
-			res, err := cli.EnqueueRepoUpdate(ctx, tc.repo)
+			enqueue := cli.EnqueueRepoUpdate
+			if tc.pushed {
+				enqueue = cli.EnqueuePushedRepoUpdate
+			}
+			res, err := enqueue(ctx, tc.repo)
 			if have, want := fmt.Sprint(err), tc.err; have != want {
 				t.Errorf("have err: %q, want: %q", have, want)
 			}
@@ -446,6 +468,14 @@ func TestServer_EnqueueRepoUpdate(t *testing.T) {
 			if have, want := res, tc.res; !reflect.DeepEqual(have, want) {
 				t.Errorf("response: %s", cmp.Diff(have, want))
 			}
+
+			var wantPushed []api.RepoID
+			if tc.pushed {
+				wantPushed = []api.RepoID{res.ID}
+			}
+			if have, want := scheduler.pushed, wantPushed; !reflect.DeepEqual(have, want) {
+				t.Errorf("pushed repos: %s", cmp.Diff(have, want))
+			}
 		})
 	}
 }
@@ -1134,9 +1164,12 @@ func (s *fakeRepoSource) GetRepo(context.Context, string) (*repos.Repo, error) {
 	return s.repo.Clone(), s.err
 }
 
-type fakeScheduler struct{}
+type fakeScheduler struct {
+	pushed []api.RepoID
+}
 
 func (s *fakeScheduler) UpdateOnce(_ api.RepoID, _ api.RepoName, _ string) {}
+func (s *fakeScheduler) SetPushed(id api.RepoID)                           { s.pushed = append(s.pushed, id) }
 func (s *fakeScheduler) ScheduleInfo(id api.RepoID) *protocol.RepoUpdateSchedulerInfoResult {
 	return &protocol.RepoUpdateSchedulerInfoResult{}
 }
@@ -2,6 +2,7 @@
 package protocol
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -81,6 +82,12 @@ type PatternInfo struct {
 	// glob or Go regexp that represents multiple such patterns ANDed together.
 	IncludePatterns []string
 
+	// IncludePaths, if non-empty, is the list of the paths of the only files
+	// to search. It only applies when IsStructuralPat is true. The frontend
+	// sets it to the files that indexed search found to contain all literal
+	// substrings of the pattern.
+	IncludePaths []string
+
 	// IncludeExcludePatternAreRegExps indicates that ExcludePattern, IncludePattern,
 	// and IncludePatterns are regular expressions (not globs).
 	PathPatternsAreRegExps bool
@@ -105,10 +112,19 @@ type PatternInfo struct {
 
 	// CombyRule is a rule that constrains matching for structural search. It only applies when IsStructuralPat is true.
 	CombyRule string
+
+	// PatternExpression, if non-nil, is a boolean expression of patterns that
+	// is searched for instead of Pattern. Its patterns are interpreted like
+	// Pattern (according to IsRegExp, IsWordMatch and IsCaseSensitive). It is
+	// not supported for structural search.
+	PatternExpression *PatternExpression
 }
 
 func (p *PatternInfo) String() string {
 	args := []string{fmt.Sprintf("%q", p.Pattern)}
+	if p.PatternExpression != nil {
+		args[0] = p.PatternExpression.String()
+	}
 	if p.IsRegExp {
 		args = append(args, "re")
 	}
@@ -155,6 +171,92 @@ func (p *PatternInfo) String() string {
 	return fmt.Sprintf("PatternInfo{%s}", strings.Join(args, ","))
 }
 
+// PatternExpressionKind is the kind of a node in a PatternExpression.
+type PatternExpressionKind string
+
+const (
+	// PatternExpressionPattern is a leaf node matching its Pattern.
+	PatternExpressionPattern PatternExpressionKind = "pattern"
+	// PatternExpressionAnd matches if all of its Operands match.
+	PatternExpressionAnd PatternExpressionKind = "and"
+	// PatternExpressionOr matches if any of its Operands match.
+	PatternExpressionOr PatternExpressionKind = "or"
+	// PatternExpressionNot matches if its single operand does not match.
+	PatternExpressionNot PatternExpressionKind = "not"
+)
+
+// PatternExpression is a boolean expression of search patterns, such as
+// "foo and not bar". A file matches the expression if its content (or path,
+// see PatternInfo.PatternMatchesPath) satisfies it.
+type PatternExpression struct {
+	Kind PatternExpressionKind `json:"kind"`
+
+	// Pattern is the pattern matched by a PatternExpressionPattern node.
+	Pattern string `json:"pattern,omitempty"`
+
+	// Operands are the child nodes of an and, or or not node.
+	Operands []*PatternExpression `json:"operands,omitempty"`
+}
+
+// UnmarshalText decodes and validates an expression encoded as JSON, which is
+// how it is sent as a single form value.
+func (e *PatternExpression) UnmarshalText(text []byte) error {
+	if err := json.Unmarshal(text, e); err != nil {
+		return err
+	}
+	return e.Validate()
+}
+
+// UnmarshalJSON decodes an expression from a JSON object. It is needed
+// because encoding/json would otherwise use UnmarshalText, which expects a
+// JSON string.
+func (e *PatternExpression) UnmarshalJSON(data []byte) error {
+	type expression PatternExpression
+	return json.Unmarshal(data, (*expression)(e))
+}
+
+// Validate returns an error if the expression or one of its descendants is
+// malformed.
+func (e *PatternExpression) Validate() error {
+	switch e.Kind {
+	case PatternExpressionPattern:
+		if len(e.Operands) > 0 {
+			return fmt.Errorf("pattern expression %q has operands", e.Pattern)
+		}
+		return nil
+	case PatternExpressionAnd, PatternExpressionOr:
+		if len(e.Operands) == 0 {
+			return fmt.Errorf("%s expression has no operands", e.Kind)
+		}
+	case PatternExpressionNot:
+		if len(e.Operands) != 1 {
+			return fmt.Errorf("not expression has %d operands, want 1", len(e.Operands))
+		}
+	default:
+		return fmt.Errorf("unknown pattern expression kind %q", e.Kind)
+	}
+	for _, operand := range e.Operands {
+		if operand == nil {
+			return fmt.Errorf("%s expression has a nil operand", e.Kind)
+		}
+		if err := operand.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *PatternExpression) String() string {
+	if e.Kind == PatternExpressionPattern {
+		return fmt.Sprintf("%q", e.Pattern)
+	}
+	operands := make([]string, 0, len(e.Operands))
+	for _, operand := range e.Operands {
+		operands = append(operands, operand.String())
+	}
+	return fmt.Sprintf("(%s %s)", e.Kind, strings.Join(operands, " "))
+}
+
 // Response represents the response from a Search request.
 type Response struct {
 	Matches []FileMatch
@@ -0,0 +1,129 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+)
+
+// matchTree is a compiled protocol.PatternExpression. Each pattern in the
+// expression is compiled like the Pattern of a request.
+type matchTree struct {
+	kind protocol.PatternExpressionKind
+
+	// re and literalSubstring are set for pattern nodes. See readerGrep.
+	re               *regexp.Regexp
+	literalSubstring []byte
+
+	operands []*matchTree
+}
+
+// compileMatchTree compiles the patterns of expr, interpreting them according
+// to the options in p.
+func compileMatchTree(expr *protocol.PatternExpression, p *protocol.PatternInfo) (*matchTree, error) {
+	if err := expr.Validate(); err != nil {
+		return nil, err
+	}
+	if expr.Kind == protocol.PatternExpressionPattern {
+		re, literalSubstring, err := compilePattern(expr.Pattern, p)
+		if err != nil {
+			return nil, err
+		}
+		return &matchTree{kind: expr.Kind, re: re, literalSubstring: literalSubstring}, nil
+	}
+
+	t := &matchTree{kind: expr.Kind}
+	for _, operand := range expr.Operands {
+		o, err := compileMatchTree(operand, p)
+		if err != nil {
+			return nil, err
+		}
+		t.operands = append(t.operands, o)
+	}
+	return t, nil
+}
+
+// find reports whether buf satisfies the expression. If collect is true, it
+// also returns the sorted locations of the matches of every pattern that
+// contributes to buf satisfying the expression, so that they can be
+// highlighted. Negated patterns never contribute locations.
+func (t *matchTree) find(buf []byte, collect bool) (matched bool, locs [][]int) {
+	switch t.kind {
+	case protocol.PatternExpressionPattern:
+		if !bytes.Contains(buf, t.literalSubstring) {
+			return false, nil
+		}
+		if !collect {
+			return t.re.Match(buf), nil
+		}
+		locs = t.re.FindAllIndex(buf, maxLineMatches+1)
+		return len(locs) > 0, locs
+
+	case protocol.PatternExpressionAnd:
+		for _, operand := range t.operands {
+			ok, operandLocs := operand.find(buf, collect)
+			if !ok {
+				return false, nil
+			}
+			locs = append(locs, operandLocs...)
+		}
+		return true, sortLocs(locs)
+
+	case protocol.PatternExpressionOr:
+		for _, operand := range t.operands {
+			ok, operandLocs := operand.find(buf, collect)
+			if !ok {
+				continue
+			}
+			matched = true
+			if !collect {
+				// We don't need the locations of the other operands.
+				break
+			}
+			locs = append(locs, operandLocs...)
+		}
+		return matched, sortLocs(locs)
+
+	case protocol.PatternExpressionNot:
+		ok, _ := t.operands[0].find(buf, false)
+		return !ok, nil
+	}
+	// Unreachable, compileMatchTree validates the expression.
+	return false, nil
+}
+
+// sortLocs sorts match locations by start (and then end) offset and removes
+// duplicates, which occur when operands match the same text.
+func sortLocs(locs [][]int) [][]int {
+	if len(locs) < 2 {
+		return locs
+	}
+	sort.Slice(locs, func(i, j int) bool {
+		if locs[i][0] != locs[j][0] {
+			return locs[i][0] < locs[j][0]
+		}
+		return locs[i][1] < locs[j][1]
+	})
+	deduped := locs[:1]
+	for _, loc := range locs[1:] {
+		if last := deduped[len(deduped)-1]; loc[0] != last[0] || loc[1] != last[1] {
+			deduped = append(deduped, loc)
+		}
+	}
+	return deduped
+}
+
+func (t *matchTree) String() string {
+	if t.kind == protocol.PatternExpressionPattern {
+		return fmt.Sprintf("%q", t.re.String())
+	}
+	operands := make([]string, 0, len(t.operands))
+	for _, operand := range t.operands {
+		operands = append(operands, operand.String())
+	}
+	return fmt.Sprintf("(%s %s)", t.kind, strings.Join(operands, " "))
+}
@@ -223,7 +223,21 @@ func (s *Service) search(ctx context.Context, p *protocol.Request) (matches []pr
 	archiveSize.Observe(float64(bytes))
 
 	if p.IsStructuralPat {
-		matches, limitHit, err = structuralSearch(ctx, zipPath, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, p.Repo)
+		includePatterns := p.IncludePatterns
+		searchedFiles := len(zf.Files)
+		if len(p.IncludePaths) > 0 {
+			// Only pass the files that may contain matches to comby.
+			var cleanup func()
+			zipPath, searchedFiles, cleanup, err = writeFilteredZip(zf, p.IncludePaths)
+			if err != nil {
+				return nil, false, false, errors.Wrap(err, "failed to write filtered archive")
+			}
+			defer cleanup()
+			includePatterns = p.IncludePaths
+		}
+		structuralSearchFiles.WithLabelValues("searched").Add(float64(searchedFiles))
+		structuralSearchFiles.WithLabelValues("skipped").Add(float64(len(zf.Files) - searchedFiles))
+		matches, limitHit, err = structuralSearch(ctx, zipPath, p.Pattern, p.CombyRule, p.Languages, includePatterns, p.Repo)
 	} else {
 		rg, err := compile(&p.PatternInfo)
 		if err != nil {
@@ -242,9 +256,12 @@ func validateParams(p *protocol.Request) error {
 	if len(p.Commit) != 40 {
 		return errors.Errorf("Commit must be resolved (Commit=%q)", p.Commit)
 	}
-	if p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 {
+	if p.Pattern == "" && p.PatternExpression == nil && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 {
 		return errors.New("At least one of pattern and include/exclude pattners must be non-empty")
 	}
+	if p.PatternExpression != nil && p.IsStructuralPat {
+		return errors.New("PatternExpression is not supported for structural search")
+	}
 	return nil
 }
 
@@ -43,6 +43,10 @@ type readerGrep struct {
 	// re is the regexp to match, or nil if empty ("match all files' content").
 	re *regexp.Regexp
 
+	// tree is the boolean expression of patterns to match instead of re, if
+	// the request has a pattern expression.
+	tree *matchTree
+
 	// ignoreCase if true means we need to do case insensitive matching.
 	ignoreCase bool
 
@@ -68,47 +72,18 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 	var (
 		re               *regexp.Regexp
 		literalSubstring []byte
+		tree             *matchTree
+		err              error
 	)
-	if p.Pattern != "" {
-		expr := p.Pattern
-		if !p.IsRegExp {
-			expr = regexp.QuoteMeta(expr)
-		}
-		if p.IsWordMatch {
-			expr = `\b` + expr + `\b`
-		}
-		if p.IsRegExp {
-			// We don't do the search line by line, therefore we want the
-			// regex engine to consider newlines for anchors (^$).
-			expr = "(?m:" + expr + ")"
-		}
-		if !p.IsCaseSensitive {
-			// We don't just use (?i) because regexp library doesn't seem
-			// to contain good optimizations for case insensitive
-			// search. Instead we lowercase the input and pattern.
-			re, err := syntax.Parse(expr, syntax.Perl)
-			if err != nil {
-				return nil, err
-			}
-			lowerRegexpASCII(re)
-			expr = re.String()
-		}
-
-		var err error
-		re, err = regexp.Compile(expr)
+	if p.PatternExpression != nil {
+		tree, err = compileMatchTree(p.PatternExpression, p)
 		if err != nil {
 			return nil, err
 		}
-
-		// Only use literalSubstring optimization if the regex engine doesn't
-		// have a prefix to use.
-		if pre, _ := re.LiteralPrefix(); pre == "" {
-			ast, err := syntax.Parse(expr, syntax.Perl)
-			if err != nil {
-				return nil, err
-			}
-			ast = ast.Simplify()
-			literalSubstring = []byte(longestLiteral(ast))
+	} else if p.Pattern != "" {
+		re, literalSubstring, err = compilePattern(p.Pattern, p)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -123,17 +98,66 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 
 	return &readerGrep{
 		re:               re,
+		tree:             tree,
 		ignoreCase:       !p.IsCaseSensitive,
 		matchPath:        matchPath,
 		literalSubstring: literalSubstring,
 	}, nil
 }
 
+// compilePattern returns the regexp for matching pattern, interpreted
+// according to the options in p, and a literal substring of every match (see
+// readerGrep.literalSubstring).
+func compilePattern(pattern string, p *protocol.PatternInfo) (*regexp.Regexp, []byte, error) {
+	expr := pattern
+	if !p.IsRegExp {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if p.IsWordMatch {
+		expr = `\b` + expr + `\b`
+	}
+	if p.IsRegExp {
+		// We don't do the search line by line, therefore we want the
+		// regex engine to consider newlines for anchors (^$).
+		expr = "(?m:" + expr + ")"
+	}
+	if !p.IsCaseSensitive {
+		// We don't just use (?i) because regexp library doesn't seem
+		// to contain good optimizations for case insensitive
+		// search. Instead we lowercase the input and pattern.
+		re, err := syntax.Parse(expr, syntax.Perl)
+		if err != nil {
+			return nil, nil, err
+		}
+		lowerRegexpASCII(re)
+		expr = re.String()
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Only use literalSubstring optimization if the regex engine doesn't
+	// have a prefix to use.
+	var literalSubstring []byte
+	if pre, _ := re.LiteralPrefix(); pre == "" {
+		ast, err := syntax.Parse(expr, syntax.Perl)
+		if err != nil {
+			return nil, nil, err
+		}
+		ast = ast.Simplify()
+		literalSubstring = []byte(longestLiteral(ast))
+	}
+	return re, literalSubstring, nil
+}
+
 // Copy returns a copied version of rg that is safe to use from another
 // goroutine.
 func (rg *readerGrep) Copy() *readerGrep {
 	return &readerGrep{
 		re:               rg.re,
+		tree:             rg.tree,
 		ignoreCase:       rg.ignoreCase,
 		matchPath:        rg.matchPath,
 		literalSubstring: rg.literalSubstring,
@@ -143,19 +167,25 @@ func (rg *readerGrep) Copy() *readerGrep {
 // matchString returns whether rg's regexp pattern matches s. It is intended to be
 // used to match file paths.
 func (rg *readerGrep) matchString(s string) bool {
-	if rg.re == nil {
+	if rg.re == nil && rg.tree == nil {
 		return true
 	}
 	if rg.ignoreCase {
 		s = strings.ToLower(s)
 	}
+	if rg.tree != nil {
+		matched, _ := rg.tree.find([]byte(s), false)
+		return matched
+	}
 	return rg.re.MatchString(s)
 }
 
-// Find returns a LineMatch for each line that matches rg in reader.
+// Find returns a LineMatch for each line that matches rg in reader. matched
+// reports whether the file matches, which for a pattern expression may be the
+// case even if there are no LineMatches (e.g. for "not foo").
 // LimitHit is true if some matches may not have been included in the result.
 // NOTE: This is not safe to use concurrently.
-func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []protocol.LineMatch, limitHit bool, err error) {
+func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []protocol.LineMatch, matched, limitHit bool, err error) {
 	// fileMatchBuf is what we run match on, fileBuf is the original
 	// data (for Preview).
 	fileBuf := zf.DataFor(f)
@@ -181,11 +211,19 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 	// searching for results. We use the same approach when we search
 	// per-line. Additionally if we have a non-empty literalSubstring, we use
 	// that to prune out files since doing bytes.Index is very fast.
-	if !bytes.Contains(fileMatchBuf, rg.literalSubstring) {
-		return nil, false, nil
+	var locs [][]int
+	if rg.tree != nil {
+		matched, locs = rg.tree.find(fileMatchBuf, true)
+		if !matched {
+			return nil, false, false, nil
+		}
+	} else {
+		if !bytes.Contains(fileMatchBuf, rg.literalSubstring) {
+			return nil, false, false, nil
+		}
+		locs = rg.re.FindAllIndex(fileMatchBuf, maxLineMatches+1)
 	}
 
-	locs := rg.re.FindAllIndex(fileMatchBuf, maxLineMatches+1)
 	lastStart := 0
 	lastLineNumber := 0
 	lastMatchIndex := 0
@@ -224,7 +262,11 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 			break
 		}
 	}
-	return matches, limitHit, nil
+	if rg.tree == nil {
+		// A pattern matches a file only if it matches a non-empty line.
+		matched = len(matches) > 0
+	}
+	return matches, matched, limitHit, nil
 }
 
 func hydrateLineNumbers(fileBuf []byte, lastLineNumber, lastMatchIndex, lineStart int, match []int) (lineNumber, matchIndex int) {
@@ -284,14 +326,14 @@ func appendMatches(matches []protocol.LineMatch, fileBuf []byte, matchLineBuf []
 }
 
 // FindZip is a convenience function to run Find on f.
-func (rg *readerGrep) FindZip(zf *store.ZipFile, f *store.SrcFile) (protocol.FileMatch, error) {
-	lm, limitHit, err := rg.Find(zf, f)
+func (rg *readerGrep) FindZip(zf *store.ZipFile, f *store.SrcFile) (protocol.FileMatch, bool, error) {
+	lm, matched, limitHit, err := rg.Find(zf, f)
 	return protocol.FileMatch{
 		Path:        f.Name,
 		LineMatches: lm,
 		MatchCount:  len(lm),
 		LimitHit:    limitHit,
-	}, err
+	}, matched, err
 }
 
 // regexSearch concurrently searches files in zr looking for matches using rg.
@@ -301,6 +343,9 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *store.ZipFile, fileMat
 	if rg.re != nil {
 		span.SetTag("re", rg.re.String())
 	}
+	if rg.tree != nil {
+		span.SetTag("tree", rg.tree.String())
+	}
 	span.SetTag("path", rg.matchPath.String())
 	defer func() {
 		if err != nil {
@@ -337,7 +382,7 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *store.ZipFile, fileMat
 		matches   = []protocol.FileMatch{}
 	)
 
-	if rg.re == nil || (patternMatchesPaths && !patternMatchesContent) {
+	if (rg.re == nil && rg.tree == nil) || (patternMatchesPaths && !patternMatchesContent) {
 		// Fast path for only matching file paths (or with a nil pattern, which matches all files,
 		// so is effectively matching only on file paths).
 		for _, f := range files {
@@ -394,8 +439,7 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *store.ZipFile, fileMat
 				atomic.AddUint32(&filesSearched, 1)
 
 				// process
-				var fm protocol.FileMatch
-				fm, err := rg.FindZip(zf, f)
+				fm, match, err := rg.FindZip(zf, f)
 				if err != nil {
 					wgErrOnce.Do(func() {
 						wgErr = err
@@ -403,7 +447,6 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *store.ZipFile, fileMat
 					})
 					return
 				}
-				match := len(fm.LineMatches) > 0
 				if !match && patternMatchesPaths {
 					// Try matching against the file path.
 					match = rg.matchString(f.Name)
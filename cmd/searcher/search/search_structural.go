@@ -1,8 +1,11 @@
 package search
 
 import (
+	"archive/zip"
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -11,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/comby"
+	"github.com/sourcegraph/sourcegraph/internal/store"
 )
 
 // The Sourcegraph frontend and interface only allow LineMatches (matches on a
@@ -219,11 +223,64 @@ func structuralSearch(ctx context.Context, zipPath, pattern, rule string, langua
 	return matches, false, err
 }
 
+// writeFilteredZip writes the files of zf with the given paths to a new zip
+// archive in a temporary file, so that comby only searches these files. It
+// returns the path of the archive, the number of files in it and a function
+// that removes it.
+func writeFilteredZip(zf *store.ZipFile, paths []string) (zipPath string, n int, cleanup func(), err error) {
+	include := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		include[path] = true
+	}
+
+	f, err := ioutil.TempFile("", "structural-search-*.zip")
+	if err != nil {
+		return "", 0, nil, err
+	}
+	remove := func() { os.Remove(f.Name()) }
+	defer func() {
+		if err != nil {
+			remove()
+		}
+	}()
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	zw := zip.NewWriter(f)
+	for i := range zf.Files {
+		file := &zf.Files[i]
+		if !include[file.Name] {
+			continue
+		}
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: file.Name, Method: zip.Store})
+		if err != nil {
+			return "", 0, nil, err
+		}
+		if _, err := w.Write(zf.DataFor(file)); err != nil {
+			return "", 0, nil, err
+		}
+		n++
+	}
+	if err := zw.Close(); err != nil {
+		return "", 0, nil, err
+	}
+	return f.Name(), n, remove, nil
+}
+
 var requestTotalStructuralSearch = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Name: "searcher_service_request_total_structural_search",
 	Help: "Number of returned structural search requests.",
 }, []string{"language"})
 
+var structuralSearchFiles = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "searcher_service_structural_search_files_total",
+	Help: "Number of files in the archives of structural search requests, by whether comby searched them or the indexed search prefilter skipped them.",
+}, []string{"status"})
+
 func init() {
 	prometheus.MustRegister(requestTotalStructuralSearch)
+	prometheus.MustRegister(structuralSearchFiles)
 }
@@ -1,8 +1,10 @@
 package search
 
 import (
+	"archive/zip"
 	"context"
 	"encoding/json"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"sort"
@@ -427,3 +429,62 @@ func bar() {
 		}
 	})
 }
+
+func TestWriteFilteredZip(t *testing.T) {
+	input := map[string]string{
+		"main.go":       "func main() {}",
+		"cmd/foo.go":    "func foo() {}",
+		"cmd/bar.go":    "func bar() {}",
+		"README.md":     "# readme",
+		"cmd/README.md": "# cmd",
+	}
+	zipData, err := testutil.CreateZip(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zf, err := testutil.MockZipFile(zipData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zipPath, n, cleanup, err := writeFilteredZip(zf, []string{"main.go", "cmd/bar.go", "missing.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	if n != 2 {
+		t.Errorf("got %d files, want 2", n)
+	}
+
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	got := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[f.Name] = string(data)
+	}
+	want := map[string]string{
+		"main.go":    "func main() {}",
+		"cmd/bar.go": "func bar() {}",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	cleanup()
+	if _, err := os.Stat(zipPath); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be removed, got err %v", zipPath, err)
+	}
+}
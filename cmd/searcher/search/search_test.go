@@ -181,6 +181,25 @@ main.go:7:}
 `},
 
 		{protocol.PatternInfo{Pattern: "^$", IsRegExp: true}, ``},
+
+		{protocol.PatternInfo{PatternExpression: and(pattern("hello"), not(pattern("fmt")))}, `
+README.md:1:# Hello World
+README.md:3:Hello world example in go
+`},
+
+		{protocol.PatternInfo{PatternExpression: and(pattern("package"), or(pattern("world"), pattern("println"))), IsCaseSensitive: true}, `
+main.go:1:package main
+main.go:6:	fmt.Println("Hello world")
+`},
+
+		{protocol.PatternInfo{PatternExpression: or(pattern("func.*main"), pattern("^# "), pattern("doesnotmatch")), IsRegExp: true}, `
+README.md:1:# Hello World
+main.go:5:func main() {
+`},
+
+		{protocol.PatternInfo{PatternExpression: not(pattern("o")), IncludePatterns: []string{"*.{go,md,txt,plus}"}}, `
+abc.txt
+`},
 		{protocol.PatternInfo{
 			Pattern:         "filename contains regex metachars",
 			IncludePatterns: []string{"file++.plus"},
@@ -291,6 +310,27 @@ func TestSearch_badrequest(t *testing.T) {
 			},
 		},
 
+		// Malformed pattern expression
+		{
+			Repo:   "foo",
+			URL:    "u",
+			Commit: "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			PatternInfo: protocol.PatternInfo{
+				PatternExpression: &protocol.PatternExpression{Kind: protocol.PatternExpressionNot},
+			},
+		},
+
+		// Pattern expression with structural search
+		{
+			Repo:   "foo",
+			URL:    "u",
+			Commit: "deadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			PatternInfo: protocol.PatternInfo{
+				PatternExpression: pattern("test"),
+				IsStructuralPat:   true,
+			},
+		},
+
 		// Bad include glob
 		{
 			Repo:   "foo",
@@ -368,6 +408,13 @@ func doSearch(u string, p *protocol.Request) ([]protocol.FileMatch, error) {
 		"IncludePatterns": p.IncludePatterns,
 		"ExcludePattern":  []string{p.ExcludePattern},
 	}
+	if p.PatternExpression != nil {
+		expr, err := json.Marshal(p.PatternExpression)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("PatternExpression", string(expr))
+	}
 	if p.IsRegExp {
 		form.Set("IsRegExp", "true")
 	}
@@ -413,6 +460,22 @@ func doSearch(u string, p *protocol.Request) ([]protocol.FileMatch, error) {
 	return r.Matches, err
 }
 
+func pattern(p string) *protocol.PatternExpression {
+	return &protocol.PatternExpression{Kind: protocol.PatternExpressionPattern, Pattern: p}
+}
+
+func and(operands ...*protocol.PatternExpression) *protocol.PatternExpression {
+	return &protocol.PatternExpression{Kind: protocol.PatternExpressionAnd, Operands: operands}
+}
+
+func or(operands ...*protocol.PatternExpression) *protocol.PatternExpression {
+	return &protocol.PatternExpression{Kind: protocol.PatternExpressionOr, Operands: operands}
+}
+
+func not(operand *protocol.PatternExpression) *protocol.PatternExpression {
+	return &protocol.PatternExpression{Kind: protocol.PatternExpressionNot, Operands: []*protocol.PatternExpression{operand}}
+}
+
 func newStore(files map[string]string) (*store.Store, func(), error) {
 	buf := new(bytes.Buffer)
 	w := tar.NewWriter(buf)
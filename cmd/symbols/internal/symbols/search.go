@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"regexp/syntax"
 	"strings"
 	"time"
@@ -16,8 +18,10 @@ import (
 	"github.com/keegancsmith/sqlf"
 	"github.com/opentracing/opentracing-go/ext"
 	otlog "github.com/opentracing/opentracing-go/log"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 	nettrace "golang.org/x/net/trace"
 )
 
@@ -97,8 +101,8 @@ func (s *Service) search(ctx context.Context, args protocol.SearchArgs) (result
 // specified in `args`. If the database doesn't already exist in the disk cache,
 // it will create a new one and write all the symbols into it.
 func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (string, error) {
-	diskcacheFile, err := s.cache.OpenWithPath(ctx, fmt.Sprintf("%d-%s@%s", symbolsDBVersion, args.Repo, args.CommitID), func(fetcherCtx context.Context, tempDBFile string) error {
-		err := s.writeAllSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
+	diskcacheFile, err := s.cache.OpenWithPath(ctx, dbKey(args.Repo, args.CommitID), func(fetcherCtx context.Context, tempDBFile string) error {
+		err := s.writeSymbolsToNewDB(fetcherCtx, tempDBFile, args.Repo, args.CommitID)
 		if err != nil {
 			if err == context.Canceled {
 				log15.Error("Unable to parse repository symbols within the context", "repo", args.Repo, "commit", args.CommitID, "query", args.Query)
@@ -115,6 +119,11 @@ func (s *Service) getDBFile(ctx context.Context, args protocol.SearchArgs) (stri
 	return diskcacheFile.File.Name(), err
 }
 
+// dbKey returns the disk cache key of the symbols database of repo@commitID.
+func dbKey(repo api.RepoName, commitID api.CommitID) string {
+	return fmt.Sprintf("%d-%s@%s", symbolsDBVersion, repo, commitID)
+}
+
 // isLiteralEquality checks if the given regex matches literal strings exactly.
 // Returns whether or not the regex is exact, along with the literal string if
 // so.
@@ -148,6 +157,14 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 		args.First = maxFirst
 	}
 
+	// makeRegexpCondition returns a condition matching column against regex.
+	makeRegexpCondition := func(column string, regex string) *sqlf.Query {
+		if !args.IsCaseSensitive {
+			regex = "(?i:" + regex + ")"
+		}
+		return sqlf.Sprintf(column+" REGEXP %s", regex)
+	}
+
 	makeCondition := func(column string, regex string) []*sqlf.Query {
 		conditions := []*sqlf.Query{}
 
@@ -164,15 +181,22 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 				conditions = append(conditions, sqlf.Sprintf(column+"lowercase = %s", strings.ToLower(symbolName)))
 			}
 		} else {
-			if !args.IsCaseSensitive {
-				regex = "(?i:" + regex + ")"
-			}
-			conditions = append(conditions, sqlf.Sprintf(column+" REGEXP %s", regex))
+			conditions = append(conditions, makeRegexpCondition(column, regex))
 		}
 
 		return conditions
 	}
 
+	// makeKindsCondition returns a condition matching symbols of one of the
+	// given kinds.
+	makeKindsCondition := func(kinds []string) *sqlf.Query {
+		lowercaseKinds := make([]*sqlf.Query, 0, len(kinds))
+		for _, kind := range kinds {
+			lowercaseKinds = append(lowercaseKinds, sqlf.Sprintf("%s", strings.ToLower(kind)))
+		}
+		return sqlf.Sprintf("lower(kind) IN (%s)", sqlf.Join(lowercaseKinds, ","))
+	}
+
 	negateAll := func(oldConditions []*sqlf.Query) []*sqlf.Query {
 		newConditions := []*sqlf.Query{}
 
@@ -189,6 +213,18 @@ func filterSymbols(ctx context.Context, db *sqlx.DB, args protocol.SearchArgs) (
 		conditions = append(conditions, makeCondition("path", includePattern)...)
 	}
 	conditions = append(conditions, negateAll(makeCondition("path", args.ExcludePattern))...)
+	if len(args.Kinds) > 0 {
+		conditions = append(conditions, makeKindsCondition(args.Kinds))
+	}
+	if len(args.ExcludeKinds) > 0 {
+		conditions = append(conditions, negateAll([]*sqlf.Query{makeKindsCondition(args.ExcludeKinds)})...)
+	}
+	for _, parentPattern := range args.ParentPatterns {
+		conditions = append(conditions, makeRegexpCondition("parent", parentPattern))
+	}
+	if args.ExcludeParentPattern != "" {
+		conditions = append(conditions, negateAll([]*sqlf.Query{makeRegexpCondition("parent", args.ExcludeParentPattern)})...)
+	}
 
 	var sqlQuery *sqlf.Query
 	if len(conditions) == 0 {
@@ -270,6 +306,61 @@ func symbolInDBToSymbol(symbolInDB symbolInDB) protocol.Symbol {
 	}
 }
 
+// writeSymbolsToNewDB writes the symbols of repo@commit to the blank database
+// file `dbFile`. If the database of a recent ancestor commit is in the cache,
+// it is copied and only the files that changed since that commit are parsed.
+// Otherwise, all symbols are parsed.
+func (s *Service) writeSymbolsToNewDB(ctx context.Context, dbFile string, repoName api.RepoName, commitID api.CommitID) error {
+	ancestorDBFile, changes, err := s.findIndexedAncestor(ctx, repoName, commitID)
+	if err != nil {
+		log15.Warn("Unable to index symbols incrementally, parsing all symbols", "repo", repoName, "commit", commitID, "error", err)
+	}
+	if ancestorDBFile == nil {
+		return s.writeAllSymbolsToNewDB(ctx, dbFile, repoName, commitID)
+	}
+	defer ancestorDBFile.Close()
+
+	return s.updateSymbolsInNewDB(ctx, dbFile, ancestorDBFile, repoName, commitID, changes)
+}
+
+// findIndexedAncestor returns the database of the nearest ancestor of
+// repo@commit whose symbols are already in the cache, along with the changes
+// between the ancestor and commit. The returned file is nil if no such
+// ancestor exists, or if too many files changed since.
+func (s *Service) findIndexedAncestor(ctx context.Context, repoName api.RepoName, commitID api.CommitID) (*diskcache.File, *git.Changes, error) {
+	if s.ListAncestors == nil || s.GitDiff == nil {
+		return nil, nil, nil
+	}
+
+	ancestors, err := s.ListAncestors(ctx, repoName, commitID, git.MaxAncestorsToCheck)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, ancestor := range ancestors {
+		f, err := s.cache.OpenIfExists(dbKey(repoName, ancestor))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		changes, err := s.GitDiff(ctx, repoName, ancestor, commitID)
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		if len(changes.Added)+len(changes.Modified)+len(changes.Deleted) > git.MaxChangedPaths {
+			f.Close()
+			return nil, nil, nil
+		}
+		return f, changes, nil
+	}
+
+	return nil, nil, nil
+}
+
 // writeAllSymbolsToNewDB fetches the repo@commit from gitserver, parses all the
 // symbols, and writes them to the blank database file `dbFile`.
 func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, repoName api.RepoName, commitID api.CommitID) error {
@@ -285,10 +376,81 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 		return err
 	}
 
+	err = createSymbolsTable(tx)
+	if err != nil {
+		return err
+	}
+
+	err = s.insertSymbols(ctx, tx, repoName, commitID, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// updateSymbolsInNewDB copies the database of an ancestor commit to the blank
+// database file `dbFile`, and then updates it to contain the symbols of
+// repo@commit by removing the symbols of the changed files and parsing the
+// added and modified files again.
+func (s *Service) updateSymbolsInNewDB(ctx context.Context, dbFile string, ancestorDBFile *diskcache.File, repoName api.RepoName, commitID api.CommitID, changes *git.Changes) error {
+	err := copyFile(dbFile, ancestorDBFile.File)
+	if err != nil {
+		return err
+	}
+
+	db, err := sqlx.Open("sqlite3_with_pcre", dbFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	deleteStatement, err := tx.Preparex("DELETE FROM symbols WHERE path = ?")
+	if err != nil {
+		return err
+	}
+	for _, paths := range [][]string{changes.Modified, changes.Deleted} {
+		for _, path := range paths {
+			_, err = deleteStatement.Exec(path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// An empty list of paths would parse all files.
+	paths := append(append([]string{}, changes.Added...), changes.Modified...)
+	if len(paths) > 0 {
+		err = s.insertSymbols(ctx, tx, repoName, commitID, paths)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createSymbolsTable creates the symbols table and its indexes.
+func createSymbolsTable(tx *sqlx.Tx) error {
 	// The column names are the lowercase version of fields in `symbolInDB`
 	// because sqlx lowercases struct fields by default. See
 	// http://jmoiron.github.io/sqlx/#query
-	_, err = tx.Exec(
+	_, err := tx.Exec(
 		`CREATE TABLE IF NOT EXISTS symbols (
 			name VARCHAR(256) NOT NULL,
 			namelowercase VARCHAR(256) NOT NULL,
@@ -328,6 +490,13 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 		return err
 	}
 
+	return nil
+}
+
+// insertSymbols parses the symbols of repo@commit and inserts them into the
+// symbols table. If paths is non-empty, only the files at these paths are
+// parsed.
+func (s *Service) insertSymbols(ctx context.Context, tx *sqlx.Tx, repoName api.RepoName, commitID api.CommitID, paths []string) error {
 	insertStatement, err := tx.PrepareNamed(
 		fmt.Sprintf(
 			"INSERT INTO symbols %s VALUES %s",
@@ -337,19 +506,22 @@ func (s *Service) writeAllSymbolsToNewDB(ctx context.Context, dbFile string, rep
 		return err
 	}
 
-	err = s.parseUncached(ctx, repoName, commitID, func(symbol protocol.Symbol) error {
+	return s.parseUncached(ctx, repoName, commitID, paths, func(symbol protocol.Symbol) error {
 		symbolInDBValue := symbolToSymbolInDB(symbol)
 		_, err := insertStatement.Exec(&symbolInDBValue)
 		return err
 	})
+}
+
+// copyFile copies the contents of src to the file at path dst.
+func copyFile(dst string, src io.Reader) error {
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
-
-	err = tx.Commit()
-	if err != nil {
+	if _, err := io.Copy(f, src); err != nil {
+		f.Close()
 		return err
 	}
-
-	return nil
+	return f.Close()
 }
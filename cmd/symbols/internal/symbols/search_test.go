@@ -3,6 +3,7 @@ package symbols
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path"
@@ -10,6 +11,8 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/pkg/ctags"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/sqliteutil"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/testutil"
@@ -21,7 +24,11 @@ func BenchmarkSearch(b *testing.B) {
 	log15.Root().SetHandler(log15.LvlFilterHandler(log15.LvlError, log15.Root().GetHandler()))
 
 	service := Service{
-		FetchTar: testutil.FetchTarFromGithub,
+		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			// paths is always empty since ListAncestors and GitDiff are not
+			// set.
+			return testutil.FetchTarFromGithub(ctx, repo, commit)
+		},
 		NewParser: func() (ctags.Parser, error) {
 			return ctags.New()
 		},
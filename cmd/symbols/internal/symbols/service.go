@@ -13,14 +13,26 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/diskcache"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 // Service is the symbols service.
 type Service struct {
 	// FetchTar returns an io.ReadCloser to a tar archive of a repository at the specified Git
-	// remote URL and commit ID. If the error implements "BadRequest() bool", it will be used to
-	// determine if the error is a bad request (eg invalid repo).
-	FetchTar func(context.Context, gitserver.Repo, api.CommitID) (io.ReadCloser, error)
+	// remote URL and commit ID. If paths is non-empty, only these paths are included in the
+	// archive. If the error implements "BadRequest() bool", it will be used to determine if the
+	// error is a bad request (eg invalid repo).
+	FetchTar func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, paths []string) (io.ReadCloser, error)
+
+	// ListAncestors returns up to n ancestors of a commit, nearest first.
+	// Together with GitDiff, it is used to index the symbols of a commit
+	// incrementally from the database of an already indexed ancestor, only
+	// parsing the files that changed since. If either is nil, all symbols of
+	// every commit are parsed.
+	ListAncestors func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error)
+
+	// GitDiff returns the paths that changed between two commits.
+	GitDiff func(ctx context.Context, repo api.RepoName, base, head api.CommitID) (*git.Changes, error)
 
 	// MaxConcurrentFetchTar is the maximum number of concurrent calls allowed
 	// to FetchTar. It defaults to 15.
@@ -9,8 +9,11 @@ import (
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"sort"
+	"sync"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/cmd/symbols/internal/pkg/ctags"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -18,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/sqliteutil"
 	symbolsclient "github.com/sourcegraph/sourcegraph/internal/symbols"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 func init() {
@@ -67,11 +71,14 @@ func TestService(t *testing.T) {
 
 	files := map[string]string{"a.js": "var x = 1"}
 	service := Service{
-		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error) {
+		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, paths []string) (io.ReadCloser, error) {
 			return createTar(files)
 		},
 		NewParser: func() (ctags.Parser, error) {
-			return mockParser{"x", "y"}, nil
+			return mockParser{
+				{Name: "x", Path: "a.js", Kind: "variable"},
+				{Name: "y", Path: "a.js", Kind: "function", Parent: "Foo", ParentKind: "class"},
+			}, nil
 		},
 		Path: tmpDir,
 	}
@@ -82,8 +89,8 @@ func TestService(t *testing.T) {
 	server := httptest.NewServer(service.Handler())
 	defer server.Close()
 	client := symbolsclient.Client{URL: server.URL}
-	x := protocol.Symbol{Name: "x", Path: "a.js"}
-	y := protocol.Symbol{Name: "y", Path: "a.js"}
+	x := protocol.Symbol{Name: "x", Path: "a.js", Kind: "variable"}
+	y := protocol.Symbol{Name: "y", Path: "a.js", Kind: "function", Parent: "Foo", ParentKind: "class"}
 
 	tests := map[string]struct {
 		args search.SymbolsParameters
@@ -129,6 +136,26 @@ func TestService(t *testing.T) {
 			args: search.SymbolsParameters{ExcludePattern: "a.js", IsCaseSensitive: true, First: 10},
 			want: protocol.SearchResult{},
 		},
+		"kinds": {
+			args: search.SymbolsParameters{Kinds: []string{"Function", "method"}, First: 10},
+			want: protocol.SearchResult{Symbols: []protocol.Symbol{y}},
+		},
+		"excludekinds": {
+			args: search.SymbolsParameters{ExcludeKinds: []string{"function"}, First: 10},
+			want: protocol.SearchResult{Symbols: []protocol.Symbol{x}},
+		},
+		"parent": {
+			args: search.SymbolsParameters{ParentPatterns: []string{"^foo$"}, First: 10},
+			want: protocol.SearchResult{Symbols: []protocol.Symbol{y}},
+		},
+		"casesensitiveparent": {
+			args: search.SymbolsParameters{ParentPatterns: []string{"^foo$"}, IsCaseSensitive: true, First: 10},
+			want: protocol.SearchResult{},
+		},
+		"excludeparent": {
+			args: search.SymbolsParameters{ExcludeParentPattern: "Foo", First: 10},
+			want: protocol.SearchResult{Symbols: []protocol.Symbol{x}},
+		},
 	}
 	for label, test := range tests {
 		t.Run(label, func(t *testing.T) {
@@ -143,6 +170,93 @@ func TestService(t *testing.T) {
 	}
 }
 
+func TestService_incremental(t *testing.T) {
+	sqliteutil.MustRegisterSqlite3WithPcre()
+
+	tmpDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { os.RemoveAll(tmpDir) }()
+
+	commits := map[api.CommitID]map[string]string{
+		"c1": {"a.js": "a1", "b.js": "b1", "c.js": "c1"},
+		"c2": {"a.js": "a2", "c.js": "c1", "d.js": "d2"},
+	}
+	var (
+		mu           sync.Mutex
+		fetchedPaths map[api.CommitID][]string
+	)
+	service := Service{
+		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			mu.Lock()
+			fetchedPaths[commit] = paths
+			mu.Unlock()
+			files := commits[commit]
+			if len(paths) > 0 {
+				files = map[string]string{}
+				for _, path := range paths {
+					files[path] = commits[commit][path]
+				}
+			}
+			return createTar(files)
+		},
+		ListAncestors: func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error) {
+			if commit == "c2" {
+				return []api.CommitID{"c1"}, nil
+			}
+			return nil, nil
+		},
+		GitDiff: func(ctx context.Context, repo api.RepoName, base, head api.CommitID) (*git.Changes, error) {
+			return &git.Changes{Added: []string{"d.js"}, Modified: []string{"a.js"}, Deleted: []string{"b.js"}}, nil
+		},
+		NewParser: func() (ctags.Parser, error) {
+			return contentParser{}, nil
+		},
+		Path: tmpDir,
+	}
+	if err := service.Start(); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(service.Handler())
+	defer server.Close()
+	client := symbolsclient.Client{URL: server.URL}
+
+	searchCommit := func(commit api.CommitID) []string {
+		t.Helper()
+		result, err := client.Search(context.Background(), search.SymbolsParameters{Repo: "r", CommitID: commit, First: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, s := range result.Symbols {
+			names = append(names, s.Path+":"+s.Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	t.Run("without indexed ancestor", func(t *testing.T) {
+		fetchedPaths = map[api.CommitID][]string{}
+		if diff := cmp.Diff([]string{"a.js:a1", "b.js:b1", "c.js:c1"}, searchCommit("c1")); diff != "" {
+			t.Error(diff)
+		}
+		if paths, ok := fetchedPaths["c1"]; !ok || len(paths) != 0 {
+			t.Errorf("expected all paths of c1 to be fetched, got %v", paths)
+		}
+	})
+
+	t.Run("with indexed ancestor", func(t *testing.T) {
+		fetchedPaths = map[api.CommitID][]string{}
+		if diff := cmp.Diff([]string{"a.js:a2", "c.js:c1", "d.js:d2"}, searchCommit("c2")); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff([]string{"d.js", "a.js"}, fetchedPaths["c2"]); diff != "" {
+			t.Errorf("unexpected fetched paths: %s", diff)
+		}
+	})
+}
+
 func createTar(files map[string]string) (io.ReadCloser, error) {
 	buf := new(bytes.Buffer)
 	w := tar.NewWriter(buf)
@@ -167,14 +281,21 @@ func createTar(files map[string]string) (io.ReadCloser, error) {
 	return ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil
 }
 
-type mockParser []string
+type mockParser []ctags.Entry
 
 func (m mockParser) Parse(name string, content []byte) ([]ctags.Entry, error) {
 	entries := make([]ctags.Entry, len(m))
-	for i, name := range m {
-		entries[i] = ctags.Entry{Name: name, Path: "a.js"}
-	}
+	copy(entries, m)
 	return entries, nil
 }
 
 func (mockParser) Close() {}
+
+// contentParser returns one symbol per file, named like the file's contents.
+type contentParser struct{}
+
+func (contentParser) Parse(name string, content []byte) ([]ctags.Entry, error) {
+	return []ctags.Entry{{Name: string(content), Path: name, Kind: "variable"}}, nil
+}
+
+func (contentParser) Close() {}
@@ -25,6 +25,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/sqliteutil"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 const port = "3184"
@@ -46,8 +47,14 @@ func main() {
 	go debugserver.Start()
 
 	service := symbols.Service{
-		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error) {
-			return gitserver.DefaultClient.Archive(ctx, repo, gitserver.ArchiveOptions{Treeish: string(commit), Format: "tar"})
+		FetchTar: func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+			return gitserver.DefaultClient.Archive(ctx, repo, gitserver.ArchiveOptions{Treeish: string(commit), Format: "tar", Paths: paths})
+		},
+		ListAncestors: func(ctx context.Context, repo api.RepoName, commit api.CommitID, n int) ([]api.CommitID, error) {
+			return git.Ancestors(ctx, gitserver.Repo{Name: repo}, commit, n)
+		},
+		GitDiff: func(ctx context.Context, repo api.RepoName, base, head api.CommitID) (*git.Changes, error) {
+			return git.DiffPaths(ctx, gitserver.Repo{Name: repo}, base, head)
 		},
 		NewParser: ctags.New,
 		Path:      cacheDir,
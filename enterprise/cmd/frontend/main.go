@@ -158,6 +158,7 @@ func initCampaigns(ctx context.Context, enterpriseServices *enterprise.Services)
 
 	enterpriseServices.CampaignsResolver = campaignsResolvers.NewResolver(dbconn.Global)
 	enterpriseServices.GithubWebhook = campaigns.NewGitHubWebhook(campaignsStore, repositories, msResolutionClock)
+	enterpriseServices.GitLabWebhook = campaigns.NewGitLabWebhook(campaignsStore, repositories, msResolutionClock)
 	enterpriseServices.BitbucketServerWebhook = campaigns.NewBitbucketServerWebhook(
 		campaignsStore,
 		repositories,
@@ -30,6 +30,12 @@ type Database interface {
 	// References returns the set of locations referencing the symbol at the given position.
 	References(ctx context.Context, path string, line, character int) ([]bundles.Location, error)
 
+	// TypeDefinitions returns the set of locations defining the type of the symbol at the given position.
+	TypeDefinitions(ctx context.Context, path string, line, character int) ([]bundles.Location, error)
+
+	// Implementations returns the set of locations implementing the symbol at the given position.
+	Implementations(ctx context.Context, path string, line, character int) ([]bundles.Location, error)
+
 	// Hover returns the hover text of the symbol at the given position.
 	Hover(ctx context.Context, path string, line, character int) (string, bundles.Range, bool, error)
 
@@ -43,7 +49,7 @@ type Database interface {
 	// the range attached to earlier monikers enclose the range attached to later monikers.
 	MonikersByPosition(ctx context.Context, path string, line, character int) ([][]bundles.MonikerData, error)
 
-	// MonikerResults returns the locations that define or reference the given moniker. This method
+	// MonikerResults returns the locations stored for the given moniker in the given table. This method
 	// also returns the size of the complete result set to aid in pagination (along with skip and take).
 	MonikerResults(ctx context.Context, tableName, scheme, identifier string, skip, take int) ([]bundles.Location, int, error)
 
@@ -242,6 +248,70 @@ func (db *databaseImpl) references(ctx context.Context, r types.RangeData) ([]bu
 	return locations, true, nil
 }
 
+// TypeDefinitions returns the set of locations defining the type of the symbol at the given position.
+func (db *databaseImpl) TypeDefinitions(ctx context.Context, path string, line, character int) ([]bundles.Location, error) {
+	_, ranges, exists, err := db.getRangeByPosition(ctx, path, line, character)
+	if err != nil || !exists {
+		return nil, pkgerrors.Wrap(err, "db.getRangeByPosition")
+	}
+
+	for _, r := range ranges {
+		locations, exists, err := db.resultLocations(ctx, r.TypeDefinitionResultID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		return locations, nil
+	}
+
+	return []bundles.Location{}, nil
+}
+
+// Implementations returns the set of locations implementing the symbol at the given position.
+func (db *databaseImpl) Implementations(ctx context.Context, path string, line, character int) ([]bundles.Location, error) {
+	_, ranges, exists, err := db.getRangeByPosition(ctx, path, line, character)
+	if err != nil || !exists {
+		return nil, pkgerrors.Wrap(err, "db.getRangeByPosition")
+	}
+
+	for _, r := range ranges {
+		locations, exists, err := db.resultLocations(ctx, r.ImplementationResultID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+
+		return locations, nil
+	}
+
+	return []bundles.Location{}, nil
+}
+
+// resultLocations returns the locations of the result with the given identifier. The returned flag
+// is false if the identifier is empty.
+func (db *databaseImpl) resultLocations(ctx context.Context, id types.ID) ([]bundles.Location, bool, error) {
+	if id == "" {
+		return nil, false, nil
+	}
+
+	results, err := db.getResultByID(ctx, id)
+	if err != nil {
+		return nil, false, pkgerrors.Wrap(err, "db.getResultByID")
+	}
+
+	locations, err := db.convertRangesToLocations(ctx, results)
+	if err != nil {
+		return nil, false, pkgerrors.Wrap(err, "db.convertRangesToLocations")
+	}
+
+	return locations, true, nil
+}
+
 // Hover returns the hover text of the symbol at the given position.
 func (db *databaseImpl) Hover(ctx context.Context, path string, line, character int) (string, bundles.Range, bool, error) {
 	documentData, ranges, exists, err := db.getRangeByPosition(ctx, path, line, character)
@@ -367,7 +437,7 @@ func (db *databaseImpl) MonikersByPosition(ctx context.Context, path string, lin
 	return monikerData, nil
 }
 
-// MonikerResults returns the locations that define or reference the given moniker. This method
+// MonikerResults returns the locations stored for the given moniker in the given table. This method
 // also returns the size of the complete result set to aid in pagination (along with skip and take).
 func (db *databaseImpl) MonikerResults(ctx context.Context, tableName, scheme, identifier string, skip, take int) (_ []bundles.Location, _ int, err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "getResultChunkByResultID")
@@ -393,6 +463,14 @@ func (db *databaseImpl) MonikerResults(ctx context.Context, tableName, scheme, i
 		if rows, totalCount, err = db.reader.ReadReferences(ctx, scheme, identifier, skip, take); err != nil {
 			err = pkgerrors.Wrap(err, "reader.ReadReferences")
 		}
+	} else if tableName == "type_definitions" {
+		if rows, totalCount, err = db.reader.ReadTypeDefinitions(ctx, scheme, identifier, skip, take); err != nil {
+			err = pkgerrors.Wrap(err, "reader.ReadTypeDefinitions")
+		}
+	} else if tableName == "implementations" {
+		if rows, totalCount, err = db.reader.ReadImplementations(ctx, scheme, identifier, skip, take); err != nil {
+			err = pkgerrors.Wrap(err, "reader.ReadImplementations")
+		}
 	}
 
 	if err != nil {
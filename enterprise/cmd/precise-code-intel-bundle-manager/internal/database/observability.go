@@ -17,6 +17,8 @@ type ObservedDatabase struct {
 	rangesOperation             *observation.Operation
 	definitionsOperation        *observation.Operation
 	referencesOperation         *observation.Operation
+	typeDefinitionsOperation    *observation.Operation
+	implementationsOperation    *observation.Operation
 	hoverOperation              *observation.Operation
 	diagnosticsOperation        *observation.Operation
 	monikersByPositionOperation *observation.Operation
@@ -65,6 +67,16 @@ func NewObserved(database Database, filename string, observationContext *observa
 			MetricLabels: []string{"references"},
 			Metrics:      metrics,
 		}),
+		typeDefinitionsOperation: observationContext.Operation(observation.Op{
+			Name:         "Database.TypeDefinitions",
+			MetricLabels: []string{"type_definitions"},
+			Metrics:      metrics,
+		}),
+		implementationsOperation: observationContext.Operation(observation.Op{
+			Name:         "Database.Implementations",
+			MetricLabels: []string{"implementations"},
+			Metrics:      metrics,
+		}),
 		hoverOperation: observationContext.Operation(observation.Op{
 			Name:         "Database.Hover",
 			MetricLabels: []string{"hover"},
@@ -150,6 +162,34 @@ func (db *ObservedDatabase) References(ctx context.Context, path string, line, c
 	return db.database.References(ctx, path, line, character)
 }
 
+// TypeDefinitions calls into the inner Database and registers the observed results.
+func (db *ObservedDatabase) TypeDefinitions(ctx context.Context, path string, line, character int) (typeDefinitions []bundles.Location, err error) {
+	ctx, endObservation := db.typeDefinitionsOperation.With(ctx, &err, observation.Args{
+		LogFields: []log.Field{
+			log.String("filename", db.filename),
+			log.String("path", path),
+			log.Int("line", line),
+			log.Int("character", character),
+		},
+	})
+	defer func() { endObservation(float64(len(typeDefinitions)), observation.Args{}) }()
+	return db.database.TypeDefinitions(ctx, path, line, character)
+}
+
+// Implementations calls into the inner Database and registers the observed results.
+func (db *ObservedDatabase) Implementations(ctx context.Context, path string, line, character int) (implementations []bundles.Location, err error) {
+	ctx, endObservation := db.implementationsOperation.With(ctx, &err, observation.Args{
+		LogFields: []log.Field{
+			log.String("filename", db.filename),
+			log.String("path", path),
+			log.Int("line", line),
+			log.Int("character", character),
+		},
+	})
+	defer func() { endObservation(float64(len(implementations)), observation.Args{}) }()
+	return db.database.Implementations(ctx, path, line, character)
+}
+
 // Hover calls into the inner Database and registers the observed results.
 func (db *ObservedDatabase) Hover(ctx context.Context, path string, line, character int) (_ string, _ bundles.Range, _ bool, err error) {
 	ctx, endObservation := db.hoverOperation.With(ctx, &err, observation.Args{
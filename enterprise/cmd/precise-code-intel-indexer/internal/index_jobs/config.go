@@ -0,0 +1,101 @@
+package indexjobs
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFilename is the name of the file at the root of a repository that
+// configures its index jobs. If present, it replaces the index jobs inferred
+// from the files of the repository.
+//
+// Example:
+//
+//	index_jobs:
+//	  - root: web
+//	    indexer: lsif-tsc
+//	    indexer_args: [-p, ., --out, dump.lsif]
+//
+// The configuration comes from the repository being indexed, so it can't run
+// arbitrary commands on the indexer: only the AllowedIndexers can be run, with
+// arguments and an outfile that stay within the repository.
+const ConfigFilename = "sourcegraph.yaml"
+
+// AllowedIndexers are the indexers that configured index jobs may run.
+var AllowedIndexers = []string{"lsif-go", "lsif-tsc", "lsif-java", "rust-analyzer", "lsif-py"}
+
+// IsAllowedIndexer returns true if indexer is one of the AllowedIndexers.
+func IsAllowedIndexer(indexer string) bool {
+	for _, allowed := range AllowedIndexers {
+		if indexer == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+type config struct {
+	IndexJobs []IndexJob `yaml:"index_jobs"`
+}
+
+// ParseConfig parses the contents of a ConfigFilename file.
+func ParseConfig(data []byte) ([]IndexJob, error) {
+	var c config
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return nil, errors.Wrap(err, "invalid index configuration")
+	}
+
+	for i := range c.IndexJobs {
+		job := &c.IndexJobs[i]
+		if job.Indexer == "" {
+			return nil, fmt.Errorf("index job %d: missing indexer", i)
+		}
+		if !IsAllowedIndexer(job.Indexer) {
+			return nil, fmt.Errorf("index job %d: unknown indexer %q (allowed: %s)", i, job.Indexer, strings.Join(AllowedIndexers, ", "))
+		}
+
+		root, ok := relativePath(job.Root)
+		if !ok {
+			return nil, fmt.Errorf("index job %d: invalid root %q", i, job.Root)
+		}
+		job.Root = root
+
+		if job.Outfile == "" {
+			job.Outfile = DefaultOutfile
+		}
+		if outfile, ok := relativePath(job.Outfile); !ok || outfile == "" {
+			return nil, fmt.Errorf("index job %d: invalid outfile %q", i, job.Outfile)
+		}
+
+		for _, arg := range job.IndexerArgs {
+			// Check the values of flags such as --out=dump.lsif too.
+			value := arg
+			if j := strings.Index(arg, "="); j >= 0 {
+				value = arg[j+1:]
+			}
+			if _, ok := relativePath(value); !ok {
+				return nil, fmt.Errorf("index job %d: invalid indexer argument %q", i, arg)
+			}
+		}
+	}
+
+	return c.IndexJobs, nil
+}
+
+// relativePath cleans the slash-separated path p, which must be relative and
+// stay within the directory it is relative to. The cleaned path is empty if p
+// refers to that directory.
+func relativePath(p string) (string, bool) {
+	p = path.Clean(p)
+	if path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+		return "", false
+	}
+	if p == "." {
+		p = ""
+	}
+	return p, true
+}
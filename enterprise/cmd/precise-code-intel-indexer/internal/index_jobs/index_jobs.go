@@ -0,0 +1,247 @@
+package indexjobs
+
+import (
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultOutfile is the file, relative to the root of an index job, to which
+// indexers write their dump unless configured otherwise.
+const DefaultOutfile = "dump.lsif"
+
+// IndexJob describes how to index one project of a repository.
+type IndexJob struct {
+	// Root is the directory of the project relative to the root of the
+	// repository. It is empty for the repository root.
+	Root string `yaml:"root"`
+
+	// Steps are the commands run in Root before the indexer, e.g. to install
+	// dependencies. Each step is a command followed by its arguments. Only
+	// inferred index jobs have steps: they can't be configured.
+	Steps [][]string `yaml:"-"`
+
+	// Indexer is the LSIF indexer command run in Root. It is also reported
+	// as the indexer of the uploaded dump.
+	Indexer string `yaml:"indexer"`
+
+	// IndexerArgs are the arguments of the indexer.
+	IndexerArgs []string `yaml:"indexer_args"`
+
+	// Outfile is the file, relative to Root, containing the dump once the
+	// indexer finished. It defaults to DefaultOutfile.
+	Outfile string `yaml:"outfile"`
+
+	// IndexerWritesStdout is true if the indexer writes the dump to its
+	// standard output rather than to Outfile.
+	IndexerWritesStdout bool `yaml:"indexer_writes_stdout"`
+}
+
+// projectType describes a type of project that can be detected by the name of
+// a file at its root.
+type projectType struct {
+	// markers are the names of the files that mark a project root. The root
+	// is detected once per directory, even if it contains several markers.
+	markers []string
+
+	// nested is true if a project of this type nested in another one is a
+	// separate project (e.g. Go modules). Otherwise, it is part of the
+	// enclosing project (e.g. Maven modules).
+	nested bool
+
+	// job returns the index job of the project in root, given the names of
+	// the files in root.
+	job func(root string, files map[string]struct{}) IndexJob
+}
+
+// projectTypes are the types of projects detected by InferIndexJobs, by
+// order of precedence: if several types of projects share a root, only the
+// first one is indexed.
+var projectTypes = []projectType{
+	{
+		markers: []string{"go.mod"},
+		nested:  true,
+		job: func(root string, files map[string]struct{}) IndexJob {
+			// The repository root and module version flags are added by the
+			// indexer, as they depend on the checkout.
+			return IndexJob{Root: root, Indexer: "lsif-go"}
+		},
+	},
+	{
+		markers: []string{"tsconfig.json", "package.json"},
+		nested:  true,
+		job: func(root string, files map[string]struct{}) IndexJob {
+			job := IndexJob{
+				Root:        root,
+				Indexer:     "lsif-tsc",
+				IndexerArgs: []string{"-p", ".", "--out", DefaultOutfile},
+			}
+			if _, ok := files["tsconfig.json"]; !ok {
+				job.IndexerArgs = []string{"--inferTSConfig", "--out", DefaultOutfile}
+			}
+			if _, ok := files["package.json"]; ok {
+				if _, ok := files["yarn.lock"]; ok {
+					job.Steps = [][]string{{"yarn", "install", "--ignore-scripts", "--ignore-engines"}}
+				} else {
+					job.Steps = [][]string{{"npm", "install", "--ignore-scripts"}}
+				}
+			}
+			return job
+		},
+	},
+	{
+		markers: []string{"pom.xml"},
+		job: func(root string, files map[string]struct{}) IndexJob {
+			return IndexJob{
+				Root:        root,
+				Indexer:     "lsif-java",
+				IndexerArgs: []string{"index", "--output", DefaultOutfile},
+			}
+		},
+	},
+	{
+		markers: []string{"Cargo.toml"},
+		job: func(root string, files map[string]struct{}) IndexJob {
+			return IndexJob{
+				Root:                root,
+				Indexer:             "rust-analyzer",
+				IndexerArgs:         []string{"lsif", "."},
+				IndexerWritesStdout: true,
+			}
+		},
+	},
+	{
+		markers: []string{"setup.py"},
+		job: func(root string, files map[string]struct{}) IndexJob {
+			return IndexJob{
+				Root:        root,
+				Indexer:     "lsif-py",
+				IndexerArgs: []string{".", "--file", DefaultOutfile},
+			}
+		},
+	},
+}
+
+// lookPath returns the path of an installed command. It is replaced in tests.
+var lookPath = exec.LookPath
+
+// IsInstalledIndexer returns true if indexer is installed, so that index jobs
+// running it can be inferred.
+func IsInstalledIndexer(indexer string) bool {
+	_, err := lookPath(indexer)
+	return err == nil
+}
+
+// ignoredDirs are the names of directories whose contents are never
+// considered to be projects of the repository.
+var ignoredDirs = map[string]struct{}{
+	"node_modules": {},
+	"vendor":       {},
+	"testdata":     {},
+	"third_party":  {},
+}
+
+// IsIgnoredDir returns true if the directory with the given name should not be
+// searched for projects.
+func IsIgnoredDir(name string) bool {
+	_, ok := ignoredDirs[name]
+	return ok || strings.HasPrefix(name, ".")
+}
+
+// InferIndexJobs returns the index jobs of the projects of a repository
+// containing the given files. The paths are slash-separated and relative to
+// the root of the repository. The jobs are ordered by root. Projects whose
+// indexer isn't installed are skipped, so a project of another type sharing
+// their root may be indexed instead.
+func InferIndexJobs(paths []string) []IndexJob {
+	filesByDir := map[string]map[string]struct{}{}
+	for _, p := range paths {
+		dir, name := path.Split(p)
+		dir = strings.TrimSuffix(dir, "/")
+		if isInIgnoredDir(dir) {
+			continue
+		}
+		if filesByDir[dir] == nil {
+			filesByDir[dir] = map[string]struct{}{}
+		}
+		filesByDir[dir][name] = struct{}{}
+	}
+
+	dirs := make([]string, 0, len(filesByDir))
+	for dir := range filesByDir {
+		dirs = append(dirs, dir)
+	}
+	// Parent directories sort before their children, which lets us skip
+	// the roots of projects that can't be nested.
+	sort.Strings(dirs)
+
+	var jobs []IndexJob
+	outerRoots := make([][]string, len(projectTypes))
+	for _, dir := range dirs {
+		files := filesByDir[dir]
+		for i, t := range projectTypes {
+			if !hasAny(files, t.markers) {
+				continue
+			}
+			if !t.nested && isInAny(dir, outerRoots[i]) {
+				continue
+			}
+			job := t.job(dir, files)
+			if !IsInstalledIndexer(job.Indexer) {
+				continue
+			}
+			outerRoots[i] = append(outerRoots[i], dir)
+
+			job.Outfile = DefaultOutfile
+			jobs = append(jobs, job)
+			break
+		}
+	}
+
+	return jobs
+}
+
+// IsIndexable returns true if a repository containing the given files has a
+// project that can be indexed with an installed indexer, or configures its index jobs explicitly. The
+// paths are those of all files of the repository, as for InferIndexJobs, so
+// that projects nested below the root are found.
+func IsIndexable(paths []string) bool {
+	for _, p := range paths {
+		if p == ConfigFilename {
+			return true
+		}
+	}
+	return len(InferIndexJobs(paths)) > 0
+}
+
+func isInIgnoredDir(dir string) bool {
+	if dir == "" {
+		return false
+	}
+	for _, name := range strings.Split(dir, "/") {
+		if IsIgnoredDir(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAny(files map[string]struct{}, names []string) bool {
+	for _, name := range names {
+		if _, ok := files[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isInAny returns true if dir is one of roots or nested in one of them.
+func isInAny(dir string, roots []string) bool {
+	for _, root := range roots {
+		if root == "" || dir == root || strings.HasPrefix(dir, root+"/") {
+			return true
+		}
+	}
+	return false
+}
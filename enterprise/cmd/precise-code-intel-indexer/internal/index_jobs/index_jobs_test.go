@@ -0,0 +1,118 @@
+package indexjobs
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// setInstalledIndexers makes IsInstalledIndexer return true for the given
+// indexers only, for the duration of the test.
+func setInstalledIndexers(t *testing.T, indexers ...string) {
+	lookPath = func(file string) (string, error) {
+		for _, indexer := range indexers {
+			if file == indexer {
+				return "/usr/local/bin/" + file, nil
+			}
+		}
+		return "", errors.New("not installed")
+	}
+	t.Cleanup(func() { lookPath = exec.LookPath })
+}
+
+func TestInferIndexJobs(t *testing.T) {
+	setInstalledIndexers(t, AllowedIndexers...)
+
+	paths := []string{
+		"go.mod",
+		"main.go",
+		"cmd/tool/go.mod",
+		"web/package.json",
+		"web/tsconfig.json",
+		"web/yarn.lock",
+		"web/node_modules/dep/package.json",
+		"scripts/package.json",
+		"java/pom.xml",
+		"java/module/pom.xml",
+		"rust/Cargo.toml",
+		"rust/crate/Cargo.toml",
+		"python/setup.py",
+		"python/testdata/setup.py",
+		".github/package.json",
+	}
+
+	expected := []IndexJob{
+		{Root: "", Indexer: "lsif-go", Outfile: "dump.lsif"},
+		{Root: "cmd/tool", Indexer: "lsif-go", Outfile: "dump.lsif"},
+		{Root: "java", Indexer: "lsif-java", IndexerArgs: []string{"index", "--output", "dump.lsif"}, Outfile: "dump.lsif"},
+		{Root: "python", Indexer: "lsif-py", IndexerArgs: []string{".", "--file", "dump.lsif"}, Outfile: "dump.lsif"},
+		{Root: "rust", Indexer: "rust-analyzer", IndexerArgs: []string{"lsif", "."}, Outfile: "dump.lsif", IndexerWritesStdout: true},
+		{
+			Root:        "scripts",
+			Steps:       [][]string{{"npm", "install", "--ignore-scripts"}},
+			Indexer:     "lsif-tsc",
+			IndexerArgs: []string{"--inferTSConfig", "--out", "dump.lsif"},
+			Outfile:     "dump.lsif",
+		},
+		{
+			Root:        "web",
+			Steps:       [][]string{{"yarn", "install", "--ignore-scripts", "--ignore-engines"}},
+			Indexer:     "lsif-tsc",
+			IndexerArgs: []string{"-p", ".", "--out", "dump.lsif"},
+			Outfile:     "dump.lsif",
+		},
+	}
+	if diff := cmp.Diff(expected, InferIndexJobs(paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestInferIndexJobsUninstalledIndexers(t *testing.T) {
+	setInstalledIndexers(t, "lsif-tsc")
+
+	paths := []string{
+		"go.mod",
+		"package.json",
+		"rust/Cargo.toml",
+	}
+
+	expected := []IndexJob{
+		{
+			Root:        "",
+			Steps:       [][]string{{"npm", "install", "--ignore-scripts"}},
+			Indexer:     "lsif-tsc",
+			IndexerArgs: []string{"--inferTSConfig", "--out", "dump.lsif"},
+			Outfile:     "dump.lsif",
+		},
+	}
+	if diff := cmp.Diff(expected, InferIndexJobs(paths)); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestIsIndexable(t *testing.T) {
+	setInstalledIndexers(t, "lsif-go", "lsif-tsc")
+
+	testCases := []struct {
+		paths    []string
+		expected bool
+	}{
+		{paths: []string{"go.mod", "main.go"}, expected: true},
+		{paths: []string{"README.md", "package.json"}, expected: true},
+		{paths: []string{"README.md", "sourcegraph.yaml"}, expected: true},
+		{paths: []string{"README.md", "Makefile"}, expected: false},
+		{paths: []string{"README.md", "cmd/server/go.mod", "cmd/server/main.go"}, expected: true},
+		{paths: []string{"README.md", "web/package.json"}, expected: true},
+		{paths: []string{"README.md", "web/node_modules/left-pad/package.json"}, expected: false},
+		{paths: []string{"README.md", "docs/sourcegraph.yaml"}, expected: false},
+		{paths: []string{"README.md", "Cargo.toml"}, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		if indexable := IsIndexable(testCase.paths); indexable != testCase.expected {
+			t.Errorf("unexpected result for %v. want=%v have=%v", testCase.paths, testCase.expected, indexable)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
+	indexjobs "github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-indexer/internal/index_jobs"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
@@ -91,9 +92,12 @@ func (u *Updater) queueRepository(ctx context.Context, repoUsageStatistics store
 		return errors.Wrap(err, "gitserver.Head")
 	}
 
-	exists, err := u.gitserverClient.FileExists(ctx, u.store, repoUsageStatistics.RepositoryID, commit, "go.mod")
-	if err != nil || !exists {
-		return errors.Wrap(err, "gitserver.FileExists")
+	paths, err := u.gitserverClient.ListFiles(ctx, u.store, repoUsageStatistics.RepositoryID, commit)
+	if err != nil {
+		return errors.Wrap(err, "gitserver.ListFiles")
+	}
+	if !indexjobs.IsIndexable(paths) {
+		return nil
 	}
 
 	// TODO(efritz) - also check repo size
@@ -4,7 +4,9 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 
@@ -24,7 +26,27 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+// installFakeIndexer puts an executable with the name of indexer on the PATH
+// for the duration of the test, so that index jobs running it are inferred.
+func installFakeIndexer(t *testing.T, indexer string) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := ioutil.WriteFile(filepath.Join(dir, indexer), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("unexpected error writing file: %s", err)
+	}
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+	t.Cleanup(func() { os.Setenv("PATH", path) })
+}
+
 func TestUpdate(t *testing.T) {
+	installFakeIndexer(t, "lsif-tsc")
+
 	mockStore := storemocks.NewMockStore()
 	mockStore.RepoUsageStatisticsFunc.SetDefaultReturn([]store.RepoUsageStatistics{
 		{RepositoryID: 1, SearchCount: 200, PreciseCount: 50},
@@ -34,8 +56,11 @@ func TestUpdate(t *testing.T) {
 	}, nil)
 
 	mockGitserverClient := gitservermocks.NewMockClient()
-	mockGitserverClient.FileExistsFunc.SetDefaultHook(func(ctx context.Context, store store.Store, repositoryID int, commit, file string) (bool, error) {
-		return repositoryID%2 == 0, nil
+	mockGitserverClient.ListFilesFunc.SetDefaultHook(func(ctx context.Context, store store.Store, repositoryID int, commit string) ([]string, error) {
+		if repositoryID%2 == 0 {
+			return []string{"README.md", "web/package.json"}, nil
+		}
+		return []string{"README.md", "web/node_modules/left-pad/package.json"}, nil
 	})
 	mockGitserverClient.HeadFunc.SetDefaultHook(func(ctx context.Context, store store.Store, repositoryID int) (string, error) {
 		return fmt.Sprintf("c%d", repositoryID), nil
@@ -51,20 +76,17 @@ func TestUpdate(t *testing.T) {
 		t.Fatalf("unexpected error performing update: %s", err)
 	}
 
-	if len(mockGitserverClient.FileExistsFunc.History()) != 4 {
-		t.Errorf("unexpected number of calls to FileExists. want=%d have=%d", 2, len(mockGitserverClient.FileExistsFunc.History()))
+	if len(mockGitserverClient.ListFilesFunc.History()) != 4 {
+		t.Errorf("unexpected number of calls to ListFiles. want=%d have=%d", 4, len(mockGitserverClient.ListFilesFunc.History()))
 	} else {
 		var repositoryIDs []int
-		for _, call := range mockGitserverClient.FileExistsFunc.History() {
+		for _, call := range mockGitserverClient.ListFilesFunc.History() {
 			repositoryIDs = append(repositoryIDs, call.Arg2)
 			expectedCommit := fmt.Sprintf("c%d", call.Arg2)
 
 			if call.Arg3 != expectedCommit {
 				t.Errorf("unexpected commit argument. want=%q have=%q", expectedCommit, call.Arg3)
 			}
-			if call.Arg4 != "go.mod" {
-				t.Errorf("unexpected file argument. want=%q have=%q", "go.mod", call.Arg4)
-			}
 		}
 		sort.Ints(repositoryIDs)
 
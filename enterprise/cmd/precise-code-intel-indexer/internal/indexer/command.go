@@ -1,7 +1,9 @@
 package indexer
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"os/exec"
 
 	"github.com/pkg/errors"
@@ -17,3 +19,29 @@ func command(dir, command string, args ...string) error {
 
 	return nil
 }
+
+// commandWithStdout is like command, but writes the standard output of the
+// command to the file at outfile. An existing file at outfile is replaced
+// rather than written through, in case it is a symlink.
+func commandWithStdout(dir, outfile, command string, args ...string) error {
+	if err := os.Remove(outfile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	f, err := os.OpenFile(outfile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var stderr bytes.Buffer
+	indexCmd := exec.Command(command, args...)
+	indexCmd.Dir = dir
+	indexCmd.Stdout = f
+	indexCmd.Stderr = &stderr
+
+	if err := indexCmd.Run(); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("command failed: %s\n", stderr.Bytes()))
+	}
+
+	return f.Close()
+}
@@ -3,12 +3,17 @@ package indexer
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/codeintelutils"
+	indexjobs "github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-indexer/internal/index_jobs"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
 )
@@ -32,18 +37,14 @@ func (p *processor) Process(ctx context.Context, index store.Index) error {
 		_ = os.RemoveAll(repoDir)
 	}()
 
-	if err := p.index(ctx, repoDir, index); err != nil {
-		return errors.Wrap(err, "failed to index repository")
+	jobs, err := indexJobs(repoDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to determine index jobs")
 	}
-
-	if err := p.upload(ctx, repoDir, index); err != nil {
-		return errors.Wrap(err, "failed to upload index")
+	if len(jobs) == 0 {
+		return errors.New("no indexable projects found")
 	}
 
-	return nil
-}
-
-func (p *processor) index(ctx context.Context, repoDir string, index store.Index) error {
 	tag, exact, err := p.gitserverClient.Tags(ctx, p.store, index.RepositoryID, index.Commit)
 	if err != nil {
 		return err
@@ -52,28 +53,124 @@ func (p *processor) index(ctx context.Context, repoDir string, index store.Index
 		tag = fmt.Sprintf("%s-%s", tag, index.Commit[:12])
 	}
 
-	args := []string{
-		"--repositoryRoot=.",
-		fmt.Sprintf("--moduleVersion=%s", tag),
+	// Index and upload each project even if another one failed, so that a
+	// single broken project doesn't prevent the others from being indexed.
+	var errs error
+	for _, job := range jobs {
+		if err := p.index(ctx, repoDir, job, tag); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to index %q with %s", job.Root, job.Indexer))
+			continue
+		}
+
+		if err := p.upload(ctx, repoDir, index, job); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to upload index of %q", job.Root))
+		}
+	}
+
+	return errs
+}
+
+// indexJobs returns the index jobs of the repository checked out in
+// repoDir. They are read from the configuration file of the repository if
+// it exists, and inferred from the files of the repository otherwise.
+func indexJobs(repoDir string) ([]indexjobs.IndexJob, error) {
+	config, err := ioutil.ReadFile(filepath.Join(repoDir, indexjobs.ConfigFilename))
+	if err == nil {
+		return indexjobs.ParseConfig(config)
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var paths []string
+	err = filepath.Walk(repoDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != repoDir && indexjobs.IsIgnoredDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(repoDir, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return indexjobs.InferIndexJobs(paths), nil
+}
+
+func (p *processor) index(ctx context.Context, repoDir string, job indexjobs.IndexJob, tag string) error {
+	// ParseConfig already rejects other indexers, but we don't want to run
+	// arbitrary commands even if an index job didn't come from there.
+	if !indexjobs.IsAllowedIndexer(job.Indexer) {
+		return fmt.Errorf("indexer %q is not allowed", job.Indexer)
+	}
+
+	dir, err := resolveInRepo(repoDir, job.Root)
+	if err != nil {
+		return err
+	}
+
+	for _, step := range job.Steps {
+		if len(step) == 0 {
+			continue
+		}
+		if err := command(dir, step[0], step[1:]...); err != nil {
+			return err
+		}
 	}
 
-	return command(repoDir, "lsif-go", args...)
+	args := job.IndexerArgs
+	if job.Indexer == "lsif-go" {
+		repositoryRoot, err := filepath.Rel(dir, repoDir)
+		if err != nil {
+			return err
+		}
+
+		args = append([]string{
+			fmt.Sprintf("--repositoryRoot=%s", repositoryRoot),
+			fmt.Sprintf("--moduleVersion=%s", tag),
+		}, args...)
+	}
+
+	if job.IndexerWritesStdout {
+		outDir, err := resolveInRepo(repoDir, path.Dir(path.Join(job.Root, job.Outfile)))
+		if err != nil {
+			return err
+		}
+		return commandWithStdout(dir, filepath.Join(outDir, path.Base(job.Outfile)), job.Indexer, args...)
+	}
+	return command(dir, job.Indexer, args...)
 }
 
-func (p *processor) upload(ctx context.Context, repoDir string, index store.Index) error {
+func (p *processor) upload(ctx context.Context, repoDir string, index store.Index, job indexjobs.IndexJob) error {
 	repoName, err := p.store.RepoName(ctx, index.RepositoryID)
 	if err != nil {
 		return errors.Wrap(err, "store.RepoName")
 	}
 
+	file, err := resolveInRepo(repoDir, path.Join(job.Root, job.Outfile))
+	if err != nil {
+		return err
+	}
+
 	opts := codeintelutils.UploadIndexOpts{
 		Endpoint:            fmt.Sprintf("http://%s", p.frontendURL),
 		Path:                "/.internal/lsif/upload",
 		Repo:                repoName,
 		Commit:              index.Commit,
-		Root:                "",
-		Indexer:             "lsif-go",
-		File:                filepath.Join(repoDir, "dump.lsif"),
+		Root:                job.Root,
+		Indexer:             job.Indexer,
+		File:                file,
 		MaxPayloadSizeBytes: 100 * 1000 * 1000, // 100Mb
 		MaxRetries:          10,
 		RetryInterval:       time.Second * 250,
@@ -85,3 +182,22 @@ func (p *processor) upload(ctx context.Context, repoDir string, index store.Inde
 
 	return nil
 }
+
+// resolveInRepo returns the path of the file or directory at the given
+// slash-separated path relative to repoDir, with symlinks resolved. It
+// returns an error if the path doesn't exist or resolves to a path outside of
+// repoDir, since repositories can contain symlinks to arbitrary paths.
+func resolveInRepo(repoDir, rel string) (string, error) {
+	root, err := filepath.EvalSymlinks(repoDir)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(repoDir, filepath.FromSlash(rel)))
+	if err != nil {
+		return "", err
+	}
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("%q resolves to a path outside of the repository", rel)
+	}
+	return resolved, nil
+}
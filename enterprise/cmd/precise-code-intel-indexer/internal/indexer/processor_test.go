@@ -1,3 +1,93 @@
 package indexer
 
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	indexjobs "github.com/sourcegraph/sourcegraph/enterprise/cmd/precise-code-intel-indexer/internal/index_jobs"
+)
+
 // TODO(efritz) - write index processor tests
+
+func TestIndexJobsConfigured(t *testing.T) {
+	repoDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(repoDir)
+
+	files := map[string]string{
+		"go.mod":           "module example.com/test",
+		"sourcegraph.yaml": "index_jobs: [{root: web, indexer: lsif-tsc}]",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error writing file: %s", err)
+		}
+	}
+
+	jobs, err := indexJobs(repoDir)
+	if err != nil {
+		t.Fatalf("unexpected error determining index jobs: %s", err)
+	}
+
+	expected := []indexjobs.IndexJob{{Root: "web", Indexer: "lsif-tsc", Outfile: "dump.lsif"}}
+	if diff := cmp.Diff(expected, jobs); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
+
+func TestIndexUnknownIndexer(t *testing.T) {
+	repoDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(repoDir)
+
+	job := indexjobs.IndexJob{Indexer: "sh", IndexerArgs: []string{"-c", "touch pwned"}, Outfile: "dump.lsif"}
+	if err := (&processor{}).index(context.Background(), repoDir, job, "v1"); err == nil {
+		t.Fatal("expected an error running an unknown indexer")
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "pwned")); !os.IsNotExist(err) {
+		t.Errorf("expected the unknown indexer not to run")
+	}
+}
+
+func TestResolveInRepo(t *testing.T) {
+	repoDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp dir: %s", err)
+	}
+	defer os.RemoveAll(repoDir)
+
+	if err := os.Mkdir(filepath.Join(repoDir, "web"), 0755); err != nil {
+		t.Fatalf("unexpected error creating dir: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(repoDir, "web", "dump.lsif"), nil, 0644); err != nil {
+		t.Fatalf("unexpected error writing file: %s", err)
+	}
+	for name, target := range map[string]string{
+		"dump.lsif":     "/etc/passwd",
+		"etc":           "/etc",
+		"web/link.lsif": "dump.lsif",
+	} {
+		if err := os.Symlink(target, filepath.Join(repoDir, name)); err != nil {
+			t.Fatalf("unexpected error creating symlink: %s", err)
+		}
+	}
+
+	for _, rel := range []string{"", "web", "web/dump.lsif", "web/link.lsif"} {
+		if _, err := resolveInRepo(repoDir, rel); err != nil {
+			t.Errorf("unexpected error resolving %q: %s", rel, err)
+		}
+	}
+	for _, rel := range []string{"dump.lsif", "etc", "etc/passwd"} {
+		if _, err := resolveInRepo(repoDir, rel); err == nil {
+			t.Errorf("expected an error resolving %q", rel)
+		}
+	}
+}
@@ -46,6 +46,8 @@ func canonicalizeDocuments(state *State) {
 			// Move definition/reference data into the canonical document
 			canonicalizeDocumentsInDefinitionReferences(state, state.DefinitionData, documentID, canonicalID)
 			canonicalizeDocumentsInDefinitionReferences(state, state.ReferenceData, documentID, canonicalID)
+			canonicalizeDocumentsInDefinitionReferences(state, state.TypeDefinitionData, documentID, canonicalID)
+			canonicalizeDocumentsInDefinitionReferences(state, state.ImplementationData, documentID, canonicalID)
 
 			// Remove non-canonical document
 			delete(state.DocumentData, documentID)
@@ -145,8 +147,9 @@ func canonicalizeResultSets(state *State) {
 	}
 }
 
-// canonicalizeResultSets "merges down" the definition, reference, and hover result identifiers
-// from the element's "next" result set if such an element exists and the identifier is not already.
+// canonicalizeResultSets "merges down" the definition, reference, type definition, implementation,
+// and hover result identifiers from the element's "next" result set if such an element exists and
+// the identifier is not already.
 // defined. This also merges down the moniker ids by unioning the sets.
 //
 // This method is assumed to be invoked only after canonicalizeResultSets, otherwise the next element
@@ -164,9 +167,9 @@ func canonicalizeRanges(state *State) {
 	}
 }
 
-// canonicalizeResultSets "merges down" the definition, reference, and hover result identifiers
-// from the element's "next" result set if such an element exists and the identifier is not
-// already defined. This also merges down the moniker ids by unioning the sets.
+// canonicalizeResultSets "merges down" the definition, reference, type definition, implementation,
+// and hover result identifiers from the element's "next" result set if such an element exists and
+// the identifier is not already defined. This also merges down the moniker ids by unioning the sets.
 func canonicalizeResultSetData(state *State, id int, item lsif.ResultSet) lsif.ResultSet {
 	if nextID, nextItem, ok := next(state, id); ok {
 		// Recursively canonicalize the next element
@@ -181,9 +184,9 @@ func canonicalizeResultSetData(state *State, id int, item lsif.ResultSet) lsif.R
 	return item
 }
 
-// mergeNextResultSetData merges the definition, reference, and hover result identifiers from
-// nextItem into item when not already defined. The moniker identifiers of nextItem are unioned
-// into the moniker identifiers of item.
+// mergeNextResultSetData merges the definition, reference, type definition, implementation, and
+// hover result identifiers from nextItem into item when not already defined. The moniker
+// identifiers of nextItem are unioned into the moniker identifiers of item.
 func mergeNextResultSetData(item, nextItem lsif.ResultSet) lsif.ResultSet {
 	if item.DefinitionResultID == 0 {
 		item = item.SetDefinitionResultID(nextItem.DefinitionResultID)
@@ -191,6 +194,12 @@ func mergeNextResultSetData(item, nextItem lsif.ResultSet) lsif.ResultSet {
 	if item.ReferenceResultID == 0 {
 		item = item.SetReferenceResultID(nextItem.ReferenceResultID)
 	}
+	if item.TypeDefinitionResultID == 0 {
+		item = item.SetTypeDefinitionResultID(nextItem.TypeDefinitionResultID)
+	}
+	if item.ImplementationResultID == 0 {
+		item = item.SetImplementationResultID(nextItem.ImplementationResultID)
+	}
 	if item.HoverResultID == 0 {
 		item = item.SetHoverResultID(nextItem.HoverResultID)
 	}
@@ -199,9 +208,9 @@ func mergeNextResultSetData(item, nextItem lsif.ResultSet) lsif.ResultSet {
 	return item
 }
 
-// mergeNextRangeData merges the definition, reference, and hover result identifiers from nextItem
-// into item when not already defined. The moniker identifiers of nextItem are unioned into the
-// moniker identifiers of item.
+// mergeNextRangeData merges the definition, reference, type definition, implementation, and hover
+// result identifiers from nextItem into item when not already defined. The moniker identifiers of
+// nextItem are unioned into the moniker identifiers of item.
 func mergeNextRangeData(item lsif.Range, nextItem lsif.ResultSet) lsif.Range {
 	if item.DefinitionResultID == 0 {
 		item = item.SetDefinitionResultID(nextItem.DefinitionResultID)
@@ -209,6 +218,12 @@ func mergeNextRangeData(item lsif.Range, nextItem lsif.ResultSet) lsif.Range {
 	if item.ReferenceResultID == 0 {
 		item = item.SetReferenceResultID(nextItem.ReferenceResultID)
 	}
+	if item.TypeDefinitionResultID == 0 {
+		item = item.SetTypeDefinitionResultID(nextItem.TypeDefinitionResultID)
+	}
+	if item.ImplementationResultID == 0 {
+		item = item.SetImplementationResultID(nextItem.ImplementationResultID)
+	}
 	if item.HoverResultID == 0 {
 		item = item.SetHoverResultID(nextItem.HoverResultID)
 	}
@@ -281,10 +281,12 @@ func TestCanonicalizeRanges(t *testing.T) {
 				MonikerIDs:         datastructures.IDSetWith(4004),
 			},
 			5002: {
-				DefinitionResultID: 0,
-				ReferenceResultID:  2008,
-				HoverResultID:      2008,
-				MonikerIDs:         datastructures.IDSetWith(4005),
+				DefinitionResultID:     0,
+				ReferenceResultID:      2008,
+				TypeDefinitionResultID: 2009,
+				ImplementationResultID: 2010,
+				HoverResultID:          2008,
+				MonikerIDs:             datastructures.IDSetWith(4005),
 			},
 		},
 		NextData: map[int]int{
@@ -310,10 +312,12 @@ func TestCanonicalizeRanges(t *testing.T) {
 				MonikerIDs:         datastructures.IDSetWith(4002, 4005),
 			},
 			3003: {
-				DefinitionResultID: 2004,
-				ReferenceResultID:  2005,
-				HoverResultID:      2008,
-				MonikerIDs:         datastructures.IDSetWith(4002, 4003, 4005),
+				DefinitionResultID:     2004,
+				ReferenceResultID:      2005,
+				TypeDefinitionResultID: 2009,
+				ImplementationResultID: 2010,
+				HoverResultID:          2008,
+				MonikerIDs:             datastructures.IDSetWith(4002, 4003, 4005),
 			},
 		},
 		ResultSetData: map[int]lsif.ResultSet{
@@ -324,10 +328,12 @@ func TestCanonicalizeRanges(t *testing.T) {
 				MonikerIDs:         datastructures.IDSetWith(4004),
 			},
 			5002: {
-				DefinitionResultID: 0,
-				ReferenceResultID:  2008,
-				HoverResultID:      2008,
-				MonikerIDs:         datastructures.IDSetWith(4005),
+				DefinitionResultID:     0,
+				ReferenceResultID:      2008,
+				TypeDefinitionResultID: 2009,
+				ImplementationResultID: 2010,
+				HoverResultID:          2008,
+				MonikerIDs:             datastructures.IDSetWith(4005),
 			},
 		},
 		NextData:       map[int]int{},
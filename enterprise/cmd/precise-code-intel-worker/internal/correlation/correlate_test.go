@@ -38,12 +38,13 @@ func TestCorrelate(t *testing.T) {
 		},
 		RangeData: map[int]lsif.Range{
 			4: {
-				StartLine:          1,
-				StartCharacter:     2,
-				EndLine:            3,
-				EndCharacter:       4,
-				DefinitionResultID: 13,
-				MonikerIDs:         datastructures.NewIDSet(),
+				StartLine:              1,
+				StartCharacter:         2,
+				EndLine:                3,
+				EndCharacter:           4,
+				DefinitionResultID:     13,
+				ImplementationResultID: 52,
+				MonikerIDs:             datastructures.NewIDSet(),
 			},
 			5: {
 				StartLine:         2,
@@ -88,9 +89,10 @@ func TestCorrelate(t *testing.T) {
 		},
 		ResultSetData: map[int]lsif.ResultSet{
 			10: {
-				DefinitionResultID: 12,
-				ReferenceResultID:  14,
-				MonikerIDs:         datastructures.IDSetWith(20),
+				DefinitionResultID:     12,
+				ReferenceResultID:      14,
+				TypeDefinitionResultID: 51,
+				MonikerIDs:             datastructures.IDSetWith(20),
 			},
 			11: {
 				HoverResultID: 16,
@@ -105,6 +107,12 @@ func TestCorrelate(t *testing.T) {
 			14: {2: datastructures.IDSetWith(4, 5)},
 			15: {},
 		},
+		TypeDefinitionData: map[int]datastructures.DefaultIDSetMap{
+			51: {3: datastructures.IDSetWith(8)},
+		},
+		ImplementationData: map[int]datastructures.DefaultIDSetMap{
+			52: {2: datastructures.IDSetWith(5, 6)},
+		},
 		HoverData: map[int]string{
 			16: "```go\ntext A\n```",
 			17: "```go\ntext B\n```",
@@ -201,6 +209,8 @@ func TestCorrelateMetaDataRoot(t *testing.T) {
 		ResultSetData:          map[int]lsif.ResultSet{},
 		DefinitionData:         map[int]datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]datastructures.DefaultIDSetMap{},
+		TypeDefinitionData:     map[int]datastructures.DefaultIDSetMap{},
+		ImplementationData:     map[int]datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]lsif.Moniker{},
 		PackageInformationData: map[int]lsif.PackageInformation{},
@@ -242,6 +252,8 @@ func TestCorrelateMetaDataRootX(t *testing.T) {
 		ResultSetData:          map[int]lsif.ResultSet{},
 		DefinitionData:         map[int]datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]datastructures.DefaultIDSetMap{},
+		TypeDefinitionData:     map[int]datastructures.DefaultIDSetMap{},
+		ImplementationData:     map[int]datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]lsif.Moniker{},
 		PackageInformationData: map[int]lsif.PackageInformation{},
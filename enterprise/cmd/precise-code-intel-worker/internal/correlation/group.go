@@ -21,6 +21,8 @@ type GroupedBundleData struct {
 	ResultChunks      map[int]types.ResultChunkData
 	Definitions       []types.MonikerLocations
 	References        []types.MonikerLocations
+	TypeDefinitions   []types.MonikerLocations
+	Implementations   []types.MonikerLocations
 	Packages          []types.Package
 	PackageReferences []types.PackageReference
 }
@@ -30,7 +32,7 @@ const ResultsPerResultChunk = 500
 
 // groupBundleData converts a raw (but canonicalized) correlation State into a GroupedBundleData.
 func groupBundleData(state *State, dumpID int) (*GroupedBundleData, error) {
-	numResults := len(state.DefinitionData) + len(state.ReferenceData)
+	numResults := len(state.DefinitionData) + len(state.ReferenceData) + len(state.TypeDefinitionData) + len(state.ImplementationData)
 	numResultChunks := int(math.Min(
 		MaxNumResultChunks,
 		math.Max(
@@ -44,6 +46,8 @@ func groupBundleData(state *State, dumpID int) (*GroupedBundleData, error) {
 	resultChunks := serializeResultChunks(state, numResultChunks)
 	definitionRows := gatherMonikersLocations(state, state.DefinitionData, getDefinitionResultID)
 	referenceRows := gatherMonikersLocations(state, state.ReferenceData, getReferenceResultID)
+	typeDefinitionRows := gatherMonikersLocations(state, state.TypeDefinitionData, getTypeDefinitionResultID)
+	implementationRows := gatherMonikersLocations(state, state.ImplementationData, getImplementationResultID)
 	packages := gatherPackages(state, dumpID)
 	packageReferences, err := gatherPackageReferences(state, dumpID)
 	if err != nil {
@@ -56,6 +60,8 @@ func groupBundleData(state *State, dumpID int) (*GroupedBundleData, error) {
 		ResultChunks:      resultChunks,
 		Definitions:       definitionRows,
 		References:        referenceRows,
+		TypeDefinitions:   typeDefinitionRows,
+		Implementations:   implementationRows,
 		Packages:          packages,
 		PackageReferences: packageReferences,
 	}, nil
@@ -93,14 +99,16 @@ func serializeDocument(state *State, doc lsif.Document) types.DocumentData {
 		})
 
 		document.Ranges[toID(k)] = types.RangeData{
-			StartLine:          v.StartLine,
-			StartCharacter:     v.StartCharacter,
-			EndLine:            v.EndLine,
-			EndCharacter:       v.EndCharacter,
-			DefinitionResultID: toID(v.DefinitionResultID),
-			ReferenceResultID:  toID(v.ReferenceResultID),
-			HoverResultID:      toID(v.HoverResultID),
-			MonikerIDs:         monikerIDs,
+			StartLine:              v.StartLine,
+			StartCharacter:         v.StartCharacter,
+			EndLine:                v.EndLine,
+			EndCharacter:           v.EndCharacter,
+			DefinitionResultID:     toID(v.DefinitionResultID),
+			ReferenceResultID:      toID(v.ReferenceResultID),
+			TypeDefinitionResultID: toID(v.TypeDefinitionResultID),
+			ImplementationResultID: toID(v.ImplementationResultID),
+			HoverResultID:          toID(v.HoverResultID),
+			MonikerIDs:             monikerIDs,
 		}
 
 		if v.HoverResultID != 0 {
@@ -156,6 +164,8 @@ func serializeResultChunks(state *State, numResultChunks int) map[int]types.Resu
 
 	addToChunk(state, resultChunks, state.DefinitionData)
 	addToChunk(state, resultChunks, state.ReferenceData)
+	addToChunk(state, resultChunks, state.TypeDefinitionData)
+	addToChunk(state, resultChunks, state.ImplementationData)
 
 	out := make(map[int]types.ResultChunkData, len(resultChunks))
 	for id, resultChunk := range resultChunks {
@@ -195,8 +205,10 @@ func addToChunk(state *State, resultChunks []types.ResultChunkData, data map[int
 }
 
 var (
-	getDefinitionResultID = func(r lsif.Range) int { return r.DefinitionResultID }
-	getReferenceResultID  = func(r lsif.Range) int { return r.ReferenceResultID }
+	getDefinitionResultID     = func(r lsif.Range) int { return r.DefinitionResultID }
+	getReferenceResultID      = func(r lsif.Range) int { return r.ReferenceResultID }
+	getTypeDefinitionResultID = func(r lsif.Range) int { return r.TypeDefinitionResultID }
+	getImplementationResultID = func(r lsif.Range) int { return r.ImplementationResultID }
 )
 
 func gatherMonikersLocations(state *State, data map[int]datastructures.DefaultIDSetMap, getResultID func(r lsif.Range) int) []types.MonikerLocations {
@@ -33,22 +33,24 @@ func TestGroupBundleData(t *testing.T) {
 		},
 		RangeData: map[int]lsif.Range{
 			2001: {
-				StartLine:          1,
-				StartCharacter:     2,
-				EndLine:            3,
-				EndCharacter:       4,
-				DefinitionResultID: 3001,
-				ReferenceResultID:  0,
-				MonikerIDs:         datastructures.IDSetWith(4001, 4002),
+				StartLine:              1,
+				StartCharacter:         2,
+				EndLine:                3,
+				EndCharacter:           4,
+				DefinitionResultID:     3001,
+				ReferenceResultID:      0,
+				TypeDefinitionResultID: 3011,
+				MonikerIDs:             datastructures.IDSetWith(4001, 4002),
 			},
 			2002: {
-				StartLine:          2,
-				StartCharacter:     3,
-				EndLine:            4,
-				EndCharacter:       5,
-				DefinitionResultID: 0,
-				ReferenceResultID:  3006,
-				MonikerIDs:         datastructures.IDSetWith(4003, 4004)},
+				StartLine:              2,
+				StartCharacter:         3,
+				EndLine:                4,
+				EndCharacter:           5,
+				DefinitionResultID:     0,
+				ReferenceResultID:      3006,
+				ImplementationResultID: 3010,
+				MonikerIDs:             datastructures.IDSetWith(4003, 4004)},
 			2003: {
 				StartLine:          3,
 				StartCharacter:     4,
@@ -147,6 +149,16 @@ func TestGroupBundleData(t *testing.T) {
 				1003: datastructures.IDSetWith(2007, 2009),
 			},
 		},
+		TypeDefinitionData: map[int]datastructures.DefaultIDSetMap{
+			3011: {
+				1002: datastructures.IDSetWith(2005),
+			},
+		},
+		ImplementationData: map[int]datastructures.DefaultIDSetMap{
+			3010: {
+				1003: datastructures.IDSetWith(2008),
+			},
+		},
 		HoverData: map[int]string{
 			3008: "foo",
 			3009: "bar",
@@ -265,24 +277,26 @@ func TestGroupBundleData(t *testing.T) {
 			"foo.go": {
 				Ranges: map[types.ID]types.RangeData{
 					"2001": {
-						StartLine:          1,
-						StartCharacter:     2,
-						EndLine:            3,
-						EndCharacter:       4,
-						DefinitionResultID: "3001",
-						ReferenceResultID:  "",
-						HoverResultID:      "",
-						MonikerIDs:         []types.ID{"4001", "4002"},
+						StartLine:              1,
+						StartCharacter:         2,
+						EndLine:                3,
+						EndCharacter:           4,
+						DefinitionResultID:     "3001",
+						ReferenceResultID:      "",
+						TypeDefinitionResultID: "3011",
+						HoverResultID:          "",
+						MonikerIDs:             []types.ID{"4001", "4002"},
 					},
 					"2002": {
-						StartLine:          2,
-						StartCharacter:     3,
-						EndLine:            4,
-						EndCharacter:       5,
-						DefinitionResultID: "",
-						ReferenceResultID:  "3006",
-						HoverResultID:      "",
-						MonikerIDs:         []types.ID{"4003", "4004"},
+						StartLine:              2,
+						StartCharacter:         3,
+						EndLine:                4,
+						EndCharacter:           5,
+						DefinitionResultID:     "",
+						ReferenceResultID:      "3006",
+						ImplementationResultID: "3010",
+						HoverResultID:          "",
+						MonikerIDs:             []types.ID{"4003", "4004"},
 					},
 					"2003": {
 						StartLine:          3,
@@ -496,6 +510,12 @@ func TestGroupBundleData(t *testing.T) {
 						{DocumentID: "1003", RangeID: "2007"},
 						{DocumentID: "1003", RangeID: "2009"},
 					},
+					"3010": {
+						{DocumentID: "1003", RangeID: "2008"},
+					},
+					"3011": {
+						{DocumentID: "1002", RangeID: "2005"},
+					},
 				},
 			},
 		},
@@ -539,6 +559,38 @@ func TestGroupBundleData(t *testing.T) {
 				},
 			},
 		},
+		TypeDefinitions: []types.MonikerLocations{
+			{
+				Scheme:     "scheme A",
+				Identifier: "ident A",
+				Locations: []types.Location{
+					{URI: "bar.go", StartLine: 5, StartCharacter: 6, EndLine: 7, EndCharacter: 8},
+				},
+			},
+			{
+				Scheme:     "scheme B",
+				Identifier: "ident B",
+				Locations: []types.Location{
+					{URI: "bar.go", StartLine: 5, StartCharacter: 6, EndLine: 7, EndCharacter: 8},
+				},
+			},
+		},
+		Implementations: []types.MonikerLocations{
+			{
+				Scheme:     "scheme C",
+				Identifier: "ident C",
+				Locations: []types.Location{
+					{URI: "baz.go", StartLine: 8, StartCharacter: 9, EndLine: 0, EndCharacter: 1},
+				},
+			},
+			{
+				Scheme:     "scheme D",
+				Identifier: "ident D",
+				Locations: []types.Location{
+					{URI: "baz.go", StartLine: 8, StartCharacter: 9, EndLine: 0, EndCharacter: 1},
+				},
+			},
+		},
 		Packages: []types.Package{
 			{DumpID: 42, Scheme: "scheme C", Name: "pkg B", Version: "1.2.3"},
 		},
@@ -572,6 +624,8 @@ func normalizeGroupedBundleData(groupedBundleData *GroupedBundleData) {
 
 	sortMonikerLocations(groupedBundleData.Definitions)
 	sortMonikerLocations(groupedBundleData.References)
+	sortMonikerLocations(groupedBundleData.TypeDefinitions)
+	sortMonikerLocations(groupedBundleData.Implementations)
 }
 
 func sortMonikerIDs(s []types.ID) {
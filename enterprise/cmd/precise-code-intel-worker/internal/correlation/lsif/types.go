@@ -28,108 +28,180 @@ type Document struct {
 }
 
 type Range struct {
-	StartLine          int
-	StartCharacter     int
-	EndLine            int
-	EndCharacter       int
-	DefinitionResultID int
-	ReferenceResultID  int
-	HoverResultID      int
-	MonikerIDs         *datastructures.IDSet
+	StartLine              int
+	StartCharacter         int
+	EndLine                int
+	EndCharacter           int
+	DefinitionResultID     int
+	ReferenceResultID      int
+	TypeDefinitionResultID int
+	ImplementationResultID int
+	HoverResultID          int
+	MonikerIDs             *datastructures.IDSet
 }
 
 func (d Range) SetDefinitionResultID(id int) Range {
 	return Range{
-		StartLine:          d.StartLine,
-		StartCharacter:     d.StartCharacter,
-		EndLine:            d.EndLine,
-		EndCharacter:       d.EndCharacter,
-		DefinitionResultID: id,
-		ReferenceResultID:  d.ReferenceResultID,
-		HoverResultID:      d.HoverResultID,
-		MonikerIDs:         d.MonikerIDs,
+		StartLine:              d.StartLine,
+		StartCharacter:         d.StartCharacter,
+		EndLine:                d.EndLine,
+		EndCharacter:           d.EndCharacter,
+		DefinitionResultID:     id,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             d.MonikerIDs,
 	}
 }
 
 func (d Range) SetReferenceResultID(id int) Range {
 	return Range{
-		StartLine:          d.StartLine,
-		StartCharacter:     d.StartCharacter,
-		EndLine:            d.EndLine,
-		EndCharacter:       d.EndCharacter,
-		DefinitionResultID: d.DefinitionResultID,
-		ReferenceResultID:  id,
-		HoverResultID:      d.HoverResultID,
-		MonikerIDs:         d.MonikerIDs,
+		StartLine:              d.StartLine,
+		StartCharacter:         d.StartCharacter,
+		EndLine:                d.EndLine,
+		EndCharacter:           d.EndCharacter,
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      id,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             d.MonikerIDs,
+	}
+}
+
+func (d Range) SetTypeDefinitionResultID(id int) Range {
+	return Range{
+		StartLine:              d.StartLine,
+		StartCharacter:         d.StartCharacter,
+		EndLine:                d.EndLine,
+		EndCharacter:           d.EndCharacter,
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: id,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             d.MonikerIDs,
+	}
+}
+
+func (d Range) SetImplementationResultID(id int) Range {
+	return Range{
+		StartLine:              d.StartLine,
+		StartCharacter:         d.StartCharacter,
+		EndLine:                d.EndLine,
+		EndCharacter:           d.EndCharacter,
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: id,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             d.MonikerIDs,
 	}
 }
 
 func (d Range) SetHoverResultID(id int) Range {
 	return Range{
-		StartLine:          d.StartLine,
-		StartCharacter:     d.StartCharacter,
-		EndLine:            d.EndLine,
-		EndCharacter:       d.EndCharacter,
-		DefinitionResultID: d.DefinitionResultID,
-		ReferenceResultID:  d.ReferenceResultID,
-		HoverResultID:      id,
-		MonikerIDs:         d.MonikerIDs,
+		StartLine:              d.StartLine,
+		StartCharacter:         d.StartCharacter,
+		EndLine:                d.EndLine,
+		EndCharacter:           d.EndCharacter,
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          id,
+		MonikerIDs:             d.MonikerIDs,
 	}
 }
 
 func (d Range) SetMonikerIDs(ids *datastructures.IDSet) Range {
 	return Range{
-		StartLine:          d.StartLine,
-		StartCharacter:     d.StartCharacter,
-		EndLine:            d.EndLine,
-		EndCharacter:       d.EndCharacter,
-		DefinitionResultID: d.DefinitionResultID,
-		ReferenceResultID:  d.ReferenceResultID,
-		HoverResultID:      d.HoverResultID,
-		MonikerIDs:         ids,
+		StartLine:              d.StartLine,
+		StartCharacter:         d.StartCharacter,
+		EndLine:                d.EndLine,
+		EndCharacter:           d.EndCharacter,
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             ids,
 	}
 }
 
 type ResultSet struct {
-	DefinitionResultID int
-	ReferenceResultID  int
-	HoverResultID      int
-	MonikerIDs         *datastructures.IDSet
+	DefinitionResultID     int
+	ReferenceResultID      int
+	TypeDefinitionResultID int
+	ImplementationResultID int
+	HoverResultID          int
+	MonikerIDs             *datastructures.IDSet
 }
 
 func (d ResultSet) SetDefinitionResultID(id int) ResultSet {
 	return ResultSet{
-		DefinitionResultID: id,
-		ReferenceResultID:  d.ReferenceResultID,
-		HoverResultID:      d.HoverResultID,
-		MonikerIDs:         d.MonikerIDs,
+		DefinitionResultID:     id,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             d.MonikerIDs,
 	}
 }
 
 func (d ResultSet) SetReferenceResultID(id int) ResultSet {
 	return ResultSet{
-		DefinitionResultID: d.DefinitionResultID,
-		ReferenceResultID:  id,
-		HoverResultID:      d.HoverResultID,
-		MonikerIDs:         d.MonikerIDs,
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      id,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             d.MonikerIDs,
+	}
+}
+
+func (d ResultSet) SetTypeDefinitionResultID(id int) ResultSet {
+	return ResultSet{
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: id,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             d.MonikerIDs,
+	}
+}
+
+func (d ResultSet) SetImplementationResultID(id int) ResultSet {
+	return ResultSet{
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: id,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             d.MonikerIDs,
 	}
 }
 
 func (d ResultSet) SetHoverResultID(id int) ResultSet {
 	return ResultSet{
-		DefinitionResultID: d.DefinitionResultID,
-		ReferenceResultID:  d.ReferenceResultID,
-		HoverResultID:      id,
-		MonikerIDs:         d.MonikerIDs,
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          id,
+		MonikerIDs:             d.MonikerIDs,
 	}
 }
 
 func (d ResultSet) SetMonikerIDs(ids *datastructures.IDSet) ResultSet {
 	return ResultSet{
-		DefinitionResultID: d.DefinitionResultID,
-		ReferenceResultID:  d.ReferenceResultID,
-		HoverResultID:      d.HoverResultID,
-		MonikerIDs:         ids,
+		DefinitionResultID:     d.DefinitionResultID,
+		ReferenceResultID:      d.ReferenceResultID,
+		TypeDefinitionResultID: d.TypeDefinitionResultID,
+		ImplementationResultID: d.ImplementationResultID,
+		HoverResultID:          d.HoverResultID,
+		MonikerIDs:             ids,
 	}
 }
 
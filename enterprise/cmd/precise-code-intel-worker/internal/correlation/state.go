@@ -14,6 +14,8 @@ type State struct {
 	ResultSetData          map[int]lsif.ResultSet
 	DefinitionData         map[int]datastructures.DefaultIDSetMap
 	ReferenceData          map[int]datastructures.DefaultIDSetMap
+	TypeDefinitionData     map[int]datastructures.DefaultIDSetMap
+	ImplementationData     map[int]datastructures.DefaultIDSetMap
 	HoverData              map[int]string
 	MonikerData            map[int]lsif.Moniker
 	PackageInformationData map[int]lsif.PackageInformation
@@ -33,6 +35,8 @@ func newState() *State {
 		ResultSetData:          map[int]lsif.ResultSet{},
 		DefinitionData:         map[int]datastructures.DefaultIDSetMap{},
 		ReferenceData:          map[int]datastructures.DefaultIDSetMap{},
+		TypeDefinitionData:     map[int]datastructures.DefaultIDSetMap{},
+		ImplementationData:     map[int]datastructures.DefaultIDSetMap{},
 		HoverData:              map[int]string{},
 		MonikerData:            map[int]lsif.Moniker{},
 		PackageInformationData: map[int]lsif.PackageInformation{},
@@ -174,6 +174,12 @@ func (p *processor) write(ctx context.Context, dirname string, groupedBundleData
 	if err := writer.WriteReferences(ctx, groupedBundleData.References); err != nil {
 		return errors.Wrap(err, "writer.WriteReferences")
 	}
+	if err := writer.WriteTypeDefinitions(ctx, groupedBundleData.TypeDefinitions); err != nil {
+		return errors.Wrap(err, "writer.WriteTypeDefinitions")
+	}
+	if err := writer.WriteImplementations(ctx, groupedBundleData.Implementations); err != nil {
+		return errors.Wrap(err, "writer.WriteImplementations")
+	}
 
 	return err
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
@@ -100,6 +101,9 @@ func ComputeCheckState(c *cmpgn.Changeset, events ChangesetEvents) cmpgn.Changes
 
 	case *bitbucketserver.PullRequest:
 		return computeBitbucketBuildStatus(c.UpdatedAt, m, events)
+
+	case *gitlab.MergeRequest:
+		return computeGitLabPipelineState(c.UpdatedAt, m, events)
 	}
 
 	return cmpgn.ChangesetCheckStateUnknown
@@ -192,6 +196,62 @@ func parseBitbucketBuildState(s string) cmpgn.ChangesetCheckState {
 	}
 }
 
+func computeGitLabPipelineState(lastSynced time.Time, mr *gitlab.MergeRequest, events []*cmpgn.ChangesetEvent) cmpgn.ChangesetCheckState {
+	pipelines := make(map[int]*gitlab.Pipeline, len(mr.Pipelines))
+
+	// Pipelines from last sync
+	if mr.HeadPipeline != nil {
+		pipelines[mr.HeadPipeline.ID] = mr.HeadPipeline
+	}
+	for _, p := range mr.Pipelines {
+		pipelines[p.ID] = p
+	}
+
+	// Add any pipeline updates we've received since our last sync
+	for _, e := range events {
+		switch m := e.Metadata.(type) {
+		case *gitlab.Pipeline:
+			if _, ok := pipelines[m.ID]; !ok || m.UpdatedAt.After(lastSynced) {
+				pipelines[m.ID] = m
+			}
+		}
+	}
+
+	// Each pipeline already combines the status of all of its jobs, so we
+	// only need to look at the most recent one.
+	var latest *gitlab.Pipeline
+	for _, p := range pipelines {
+		if latest == nil || p.CreatedAt.After(latest.CreatedAt) ||
+			(p.CreatedAt.Equal(latest.CreatedAt) && p.ID > latest.ID) {
+			latest = p
+		}
+	}
+
+	if latest == nil {
+		return cmpgn.ChangesetCheckStateUnknown
+	}
+	return parseGitLabPipelineStatus(latest.Status)
+}
+
+func parseGitLabPipelineStatus(s gitlab.PipelineStatus) cmpgn.ChangesetCheckState {
+	switch s {
+	case gitlab.PipelineStatusFailed, gitlab.PipelineStatusCanceled:
+		return cmpgn.ChangesetCheckStateFailed
+	case gitlab.PipelineStatusCreated,
+		gitlab.PipelineStatusWaitingForResource,
+		gitlab.PipelineStatusPreparing,
+		gitlab.PipelineStatusPending,
+		gitlab.PipelineStatusRunning,
+		gitlab.PipelineStatusManual,
+		gitlab.PipelineStatusScheduled:
+		return cmpgn.ChangesetCheckStatePending
+	case gitlab.PipelineStatusSuccess:
+		return cmpgn.ChangesetCheckStatePassed
+	default:
+		return cmpgn.ChangesetCheckStateUnknown
+	}
+}
+
 func computeGitHubCheckState(lastSynced time.Time, pr *github.PullRequest, events []*cmpgn.ChangesetEvent) cmpgn.ChangesetCheckState {
 	// We should only consider the latest commit. This could be from a sync or a webhook that
 	// has occurred later
@@ -364,6 +424,15 @@ func computeSingleChangesetState(c *cmpgn.Changeset) (s cmpgn.ChangesetState, er
 		} else {
 			s = cmpgn.ChangesetState(m.State)
 		}
+	case *gitlab.MergeRequest:
+		switch m.State {
+		case gitlab.MergeRequestStateClosed, gitlab.MergeRequestStateLocked:
+			s = cmpgn.ChangesetStateClosed
+		case gitlab.MergeRequestStateMerged:
+			s = cmpgn.ChangesetStateMerged
+		default:
+			s = cmpgn.ChangesetStateOpen
+		}
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -400,6 +469,21 @@ func computeSingleChangesetReviewState(c *cmpgn.Changeset) (s cmpgn.ChangesetRev
 				states[cmpgn.ChangesetReviewStateApproved] = true
 			}
 		}
+
+	case *gitlab.MergeRequest:
+		// GitLab doesn't return approvals with the merge request, so we
+		// replay the approval notes that were loaded along with it.
+		byAuthor := map[string]cmpgn.ChangesetReviewState{}
+		for _, n := range m.Notes {
+			switch e := n.ToEvent().(type) {
+			case *gitlab.ReviewApprovedEvent:
+				byAuthor[e.Author.Username] = cmpgn.ChangesetReviewStateApproved
+			case *gitlab.ReviewUnapprovedEvent:
+				delete(byAuthor, e.Author.Username)
+			}
+		}
+		return computeReviewState(byAuthor), nil
+
 	default:
 		return "", errors.New("unknown changeset type")
 	}
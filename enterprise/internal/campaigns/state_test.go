@@ -1,6 +1,7 @@
 package campaigns
 
 import (
+	"sort"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 )
 
 func TestComputeGithubCheckState(t *testing.T) {
@@ -261,6 +263,89 @@ func TestComputeBitbucketBuildStatus(t *testing.T) {
 	}
 }
 
+func TestComputeGitLabPipelineState(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	lastSynced := now.Add(-1 * time.Minute)
+
+	pipeline := func(id, minutesSinceSync int, status gitlab.PipelineStatus) *gitlab.Pipeline {
+		return &gitlab.Pipeline{
+			ID:        id,
+			Status:    status,
+			CreatedAt: lastSynced.Add(time.Duration(id) * time.Second),
+			UpdatedAt: now.Add(time.Duration(minutesSinceSync) * time.Minute),
+		}
+	}
+	pipelineEvent := func(id, minutesSinceSync int, status gitlab.PipelineStatus) *cmpgn.ChangesetEvent {
+		return &cmpgn.ChangesetEvent{
+			Kind:     cmpgn.ChangesetEventKindGitLabPipeline,
+			Metadata: pipeline(id, minutesSinceSync, status),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		pipelines []*gitlab.Pipeline
+		events    []*cmpgn.ChangesetEvent
+		want      cmpgn.ChangesetCheckState
+	}{
+		{
+			name: "no pipelines",
+			want: cmpgn.ChangesetCheckStateUnknown,
+		},
+		{
+			name:      "single synced success",
+			pipelines: []*gitlab.Pipeline{pipeline(1, -2, gitlab.PipelineStatusSuccess)},
+			want:      cmpgn.ChangesetCheckStatePassed,
+		},
+		{
+			name:      "single synced running",
+			pipelines: []*gitlab.Pipeline{pipeline(1, -2, gitlab.PipelineStatusRunning)},
+			want:      cmpgn.ChangesetCheckStatePending,
+		},
+		{
+			name:      "single synced canceled",
+			pipelines: []*gitlab.Pipeline{pipeline(1, -2, gitlab.PipelineStatusCanceled)},
+			want:      cmpgn.ChangesetCheckStateFailed,
+		},
+		{
+			name: "latest pipeline wins",
+			pipelines: []*gitlab.Pipeline{
+				pipeline(1, -2, gitlab.PipelineStatusFailed),
+				pipeline(2, -2, gitlab.PipelineStatusSuccess),
+			},
+			want: cmpgn.ChangesetCheckStatePassed,
+		},
+		{
+			name:      "event updates synced pipeline",
+			pipelines: []*gitlab.Pipeline{pipeline(1, -2, gitlab.PipelineStatusRunning)},
+			events:    []*cmpgn.ChangesetEvent{pipelineEvent(1, 1, gitlab.PipelineStatusFailed)},
+			want:      cmpgn.ChangesetCheckStateFailed,
+		},
+		{
+			name:      "event older than sync is ignored",
+			pipelines: []*gitlab.Pipeline{pipeline(1, -2, gitlab.PipelineStatusSuccess)},
+			events:    []*cmpgn.ChangesetEvent{pipelineEvent(1, -3, gitlab.PipelineStatusRunning)},
+			want:      cmpgn.ChangesetCheckStatePassed,
+		},
+		{
+			name:      "new pipeline from event",
+			pipelines: []*gitlab.Pipeline{pipeline(1, -2, gitlab.PipelineStatusSuccess)},
+			events:    []*cmpgn.ChangesetEvent{pipelineEvent(2, 1, gitlab.PipelineStatusPending)},
+			want:      cmpgn.ChangesetCheckStatePending,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mr := &gitlab.MergeRequest{Pipelines: tc.pipelines}
+			have := computeGitLabPipelineState(lastSynced, mr, tc.events)
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatalf(diff)
+			}
+		})
+	}
+}
+
 func TestComputeReviewState(t *testing.T) {
 	now := time.Now().UTC().Truncate(time.Microsecond)
 	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
@@ -317,6 +402,37 @@ func TestComputeReviewState(t *testing.T) {
 			},
 			want: cmpgn.ChangesetReviewStateChangesRequested,
 		},
+		{
+			name:      "gitlab - no events, no approvals",
+			changeset: gitlabChangeset(daysAgo(10), gitlab.MergeRequestStateOpened),
+			history:   []changesetStatesAtTime{},
+			want:      cmpgn.ChangesetReviewStatePending,
+		},
+		{
+			name: "gitlab - no events, approved",
+			changeset: gitlabChangeset(daysAgo(10), gitlab.MergeRequestStateOpened,
+				gitlabNote("alice", "approved this merge request", daysAgo(11)),
+			),
+			history: []changesetStatesAtTime{},
+			want:    cmpgn.ChangesetReviewStateApproved,
+		},
+		{
+			name: "gitlab - no events, approval revoked",
+			changeset: gitlabChangeset(daysAgo(10), gitlab.MergeRequestStateOpened,
+				gitlabNote("alice", "approved this merge request", daysAgo(12)),
+				gitlabNote("alice", "unapproved this merge request", daysAgo(11)),
+			),
+			history: []changesetStatesAtTime{},
+			want:    cmpgn.ChangesetReviewStatePending,
+		},
+		{
+			name:      "gitlab - changeset older than events",
+			changeset: gitlabChangeset(daysAgo(10), gitlab.MergeRequestStateOpened),
+			history: []changesetStatesAtTime{
+				{t: daysAgo(0), reviewState: campaigns.ChangesetReviewStateApproved},
+			},
+			want: cmpgn.ChangesetReviewStateApproved,
+		},
 	}
 
 	for i, tc := range tests {
@@ -335,6 +451,56 @@ func TestComputeReviewState(t *testing.T) {
 	}
 }
 
+func TestComputeReviewState_GitLabWebhooks(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	minutesAgo := func(minutes int) time.Time { return now.Add(-time.Duration(minutes) * time.Minute) }
+
+	changeset := gitlabChangeset(minutesAgo(60), gitlab.MergeRequestStateOpened)
+	changeset.Metadata.(*gitlab.MergeRequest).CreatedAt = minutesAgo(120)
+
+	// Approve, unapprove and approve again, as received through webhooks.
+	webhookEvents := []keyer{
+		&gitlab.ReviewApprovedEvent{Note: gitlabNote("alice", "approved this merge request", minutesAgo(30))},
+		&gitlab.ReviewUnapprovedEvent{Note: gitlabNote("alice", "unapproved this merge request", minutesAgo(20))},
+		&gitlab.ReviewApprovedEvent{Note: gitlabNote("alice", "approved this merge request", minutesAgo(10))},
+	}
+
+	// Upsert the events like upsertChangesetEvent does: an event with the kind
+	// and key of an existing one updates it.
+	var events ChangesetEvents
+	for _, ev := range webhookEvents {
+		event := &cmpgn.ChangesetEvent{
+			ChangesetID: changeset.ID,
+			Kind:        cmpgn.ChangesetEventKindFor(ev),
+			Key:         ev.Key(),
+			Metadata:    ev,
+		}
+		updated := false
+		for _, existing := range events {
+			if existing.Kind == event.Kind && existing.Key == event.Key {
+				existing.Update(event)
+				updated = true
+			}
+		}
+		if !updated {
+			events = append(events, event)
+		}
+	}
+	sort.Sort(events)
+
+	history, err := computeHistory(changeset, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	have, err := ComputeReviewState(changeset, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := cmpgn.ChangesetReviewStateApproved; have != want {
+		t.Errorf("wrong review state. have=%s, want=%s", have, want)
+	}
+}
+
 func TestComputeChangesetState(t *testing.T) {
 	now := time.Now().UTC().Truncate(time.Microsecond)
 	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
@@ -405,6 +571,26 @@ func TestComputeChangesetState(t *testing.T) {
 			},
 			want: cmpgn.ChangesetStateDeleted,
 		},
+		{
+			name:      "gitlab - no events, opened",
+			changeset: gitlabChangeset(daysAgo(10), gitlab.MergeRequestStateOpened),
+			history:   []changesetStatesAtTime{},
+			want:      cmpgn.ChangesetStateOpen,
+		},
+		{
+			name:      "gitlab - no events, locked",
+			changeset: gitlabChangeset(daysAgo(10), gitlab.MergeRequestStateLocked),
+			history:   []changesetStatesAtTime{},
+			want:      cmpgn.ChangesetStateClosed,
+		},
+		{
+			name:      "gitlab - changeset older than events",
+			changeset: gitlabChangeset(daysAgo(10), gitlab.MergeRequestStateOpened),
+			history: []changesetStatesAtTime{
+				{t: daysAgo(0), state: campaigns.ChangesetStateMerged},
+			},
+			want: cmpgn.ChangesetStateMerged,
+		},
 	}
 
 	for i, tc := range tests {
@@ -451,6 +637,23 @@ func githubChangeset(updatedAt time.Time, state string) *campaigns.Changeset {
 	}
 }
 
+func gitlabChangeset(updatedAt time.Time, state gitlab.MergeRequestState, notes ...*gitlab.Note) *campaigns.Changeset {
+	return &campaigns.Changeset{
+		ExternalServiceType: extsvc.TypeGitLab,
+		UpdatedAt:           updatedAt,
+		Metadata:            &gitlab.MergeRequest{State: state, Notes: notes},
+	}
+}
+
+func gitlabNote(author, body string, createdAt time.Time) *gitlab.Note {
+	return &gitlab.Note{
+		Body:      body,
+		Author:    gitlab.User{Username: author},
+		CreatedAt: createdAt,
+		System:    true,
+	}
+}
+
 func setDeletedAt(c *campaigns.Changeset, deletedAt time.Time) *campaigns.Changeset {
 	c.ExternalDeletedAt = deletedAt
 	return c
@@ -2,6 +2,7 @@ package campaigns
 
 import (
 	"context"
+	"crypto/subtle"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -21,6 +22,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -78,6 +82,8 @@ func extractExternalServiceID(extSvc *repos.ExternalService) (string, error) {
 		serviceID = c.Url
 	case *schema.BitbucketServerConnection:
 		serviceID = c.Url
+	case *schema.GitLabConnection:
+		serviceID = c.Url
 	}
 	if serviceID == "" {
 		return "", errors.New("could not determine service id")
@@ -91,6 +97,32 @@ func extractExternalServiceID(extSvc *repos.ExternalService) (string, error) {
 	return extsvc.NormalizeBaseURL(u).String(), nil
 }
 
+// enqueuePushedRepoUpdate requests an update of the repo with the given
+// external ID, for which the code host delivered a push webhook. Pushes to
+// repos that aren't synced are ignored.
+func (h Webhook) enqueuePushedRepoUpdate(ctx context.Context, externalServiceID, repoExternalID string) error {
+	rs, err := h.Repos.ListRepos(ctx, repos.StoreListReposArgs{
+		ExternalRepos: []api.ExternalRepoSpec{
+			{
+				ID:          repoExternalID,
+				ServiceType: h.ServiceType,
+				ServiceID:   externalServiceID,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to load repository")
+	}
+
+	if len(rs) != 1 {
+		log15.Debug("Push webhook event could not be matched to repo", "externalID", repoExternalID)
+		return nil
+	}
+
+	_, err = repoupdater.DefaultClient.EnqueuePushedRepoUpdate(ctx, gitserver.Repo{Name: api.RepoName(rs[0].Name)})
+	return errors.Wrap(err, "failed to enqueue repository update")
+}
+
 type keyer interface {
 	Key() string
 }
@@ -177,7 +209,8 @@ func (h Webhook) upsertChangesetEvent(
 
 // GitHubWebhook receives GitHub organization webhook events that are
 // relevant to campaigns, normalizes those events into ChangesetEvents
-// and upserts them to the database.
+// and upserts them to the database. Push events trigger an update of the
+// pushed repository.
 type GitHubWebhook struct {
 	*Webhook
 }
@@ -209,6 +242,13 @@ func (h *GitHubWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if push, ok := e.(*gh.PushEvent); ok {
+		if err := h.enqueuePushedRepoUpdate(r.Context(), externalServiceID, push.GetRepo().GetNodeID()); err != nil {
+			respond(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
 	prs, ev := h.convertEvent(r.Context(), externalServiceID, e)
 	if len(prs) == 0 || ev == nil {
 		respond(w, http.StatusOK, nil) // Nothing to do
@@ -797,6 +837,13 @@ func (h *BitbucketServerWebhook) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if push, ok := e.(*bitbucketserver.RefsChangedEvent); ok {
+		if err := h.enqueuePushedRepoUpdate(r.Context(), externalServiceID, strconv.Itoa(push.Repository.ID)); err != nil {
+			respond(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
 	prs, ev := h.convertEvent(e)
 
 	m := new(multierror.Error)
@@ -903,6 +950,152 @@ func (h *BitbucketServerWebhook) convertEvent(theirs interface{}) (prs []PR, our
 	return
 }
 
+// GitLabWebhook receives GitLab project webhook events that are relevant to
+// campaigns, normalizes those events into ChangesetEvents and upserts them
+// to the database. Push events trigger an update of the pushed repository.
+type GitLabWebhook struct {
+	*Webhook
+}
+
+func NewGitLabWebhook(store *Store, repos repos.Store, now func() time.Time) *GitLabWebhook {
+	return &GitLabWebhook{&Webhook{store, repos, now, extsvc.TypeGitLab}}
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *GitLabWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e, extSvc, hErr := h.parseEvent(r)
+	if hErr != nil {
+		respond(w, hErr.code, hErr)
+		return
+	}
+
+	externalServiceID, err := extractExternalServiceID(extSvc)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if push, ok := e.(*gitlab.PushWebhookEvent); ok {
+		if err := h.enqueuePushedRepoUpdate(r.Context(), externalServiceID, strconv.Itoa(push.Project.ID)); err != nil {
+			respond(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	prs, ev := h.convertEvent(e)
+	if len(prs) == 0 || ev == nil {
+		respond(w, http.StatusOK, nil) // Nothing to do
+		return
+	}
+
+	m := new(multierror.Error)
+	for _, pr := range prs {
+		if pr == (PR{}) {
+			continue
+		}
+
+		err := h.upsertChangesetEvent(r.Context(), externalServiceID, pr, ev)
+		if err != nil {
+			m = multierror.Append(m, err)
+		}
+	}
+	if m.ErrorOrNil() != nil {
+		respond(w, http.StatusInternalServerError, m)
+	}
+}
+
+func (h *GitLabWebhook) parseEvent(r *http.Request) (interface{}, *repos.ExternalService, *httpError) {
+	payload, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	rawID := r.FormValue(extsvc.IDParam)
+	if rawID == "" {
+		return nil, nil, &httpError{http.StatusBadRequest, errors.New("missing external service id")}
+	}
+	externalServiceID, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "invalid external service id")}
+	}
+
+	args := repos.StoreListExternalServicesArgs{
+		IDs:   []int64{externalServiceID},
+		Kinds: []string{extsvc.KindGitLab},
+	}
+	es, err := h.Repos.ListExternalServices(r.Context(), args)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	// 🚨 SECURITY: GitLab doesn't sign webhook payloads, it sends the
+	// configured secret token in a header instead. If there are no secrets or
+	// none of them match the token, we return a 401 to the client.
+	token := []byte(gitlab.WebhookToken(r))
+
+	var extSvc *repos.ExternalService
+	for _, e := range es {
+		c, _ := e.Configuration()
+		con, ok := c.(*schema.GitLabConnection)
+		if !ok {
+			continue
+		}
+
+		for _, hook := range con.Webhooks {
+			if hook.Secret == "" {
+				continue
+			}
+
+			if subtle.ConstantTimeCompare(token, []byte(hook.Secret)) == 1 {
+				extSvc = e
+				break
+			}
+		}
+	}
+
+	if extSvc == nil {
+		return nil, nil, &httpError{http.StatusUnauthorized, nil}
+	}
+
+	e, err := gitlab.ParseWebhookEvent(gitlab.WebhookEventType(r), payload)
+	if err != nil {
+		return nil, nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "parsing webhook")}
+	}
+	return e, extSvc, nil
+}
+
+func (h *GitLabWebhook) convertEvent(theirs interface{}) (prs []PR, ours keyer) {
+	log15.Debug("GitLab webhook received", "type", fmt.Sprintf("%T", theirs))
+
+	switch e := theirs.(type) {
+	case *gitlab.MergeRequestWebhookEvent:
+		ev, ok := e.ToEvent().(keyer)
+		if !ok {
+			return nil, nil
+		}
+
+		repoID := strconv.Itoa(e.Project.ID)
+		prs = append(prs, PR{ID: int64(e.ObjectAttributes.IID), RepoExternalID: repoID})
+		return prs, ev
+
+	case *gitlab.PipelineWebhookEvent:
+		// Pipelines that don't belong to a merge request can't be matched
+		// to a changeset.
+		if e.MergeRequest == nil {
+			return nil, nil
+		}
+
+		repoID := strconv.Itoa(e.Project.ID)
+		prs = append(prs, PR{ID: int64(e.MergeRequest.IID), RepoExternalID: repoID})
+
+		pipeline := e.Pipeline()
+		pipeline.UpdatedAt = h.Now()
+		return prs, pipeline
+	}
+
+	return nil, nil
+}
+
 type httpError struct {
 	code int
 	err  error
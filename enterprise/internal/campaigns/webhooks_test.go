@@ -23,10 +23,13 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repos"
 	ct "github.com/sourcegraph/sourcegraph/enterprise/internal/campaigns/testing"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/campaigns"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/httptestutil"
 	"github.com/sourcegraph/sourcegraph/internal/rcache"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
 	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -438,3 +441,96 @@ func marshalJSON(t testing.TB, v interface{}) string {
 
 	return string(bs)
 }
+
+func TestGitLabWebhook(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	clock := func() time.Time { return now }
+
+	store := new(repos.FakeStore)
+	extSvc := &repos.ExternalService{
+		Kind:        extsvc.KindGitLab,
+		DisplayName: "GitLab",
+		Config: marshalJSON(t, &schema.GitLabConnection{
+			Url:      "https://gitlab.com",
+			Token:    "secret-token",
+			Webhooks: []*schema.GitLabWebhook{{Secret: "secret"}},
+		}),
+	}
+	if err := store.UpsertExternalServices(ctx, extSvc); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := &repos.Repo{
+		Name: "gitlab.com/group/project",
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          "42",
+			ServiceType: extsvc.TypeGitLab,
+			ServiceID:   "https://gitlab.com/",
+		},
+	}
+	if err := store.UpsertRepos(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+
+	var enqueued []api.RepoName
+	repoupdater.MockEnqueuePushedRepoUpdate = func(_ context.Context, repo gitserver.Repo) (*protocol.RepoUpdateResponse, error) {
+		enqueued = append(enqueued, repo.Name)
+		return &protocol.RepoUpdateResponse{}, nil
+	}
+	defer func() { repoupdater.MockEnqueuePushedRepoUpdate = nil }()
+
+	hook := NewGitLabWebhook(nil, store, clock)
+
+	mergeRequestUpdated := `{
+		"project": {"id": 42},
+		"object_attributes": {"iid": 2, "action": "update", "updated_at": "2020-06-15T09:04:05Z"}
+	}`
+
+	for _, tc := range []struct {
+		name      string
+		id        int64
+		token     string
+		eventType string
+		body      string
+		want      int
+		enqueued  []api.RepoName
+	}{
+		{name: "missing id", token: "secret", eventType: "Merge Request Hook", body: mergeRequestUpdated, want: http.StatusBadRequest},
+		{name: "wrong id", id: extSvc.ID + 1, token: "secret", eventType: "Merge Request Hook", body: mergeRequestUpdated, want: http.StatusUnauthorized},
+		{name: "wrong token", id: extSvc.ID, token: "not-secret", eventType: "Merge Request Hook", body: mergeRequestUpdated, want: http.StatusUnauthorized},
+		{name: "unknown event", id: extSvc.ID, token: "secret", eventType: "Issue Hook", body: `{}`, want: http.StatusBadRequest},
+		{name: "untracked action", id: extSvc.ID, token: "secret", eventType: "Merge Request Hook", body: mergeRequestUpdated, want: http.StatusOK},
+		{name: "pipeline without merge request", id: extSvc.ID, token: "secret", eventType: "Pipeline Hook", body: `{"project": {"id": 42}, "object_attributes": {"id": 7}}`, want: http.StatusOK},
+		{name: "push", id: extSvc.ID, token: "secret", eventType: "Push Hook", body: `{"project": {"id": 42}}`, want: http.StatusOK, enqueued: []api.RepoName{"gitlab.com/group/project"}},
+		{name: "tag push", id: extSvc.ID, token: "secret", eventType: "Tag Push Hook", body: `{"project": {"id": 42}}`, want: http.StatusOK, enqueued: []api.RepoName{"gitlab.com/group/project"}},
+		{name: "push to unknown project", id: extSvc.ID, token: "secret", eventType: "Push Hook", body: `{"project": {"id": 43}}`, want: http.StatusOK},
+		{name: "push with wrong token", id: extSvc.ID, token: "not-secret", eventType: "Push Hook", body: `{"project": {"id": 42}}`, want: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			enqueued = nil
+
+			u := extsvc.WebhookURL(extsvc.KindGitLab, tc.id, "https://example.com")
+			if tc.id == 0 {
+				u = "https://example.com/.api/gitlab-webhooks"
+			}
+
+			req, err := http.NewRequest("POST", u, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("X-Gitlab-Event", tc.eventType)
+			req.Header.Set("X-Gitlab-Token", tc.token)
+
+			rec := httptest.NewRecorder()
+			hook.ServeHTTP(rec, req)
+
+			if have, want := rec.Code, tc.want; have != want {
+				t.Errorf("wrong status code: have %d, want %d (body: %q)", have, want, rec.Body.String())
+			}
+			if diff := cmp.Diff(tc.enqueued, enqueued); diff != "" {
+				t.Errorf("wrong enqueued repo updates: %s", diff)
+			}
+		})
+	}
+}
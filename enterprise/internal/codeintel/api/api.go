@@ -25,6 +25,14 @@ type CodeIntelAPI interface {
 	// This may include remote definitions if the remote repository is also indexed.
 	Definitions(ctx context.Context, file string, line, character, uploadID int) ([]ResolvedLocation, error)
 
+	// TypeDefinitions returns the list of source locations that define the type of the symbol at the
+	// given position. This may include remote type definitions if the remote repository is also indexed.
+	TypeDefinitions(ctx context.Context, file string, line, character, uploadID int) ([]ResolvedLocation, error)
+
+	// Implementations returns the list of source locations that implement the symbol at the given
+	// position. This may include implementations from other dumps and repositories.
+	Implementations(ctx context.Context, file string, line, character, uploadID int) ([]ResolvedLocation, error)
+
 	// References returns the list of source locations that reference the symbol at the given position.
 	// This may include references from other dumps and repositories.
 	References(ctx context.Context, repositoryID int, commit string, limit int, cursor Cursor) ([]ResolvedLocation, Cursor, bool, error)
@@ -227,6 +227,36 @@ func setMockBundleClientReferences(t *testing.T, mockBundleClient *bundlemocks.M
 	})
 }
 
+func setMockBundleClientTypeDefinitions(t *testing.T, mockBundleClient *bundlemocks.MockBundleClient, expectedPath string, expectedLine, expectedCharacter int, locations []bundles.Location) {
+	mockBundleClient.TypeDefinitionsFunc.SetDefaultHook(func(ctx context.Context, path string, line, character int) ([]bundles.Location, error) {
+		if path != expectedPath {
+			t.Errorf("unexpected path for TypeDefinitions. want=%s have=%s", expectedPath, path)
+		}
+		if line != expectedLine {
+			t.Errorf("unexpected line for TypeDefinitions. want=%d have=%d", expectedLine, line)
+		}
+		if character != expectedCharacter {
+			t.Errorf("unexpected character for TypeDefinitions. want=%d have=%d", expectedCharacter, character)
+		}
+		return locations, nil
+	})
+}
+
+func setMockBundleClientImplementations(t *testing.T, mockBundleClient *bundlemocks.MockBundleClient, expectedPath string, expectedLine, expectedCharacter int, locations []bundles.Location) {
+	mockBundleClient.ImplementationsFunc.SetDefaultHook(func(ctx context.Context, path string, line, character int) ([]bundles.Location, error) {
+		if path != expectedPath {
+			t.Errorf("unexpected path for Implementations. want=%s have=%s", expectedPath, path)
+		}
+		if line != expectedLine {
+			t.Errorf("unexpected line for Implementations. want=%d have=%d", expectedLine, line)
+		}
+		if character != expectedCharacter {
+			t.Errorf("unexpected character for Implementations. want=%d have=%d", expectedCharacter, character)
+		}
+		return locations, nil
+	})
+}
+
 func setMockBundleClientHover(t *testing.T, mockBundleClient *bundlemocks.MockBundleClient, expectedPath string, expectedLine, expectedCharacter int, text string, r bundles.Range, exists bool) {
 	mockBundleClient.HoverFunc.SetDefaultHook(func(ctx context.Context, path string, line, character int) (string, bundles.Range, bool, error) {
 		if path != expectedPath {
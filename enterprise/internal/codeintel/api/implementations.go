@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	bundles "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+)
+
+// ImplementationMonikersLimit is the maximum number of implementation moniker results we'll
+// ask for from a single bundle.
+const ImplementationMonikersLimit = 100
+
+// ImplementationRemoteDumpLimit is the maximum number of remote dumps that will be searched
+// for implementations of a symbol defined in another package.
+const ImplementationRemoteDumpLimit = 20
+
+// Implementations returns the list of source locations that implement the symbol at the given
+// position. This includes implementations encoded in the current dump, implementations stored
+// in the dump that defines the symbol, and implementations stored in indexed dumps of other
+// repositories that depend on the package providing the symbol.
+func (api *codeIntelAPI) Implementations(ctx context.Context, file string, line, character, uploadID int) ([]ResolvedLocation, error) {
+	dump, exists, err := api.store.GetDumpByID(ctx, uploadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store.GetDumpByID")
+	}
+	if !exists {
+		return nil, ErrMissingDump
+	}
+
+	pathInBundle := strings.TrimPrefix(file, dump.Root)
+	bundleClient := api.bundleManagerClient.BundleClient(dump.ID)
+
+	locations, err := bundleClient.Implementations(ctx, pathInBundle, line, character)
+	if err != nil {
+		if err == bundles.ErrNotFound {
+			log15.Warn("Bundle does not exist")
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "bundleClient.Implementations")
+	}
+
+	rangeMonikers, err := bundleClient.MonikersByPosition(ctx, pathInBundle, line, character)
+	if err != nil {
+		if err == bundles.ErrNotFound {
+			log15.Warn("Bundle does not exist")
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "bundleClient.MonikersByPosition")
+	}
+
+	hashes := map[string]struct{}{}
+	for _, location := range locations {
+		hashes[hashLocation(location)] = struct{}{}
+	}
+
+	// Search the implementations table of the current dump for implementations that
+	// are not fully linked in the graph-encoded portion of the bundle.
+	for _, monikers := range rangeMonikers {
+		for _, moniker := range monikers {
+			results, _, err := bundleClient.MonikerResults(ctx, "implementation", moniker.Scheme, moniker.Identifier, 0, ImplementationMonikersLimit)
+			if err != nil {
+				if err == bundles.ErrNotFound {
+					log15.Warn("Bundle does not exist")
+					return nil, nil
+				}
+				return nil, errors.Wrap(err, "bundleClient.MonikerResults")
+			}
+
+			for _, location := range results {
+				if _, ok := hashes[hashLocation(location)]; !ok {
+					hashes[hashLocation(location)] = struct{}{}
+					locations = append(locations, location)
+				}
+			}
+		}
+	}
+
+	resolvedLocations := resolveLocationsWithDump(dump, locations)
+
+	resolvedHashes := map[string]struct{}{}
+	for _, location := range resolvedLocations {
+		resolvedHashes[hashResolvedLocation(location)] = struct{}{}
+	}
+	addResolvedLocations := func(locations []ResolvedLocation) {
+		for _, location := range locations {
+			if _, ok := resolvedHashes[hashResolvedLocation(location)]; !ok {
+				resolvedHashes[hashResolvedLocation(location)] = struct{}{}
+				resolvedLocations = append(resolvedLocations, location)
+			}
+		}
+	}
+
+	// Symbols imported from another package may also be implemented in the dump that
+	// defines the package. Symbols of any package may also be implemented in other dumps
+	// of this repository or in dumps of other repositories that depend on the package.
+	// The same dump can be found in more than one way, so these results are deduplicated.
+	for _, monikers := range rangeMonikers {
+		for _, moniker := range monikers {
+			if moniker.PackageInformationID == "" {
+				continue
+			}
+
+			if moniker.Kind == "import" {
+				remoteLocations, _, err := lookupMoniker(api.store, api.bundleManagerClient, dump.ID, pathInBundle, "implementation", moniker, 0, ImplementationMonikersLimit)
+				if err != nil {
+					return nil, err
+				}
+				addResolvedLocations(remoteLocations)
+			}
+
+			remoteLocations, err := api.remoteImplementations(ctx, dump, bundleClient, pathInBundle, moniker)
+			if err != nil {
+				return nil, err
+			}
+			addResolvedLocations(remoteLocations)
+		}
+	}
+
+	return resolvedLocations, nil
+}
+
+// remoteImplementations returns the implementations of the given moniker that are stored in
+// other dumps of the same repository and in dumps of other repositories that reference the
+// package providing the moniker.
+func (api *codeIntelAPI) remoteImplementations(ctx context.Context, dump store.Dump, bundleClient bundles.BundleClient, pathInBundle string, moniker bundles.MonikerData) ([]ResolvedLocation, error) {
+	packageInformation, err := bundleClient.PackageInformation(ctx, pathInBundle, moniker.PackageInformationID)
+	if err != nil {
+		if err == bundles.ErrNotFound {
+			log15.Warn("Bundle does not exist")
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "bundleClient.PackageInformation")
+	}
+
+	totalCount, pager, err := api.store.SameRepoPager(ctx, dump.RepositoryID, dump.Commit, moniker.Scheme, packageInformation.Name, packageInformation.Version, ImplementationRemoteDumpLimit)
+	if err != nil {
+		return nil, errors.Wrap(err, "store.SameRepoPager")
+	}
+
+	seenDumpIDs := map[int]struct{}{dump.ID: {}}
+	sameRepoDumpIDs, err := referencingDumpIDs(ctx, pager, totalCount, moniker.Identifier, seenDumpIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	totalCount, pager, err = api.store.PackageReferencePager(ctx, moniker.Scheme, packageInformation.Name, packageInformation.Version, dump.RepositoryID, ImplementationRemoteDumpLimit)
+	if err != nil {
+		return nil, errors.Wrap(err, "store.PackageReferencePager")
+	}
+
+	remoteRepoDumpIDs, err := referencingDumpIDs(ctx, pager, totalCount, moniker.Identifier, seenDumpIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	dumpIDs := append(sameRepoDumpIDs, remoteRepoDumpIDs...)
+
+	var resolvedLocations []ResolvedLocation
+	for _, dumpID := range dumpIDs {
+		remoteDump, exists, err := api.store.GetDumpByID(ctx, dumpID)
+		if err != nil {
+			return nil, errors.Wrap(err, "store.GetDumpByID")
+		}
+		if !exists {
+			continue
+		}
+
+		locations, _, err := api.bundleManagerClient.BundleClient(dumpID).MonikerResults(ctx, "implementation", moniker.Scheme, moniker.Identifier, 0, ImplementationMonikersLimit)
+		if err != nil {
+			if err == bundles.ErrNotFound {
+				log15.Warn("Bundle does not exist")
+				continue
+			}
+			return nil, errors.Wrap(err, "bundleClient.MonikerResults")
+		}
+
+		resolvedLocations = append(resolvedLocations, resolveLocationsWithDump(remoteDump, locations)...)
+	}
+
+	return resolvedLocations, nil
+}
+
+// referencingDumpIDs returns the identifiers of at most ImplementationRemoteDumpLimit dumps read
+// from the given pager whose bloom filter may contain the given identifier. Dumps in the given
+// set are skipped, and the returned dumps are added to it. The pager is closed on return.
+func referencingDumpIDs(ctx context.Context, pager store.ReferencePager, totalCount int, identifier string, seenDumpIDs map[int]struct{}) ([]int, error) {
+	var dumpIDs []int
+	for offset := 0; len(dumpIDs) < ImplementationRemoteDumpLimit && offset < totalCount; {
+		page, err := pager.PageFromOffset(ctx, offset)
+		if err != nil {
+			return nil, pager.Done(err)
+		}
+		if len(page) == 0 {
+			break
+		}
+
+		filtered, scanned := applyBloomFilter(page, identifier, ImplementationRemoteDumpLimit-len(dumpIDs))
+		for _, ref := range filtered {
+			if _, ok := seenDumpIDs[ref.DumpID]; ok {
+				continue
+			}
+			seenDumpIDs[ref.DumpID] = struct{}{}
+			dumpIDs = append(dumpIDs, ref.DumpID)
+		}
+		offset += scanned
+	}
+
+	if err := pager.Done(nil); err != nil {
+		return nil, err
+	}
+
+	return dumpIDs, nil
+}
+
+// hashResolvedLocation returns a key identifying the given location within its dump.
+func hashResolvedLocation(location ResolvedLocation) string {
+	return fmt.Sprintf("%d:%s", location.Dump.ID, hashLocation(bundles.Location{
+		DumpID: location.Dump.ID,
+		Path:   location.Path,
+		Range:  location.Range,
+	}))
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	bundles "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client"
+	bundlemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client/mocks"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/types"
+	gitservermocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver/mocks"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+	storemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store/mocks"
+)
+
+func TestImplementations(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockBundleClient := bundlemocks.NewMockBundleClient()
+	mockGitserverClient := gitservermocks.NewMockClient()
+
+	setMockStoreGetDumpByID(t, mockStore, map[int]store.Dump{42: testDump1})
+	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{42: mockBundleClient})
+	setMockBundleClientImplementations(t, mockBundleClient, "main.go", 10, 50, []bundles.Location{
+		{DumpID: 42, Path: "foo.go", Range: testRange1},
+		{DumpID: 42, Path: "bar.go", Range: testRange2},
+	})
+	setMockBundleClientMonikersByPosition(t, mockBundleClient, "main.go", 10, 50, [][]bundles.MonikerData{{testMoniker3}})
+	setMockBundleClientMonikerResults(t, mockBundleClient, "implementation", "gomod", "pad", 0, 100, []bundles.Location{
+		{DumpID: 42, Path: "bar.go", Range: testRange2},
+		{DumpID: 42, Path: "baz.go", Range: testRange3},
+	}, 2)
+
+	api := testAPI(mockStore, mockBundleManagerClient, mockGitserverClient)
+	implementations, err := api.Implementations(context.Background(), "sub1/main.go", 10, 50, 42)
+	if err != nil {
+		t.Fatalf("expected error getting implementations: %s", err)
+	}
+
+	expectedImplementations := []ResolvedLocation{
+		{Dump: testDump1, Path: "sub1/foo.go", Range: testRange1},
+		{Dump: testDump1, Path: "sub1/bar.go", Range: testRange2},
+		{Dump: testDump1, Path: "sub1/baz.go", Range: testRange3},
+	}
+	if diff := cmp.Diff(expectedImplementations, implementations); diff != "" {
+		t.Errorf("unexpected implementations (-want +got):\n%s", diff)
+	}
+}
+
+func TestImplementationsUnknownDump(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockGitserverClient := gitservermocks.NewMockClient()
+	setMockStoreGetDumpByID(t, mockStore, nil)
+
+	api := testAPI(mockStore, mockBundleManagerClient, mockGitserverClient)
+	if _, err := api.Implementations(context.Background(), "sub1/main.go", 10, 50, 25); err != ErrMissingDump {
+		t.Fatalf("unexpected error getting implementations. want=%q have=%q", ErrMissingDump, err)
+	}
+}
+
+func TestImplementationsViaRemoteRepo(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockBundleClient1 := bundlemocks.NewMockBundleClient()
+	mockBundleClient2 := bundlemocks.NewMockBundleClient()
+	mockBundleClient3 := bundlemocks.NewMockBundleClient()
+	mockGitserverClient := gitservermocks.NewMockClient()
+	mockSameRepoPager := storemocks.NewMockReferencePager()
+	mockReferencePager := storemocks.NewMockReferencePager()
+
+	moniker := bundles.MonikerData{Kind: "import", Scheme: "gomod", Identifier: "bar", PackageInformationID: "1234"}
+
+	setMockStoreGetDumpByID(t, mockStore, map[int]store.Dump{42: testDump1, 50: testDump2, 51: testDump3})
+	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{42: mockBundleClient1, 50: mockBundleClient2, 51: mockBundleClient3})
+	setMockBundleClientImplementations(t, mockBundleClient1, "main.go", 10, 50, []bundles.Location{
+		{DumpID: 42, Path: "foo.go", Range: testRange1},
+	})
+	setMockBundleClientMonikersByPosition(t, mockBundleClient1, "main.go", 10, 50, [][]bundles.MonikerData{{moniker}})
+	setMockBundleClientMonikerResults(t, mockBundleClient1, "implementation", "gomod", "bar", 0, 100, nil, 0)
+	setMockBundleClientPackageInformation(t, mockBundleClient1, "main.go", "1234", testPackageInformation)
+	setMockStoreGetPackage(t, mockStore, "gomod", "leftpad", "0.1.0", testDump2, true)
+	setMockStoreSameRepoPager(t, mockStore, 0, "", "gomod", "leftpad", "0.1.0", 20, 0, mockSameRepoPager)
+	setMockStorePackageReferencePager(t, mockStore, "gomod", "leftpad", "0.1.0", 0, 20, 2, mockReferencePager)
+	setMockReferencePagerPageFromOffset(t, mockReferencePager, 0, []types.PackageReference{
+		{DumpID: 50, Filter: readTestFilter(t, "normal", "1")},
+		{DumpID: 51, Filter: readTestFilter(t, "normal", "1")},
+	})
+	setMockBundleClientMonikerResults(t, mockBundleClient2, "implementation", "gomod", "bar", 0, 100, []bundles.Location{
+		{DumpID: 50, Path: "bar.go", Range: testRange2},
+		{DumpID: 50, Path: "baz.go", Range: testRange3},
+	}, 2)
+	setMockBundleClientMonikerResults(t, mockBundleClient3, "implementation", "gomod", "bar", 0, 100, []bundles.Location{
+		{DumpID: 51, Path: "bar.go", Range: testRange2},
+	}, 1)
+
+	api := testAPI(mockStore, mockBundleManagerClient, mockGitserverClient)
+	implementations, err := api.Implementations(context.Background(), "sub1/main.go", 10, 50, 42)
+	if err != nil {
+		t.Fatalf("expected error getting implementations: %s", err)
+	}
+
+	// The implementations of the dump defining the package are not repeated
+	// when that dump also references the package.
+	expectedImplementations := []ResolvedLocation{
+		{Dump: testDump1, Path: "sub1/foo.go", Range: testRange1},
+		{Dump: testDump2, Path: "sub2/bar.go", Range: testRange2},
+		{Dump: testDump2, Path: "sub2/baz.go", Range: testRange3},
+		{Dump: testDump3, Path: "sub3/bar.go", Range: testRange2},
+	}
+	if diff := cmp.Diff(expectedImplementations, implementations); diff != "" {
+		t.Errorf("unexpected implementations (-want +got):\n%s", diff)
+	}
+}
+
+func TestImplementationsExportMoniker(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockBundleClient1 := bundlemocks.NewMockBundleClient()
+	mockBundleClient2 := bundlemocks.NewMockBundleClient()
+	mockBundleClient3 := bundlemocks.NewMockBundleClient()
+	mockGitserverClient := gitservermocks.NewMockClient()
+	mockSameRepoPager := storemocks.NewMockReferencePager()
+	mockReferencePager := storemocks.NewMockReferencePager()
+
+	moniker := bundles.MonikerData{Kind: "export", Scheme: "gomod", Identifier: "bar", PackageInformationID: "1234"}
+
+	setMockStoreGetDumpByID(t, mockStore, map[int]store.Dump{42: testDump1, 50: testDump2, 51: testDump3})
+	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{42: mockBundleClient1, 50: mockBundleClient2, 51: mockBundleClient3})
+	setMockBundleClientImplementations(t, mockBundleClient1, "main.go", 10, 50, []bundles.Location{
+		{DumpID: 42, Path: "foo.go", Range: testRange1},
+	})
+	setMockBundleClientMonikersByPosition(t, mockBundleClient1, "main.go", 10, 50, [][]bundles.MonikerData{{moniker}})
+	setMockBundleClientMonikerResults(t, mockBundleClient1, "implementation", "gomod", "bar", 0, 100, nil, 0)
+	setMockBundleClientPackageInformation(t, mockBundleClient1, "main.go", "1234", testPackageInformation)
+	setMockStoreSameRepoPager(t, mockStore, 0, "", "gomod", "leftpad", "0.1.0", 20, 2, mockSameRepoPager)
+	setMockReferencePagerPageFromOffset(t, mockSameRepoPager, 0, []types.PackageReference{
+		{DumpID: 42, Filter: readTestFilter(t, "normal", "1")},
+		{DumpID: 50, Filter: readTestFilter(t, "normal", "1")},
+	})
+	setMockStorePackageReferencePager(t, mockStore, "gomod", "leftpad", "0.1.0", 0, 20, 2, mockReferencePager)
+	setMockReferencePagerPageFromOffset(t, mockReferencePager, 0, []types.PackageReference{
+		{DumpID: 50, Filter: readTestFilter(t, "normal", "1")},
+		{DumpID: 51, Filter: readTestFilter(t, "normal", "1")},
+	})
+	setMockBundleClientMonikerResults(t, mockBundleClient2, "implementation", "gomod", "bar", 0, 100, []bundles.Location{
+		{DumpID: 50, Path: "bar.go", Range: testRange2},
+	}, 1)
+	setMockBundleClientMonikerResults(t, mockBundleClient3, "implementation", "gomod", "bar", 0, 100, []bundles.Location{
+		{DumpID: 51, Path: "baz.go", Range: testRange3},
+	}, 1)
+
+	api := testAPI(mockStore, mockBundleManagerClient, mockGitserverClient)
+	implementations, err := api.Implementations(context.Background(), "sub1/main.go", 10, 50, 42)
+	if err != nil {
+		t.Fatalf("expected error getting implementations: %s", err)
+	}
+
+	expectedImplementations := []ResolvedLocation{
+		{Dump: testDump1, Path: "sub1/foo.go", Range: testRange1},
+		{Dump: testDump2, Path: "sub2/bar.go", Range: testRange2},
+		{Dump: testDump3, Path: "sub3/baz.go", Range: testRange3},
+	}
+	if diff := cmp.Diff(expectedImplementations, implementations); diff != "" {
+		t.Errorf("unexpected implementations (-want +got):\n%s", diff)
+	}
+	if len(mockStore.GetPackageFunc.History()) != 0 {
+		t.Errorf("unexpected number of calls to GetPackage. want=%d have=%d", 0, len(mockStore.GetPackageFunc.History()))
+	}
+	if len(mockBundleClient2.MonikerResultsFunc.History()) != 1 {
+		t.Errorf("unexpected number of calls to MonikerResults. want=%d have=%d", 1, len(mockBundleClient2.MonikerResultsFunc.History()))
+	}
+}
@@ -27,12 +27,18 @@ type MockCodeIntelAPI struct {
 	// HoverFunc is an instance of a mock function object controlling the
 	// behavior of the method Hover.
 	HoverFunc *CodeIntelAPIHoverFunc
+	// ImplementationsFunc is an instance of a mock function object
+	// controlling the behavior of the method Implementations.
+	ImplementationsFunc *CodeIntelAPIImplementationsFunc
 	// RangesFunc is an instance of a mock function object controlling the
 	// behavior of the method Ranges.
 	RangesFunc *CodeIntelAPIRangesFunc
 	// ReferencesFunc is an instance of a mock function object controlling
 	// the behavior of the method References.
 	ReferencesFunc *CodeIntelAPIReferencesFunc
+	// TypeDefinitionsFunc is an instance of a mock function object
+	// controlling the behavior of the method TypeDefinitions.
+	TypeDefinitionsFunc *CodeIntelAPITypeDefinitionsFunc
 }
 
 // NewMockCodeIntelAPI creates a new mock of the CodeIntelAPI interface. All
@@ -59,6 +65,11 @@ func NewMockCodeIntelAPI() *MockCodeIntelAPI {
 				return "", client.Range{}, false, nil
 			},
 		},
+		ImplementationsFunc: &CodeIntelAPIImplementationsFunc{
+			defaultHook: func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error) {
+				return nil, nil
+			},
+		},
 		RangesFunc: &CodeIntelAPIRangesFunc{
 			defaultHook: func(context.Context, string, int, int, int) ([]api.ResolvedCodeIntelligenceRange, error) {
 				return nil, nil
@@ -69,6 +80,11 @@ func NewMockCodeIntelAPI() *MockCodeIntelAPI {
 				return nil, api.Cursor{}, false, nil
 			},
 		},
+		TypeDefinitionsFunc: &CodeIntelAPITypeDefinitionsFunc{
+			defaultHook: func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -89,12 +105,18 @@ func NewMockCodeIntelAPIFrom(i api.CodeIntelAPI) *MockCodeIntelAPI {
 		HoverFunc: &CodeIntelAPIHoverFunc{
 			defaultHook: i.Hover,
 		},
+		ImplementationsFunc: &CodeIntelAPIImplementationsFunc{
+			defaultHook: i.Implementations,
+		},
 		RangesFunc: &CodeIntelAPIRangesFunc{
 			defaultHook: i.Ranges,
 		},
 		ReferencesFunc: &CodeIntelAPIReferencesFunc{
 			defaultHook: i.References,
 		},
+		TypeDefinitionsFunc: &CodeIntelAPITypeDefinitionsFunc{
+			defaultHook: i.TypeDefinitions,
+		},
 	}
 }
 
@@ -582,6 +604,125 @@ func (c CodeIntelAPIHoverFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
 }
 
+// CodeIntelAPIImplementationsFunc describes the behavior when the
+// Implementations method of the parent MockCodeIntelAPI instance is
+// invoked.
+type CodeIntelAPIImplementationsFunc struct {
+	defaultHook func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error)
+	hooks       []func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error)
+	history     []CodeIntelAPIImplementationsFuncCall
+	mutex       sync.Mutex
+}
+
+// Implementations delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeIntelAPI) Implementations(v0 context.Context, v1 string, v2 int, v3 int, v4 int) ([]api.ResolvedLocation, error) {
+	r0, r1 := m.ImplementationsFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.ImplementationsFunc.appendCall(CodeIntelAPIImplementationsFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Implementations
+// method of the parent MockCodeIntelAPI instance is invoked and the hook
+// queue is empty.
+func (f *CodeIntelAPIImplementationsFunc) SetDefaultHook(hook func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Implementations method of the parent MockCodeIntelAPI instance inovkes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeIntelAPIImplementationsFunc) PushHook(hook func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *CodeIntelAPIImplementationsFunc) SetDefaultReturn(r0 []api.ResolvedLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *CodeIntelAPIImplementationsFunc) PushReturn(r0 []api.ResolvedLocation, r1 error) {
+	f.PushHook(func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeIntelAPIImplementationsFunc) nextHook() func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeIntelAPIImplementationsFunc) appendCall(r0 CodeIntelAPIImplementationsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeIntelAPIImplementationsFuncCall objects
+// describing the invocations of this function.
+func (f *CodeIntelAPIImplementationsFunc) History() []CodeIntelAPIImplementationsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeIntelAPIImplementationsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeIntelAPIImplementationsFuncCall is an object that describes an
+// invocation of method Implementations on an instance of MockCodeIntelAPI.
+type CodeIntelAPIImplementationsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []api.ResolvedLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeIntelAPIImplementationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeIntelAPIImplementationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // CodeIntelAPIRangesFunc describes the behavior when the Ranges method of
 // the parent MockCodeIntelAPI instance is invoked.
 type CodeIntelAPIRangesFunc struct {
@@ -822,3 +963,122 @@ func (c CodeIntelAPIReferencesFuncCall) Args() []interface{} {
 func (c CodeIntelAPIReferencesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
 }
+
+// CodeIntelAPITypeDefinitionsFunc describes the behavior when the
+// TypeDefinitions method of the parent MockCodeIntelAPI instance is
+// invoked.
+type CodeIntelAPITypeDefinitionsFunc struct {
+	defaultHook func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error)
+	hooks       []func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error)
+	history     []CodeIntelAPITypeDefinitionsFuncCall
+	mutex       sync.Mutex
+}
+
+// TypeDefinitions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockCodeIntelAPI) TypeDefinitions(v0 context.Context, v1 string, v2 int, v3 int, v4 int) ([]api.ResolvedLocation, error) {
+	r0, r1 := m.TypeDefinitionsFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.TypeDefinitionsFunc.appendCall(CodeIntelAPITypeDefinitionsFuncCall{v0, v1, v2, v3, v4, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the TypeDefinitions
+// method of the parent MockCodeIntelAPI instance is invoked and the hook
+// queue is empty.
+func (f *CodeIntelAPITypeDefinitionsFunc) SetDefaultHook(hook func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// TypeDefinitions method of the parent MockCodeIntelAPI instance inovkes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *CodeIntelAPITypeDefinitionsFunc) PushHook(hook func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *CodeIntelAPITypeDefinitionsFunc) SetDefaultReturn(r0 []api.ResolvedLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *CodeIntelAPITypeDefinitionsFunc) PushReturn(r0 []api.ResolvedLocation, r1 error) {
+	f.PushHook(func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *CodeIntelAPITypeDefinitionsFunc) nextHook() func(context.Context, string, int, int, int) ([]api.ResolvedLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *CodeIntelAPITypeDefinitionsFunc) appendCall(r0 CodeIntelAPITypeDefinitionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of CodeIntelAPITypeDefinitionsFuncCall objects
+// describing the invocations of this function.
+func (f *CodeIntelAPITypeDefinitionsFunc) History() []CodeIntelAPITypeDefinitionsFuncCall {
+	f.mutex.Lock()
+	history := make([]CodeIntelAPITypeDefinitionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// CodeIntelAPITypeDefinitionsFuncCall is an object that describes an
+// invocation of method TypeDefinitions on an instance of MockCodeIntelAPI.
+type CodeIntelAPITypeDefinitionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []api.ResolvedLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c CodeIntelAPITypeDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c CodeIntelAPITypeDefinitionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
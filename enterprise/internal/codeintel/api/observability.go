@@ -15,6 +15,8 @@ type ObservedCodeIntelAPI struct {
 	findClosestDumpsOperation *observation.Operation
 	rangesOperation           *observation.Operation
 	definitionsOperation      *observation.Operation
+	typeDefinitionsOperation  *observation.Operation
+	implementationsOperation  *observation.Operation
 	referencesOperation       *observation.Operation
 	hoverOperation            *observation.Operation
 	diagnosticsOperation      *observation.Operation
@@ -48,6 +50,16 @@ func NewObserved(codeIntelAPI CodeIntelAPI, observationContext *observation.Cont
 			MetricLabels: []string{"definitions"},
 			Metrics:      metrics,
 		}),
+		typeDefinitionsOperation: observationContext.Operation(observation.Op{
+			Name:         "CodeIntelAPI.TypeDefinitions",
+			MetricLabels: []string{"type_definitions"},
+			Metrics:      metrics,
+		}),
+		implementationsOperation: observationContext.Operation(observation.Op{
+			Name:         "CodeIntelAPI.Implementations",
+			MetricLabels: []string{"implementations"},
+			Metrics:      metrics,
+		}),
 		referencesOperation: observationContext.Operation(observation.Op{
 			Name:         "CodeIntelAPI.References",
 			MetricLabels: []string{"references"},
@@ -87,6 +99,20 @@ func (api *ObservedCodeIntelAPI) Definitions(ctx context.Context, file string, l
 	return api.codeIntelAPI.Definitions(ctx, file, line, character, uploadID)
 }
 
+// TypeDefinitions calls into the inner CodeIntelAPI and registers the observed results.
+func (api *ObservedCodeIntelAPI) TypeDefinitions(ctx context.Context, file string, line, character, uploadID int) (typeDefinitions []ResolvedLocation, err error) {
+	ctx, endObservation := api.typeDefinitionsOperation.With(ctx, &err, observation.Args{})
+	defer func() { endObservation(float64(len(typeDefinitions)), observation.Args{}) }()
+	return api.codeIntelAPI.TypeDefinitions(ctx, file, line, character, uploadID)
+}
+
+// Implementations calls into the inner CodeIntelAPI and registers the observed results.
+func (api *ObservedCodeIntelAPI) Implementations(ctx context.Context, file string, line, character, uploadID int) (implementations []ResolvedLocation, err error) {
+	ctx, endObservation := api.implementationsOperation.With(ctx, &err, observation.Args{})
+	defer func() { endObservation(float64(len(implementations)), observation.Args{}) }()
+	return api.codeIntelAPI.Implementations(ctx, file, line, character, uploadID)
+}
+
 // References calls into the inner CodeIntelAPI and registers the observed results.
 func (api *ObservedCodeIntelAPI) References(ctx context.Context, repositoryID int, commit string, limit int, cursor Cursor) (references []ResolvedLocation, _ Cursor, _ bool, err error) {
 	ctx, endObservation := api.referencesOperation.With(ctx, &err, observation.Args{})
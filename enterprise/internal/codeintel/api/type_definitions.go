@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	bundles "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client"
+)
+
+// TypeDefinitionMonikersLimit is the maximum number of type definition moniker results we'll
+// ask for from a single bundle.
+const TypeDefinitionMonikersLimit = 100
+
+// TypeDefinitions returns the list of source locations that define the type of the symbol at the
+// given position. This may include remote type definitions if the remote repository is also indexed.
+func (api *codeIntelAPI) TypeDefinitions(ctx context.Context, file string, line, character, uploadID int) ([]ResolvedLocation, error) {
+	dump, exists, err := api.store.GetDumpByID(ctx, uploadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "store.GetDumpByID")
+	}
+	if !exists {
+		return nil, ErrMissingDump
+	}
+
+	pathInBundle := strings.TrimPrefix(file, dump.Root)
+	bundleClient := api.bundleManagerClient.BundleClient(dump.ID)
+
+	locations, err := bundleClient.TypeDefinitions(ctx, pathInBundle, line, character)
+	if err != nil {
+		if err == bundles.ErrNotFound {
+			log15.Warn("Bundle does not exist")
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "bundleClient.TypeDefinitions")
+	}
+	if len(locations) > 0 {
+		return resolveLocationsWithDump(dump, locations), nil
+	}
+
+	rangeMonikers, err := bundleClient.MonikersByPosition(ctx, pathInBundle, line, character)
+	if err != nil {
+		if err == bundles.ErrNotFound {
+			log15.Warn("Bundle does not exist")
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "bundleClient.MonikersByPosition")
+	}
+
+	for _, monikers := range rangeMonikers {
+		for _, moniker := range monikers {
+			if moniker.Kind == "import" {
+				locations, _, err := lookupMoniker(api.store, api.bundleManagerClient, dump.ID, pathInBundle, "typeDefinition", moniker, 0, TypeDefinitionMonikersLimit)
+				if err != nil {
+					return nil, err
+				}
+				if len(locations) > 0 {
+					return locations, nil
+				}
+			} else {
+				// This symbol was not imported from another bundle. Search the type definitions
+				// of our own bundle in case the type definition result was not attached directly
+				// to the range but the correct monikers were.
+
+				locations, _, err := bundleClient.MonikerResults(ctx, "typeDefinition", moniker.Scheme, moniker.Identifier, 0, TypeDefinitionMonikersLimit)
+				if err != nil {
+					if err == bundles.ErrNotFound {
+						log15.Warn("Bundle does not exist")
+						return nil, nil
+					}
+					return nil, errors.Wrap(err, "bundleClient.MonikerResults")
+				}
+				if len(locations) > 0 {
+					return resolveLocationsWithDump(dump, locations), nil
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
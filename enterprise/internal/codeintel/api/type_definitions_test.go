@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	bundles "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client"
+	bundlemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/client/mocks"
+	gitservermocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/gitserver/mocks"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+	storemocks "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store/mocks"
+)
+
+func TestTypeDefinitions(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockBundleClient := bundlemocks.NewMockBundleClient()
+	mockGitserverClient := gitservermocks.NewMockClient()
+
+	setMockStoreGetDumpByID(t, mockStore, map[int]store.Dump{42: testDump1})
+	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{42: mockBundleClient})
+	setMockBundleClientTypeDefinitions(t, mockBundleClient, "main.go", 10, 50, []bundles.Location{
+		{DumpID: 42, Path: "foo.go", Range: testRange1},
+		{DumpID: 42, Path: "bar.go", Range: testRange2},
+	})
+
+	api := testAPI(mockStore, mockBundleManagerClient, mockGitserverClient)
+	typeDefinitions, err := api.TypeDefinitions(context.Background(), "sub1/main.go", 10, 50, 42)
+	if err != nil {
+		t.Fatalf("expected error getting type definitions: %s", err)
+	}
+
+	expectedTypeDefinitions := []ResolvedLocation{
+		{Dump: testDump1, Path: "sub1/foo.go", Range: testRange1},
+		{Dump: testDump1, Path: "sub1/bar.go", Range: testRange2},
+	}
+	if diff := cmp.Diff(expectedTypeDefinitions, typeDefinitions); diff != "" {
+		t.Errorf("unexpected type definitions (-want +got):\n%s", diff)
+	}
+}
+
+func TestTypeDefinitionsUnknownDump(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockGitserverClient := gitservermocks.NewMockClient()
+	setMockStoreGetDumpByID(t, mockStore, nil)
+
+	api := testAPI(mockStore, mockBundleManagerClient, mockGitserverClient)
+	if _, err := api.TypeDefinitions(context.Background(), "sub1/main.go", 10, 50, 25); err != ErrMissingDump {
+		t.Fatalf("unexpected error getting type definitions. want=%q have=%q", ErrMissingDump, err)
+	}
+}
+
+func TestTypeDefinitionViaRemoteDumpMoniker(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockBundleClient1 := bundlemocks.NewMockBundleClient()
+	mockBundleClient2 := bundlemocks.NewMockBundleClient()
+	mockGitserverClient := gitservermocks.NewMockClient()
+
+	setMockStoreGetDumpByID(t, mockStore, map[int]store.Dump{42: testDump1, 50: testDump2})
+	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{42: mockBundleClient1, 50: mockBundleClient2})
+	setMockBundleClientTypeDefinitions(t, mockBundleClient1, "main.go", 10, 50, nil)
+	setMockBundleClientMonikersByPosition(t, mockBundleClient1, "main.go", 10, 50, [][]bundles.MonikerData{{testMoniker1}})
+	setMockBundleClientPackageInformation(t, mockBundleClient1, "main.go", "1234", testPackageInformation)
+	setMockStoreGetPackage(t, mockStore, "gomod", "leftpad", "0.1.0", testDump2, true)
+	setMockBundleClientMonikerResults(t, mockBundleClient2, "typeDefinition", "gomod", "pad", 0, 100, []bundles.Location{
+		{DumpID: 50, Path: "foo.go", Range: testRange1},
+	}, 1)
+
+	api := testAPI(mockStore, mockBundleManagerClient, mockGitserverClient)
+	typeDefinitions, err := api.TypeDefinitions(context.Background(), "sub1/main.go", 10, 50, 42)
+	if err != nil {
+		t.Fatalf("expected error getting type definitions: %s", err)
+	}
+
+	expectedTypeDefinitions := []ResolvedLocation{
+		{Dump: testDump2, Path: "sub2/foo.go", Range: testRange1},
+	}
+	if diff := cmp.Diff(expectedTypeDefinitions, typeDefinitions); diff != "" {
+		t.Errorf("unexpected type definitions (-want +got):\n%s", diff)
+	}
+}
@@ -22,6 +22,12 @@ type BundleClient interface {
 	// Definitions retrieves a list of reference locations for the symbol under the given location.
 	References(ctx context.Context, path string, line, character int) ([]Location, error)
 
+	// TypeDefinitions retrieves a list of locations defining the type of the symbol under the given location.
+	TypeDefinitions(ctx context.Context, path string, line, character int) ([]Location, error)
+
+	// Implementations retrieves a list of implementation locations for the symbol under the given location.
+	Implementations(ctx context.Context, path string, line, character int) ([]Location, error)
+
 	// Hover retrieves the hover text for the symbol under the given location.
 	Hover(ctx context.Context, path string, line, character int) (string, Range, bool, error)
 
@@ -96,6 +102,32 @@ func (c *bundleClientImpl) References(ctx context.Context, path string, line, ch
 	return locations, err
 }
 
+// TypeDefinitions retrieves a list of locations defining the type of the symbol under the given location.
+func (c *bundleClientImpl) TypeDefinitions(ctx context.Context, path string, line, character int) (locations []Location, err error) {
+	args := map[string]interface{}{
+		"path":      path,
+		"line":      line,
+		"character": character,
+	}
+
+	err = c.request(ctx, "typeDefinitions", args, &locations)
+	c.addBundleIDToLocations(locations)
+	return locations, err
+}
+
+// Implementations retrieves a list of implementation locations for the symbol under the given location.
+func (c *bundleClientImpl) Implementations(ctx context.Context, path string, line, character int) (locations []Location, err error) {
+	args := map[string]interface{}{
+		"path":      path,
+		"line":      line,
+		"character": character,
+	}
+
+	err = c.request(ctx, "implementations", args, &locations)
+	c.addBundleIDToLocations(locations)
+	return locations, err
+}
+
 // Hover retrieves the hover text for the symbol under the given location.
 func (c *bundleClientImpl) Hover(ctx context.Context, path string, line, character int) (string, Range, bool, error) {
 	args := map[string]interface{}{
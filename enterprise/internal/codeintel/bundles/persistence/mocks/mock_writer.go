@@ -23,6 +23,9 @@ type MockWriter struct {
 	// WriteDocumentsFunc is an instance of a mock function object
 	// controlling the behavior of the method WriteDocuments.
 	WriteDocumentsFunc *WriterWriteDocumentsFunc
+	// WriteImplementationsFunc is an instance of a mock function object
+	// controlling the behavior of the method WriteImplementations.
+	WriteImplementationsFunc *WriterWriteImplementationsFunc
 	// WriteMetaFunc is an instance of a mock function object controlling
 	// the behavior of the method WriteMeta.
 	WriteMetaFunc *WriterWriteMetaFunc
@@ -32,6 +35,9 @@ type MockWriter struct {
 	// WriteResultChunksFunc is an instance of a mock function object
 	// controlling the behavior of the method WriteResultChunks.
 	WriteResultChunksFunc *WriterWriteResultChunksFunc
+	// WriteTypeDefinitionsFunc is an instance of a mock function object
+	// controlling the behavior of the method WriteTypeDefinitions.
+	WriteTypeDefinitionsFunc *WriterWriteTypeDefinitionsFunc
 }
 
 // NewMockWriter creates a new mock of the Writer interface. All methods
@@ -53,6 +59,11 @@ func NewMockWriter() *MockWriter {
 				return nil
 			},
 		},
+		WriteImplementationsFunc: &WriterWriteImplementationsFunc{
+			defaultHook: func(context.Context, []types.MonikerLocations) error {
+				return nil
+			},
+		},
 		WriteMetaFunc: &WriterWriteMetaFunc{
 			defaultHook: func(context.Context, types.MetaData) error {
 				return nil
@@ -68,6 +79,11 @@ func NewMockWriter() *MockWriter {
 				return nil
 			},
 		},
+		WriteTypeDefinitionsFunc: &WriterWriteTypeDefinitionsFunc{
+			defaultHook: func(context.Context, []types.MonikerLocations) error {
+				return nil
+			},
+		},
 	}
 }
 
@@ -84,6 +100,9 @@ func NewMockWriterFrom(i persistence.Writer) *MockWriter {
 		WriteDocumentsFunc: &WriterWriteDocumentsFunc{
 			defaultHook: i.WriteDocuments,
 		},
+		WriteImplementationsFunc: &WriterWriteImplementationsFunc{
+			defaultHook: i.WriteImplementations,
+		},
 		WriteMetaFunc: &WriterWriteMetaFunc{
 			defaultHook: i.WriteMeta,
 		},
@@ -93,6 +112,9 @@ func NewMockWriterFrom(i persistence.Writer) *MockWriter {
 		WriteResultChunksFunc: &WriterWriteResultChunksFunc{
 			defaultHook: i.WriteResultChunks,
 		},
+		WriteTypeDefinitionsFunc: &WriterWriteTypeDefinitionsFunc{
+			defaultHook: i.WriteTypeDefinitions,
+		},
 	}
 }
 
@@ -410,6 +432,112 @@ func (c WriterWriteDocumentsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// WriterWriteImplementationsFunc describes the behavior when the
+// WriteImplementations method of the parent MockWriter instance is invoked.
+type WriterWriteImplementationsFunc struct {
+	defaultHook func(context.Context, []types.MonikerLocations) error
+	hooks       []func(context.Context, []types.MonikerLocations) error
+	history     []WriterWriteImplementationsFuncCall
+	mutex       sync.Mutex
+}
+
+// WriteImplementations delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockWriter) WriteImplementations(v0 context.Context, v1 []types.MonikerLocations) error {
+	r0 := m.WriteImplementationsFunc.nextHook()(v0, v1)
+	m.WriteImplementationsFunc.appendCall(WriterWriteImplementationsFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the WriteImplementations
+// method of the parent MockWriter instance is invoked and the hook queue is
+// empty.
+func (f *WriterWriteImplementationsFunc) SetDefaultHook(hook func(context.Context, []types.MonikerLocations) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// WriteImplementations method of the parent MockWriter instance inovkes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *WriterWriteImplementationsFunc) PushHook(hook func(context.Context, []types.MonikerLocations) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WriterWriteImplementationsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, []types.MonikerLocations) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WriterWriteImplementationsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, []types.MonikerLocations) error {
+		return r0
+	})
+}
+
+func (f *WriterWriteImplementationsFunc) nextHook() func(context.Context, []types.MonikerLocations) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WriterWriteImplementationsFunc) appendCall(r0 WriterWriteImplementationsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WriterWriteImplementationsFuncCall objects
+// describing the invocations of this function.
+func (f *WriterWriteImplementationsFunc) History() []WriterWriteImplementationsFuncCall {
+	f.mutex.Lock()
+	history := make([]WriterWriteImplementationsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WriterWriteImplementationsFuncCall is an object that describes an
+// invocation of method WriteImplementations on an instance of MockWriter.
+type WriterWriteImplementationsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []types.MonikerLocations
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WriterWriteImplementationsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WriterWriteImplementationsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // WriterWriteMetaFunc describes the behavior when the WriteMeta method of
 // the parent MockWriter instance is invoked.
 type WriterWriteMetaFunc struct {
@@ -726,3 +854,109 @@ func (c WriterWriteResultChunksFuncCall) Args() []interface{} {
 func (c WriterWriteResultChunksFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
+
+// WriterWriteTypeDefinitionsFunc describes the behavior when the
+// WriteTypeDefinitions method of the parent MockWriter instance is invoked.
+type WriterWriteTypeDefinitionsFunc struct {
+	defaultHook func(context.Context, []types.MonikerLocations) error
+	hooks       []func(context.Context, []types.MonikerLocations) error
+	history     []WriterWriteTypeDefinitionsFuncCall
+	mutex       sync.Mutex
+}
+
+// WriteTypeDefinitions delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockWriter) WriteTypeDefinitions(v0 context.Context, v1 []types.MonikerLocations) error {
+	r0 := m.WriteTypeDefinitionsFunc.nextHook()(v0, v1)
+	m.WriteTypeDefinitionsFunc.appendCall(WriterWriteTypeDefinitionsFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the WriteTypeDefinitions
+// method of the parent MockWriter instance is invoked and the hook queue is
+// empty.
+func (f *WriterWriteTypeDefinitionsFunc) SetDefaultHook(hook func(context.Context, []types.MonikerLocations) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// WriteTypeDefinitions method of the parent MockWriter instance inovkes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *WriterWriteTypeDefinitionsFunc) PushHook(hook func(context.Context, []types.MonikerLocations) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *WriterWriteTypeDefinitionsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, []types.MonikerLocations) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *WriterWriteTypeDefinitionsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, []types.MonikerLocations) error {
+		return r0
+	})
+}
+
+func (f *WriterWriteTypeDefinitionsFunc) nextHook() func(context.Context, []types.MonikerLocations) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *WriterWriteTypeDefinitionsFunc) appendCall(r0 WriterWriteTypeDefinitionsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of WriterWriteTypeDefinitionsFuncCall objects
+// describing the invocations of this function.
+func (f *WriterWriteTypeDefinitionsFunc) History() []WriterWriteTypeDefinitionsFuncCall {
+	f.mutex.Lock()
+	history := make([]WriterWriteTypeDefinitionsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// WriterWriteTypeDefinitionsFuncCall is an object that describes an
+// invocation of method WriteTypeDefinitions on an instance of MockWriter.
+type WriterWriteTypeDefinitionsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []types.MonikerLocations
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c WriterWriteTypeDefinitionsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c WriterWriteTypeDefinitionsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
@@ -10,13 +10,15 @@ import (
 
 // An ObservedReader wraps another Reader with error logging, Prometheus metrics, and tracing.
 type ObservedReader struct {
-	reader                   Reader
-	readMetaOperation        *observation.Operation
-	pathsWithPrefixOperation *observation.Operation
-	readDocumentOperation    *observation.Operation
-	readResultChunkOperation *observation.Operation
-	readDefinitionsOperation *observation.Operation
-	readReferencesOperation  *observation.Operation
+	reader                       Reader
+	readMetaOperation            *observation.Operation
+	pathsWithPrefixOperation     *observation.Operation
+	readDocumentOperation        *observation.Operation
+	readResultChunkOperation     *observation.Operation
+	readDefinitionsOperation     *observation.Operation
+	readReferencesOperation      *observation.Operation
+	readTypeDefinitionsOperation *observation.Operation
+	readImplementationsOperation *observation.Operation
 }
 
 var _ Reader = &ObservedReader{}
@@ -69,6 +71,16 @@ func NewObserved(reader Reader, observationContext *observation.Context) Reader
 			MetricLabels: []string{"read_references"},
 			Metrics:      metrics,
 		}),
+		readTypeDefinitionsOperation: observationContext.Operation(observation.Op{
+			Name:         "Reader.ReadTypeDefinitions",
+			MetricLabels: []string{"read_type_definitions"},
+			Metrics:      metrics,
+		}),
+		readImplementationsOperation: observationContext.Operation(observation.Op{
+			Name:         "Reader.ReadImplementations",
+			MetricLabels: []string{"read_implementations"},
+			Metrics:      metrics,
+		}),
 	}
 }
 
@@ -114,6 +126,20 @@ func (r *ObservedReader) ReadReferences(ctx context.Context, scheme, identifier
 	return r.reader.ReadReferences(ctx, scheme, identifier, skip, take)
 }
 
+// ReadTypeDefinitions calls into the inner Reader and registers the observed results.
+func (r *ObservedReader) ReadTypeDefinitions(ctx context.Context, scheme, identifier string, skip, take int) (locations []types.Location, _ int, err error) {
+	ctx, endObservation := r.readTypeDefinitionsOperation.With(ctx, &err, observation.Args{})
+	defer func() { endObservation(float64(len(locations)), observation.Args{}) }()
+	return r.reader.ReadTypeDefinitions(ctx, scheme, identifier, skip, take)
+}
+
+// ReadImplementations calls into the inner Reader and registers the observed results.
+func (r *ObservedReader) ReadImplementations(ctx context.Context, scheme, identifier string, skip, take int) (locations []types.Location, _ int, err error) {
+	ctx, endObservation := r.readImplementationsOperation.With(ctx, &err, observation.Args{})
+	defer func() { endObservation(float64(len(locations)), observation.Args{}) }()
+	return r.reader.ReadImplementations(ctx, scheme, identifier, skip, take)
+}
+
 func (r *ObservedReader) Close() error {
 	return r.reader.Close()
 }
@@ -12,6 +12,7 @@ import (
 	v3 "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/persistence/sqlite/migrate/v3"
 	v4 "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/persistence/sqlite/migrate/v4"
 	v5 "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/persistence/sqlite/migrate/v5"
+	v6 "github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/persistence/sqlite/migrate/v6"
 	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/persistence/sqlite/store"
 )
 
@@ -34,6 +35,7 @@ var migrations = []struct {
 	{v3.Migrate, false},
 	{v4.Migrate, true},
 	{v5.Migrate, true},
+	{v6.Migrate, false},
 }
 
 var UnknownSchemaVersion = 0
@@ -0,0 +1,26 @@
+package v6
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/persistence/serialization"
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/bundles/persistence/sqlite/store"
+)
+
+// Migrate v6: Create the type_definitions and implementations tables. Bundles written before this
+// version did not retain type definition or implementation results, so both tables are left empty.
+func Migrate(ctx context.Context, s *store.Store, serializer serialization.Serializer) error {
+	queries := []*sqlf.Query{
+		sqlf.Sprintf(`CREATE TABLE "type_definitions" ("scheme" text NOT NULL, "identifier" text NOT NULL, "data" blob NOT NULL, PRIMARY KEY (scheme, identifier))`),
+		sqlf.Sprintf(`CREATE TABLE "implementations" ("scheme" text NOT NULL, "identifier" text NOT NULL, "data" blob NOT NULL, PRIMARY KEY (scheme, identifier))`),
+	}
+
+	for _, query := range queries {
+		if err := s.Exec(ctx, query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
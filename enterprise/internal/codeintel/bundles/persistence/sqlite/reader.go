@@ -133,6 +133,14 @@ func (r *sqliteReader) ReadReferences(ctx context.Context, scheme, identifier st
 	return r.readDefinitionReferences(ctx, "references", scheme, identifier, skip, take)
 }
 
+func (r *sqliteReader) ReadTypeDefinitions(ctx context.Context, scheme, identifier string, skip, take int) ([]types.Location, int, error) {
+	return r.readDefinitionReferences(ctx, "type_definitions", scheme, identifier, skip, take)
+}
+
+func (r *sqliteReader) ReadImplementations(ctx context.Context, scheme, identifier string, skip, take int) ([]types.Location, int, error) {
+	return r.readDefinitionReferences(ctx, "implementations", scheme, identifier, skip, take)
+}
+
 func (r *sqliteReader) readDefinitionReferences(ctx context.Context, tableName, scheme, identifier string, skip, take int) ([]types.Location, int, error) {
 	locations, err := r.readMonikerLocations(ctx, tableName, scheme, identifier)
 	if err != nil {
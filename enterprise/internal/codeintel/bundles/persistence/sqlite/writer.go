@@ -82,6 +82,14 @@ func (w *sqliteWriter) WriteReferences(ctx context.Context, monikerLocations []t
 	return batch.WriteMonikerLocations(ctx, w.store, "references", w.serializer, monikerLocations)
 }
 
+func (w *sqliteWriter) WriteTypeDefinitions(ctx context.Context, monikerLocations []types.MonikerLocations) error {
+	return batch.WriteMonikerLocations(ctx, w.store, "type_definitions", w.serializer, monikerLocations)
+}
+
+func (w *sqliteWriter) WriteImplementations(ctx context.Context, monikerLocations []types.MonikerLocations) error {
+	return batch.WriteMonikerLocations(ctx, w.store, "implementations", w.serializer, monikerLocations)
+}
+
 func (w *sqliteWriter) Close(err error) error {
 	err = w.store.Done(err)
 
@@ -100,6 +108,8 @@ func createTables(ctx context.Context, store *store.Store) error {
 		sqlf.Sprintf(`CREATE TABLE "result_chunks" ("id" integer PRIMARY KEY NOT NULL, "data" blob NOT NULL)`),
 		sqlf.Sprintf(`CREATE TABLE "definitions" ("scheme" text NOT NULL, "identifier" text NOT NULL, "data" blob NOT NULL, PRIMARY KEY (scheme, identifier))`),
 		sqlf.Sprintf(`CREATE TABLE "references" ("scheme" text NOT NULL, "identifier" text NOT NULL, "data" blob NOT NULL, PRIMARY KEY (scheme, identifier))`),
+		sqlf.Sprintf(`CREATE TABLE "type_definitions" ("scheme" text NOT NULL, "identifier" text NOT NULL, "data" blob NOT NULL, PRIMARY KEY (scheme, identifier))`),
+		sqlf.Sprintf(`CREATE TABLE "implementations" ("scheme" text NOT NULL, "identifier" text NOT NULL, "data" blob NOT NULL, PRIMARY KEY (scheme, identifier))`),
 	}
 
 	for _, query := range queries {
@@ -115,6 +115,37 @@ func TestWrite(t *testing.T) {
 		t.Fatalf("unexpected error while writing references: %s", err)
 	}
 
+	expectedTypeDefinitions := []types.Location{
+		{URI: "bar.go", StartLine: 1, StartCharacter: 2, EndLine: 3, EndCharacter: 4},
+	}
+
+	typeDefinitionMonikerLocations := []types.MonikerLocations{
+		{
+			Scheme:     "scheme D",
+			Identifier: "ident D",
+			Locations:  expectedTypeDefinitions,
+		},
+	}
+	if err := writer.WriteTypeDefinitions(ctx, typeDefinitionMonikerLocations); err != nil {
+		t.Fatalf("unexpected error while writing type definitions: %s", err)
+	}
+
+	expectedImplementations := []types.Location{
+		{URI: "bar.go", StartLine: 5, StartCharacter: 6, EndLine: 7, EndCharacter: 8},
+		{URI: "foo.go", StartLine: 2, StartCharacter: 3, EndLine: 4, EndCharacter: 5},
+	}
+
+	implementationMonikerLocations := []types.MonikerLocations{
+		{
+			Scheme:     "scheme E",
+			Identifier: "ident E",
+			Locations:  expectedImplementations,
+		},
+	}
+	if err := writer.WriteImplementations(ctx, implementationMonikerLocations); err != nil {
+		t.Fatalf("unexpected error while writing implementations: %s", err)
+	}
+
 	if err := writer.Close(nil); err != nil {
 		t.Fatalf("unexpected error closing writer: %s", err)
 	}
@@ -169,4 +200,20 @@ func TestWrite(t *testing.T) {
 	if diff := cmp.Diff(expectedReferences, references); diff != "" {
 		t.Errorf("unexpected references (-want +got):\n%s", diff)
 	}
+
+	typeDefinitions, _, err := reader.ReadTypeDefinitions(ctx, "scheme D", "ident D", 0, 100)
+	if err != nil {
+		t.Fatalf("unexpected error reading from database: %s", err)
+	}
+	if diff := cmp.Diff(expectedTypeDefinitions, typeDefinitions); diff != "" {
+		t.Errorf("unexpected type definitions (-want +got):\n%s", diff)
+	}
+
+	implementations, _, err := reader.ReadImplementations(ctx, "scheme E", "ident E", 0, 100)
+	if err != nil {
+		t.Fatalf("unexpected error reading from database: %s", err)
+	}
+	if diff := cmp.Diff(expectedImplementations, implementations); diff != "" {
+		t.Errorf("unexpected implementations (-want +got):\n%s", diff)
+	}
 }
@@ -8,8 +8,8 @@ type MetaData struct {
 }
 
 // DocumentData represents a single document within an index. The data here can answer
-// definitions, references, and hover queries if the results are all contained in the
-// same document.
+// definitions, references, type definitions, implementations, and hover queries if the
+// results are all contained in the same document.
 type DocumentData struct {
 	Ranges             map[ID]RangeData
 	HoverResults       map[ID]string // hover text normalized to markdown string
@@ -23,14 +23,16 @@ type DocumentData struct {
 // that was reachable via a result set has been collapsed into this object during
 // conversion.
 type RangeData struct {
-	StartLine          int  // 0-indexed, inclusive
-	StartCharacter     int  // 0-indexed, inclusive
-	EndLine            int  // 0-indexed, inclusive
-	EndCharacter       int  // 0-indexed, inclusive
-	DefinitionResultID ID   // possibly empty
-	ReferenceResultID  ID   // possibly empty
-	HoverResultID      ID   // possibly empty
-	MonikerIDs         []ID // possibly empty
+	StartLine              int  // 0-indexed, inclusive
+	StartCharacter         int  // 0-indexed, inclusive
+	EndLine                int  // 0-indexed, inclusive
+	EndCharacter           int  // 0-indexed, inclusive
+	DefinitionResultID     ID   // possibly empty
+	ReferenceResultID      ID   // possibly empty
+	TypeDefinitionResultID ID   // possibly empty
+	ImplementationResultID ID   // possibly empty
+	HoverResultID          ID   // possibly empty
+	MonikerIDs             []ID // possibly empty
 }
 
 // MonikerData represent a unique name (eventually) attached to a range.
@@ -64,7 +66,7 @@ type DiagnosticData struct {
 }
 
 // ResultChunkData represents a row of the resultChunk table. Each row is a subset
-// of definition and reference result data in the index. Results are inserted into
+// of definition, reference, type definition, and implementation result data in the index. Results are inserted into
 // chunks based on the hash of their identifier, thus every chunk has a roughly
 // proportional amount of data.
 type ResultChunkData struct {
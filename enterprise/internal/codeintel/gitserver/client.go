@@ -22,6 +22,10 @@ type Client interface {
 	// that key are the files nested under that directory.
 	DirectoryChildren(ctx context.Context, store store.Store, repositoryID int, commit string, dirnames []string) (map[string][]string, error)
 
+	// ListFiles returns the paths of all files known to git in the given commit via an invocation
+	// of git ls-tree. The paths are relative to the root of the repository.
+	ListFiles(ctx context.Context, store store.Store, repositoryID int, commit string) ([]string, error)
+
 	// Archive retrieves a tar-formatted archive of the given commit.
 	Archive(ctx context.Context, store store.Store, repositoryID int, commit string) (io.Reader, error)
 
@@ -50,6 +54,10 @@ func (c *defaultClient) DirectoryChildren(ctx context.Context, store store.Store
 	return DirectoryChildren(ctx, store, repositoryID, commit, dirnames)
 }
 
+func (c *defaultClient) ListFiles(ctx context.Context, store store.Store, repositoryID int, commit string) ([]string, error) {
+	return ListFiles(ctx, store, repositoryID, commit)
+}
+
 func (c *defaultClient) Archive(ctx context.Context, store store.Store, repositoryID int, commit string) (io.Reader, error) {
 	return Archive(ctx, store, repositoryID, commit)
 }
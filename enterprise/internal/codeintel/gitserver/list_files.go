@@ -0,0 +1,31 @@
+package gitserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/internal/codeintel/store"
+)
+
+// ListFiles returns the paths of all files known to git in the given commit via an invocation of
+// git ls-tree. The paths are relative to the root of the repository.
+func ListFiles(ctx context.Context, store store.Store, repositoryID int, commit string) ([]string, error) {
+	out, err := execGitCommand(ctx, store, repositoryID, "ls-tree", "-r", "-z", "--name-only", commit, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseListFiles(out), nil
+}
+
+// parseListFiles splits the NUL-separated output of git ls-tree -z --name-only into paths.
+func parseListFiles(out string) []string {
+	var paths []string
+	for _, path := range strings.Split(out, "\x00") {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,22 @@
+package gitserver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseListFiles(t *testing.T) {
+	out := "README.md\x00cmd/server/go.mod\x00web/package.json\x00web/src/new\nline.ts\x00"
+
+	expected := []string{
+		"README.md",
+		"cmd/server/go.mod",
+		"web/package.json",
+		"web/src/new\nline.ts",
+	}
+
+	if diff := cmp.Diff(expected, parseListFiles(out)); diff != "" {
+		t.Errorf("unexpected list files result (-want +got):\n%s", diff)
+	}
+}
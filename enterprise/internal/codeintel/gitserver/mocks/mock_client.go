@@ -30,6 +30,9 @@ type MockClient struct {
 	// HeadFunc is an instance of a mock function object controlling the
 	// behavior of the method Head.
 	HeadFunc *ClientHeadFunc
+	// ListFilesFunc is an instance of a mock function object controlling
+	// the behavior of the method ListFiles.
+	ListFilesFunc *ClientListFilesFunc
 	// TagsFunc is an instance of a mock function object controlling the
 	// behavior of the method Tags.
 	TagsFunc *ClientTagsFunc
@@ -64,6 +67,11 @@ func NewMockClient() *MockClient {
 				return "", nil
 			},
 		},
+		ListFilesFunc: &ClientListFilesFunc{
+			defaultHook: func(context.Context, store.Store, int, string) ([]string, error) {
+				return nil, nil
+			},
+		},
 		TagsFunc: &ClientTagsFunc{
 			defaultHook: func(context.Context, store.Store, int, string) (string, bool, error) {
 				return "", false, nil
@@ -91,6 +99,9 @@ func NewMockClientFrom(i gitserver.Client) *MockClient {
 		HeadFunc: &ClientHeadFunc{
 			defaultHook: i.Head,
 		},
+		ListFilesFunc: &ClientListFilesFunc{
+			defaultHook: i.ListFiles,
+		},
 		TagsFunc: &ClientTagsFunc{
 			defaultHook: i.Tags,
 		},
@@ -671,6 +682,120 @@ func (c ClientHeadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// ClientListFilesFunc describes the behavior when the ListFiles method of
+// the parent MockClient instance is invoked.
+type ClientListFilesFunc struct {
+	defaultHook func(context.Context, store.Store, int, string) ([]string, error)
+	hooks       []func(context.Context, store.Store, int, string) ([]string, error)
+	history     []ClientListFilesFuncCall
+	mutex       sync.Mutex
+}
+
+// ListFiles delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockClient) ListFiles(v0 context.Context, v1 store.Store, v2 int, v3 string) ([]string, error) {
+	r0, r1 := m.ListFilesFunc.nextHook()(v0, v1, v2, v3)
+	m.ListFilesFunc.appendCall(ClientListFilesFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListFiles method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientListFilesFunc) SetDefaultHook(hook func(context.Context, store.Store, int, string) ([]string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListFiles method of the parent MockClient instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientListFilesFunc) PushHook(hook func(context.Context, store.Store, int, string) ([]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ClientListFilesFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(context.Context, store.Store, int, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ClientListFilesFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(context.Context, store.Store, int, string) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientListFilesFunc) nextHook() func(context.Context, store.Store, int, string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientListFilesFunc) appendCall(r0 ClientListFilesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientListFilesFuncCall objects describing
+// the invocations of this function.
+func (f *ClientListFilesFunc) History() []ClientListFilesFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientListFilesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientListFilesFuncCall is an object that describes an invocation of
+// method ListFiles on an instance of MockClient.
+type ClientListFilesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 store.Store
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientListFilesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientListFilesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ClientTagsFunc describes the behavior when the Tags method of the parent
 // MockClient instance is invoked.
 type ClientTagsFunc struct {
@@ -43,6 +43,8 @@ type AdjustedCodeIntelligenceRange struct {
 type QueryResolver interface {
 	Ranges(ctx context.Context, startLine, endLine int) ([]AdjustedCodeIntelligenceRange, error)
 	Definitions(ctx context.Context, line, character int) ([]AdjustedLocation, error)
+	TypeDefinitions(ctx context.Context, line, character int) ([]AdjustedLocation, error)
+	Implementations(ctx context.Context, line, character int) ([]AdjustedLocation, error)
 	References(ctx context.Context, line, character, limit int, rawCursor string) ([]AdjustedLocation, string, error)
 	Hover(ctx context.Context, line, character int) (string, bundles.Range, bool, error)
 	Diagnostics(ctx context.Context, limit int) ([]AdjustedDiagnostic, int, error)
@@ -161,6 +163,64 @@ func (r *queryResolver) Definitions(ctx context.Context, line, character int) ([
 	return nil, nil
 }
 
+// TypeDefinitions returns the list of source locations that define the type of the symbol at the
+// given position. This may include remote type definitions if the remote repository is also indexed.
+// If there are multiple bundles associated with this resolver, the type definitions from the first
+// bundle with any results will be returned.
+func (r *queryResolver) TypeDefinitions(ctx context.Context, line, character int) ([]AdjustedLocation, error) {
+	position := bundles.Position{Line: line, Character: character}
+
+	for i := range r.uploads {
+		adjustedPath, adjustedPosition, ok, err := r.positionAdjuster.AdjustPosition(ctx, r.uploads[i].Commit, r.path, position, false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		locations, err := r.codeIntelAPI.TypeDefinitions(ctx, adjustedPath, adjustedPosition.Line, adjustedPosition.Character, r.uploads[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(locations) == 0 {
+			continue
+		}
+
+		return r.adjustLocations(ctx, locations)
+	}
+
+	return nil, nil
+}
+
+// Implementations returns the list of source locations that implement the symbol at the given
+// position. This may include implementations from other dumps and repositories. If there are
+// multiple bundles associated with this resolver, results from all bundles will be concatenated
+// and returned.
+func (r *queryResolver) Implementations(ctx context.Context, line, character int) ([]AdjustedLocation, error) {
+	position := bundles.Position{Line: line, Character: character}
+
+	var allLocations []codeintelapi.ResolvedLocation
+	for i := range r.uploads {
+		adjustedPath, adjustedPosition, ok, err := r.positionAdjuster.AdjustPosition(ctx, r.uploads[i].Commit, r.path, position, false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		locations, err := r.codeIntelAPI.Implementations(ctx, adjustedPath, adjustedPosition.Line, adjustedPosition.Character, r.uploads[i].ID)
+		if err != nil {
+			return nil, err
+		}
+
+		allLocations = append(allLocations, locations...)
+	}
+
+	return r.adjustLocations(ctx, allLocations)
+}
+
 // References returns the list of source locations that reference the symbol at the given position.
 // This may include references from other dumps and repositories. If there are multiple bundles
 // associated with this resolver, results from all bundles will be concatenated and returned.
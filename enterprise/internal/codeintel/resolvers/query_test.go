@@ -249,6 +249,91 @@ func TestDefinitions(t *testing.T) {
 	}
 }
 
+func TestImplementations(t *testing.T) {
+	mockStore := storemocks.NewMockStore()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockCodeIntelAPI := apimocks.NewMockCodeIntelAPI()
+	mockPositionAdjuster := NewMockPositionAdjuster()
+
+	// position can be translated for subsequent dumps
+	mockPositionAdjuster.AdjustPositionFunc.SetDefaultReturn("", bundles.Position{Line: 20, Character: 15}, true, nil)
+
+	// first requested dump (dump 42) has no equivalent position
+	mockPositionAdjuster.AdjustPositionFunc.PushReturn("", bundles.Position{}, false, nil)
+
+	mockCodeIntelAPI.ImplementationsFunc.PushReturn([]codeintelapi.ResolvedLocation{
+		{
+			Dump: store.Dump{ID: 43, RepositoryID: 50},
+			Path: "p1.go",
+			Range: bundles.Range{
+				Start: bundles.Position{Line: 11, Character: 12},
+				End:   bundles.Position{Line: 13, Character: 14},
+			},
+		},
+	}, nil)
+	mockCodeIntelAPI.ImplementationsFunc.PushReturn([]codeintelapi.ResolvedLocation{
+		{
+			Dump: store.Dump{ID: 44, RepositoryID: 51},
+			Path: "p2.go",
+			Range: bundles.Range{
+				Start: bundles.Position{Line: 21, Character: 22},
+				End:   bundles.Position{Line: 23, Character: 24},
+			},
+		},
+	}, nil)
+
+	mockPositionAdjuster.AdjustRangeFunc.SetDefaultHook(func(ctx context.Context, path, commit string, r bundles.Range, reverse bool) (string, bundles.Range, bool, error) {
+		return path, bundles.Range{
+			Start: bundles.Position{Line: r.Start.Line * 10, Character: r.Start.Character * 10},
+			End:   bundles.Position{Line: r.End.Line * 10, Character: r.End.Character * 10},
+		}, true, nil
+	})
+
+	queryResolver := NewQueryResolver(
+		mockStore,
+		mockBundleManagerClient,
+		mockCodeIntelAPI,
+		mockPositionAdjuster,
+		50,
+		"deadbeef2",
+		"/foo/bar.go",
+		[]store.Dump{
+			{ID: 42, RepositoryID: 50, Commit: "deadbeef1"},
+			{ID: 43, RepositoryID: 50, Commit: "deadbeef1"},
+			{ID: 44, RepositoryID: 50, Commit: "deadbeef1"},
+		},
+	)
+
+	implementations, err := queryResolver.Implementations(context.Background(), 10, 15)
+	if err != nil {
+		t.Fatalf("unexpected error resolving implementations: %s", err)
+	}
+
+	expectedImplementations := []AdjustedLocation{
+		{
+			Dump:           store.Dump{ID: 43, RepositoryID: 50},
+			Path:           "p1.go",
+			AdjustedCommit: "deadbeef2",
+			AdjustedRange: bundles.Range{
+				Start: bundles.Position{Line: 110, Character: 120},
+				End:   bundles.Position{Line: 130, Character: 140},
+			},
+		},
+		{
+			Dump:           store.Dump{ID: 44, RepositoryID: 51},
+			Path:           "p2.go",
+			AdjustedCommit: "",
+			AdjustedRange: bundles.Range{
+				Start: bundles.Position{Line: 21, Character: 22},
+				End:   bundles.Position{Line: 23, Character: 24},
+			},
+		},
+	}
+	if diff := cmp.Diff(expectedImplementations, implementations); diff != "" {
+		t.Errorf("unexpected implementations (-want +got):\n%s", diff)
+	}
+}
+
 func TestReferences(t *testing.T) {
 	mockStore := storemocks.NewMockStore()
 	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
@@ -98,6 +98,8 @@ type ConfigSavedQuery struct {
 	UserID          *int32  `json:"userID"`
 	OrgID           *int32  `json:"orgID"`
 	SlackWebhookURL *string `json:"slackWebhookURL"`
+
+	Webhooks []*schema.SavedQueryWebhook `json:"webhooks,omitempty"`
 }
 
 func (sq ConfigSavedQuery) Equals(other ConfigSavedQuery) bool {
@@ -148,6 +150,27 @@ type SavedQueryInfo struct {
 
 	// ExecDuration is the amount of time it took for the query to execute.
 	ExecDuration time.Duration
+
+	// ResultFingerprints identifies the matches of the last execution of a
+	// content search query, so that the next execution can determine which
+	// matches were introduced or removed since. It is nil if no execution
+	// recorded the matches yet, and always nil for diff and commit search
+	// queries, which use LatestResult instead.
+	ResultFingerprints []SavedQueryResultFingerprint
+}
+
+// SavedQueryResultFingerprint identifies a single match of a saved query
+// independently of the commit that it was found in.
+type SavedQueryResultFingerprint struct {
+	// Repo is the name of the repository containing the match.
+	Repo string
+
+	// Path is the path of the file containing the match.
+	Path string
+
+	// LineHash is a hash of the content of the matched line. It is empty for
+	// matches on the path of a file.
+	LineHash string
 }
 
 // SavedQueriesGetInfo gets the info from the DB for the given saved query. nil
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
@@ -27,6 +28,7 @@ import (
 var SupportedExternalServices = map[string]struct{}{
 	extsvc.TypeGitHub:          {},
 	extsvc.TypeBitbucketServer: {},
+	extsvc.TypeGitLab:          {},
 }
 
 // IsRepoSupported returns whether the given ExternalRepoSpec is supported by
@@ -423,6 +425,12 @@ func (c *Changeset) SetMetadata(meta interface{}) error {
 		c.ExternalServiceType = extsvc.TypeBitbucketServer
 		c.ExternalBranch = git.AbbreviateRef(pr.FromRef.ID)
 		c.ExternalUpdatedAt = unixMilliToTime(int64(pr.UpdatedDate))
+	case *gitlab.MergeRequest:
+		c.Metadata = pr
+		c.ExternalID = strconv.FormatInt(int64(pr.IID), 10)
+		c.ExternalServiceType = extsvc.TypeGitLab
+		c.ExternalBranch = pr.SourceBranch
+		c.ExternalUpdatedAt = pr.UpdatedAt
 	default:
 		return errors.New("unknown changeset type")
 	}
@@ -446,6 +454,8 @@ func (c *Changeset) Title() (string, error) {
 		return m.Title, nil
 	case *bitbucketserver.PullRequest:
 		return m.Title, nil
+	case *gitlab.MergeRequest:
+		return m.Title, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -460,6 +470,8 @@ func (c *Changeset) ExternalCreatedAt() time.Time {
 		return m.CreatedAt
 	case *bitbucketserver.PullRequest:
 		return unixMilliToTime(int64(m.CreatedDate))
+	case *gitlab.MergeRequest:
+		return m.CreatedAt
 	default:
 		return time.Time{}
 	}
@@ -472,6 +484,8 @@ func (c *Changeset) Body() (string, error) {
 		return m.Body, nil
 	case *bitbucketserver.PullRequest:
 		return m.Description, nil
+	case *gitlab.MergeRequest:
+		return m.Description, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -505,6 +519,8 @@ func (c *Changeset) state() (s ChangesetState, err error) {
 		} else {
 			s = ChangesetState(m.State)
 		}
+	case *gitlab.MergeRequest:
+		s = gitLabMergeRequestState(m.State)
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -527,6 +543,8 @@ func (c *Changeset) URL() (s string, err error) {
 		}
 		selfLink := m.Links.Self[0]
 		return selfLink.Href, nil
+	case *gitlab.MergeRequest:
+		return m.WebURL, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -625,6 +643,36 @@ func (c *Changeset) Events() (events []*ChangesetEvent) {
 			addEvent(s)
 		}
 
+	case *gitlab.MergeRequest:
+		events = make([]*ChangesetEvent, 0, len(m.Notes)+len(m.Pipelines))
+		addEvent := func(e Keyer) {
+			events = append(events, &ChangesetEvent{
+				ChangesetID: c.ID,
+				Key:         e.Key(),
+				Kind:        ChangesetEventKindFor(e),
+				Metadata:    e,
+			})
+		}
+		byKey := make(map[string]*ChangesetEvent, len(m.Notes))
+		for _, n := range m.Notes {
+			// Only system notes that record a change to the merge request
+			// are turned into events.
+			e, ok := n.ToEvent().(Keyer)
+			if !ok {
+				continue
+			}
+			// Notes are sorted oldest first, so a later event of the same
+			// kind by the same author replaces the earlier one.
+			if ev, ok := byKey[e.Key()]; ok {
+				ev.Metadata = e
+				continue
+			}
+			addEvent(e)
+			byKey[e.Key()] = events[len(events)-1]
+		}
+		for _, p := range m.Pipelines {
+			addEvent(p)
+		}
 	}
 	return events
 }
@@ -638,6 +686,8 @@ func (c *Changeset) HeadRefOid() (string, error) {
 		return m.HeadRefOid, nil
 	case *bitbucketserver.PullRequest:
 		return "", nil
+	case *gitlab.MergeRequest:
+		return m.DiffRefs.HeadSHA, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -651,6 +701,8 @@ func (c *Changeset) HeadRef() (string, error) {
 		return "refs/heads/" + m.HeadRefName, nil
 	case *bitbucketserver.PullRequest:
 		return m.FromRef.ID, nil
+	case *gitlab.MergeRequest:
+		return "refs/heads/" + m.SourceBranch, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -665,6 +717,8 @@ func (c *Changeset) BaseRefOid() (string, error) {
 		return m.BaseRefOid, nil
 	case *bitbucketserver.PullRequest:
 		return "", nil
+	case *gitlab.MergeRequest:
+		return m.DiffRefs.BaseSHA, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -678,6 +732,8 @@ func (c *Changeset) BaseRef() (string, error) {
 		return "refs/heads/" + m.BaseRefName, nil
 	case *bitbucketserver.PullRequest:
 		return m.ToRef.ID, nil
+	case *gitlab.MergeRequest:
+		return "refs/heads/" + m.TargetBranch, nil
 	default:
 		return "", errors.New("unknown changeset type")
 	}
@@ -763,6 +819,16 @@ func (e *ChangesetEvent) Actor() string {
 		a = e.Actor.Login
 	case *github.LabelEvent:
 		a = e.Actor.Login
+	case *gitlab.ReviewApprovedEvent:
+		a = e.Author.Username
+	case *gitlab.ReviewUnapprovedEvent:
+		a = e.Author.Username
+	case *gitlab.MergeRequestClosedEvent:
+		a = e.Author.Username
+	case *gitlab.MergeRequestReopenedEvent:
+		a = e.Author.Username
+	case *gitlab.MergeRequestMergedEvent:
+		a = e.Author.Username
 	}
 
 	return a
@@ -799,6 +865,20 @@ func (e *ChangesetEvent) ReviewAuthor() (string, error) {
 		}
 		return username, nil
 
+	case *gitlab.ReviewApprovedEvent:
+		username := meta.Author.Username
+		if username == "" {
+			return "", errors.New("approval author is blank")
+		}
+		return username, nil
+
+	case *gitlab.ReviewUnapprovedEvent:
+		username := meta.Author.Username
+		if username == "" {
+			return "", errors.New("unapproval author is blank")
+		}
+		return username, nil
+
 	default:
 		return "", nil
 	}
@@ -807,7 +887,8 @@ func (e *ChangesetEvent) ReviewAuthor() (string, error) {
 // ReviewState returns the review state of the ChangesetEvent if it is a review event.
 func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 	switch e.Kind {
-	case ChangesetEventKindBitbucketServerApproved:
+	case ChangesetEventKindBitbucketServerApproved,
+		ChangesetEventKindGitLabApproved:
 		return ChangesetReviewStateApproved, nil
 
 	// BitbucketServer's "REVIEWED" activity is created when someone clicks
@@ -831,7 +912,8 @@ func (e *ChangesetEvent) ReviewState() (ChangesetReviewState, error) {
 
 	case ChangesetEventKindGitHubReviewDismissed,
 		ChangesetEventKindBitbucketServerUnapproved,
-		ChangesetEventKindBitbucketServerDismissed:
+		ChangesetEventKindBitbucketServerDismissed,
+		ChangesetEventKindGitLabUnapproved:
 		return ChangesetReviewStateDismissed, nil
 
 	default:
@@ -893,6 +975,18 @@ func (e *ChangesetEvent) Timestamp() time.Time {
 		t = unixMilliToTime(int64(e.CreatedDate))
 	case *bitbucketserver.CommitStatus:
 		t = unixMilliToTime(int64(e.Status.DateAdded))
+	case *gitlab.ReviewApprovedEvent:
+		t = e.CreatedAt
+	case *gitlab.ReviewUnapprovedEvent:
+		t = e.CreatedAt
+	case *gitlab.MergeRequestClosedEvent:
+		t = e.CreatedAt
+	case *gitlab.MergeRequestReopenedEvent:
+		t = e.CreatedAt
+	case *gitlab.MergeRequestMergedEvent:
+		t = e.CreatedAt
+	case *gitlab.Pipeline:
+		t = e.CreatedAt
 	}
 
 	return t
@@ -1215,6 +1309,46 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) {
 		// We always get the full event, so safe to replace it
 		*e = *o
 
+	case *gitlab.ReviewApprovedEvent:
+		updateGitLabNote(e.Note, o.Metadata.(*gitlab.ReviewApprovedEvent).Note)
+
+	case *gitlab.ReviewUnapprovedEvent:
+		updateGitLabNote(e.Note, o.Metadata.(*gitlab.ReviewUnapprovedEvent).Note)
+
+	case *gitlab.MergeRequestClosedEvent:
+		updateGitLabNote(e.Note, o.Metadata.(*gitlab.MergeRequestClosedEvent).Note)
+
+	case *gitlab.MergeRequestReopenedEvent:
+		updateGitLabNote(e.Note, o.Metadata.(*gitlab.MergeRequestReopenedEvent).Note)
+
+	case *gitlab.MergeRequestMergedEvent:
+		updateGitLabNote(e.Note, o.Metadata.(*gitlab.MergeRequestMergedEvent).Note)
+
+	case *gitlab.Pipeline:
+		o := o.Metadata.(*gitlab.Pipeline)
+
+		// Pipeline webhook payloads don't contain all the fields we get
+		// from the API, so we only update the ones that are set.
+		if o.Status != "" {
+			e.Status = o.Status
+		}
+
+		if !o.UpdatedAt.IsZero() {
+			e.UpdatedAt = o.UpdatedAt
+		}
+
+		if e.SHA == "" {
+			e.SHA = o.SHA
+		}
+
+		if e.WebURL == "" {
+			e.WebURL = o.WebURL
+		}
+
+		if e.CreatedAt.IsZero() {
+			e.CreatedAt = o.CreatedAt
+		}
+
 	case *github.CheckRun:
 		o := o.Metadata.(*github.CheckRun)
 		updateGithubCheckRun(e, o)
@@ -1234,6 +1368,17 @@ func (e *ChangesetEvent) Update(o *ChangesetEvent) {
 	}
 }
 
+// updateGitLabNote replaces the note e with o if o is at least as recent. GitLab
+// note events are keyed by kind and author, so a newer note of the same kind by
+// the same author, such as a second approval after an unapproval, supersedes
+// the one we have.
+func updateGitLabNote(e, o *gitlab.Note) {
+	if e == nil || o == nil || o.CreatedAt.Before(e.CreatedAt) {
+		return
+	}
+	*e = *o
+}
+
 func updateGithubCheckRun(e, o *github.CheckRun) {
 	if e.Status == "" {
 		e.Status = o.Status
@@ -1362,6 +1507,18 @@ func ChangesetEventKindFor(e interface{}) ChangesetEventKind {
 		return ChangesetEventKind("bitbucketserver:participant_status:" + strings.ToLower(string(e.Action)))
 	case *bitbucketserver.CommitStatus:
 		return ChangesetEventKindBitbucketServerCommitStatus
+	case *gitlab.ReviewApprovedEvent:
+		return ChangesetEventKindGitLabApproved
+	case *gitlab.ReviewUnapprovedEvent:
+		return ChangesetEventKindGitLabUnapproved
+	case *gitlab.MergeRequestClosedEvent:
+		return ChangesetEventKindGitLabClosed
+	case *gitlab.MergeRequestReopenedEvent:
+		return ChangesetEventKindGitLabReopened
+	case *gitlab.MergeRequestMergedEvent:
+		return ChangesetEventKindGitLabMerged
+	case *gitlab.Pipeline:
+		return ChangesetEventKindGitLabPipeline
 	default:
 		panic(errors.Errorf("unknown changeset event kind for %T", e))
 	}
@@ -1419,6 +1576,21 @@ func NewChangesetEventMetadata(k ChangesetEventKind) (interface{}, error) {
 		case ChangesetEventKindCheckRun:
 			return new(github.CheckRun), nil
 		}
+	case strings.HasPrefix(string(k), "gitlab"):
+		switch k {
+		case ChangesetEventKindGitLabApproved:
+			return new(gitlab.ReviewApprovedEvent), nil
+		case ChangesetEventKindGitLabUnapproved:
+			return new(gitlab.ReviewUnapprovedEvent), nil
+		case ChangesetEventKindGitLabClosed:
+			return new(gitlab.MergeRequestClosedEvent), nil
+		case ChangesetEventKindGitLabReopened:
+			return new(gitlab.MergeRequestReopenedEvent), nil
+		case ChangesetEventKindGitLabMerged:
+			return new(gitlab.MergeRequestMergedEvent), nil
+		case ChangesetEventKindGitLabPipeline:
+			return new(gitlab.Pipeline), nil
+		}
 	}
 	return nil, errors.Errorf("unknown changeset event kind %q", k)
 }
@@ -1464,6 +1636,13 @@ const (
 	// BitbucketServer calls this an Unapprove event but we've called it Dismissed to more
 	// clearly convey that it only occurs when a request for changes has been dismissed.
 	ChangesetEventKindBitbucketServerDismissed ChangesetEventKind = "bitbucketserver:participant_status:unapproved"
+
+	ChangesetEventKindGitLabApproved   ChangesetEventKind = "gitlab:approved"
+	ChangesetEventKindGitLabUnapproved ChangesetEventKind = "gitlab:unapproved"
+	ChangesetEventKindGitLabClosed     ChangesetEventKind = "gitlab:closed"
+	ChangesetEventKindGitLabReopened   ChangesetEventKind = "gitlab:reopened"
+	ChangesetEventKindGitLabMerged     ChangesetEventKind = "gitlab:merged"
+	ChangesetEventKindGitLabPipeline   ChangesetEventKind = "gitlab:pipeline"
 )
 
 // ChangesetSyncData represents data about the sync status of a changeset
@@ -1489,6 +1668,22 @@ func UnmarshalCampaignID(id graphql.ID) (campaignID int64, err error) {
 	return
 }
 
+// gitLabMergeRequestState maps the state of a GitLab merge request to a
+// ChangesetState. Locked merge requests can't be interacted with, so they're
+// considered closed.
+func gitLabMergeRequestState(s gitlab.MergeRequestState) ChangesetState {
+	switch s {
+	case gitlab.MergeRequestStateOpened:
+		return ChangesetStateOpen
+	case gitlab.MergeRequestStateClosed, gitlab.MergeRequestStateLocked:
+		return ChangesetStateClosed
+	case gitlab.MergeRequestStateMerged:
+		return ChangesetStateMerged
+	default:
+		return ChangesetState(s)
+	}
+}
+
 func unixMilliToTime(ms int64) time.Time {
 	return time.Unix(0, ms*int64(time.Millisecond))
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 )
 
 func TestChangesetMetadata(t *testing.T) {
@@ -285,6 +286,64 @@ func TestChangesetEvents(t *testing.T) {
 		})
 	}
 
+	{ // GitLab
+
+		now := time.Now().UTC()
+		user := gitlab.User{Username: "john-doe"}
+		reviewer := gitlab.User{Username: "jane-doe"}
+
+		notes := []*gitlab.Note{
+			{ID: 1, Author: reviewer, Body: "looks good", CreatedAt: now},
+			{ID: 2, Author: reviewer, Body: "approved this merge request", CreatedAt: now, System: true},
+			{ID: 3, Author: user, Body: "added 1 commit", CreatedAt: now, System: true},
+			{ID: 4, Author: reviewer, Body: "unapproved this merge request", CreatedAt: now, System: true},
+			{ID: 5, Author: user, Body: "closed", CreatedAt: now, System: true},
+			{ID: 6, Author: user, Body: "reopened", CreatedAt: now, System: true},
+		}
+		pipeline := &gitlab.Pipeline{ID: 7, Status: gitlab.PipelineStatusSuccess, CreatedAt: now}
+
+		approved := &gitlab.ReviewApprovedEvent{Note: notes[1]}
+		unapproved := &gitlab.ReviewUnapprovedEvent{Note: notes[3]}
+		closed := &gitlab.MergeRequestClosedEvent{Note: notes[4]}
+		reopened := &gitlab.MergeRequestReopenedEvent{Note: notes[5]}
+
+		cases = append(cases, testCase{"gitlab",
+			Changeset{
+				ID: 25,
+				Metadata: &gitlab.MergeRequest{
+					Notes:     notes,
+					Pipelines: []*gitlab.Pipeline{pipeline},
+				},
+			},
+			[]*ChangesetEvent{{
+				ChangesetID: 25,
+				Kind:        ChangesetEventKindGitLabApproved,
+				Key:         approved.Key(),
+				Metadata:    approved,
+			}, {
+				ChangesetID: 25,
+				Kind:        ChangesetEventKindGitLabUnapproved,
+				Key:         unapproved.Key(),
+				Metadata:    unapproved,
+			}, {
+				ChangesetID: 25,
+				Kind:        ChangesetEventKindGitLabClosed,
+				Key:         closed.Key(),
+				Metadata:    closed,
+			}, {
+				ChangesetID: 25,
+				Kind:        ChangesetEventKindGitLabReopened,
+				Key:         reopened.Key(),
+				Metadata:    reopened,
+			}, {
+				ChangesetID: 25,
+				Kind:        ChangesetEventKindGitLabPipeline,
+				Key:         pipeline.Key(),
+				Metadata:    pipeline,
+			}},
+		})
+	}
+
 	for _, tc := range cases {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
@@ -300,6 +359,55 @@ func TestChangesetEvents(t *testing.T) {
 	}
 }
 
+func TestChangesetEvents_GitLabWebhook(t *testing.T) {
+	// The same approval, once synced as a note and once received through a
+	// webhook, whose timestamp is when the merge request was updated.
+	approvedAt := time.Date(2020, 6, 15, 9, 4, 5, 0, time.UTC)
+	reviewer := gitlab.User{Username: "jane-doe"}
+	c := &Changeset{
+		ID: 25,
+		Metadata: &gitlab.MergeRequest{Notes: []*gitlab.Note{
+			{ID: 1, Author: reviewer, Body: "approved this merge request", CreatedAt: approvedAt.Add(-time.Hour), System: true},
+			{ID: 2, Author: reviewer, Body: "unapproved this merge request", CreatedAt: approvedAt.Add(-time.Minute), System: true},
+			{ID: 3, Author: reviewer, Body: "approved this merge request", CreatedAt: approvedAt, System: true},
+		}},
+	}
+
+	payload := `{
+		"user": {"username": "jane-doe"},
+		"object_attributes": {"iid": 2, "action": "approved", "updated_at": "2020-06-15 09:04:07 UTC"}
+	}`
+	e, err := gitlab.ParseWebhookEvent("Merge Request Hook", []byte(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook := e.(*gitlab.MergeRequestWebhookEvent).ToEvent().(Keyer)
+
+	// Upserts are keyed by changeset, kind and key.
+	upserted := map[string]*ChangesetEvent{}
+	for _, ev := range append(c.Events(), &ChangesetEvent{
+		ChangesetID: c.ID,
+		Kind:        ChangesetEventKindFor(hook),
+		Key:         hook.Key(),
+		Metadata:    hook,
+	}) {
+		upserted[fmt.Sprintf("%d:%s:%s", ev.ChangesetID, ev.Kind, ev.Key)] = ev
+	}
+
+	var approvals []*ChangesetEvent
+	for _, ev := range upserted {
+		if ev.Kind == ChangesetEventKindGitLabApproved {
+			approvals = append(approvals, ev)
+		}
+	}
+	if len(approvals) != 1 {
+		t.Fatalf("got %d approval events, want 1: %+v", len(approvals), approvals)
+	}
+	if have := approvals[0].Metadata.(*gitlab.ReviewApprovedEvent).CreatedAt; !have.After(approvedAt.Add(-time.Minute)) {
+		t.Errorf("got approval at %s, want the latest approval", have)
+	}
+}
+
 func TestChangesetDiffStat(t *testing.T) {
 	var (
 		added   int32 = 77
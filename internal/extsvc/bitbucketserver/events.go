@@ -28,6 +28,9 @@ func ParseWebhookEvent(eventType string, payload []byte) (e interface{}, err err
 	case "pr:participant:status":
 		e = &PullRequestParticipantStatusEvent{}
 		return e, json.Unmarshal(payload, e)
+	case "repo:refs_changed":
+		e = &RefsChangedEvent{}
+		return e, json.Unmarshal(payload, e)
 	default:
 		return nil, fmt.Errorf("unknown webhook event type: %q", eventType)
 	}
@@ -58,6 +61,20 @@ func (a *ParticipantStatusEvent) Key() string {
 	return fmt.Sprintf("%s:%d:%d", a.Action, a.User.ID, a.CreatedDate)
 }
 
+// RefsChangedEvent is sent when branches or tags are pushed to a repository.
+type RefsChangedEvent struct {
+	Repository Repo        `json:"repository"`
+	Changes    []RefChange `json:"changes"`
+}
+
+// RefChange is a change of a ref in a RefsChangedEvent.
+type RefChange struct {
+	RefID    string `json:"refId"`
+	FromHash string `json:"fromHash"`
+	ToHash   string `json:"toHash"`
+	Type     string `json:"type"`
+}
+
 type BuildStatusEvent struct {
 	Commit       string        `json:"commit"`
 	Status       BuildStatus   `json:"status"`
@@ -0,0 +1,260 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/opentracing-contrib/go-stdlib/nethttp"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"golang.org/x/time/rate"
+)
+
+var requestCounter = metrics.NewRequestMeter("gerrit_requests_count", "Total number of requests sent to the Gerrit API.")
+
+// These fields define the self-imposed Gerrit rate limit (since Gerrit does not have a
+// concept of rate limiting in HTTP response headers).
+//
+// See https://godoc.org/golang.org/x/time/rate#Limiter for an explanation of these fields.
+const (
+	rateLimitRequestsPerSecond = 8 // 480/min or 28,800/hr
+	RateLimitMaxBurstRequests  = 500
+)
+
+// xssiPrefix is prepended by Gerrit to every JSON response body to prevent
+// against cross-site script inclusion attacks. It must be stripped before the
+// body can be decoded.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api.html#output
+var xssiPrefix = []byte(")]}'")
+
+// Client access a Gerrit instance via the REST API.
+type Client struct {
+	// HTTP Client used to communicate with the API
+	httpClient httpcli.Doer
+
+	// URL is the base URL of Gerrit.
+	URL *url.URL
+
+	// The username and HTTP password credentials for accessing the server.
+	Username, Password string
+
+	// RateLimit is the self-imposed rate limiter (since Gerrit does not have a concept
+	// of rate limiting in HTTP response headers).
+	RateLimit *rate.Limiter
+}
+
+// NewClient creates a new Gerrit API client with the given base URL. If a nil
+// httpClient is provided, http.DefaultClient will be used. Both Username and
+// Password fields are required to be set before calling any APIs.
+func NewClient(baseURL *url.URL, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	httpClient = requestCounter.Doer(httpClient, func(u *url.URL) string {
+		// The second component of the Path (after the "/a" authentication
+		// prefix) maps to the type of API request we are making.
+		var category string
+		if parts := strings.SplitN(u.Path, "/", 4); len(parts) > 2 {
+			category = parts[2]
+		}
+		return category
+	})
+
+	// Normally our registry will return a default infinite limiter when nothing has been
+	// synced from config. However, we always want to ensure there is at least some form of rate
+	// limiting for Gerrit.
+	defaultLimiter := rate.NewLimiter(rateLimitRequestsPerSecond, RateLimitMaxBurstRequests)
+	l := ratelimit.DefaultRegistry.GetOrSet(baseURL.String(), defaultLimiter)
+
+	return &Client{
+		httpClient: httpClient,
+		URL:        baseURL,
+		RateLimit:  l,
+	}
+}
+
+// Pagination describes a single page of a paginated Gerrit listing.
+type Pagination struct {
+	// PerPage is the maximum number of results returned per page.
+	PerPage int
+	// Page is the 1-based index of the page to fetch.
+	Page int
+}
+
+// ListProjectsArgs are the arguments to ListProjects.
+type ListProjectsArgs struct {
+	Cursor *Pagination
+}
+
+// ListProjects returns the page of projects visible to the authenticated user
+// described by args.Cursor, sorted by name. The returned nextPage is true when
+// Gerrit reports that more projects exist beyond this page.
+func (c *Client) ListProjects(ctx context.Context, args ListProjectsArgs) (projects []*Project, nextPage bool, err error) {
+	cursor := args.Cursor
+	if cursor == nil {
+		cursor = &Pagination{PerPage: 100, Page: 1}
+	}
+	if cursor.Page < 1 {
+		cursor.Page = 1
+	}
+
+	qry := make(url.Values)
+	qry.Set("n", strconv.Itoa(cursor.PerPage))
+	qry.Set("S", strconv.Itoa((cursor.Page-1)*cursor.PerPage))
+
+	// "d" is a flag without a value that makes Gerrit include project
+	// descriptions, so it can't go through url.Values.
+	u := url.URL{Path: "a/projects/", RawQuery: qry.Encode() + "&d"}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The projects endpoint returns a map from project name to ProjectInfo,
+	// where the name is omitted from the ProjectInfo itself.
+	var res map[string]*Project
+	if err = c.do(ctx, req, &res); err != nil {
+		return nil, false, err
+	}
+
+	projects = make([]*Project, 0, len(res))
+	for name, p := range res {
+		p.Name = name
+		if p.MoreProjects {
+			nextPage = true
+			p.MoreProjects = false
+		}
+		projects = append(projects, p)
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+
+	return projects, nextPage, nil
+}
+
+// GetProject returns the project with the given name.
+func (c *Client) GetProject(ctx context.Context, name string) (*Project, error) {
+	req, err := http.NewRequest("GET", "a/projects/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Project
+	if err = c.do(ctx, req, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
+	req.URL = c.URL.ResolveReference(req.URL)
+	req.Header.Set("Accept", "application/json")
+
+	req, ht := nethttp.TraceRequest(ot.GetTracer(ctx),
+		req.WithContext(ctx),
+		nethttp.OperationName("Gerrit"),
+		nethttp.ClientTrace(false))
+	defer ht.Finish()
+
+	req.SetBasicAuth(c.Username, c.Password)
+
+	startWait := time.Now()
+	if err := c.RateLimit.Wait(ctx); err != nil {
+		return err
+	}
+
+	if d := time.Since(startWait); d > 200*time.Millisecond {
+		log15.Warn("Gerrit self-enforced API rate limit: request delayed longer than expected due to rate limit", "delay", d)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	bs, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return errors.WithStack(&httpError{
+			URL:        req.URL,
+			StatusCode: resp.StatusCode,
+			Body:       bs,
+		})
+	}
+
+	if result != nil {
+		return json.Unmarshal(bytes.TrimPrefix(bs, xssiPrefix), result)
+	}
+
+	return nil
+}
+
+// Project is a Gerrit project, as returned by the ProjectInfo entity of the
+// REST API.
+//
+// See https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#project-info
+type Project struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Parent      string    `json:"parent,omitempty"`
+	Description string    `json:"description,omitempty"`
+	State       string    `json:"state,omitempty"`
+	WebLinks    []WebLink `json:"web_links,omitempty"`
+
+	// MoreProjects is set on the last project of a listing when the listing
+	// was truncated by the requested limit.
+	MoreProjects bool `json:"_more_projects,omitempty"`
+}
+
+// WebLink is a link to an external site rendering a Gerrit resource, such as gitiles.
+type WebLink struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Project states, as returned in Project.State.
+const (
+	ProjectStateActive   = "ACTIVE"
+	ProjectStateReadOnly = "READ_ONLY"
+	ProjectStateHidden   = "HIDDEN"
+)
+
+type httpError struct {
+	StatusCode int
+	URL        *url.URL
+	Body       []byte
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("Gerrit API HTTP error: code=%d url=%q body=%q", e.StatusCode, e.URL, e.Body)
+}
+
+func (e *httpError) Unauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized
+}
+
+func (e *httpError) NotFound() bool {
+	return e.StatusCode == http.StatusNotFound
+}
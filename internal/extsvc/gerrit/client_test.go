@@ -0,0 +1,148 @@
+package gerrit
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "update testdata")
+
+func TestClient_ListProjects(t *testing.T) {
+	cli, save := NewTestClient(t, "ListProjects", *update, &url.URL{Scheme: "https", Host: "gerrit.sgdev.org", Path: "/"})
+	defer save()
+
+	timeout, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	projects := map[string]*Project{
+		"All-Projects": {
+			ID:          "All-Projects",
+			Name:        "All-Projects",
+			Description: "Access inherited by all other projects.",
+			State:       ProjectStateActive,
+		},
+		"platform/build": {
+			ID:          "platform%2Fbuild",
+			Name:        "platform/build",
+			Description: "Build system",
+			State:       ProjectStateActive,
+			WebLinks: []WebLink{
+				{Name: "browse", URL: "https://gerrit.sgdev.org/plugins/gitiles/platform/build"},
+			},
+		},
+		"tools/repo": {
+			ID:          "tools%2Frepo",
+			Name:        "tools/repo",
+			Description: "The repo tool",
+			State:       ProjectStateReadOnly,
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		ctx      context.Context
+		cursor   *Pagination
+		projects []*Project
+		nextPage bool
+		err      string
+	}{
+		{
+			name:   "timeout",
+			ctx:    timeout,
+			cursor: &Pagination{PerPage: 2, Page: 1},
+			err:    "context deadline exceeded",
+		},
+		{
+			name:     "pagination: first page",
+			cursor:   &Pagination{PerPage: 2, Page: 1},
+			projects: []*Project{projects["All-Projects"], projects["platform/build"]},
+			nextPage: true,
+		},
+		{
+			name:     "pagination: last page",
+			cursor:   &Pagination{PerPage: 2, Page: 2},
+			projects: []*Project{projects["tools/repo"]},
+			nextPage: false,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.ctx == nil {
+				tc.ctx = context.Background()
+			}
+
+			if tc.err == "" {
+				tc.err = "<nil>"
+			}
+
+			projects, nextPage, err := cli.ListProjects(tc.ctx, ListProjectsArgs{Cursor: tc.cursor})
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Errorf("error:\nhave: %q\nwant: %q", have, want)
+			}
+
+			if have, want := nextPage, tc.nextPage; have != want {
+				t.Errorf("nextPage:\nhave: %t\nwant: %t", have, want)
+			}
+
+			if have, want := projects, tc.projects; !reflect.DeepEqual(have, want) {
+				t.Error(cmp.Diff(have, want))
+			}
+		})
+	}
+}
+
+func TestClient_GetProject(t *testing.T) {
+	cli, save := NewTestClient(t, "GetProject", *update, &url.URL{Scheme: "https", Host: "gerrit.sgdev.org", Path: "/"})
+	defer save()
+
+	for _, tc := range []struct {
+		name    string
+		project string
+		want    *Project
+		err     string
+	}{
+		{
+			name:    "found",
+			project: "platform/build",
+			want: &Project{
+				ID:          "platform%2Fbuild",
+				Name:        "platform/build",
+				Parent:      "All-Projects",
+				Description: "Build system",
+				State:       ProjectStateActive,
+				WebLinks: []WebLink{
+					{Name: "browse", URL: "https://gerrit.sgdev.org/plugins/gitiles/platform/build"},
+				},
+			},
+			err: "<nil>",
+		},
+		{
+			name:    "not found",
+			project: "does-not-exist",
+			err:     `Gerrit API HTTP error: code=404 url="https://gerrit.sgdev.org/a/projects/does-not-exist" body="Not found: does-not-exist"`,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			have, err := cli.GetProject(context.Background(), tc.project)
+			if have, want := fmt.Sprint(err), tc.err; have != want {
+				t.Errorf("error:\nhave: %q\nwant: %q", have, want)
+			}
+
+			if tc.want == nil {
+				return
+			}
+
+			if !reflect.DeepEqual(have, tc.want) {
+				t.Error(cmp.Diff(have, tc.want))
+			}
+		})
+	}
+}
@@ -35,6 +35,26 @@ type Repository struct {
 	IsFork           bool   // whether the repository is a fork of another repository
 	IsArchived       bool   // whether the repository is archived on the code host
 	ViewerPermission string // ADMIN, WRITE, READ, or empty if unknown. Only the graphql api populates this. https://developer.github.com/v4/enum/repositorypermission/
+	StargazerCount   int    `json:",omitempty"` // number of users who starred the repository
+}
+
+// UnmarshalJSON implements json.Unmarshaler. In addition to the JSON encoding
+// of Repository, it accepts the stargazer count of the GraphQL API, which is
+// nested in the stargazers connection.
+func (r *Repository) UnmarshalJSON(data []byte) error {
+	type repository Repository
+	var v struct {
+		repository
+		Stargazers *struct{ TotalCount int }
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = Repository(v.repository)
+	if v.Stargazers != nil {
+		r.StargazerCount = v.Stargazers.TotalCount
+	}
+	return nil
 }
 
 // repositoryFieldsGraphQLFragment returns a GraphQL fragment that contains the fields needed to populate the
@@ -52,6 +72,7 @@ fragment RepositoryFields on Repository {
 	isFork
 	isArchived
 	viewerPermission
+	stargazers { totalCount }
 }
 	`
 	}
@@ -68,6 +89,7 @@ fragment RepositoryFields on Repository {
 	isPrivate
 	isFork
 	isArchived
+	stargazers { totalCount }
 }
 	`
 }
@@ -228,6 +250,7 @@ type restRepository struct {
 	Fork        bool
 	Archived    bool
 	Permissions restRepositoryPermissions `json:"permissions"`
+	Stargazers  int                       `json:"stargazers_count"`
 }
 
 // getRepositoryFromAPI attempts to fetch a repository from the GitHub API without use of the redis cache.
@@ -259,6 +282,7 @@ func convertRestRepo(restRepo restRepository) *Repository {
 		IsFork:           restRepo.Fork,
 		IsArchived:       restRepo.Archived,
 		ViewerPermission: convertRestRepoPermissions(restRepo.Permissions),
+		StargazerCount:   restRepo.Stargazers,
 	}
 }
 
@@ -3,6 +3,7 @@ package github
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -80,6 +81,26 @@ func newTestClientWithToken(t *testing.T, token string, cli httpcli.Doer) *Clien
 	return NewClient(apiURL, token, cli)
 }
 
+// TestRepository_UnmarshalJSON tests that repositories decode from both the
+// GraphQL API and their own JSON encoding.
+func TestRepository_UnmarshalJSON(t *testing.T) {
+	want := &Repository{ID: "i", NameWithOwner: "o/r", StargazerCount: 12}
+	for _, data := range []string{
+		// The GraphQL API
+		`{"id": "i", "nameWithOwner": "o/r", "stargazers": {"totalCount": 12}}`,
+		// The JSON encoding of Repository
+		`{"ID": "i", "NameWithOwner": "o/r", "StargazerCount": 12}`,
+	} {
+		var repo Repository
+		if err := json.Unmarshal([]byte(data), &repo); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(&repo, want) {
+			t.Errorf("%s: got repository %+v, want %+v", data, &repo, want)
+		}
+	}
+}
+
 // TestClient_GetRepository tests the behavior of GetRepository.
 func TestClient_GetRepository(t *testing.T) {
 	mock := mockHTTPResponseBody{
@@ -89,18 +110,20 @@ func TestClient_GetRepository(t *testing.T) {
 	"full_name": "o/r",
 	"description": "d",
 	"html_url": "https://github.example.com/o/r",
-	"fork": true
+	"fork": true,
+	"stargazers_count": 12
 }
 `,
 	}
 	c := newTestClient(t, &mock)
 
 	want := Repository{
-		ID:            "i",
-		NameWithOwner: "o/r",
-		Description:   "d",
-		URL:           "https://github.example.com/o/r",
-		IsFork:        true,
+		ID:             "i",
+		NameWithOwner:  "o/r",
+		Description:    "d",
+		URL:            "https://github.example.com/o/r",
+		IsFork:         true,
+		StargazerCount: 12,
 	}
 
 	repo, err := c.GetRepository(context.Background(), "owner", "repo")
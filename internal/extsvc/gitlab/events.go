@@ -0,0 +1,105 @@
+package gitlab
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Note is a comment on a GitLab merge request. GitLab also records changes
+// to a merge request, such as approvals or state transitions, as notes
+// flagged as system notes.
+type Note struct {
+	ID        int       `json:"id"`
+	Body      string    `json:"body"`
+	Author    User      `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	System    bool      `json:"system"`
+}
+
+// ToEvent returns the merge request event the note records, or nil if the
+// note is a regular comment or a system note we don't track.
+func (n *Note) ToEvent() interface{} {
+	if !n.System {
+		return nil
+	}
+
+	switch {
+	case n.Body == "approved this merge request":
+		return &ReviewApprovedEvent{Note: n}
+	case n.Body == "unapproved this merge request":
+		return &ReviewUnapprovedEvent{Note: n}
+	case n.Body == "closed":
+		return &MergeRequestClosedEvent{Note: n}
+	case n.Body == "reopened":
+		return &MergeRequestReopenedEvent{Note: n}
+	case n.Body == "merged", strings.HasPrefix(n.Body, "merged "):
+		return &MergeRequestMergedEvent{Note: n}
+	}
+
+	return nil
+}
+
+// noteKey returns the deduplication key of a note based event. Events
+// received through webhooks carry neither a note ID nor the time the note was
+// created, so the key is derived from the kind and author of the event alone:
+// each key holds the latest event of its kind by an author, which is all that
+// is needed to compute the state of a merge request.
+func noteKey(kind string, n *Note) string {
+	return fmt.Sprintf("%s:%s", kind, n.Author.Username)
+}
+
+// ReviewApprovedEvent is recorded when a user approves a merge request.
+type ReviewApprovedEvent struct{ *Note }
+
+func (e *ReviewApprovedEvent) Key() string { return noteKey("approved", e.Note) }
+
+// ReviewUnapprovedEvent is recorded when a user revokes their approval of a
+// merge request.
+type ReviewUnapprovedEvent struct{ *Note }
+
+func (e *ReviewUnapprovedEvent) Key() string { return noteKey("unapproved", e.Note) }
+
+// MergeRequestClosedEvent is recorded when a merge request is closed.
+type MergeRequestClosedEvent struct{ *Note }
+
+func (e *MergeRequestClosedEvent) Key() string { return noteKey("closed", e.Note) }
+
+// MergeRequestReopenedEvent is recorded when a merge request is reopened.
+type MergeRequestReopenedEvent struct{ *Note }
+
+func (e *MergeRequestReopenedEvent) Key() string { return noteKey("reopened", e.Note) }
+
+// MergeRequestMergedEvent is recorded when a merge request is merged.
+type MergeRequestMergedEvent struct{ *Note }
+
+func (e *MergeRequestMergedEvent) Key() string { return noteKey("merged", e.Note) }
+
+type PipelineStatus string
+
+const (
+	PipelineStatusCreated            PipelineStatus = "created"
+	PipelineStatusWaitingForResource PipelineStatus = "waiting_for_resource"
+	PipelineStatusPreparing          PipelineStatus = "preparing"
+	PipelineStatusPending            PipelineStatus = "pending"
+	PipelineStatusRunning            PipelineStatus = "running"
+	PipelineStatusSuccess            PipelineStatus = "success"
+	PipelineStatusFailed             PipelineStatus = "failed"
+	PipelineStatusCanceled           PipelineStatus = "canceled"
+	PipelineStatusSkipped            PipelineStatus = "skipped"
+	PipelineStatusManual             PipelineStatus = "manual"
+	PipelineStatusScheduled          PipelineStatus = "scheduled"
+)
+
+// Pipeline is a GitLab CI pipeline run for a merge request.
+type Pipeline struct {
+	ID        int            `json:"id"`
+	SHA       string         `json:"sha"`
+	Ref       string         `json:"ref"`
+	Status    PipelineStatus `json:"status"`
+	WebURL    string         `json:"web_url"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (p *Pipeline) Key() string { return fmt.Sprintf("pipeline:%d", p.ID) }
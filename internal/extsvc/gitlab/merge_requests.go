@@ -0,0 +1,284 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type MergeRequestState string
+
+const (
+	MergeRequestStateOpened MergeRequestState = "opened"
+	MergeRequestStateClosed MergeRequestState = "closed"
+	MergeRequestStateLocked MergeRequestState = "locked"
+	MergeRequestStateMerged MergeRequestState = "merged"
+)
+
+// MergeRequest is a GitLab merge request (equivalent to a GitHub pull
+// request).
+type MergeRequest struct {
+	ID              int               `json:"id"`
+	IID             int               `json:"iid"`
+	ProjectID       int               `json:"project_id"`
+	SourceProjectID int               `json:"source_project_id"`
+	Title           string            `json:"title"`
+	Description     string            `json:"description"`
+	State           MergeRequestState `json:"state"`
+	WebURL          string            `json:"web_url"`
+	SourceBranch    string            `json:"source_branch"`
+	TargetBranch    string            `json:"target_branch"`
+	SHA             string            `json:"sha"`
+	MergeCommitSHA  string            `json:"merge_commit_sha"`
+	Labels          []string          `json:"labels"`
+	Author          User              `json:"author"`
+	DiffRefs        DiffRefs          `json:"diff_refs"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	HeadPipeline    *Pipeline         `json:"head_pipeline,omitempty"`
+
+	// The fields below are not returned by the merge request endpoints and
+	// are loaded separately through LoadMergeRequestNotes and
+	// LoadMergeRequestPipelines.
+	Notes     []*Note     `json:"notes,omitempty"`
+	Pipelines []*Pipeline `json:"pipelines,omitempty"`
+}
+
+// DiffRefs are the commits a merge request's diff is computed from.
+type DiffRefs struct {
+	BaseSHA  string `json:"base_sha"`
+	HeadSHA  string `json:"head_sha"`
+	StartSHA string `json:"start_sha"`
+}
+
+// ErrMergeRequestAlreadyExists is returned by CreateMergeRequest when a merge
+// request for the given source and target branches already exists.
+var ErrMergeRequestAlreadyExists = errors.New("merge request already exists")
+
+// ErrMergeRequestNotFound is returned by GetOpenMergeRequestByRefs when no
+// open merge request exists for the given refs.
+var ErrMergeRequestNotFound = errors.New("merge request not found")
+
+// CreateMergeRequestOpts are the options for creating a merge request.
+type CreateMergeRequestOpts struct {
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	Title        string `json:"title"`
+	Description  string `json:"description,omitempty"`
+}
+
+// CreateMergeRequest creates a new merge request in the given project.
+func (c *Client) CreateMergeRequest(ctx context.Context, project *Project, opts CreateMergeRequestOpts) (*MergeRequest, error) {
+	if MockCreateMergeRequest != nil {
+		return MockCreateMergeRequest(c, ctx, project, opts)
+	}
+
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling options")
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("projects/%d/merge_requests", project.ID), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	resp := &MergeRequest{}
+	if _, err := c.do(ctx, req, resp); err != nil {
+		if HTTPErrorCode(err) == http.StatusConflict {
+			return nil, ErrMergeRequestAlreadyExists
+		}
+		return nil, errors.Wrap(err, "sending request to create a merge request")
+	}
+
+	return resp, nil
+}
+
+// GetMergeRequest retrieves the merge request with the given project-scoped
+// IID.
+func (c *Client) GetMergeRequest(ctx context.Context, project *Project, iid int) (*MergeRequest, error) {
+	if MockGetMergeRequest != nil {
+		return MockGetMergeRequest(c, ctx, project, iid)
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("projects/%d/merge_requests/%d", project.ID, iid), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	resp := &MergeRequest{}
+	if _, err := c.do(ctx, req, resp); err != nil {
+		return nil, errors.Wrap(err, "sending request to get a merge request")
+	}
+
+	return resp, nil
+}
+
+// GetOpenMergeRequestByRefs retrieves the open merge request with the given
+// source and target branches. If no such merge request exists,
+// ErrMergeRequestNotFound is returned.
+func (c *Client) GetOpenMergeRequestByRefs(ctx context.Context, project *Project, source, target string) (*MergeRequest, error) {
+	if MockGetOpenMergeRequestByRefs != nil {
+		return MockGetOpenMergeRequestByRefs(c, ctx, project, source, target)
+	}
+
+	values := url.Values{
+		"state":         []string{string(MergeRequestStateOpened)},
+		"source_branch": []string{source},
+		"target_branch": []string{target},
+	}
+	u := url.URL{Path: fmt.Sprintf("projects/%d/merge_requests", project.ID), RawQuery: values.Encode()}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	var mrs []*MergeRequest
+	if _, err := c.do(ctx, req, &mrs); err != nil {
+		return nil, errors.Wrap(err, "sending request to list merge requests")
+	}
+
+	if len(mrs) == 0 {
+		return nil, ErrMergeRequestNotFound
+	}
+	// Since GitLab only allows one open merge request per branch pair, we
+	// can return the first one.
+	return mrs[0], nil
+}
+
+// UpdateMergeRequestStateEvent is the state transition to apply when
+// updating a merge request.
+type UpdateMergeRequestStateEvent string
+
+const (
+	UpdateMergeRequestStateEventClose  UpdateMergeRequestStateEvent = "close"
+	UpdateMergeRequestStateEventReopen UpdateMergeRequestStateEvent = "reopen"
+)
+
+// UpdateMergeRequestOpts are the options for updating a merge request. Empty
+// fields are left untouched.
+type UpdateMergeRequestOpts struct {
+	TargetBranch string                       `json:"target_branch,omitempty"`
+	Title        string                       `json:"title,omitempty"`
+	Description  string                       `json:"description,omitempty"`
+	StateEvent   UpdateMergeRequestStateEvent `json:"state_event,omitempty"`
+}
+
+// UpdateMergeRequest updates the given merge request and returns the updated
+// version.
+func (c *Client) UpdateMergeRequest(ctx context.Context, project *Project, mr *MergeRequest, opts UpdateMergeRequestOpts) (*MergeRequest, error) {
+	if MockUpdateMergeRequest != nil {
+		return MockUpdateMergeRequest(c, ctx, project, mr, opts)
+	}
+
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling options")
+	}
+
+	req, err := http.NewRequest("PUT", fmt.Sprintf("projects/%d/merge_requests/%d", project.ID, mr.IID), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating request")
+	}
+
+	resp := &MergeRequest{}
+	if _, err := c.do(ctx, req, resp); err != nil {
+		return nil, errors.Wrap(err, "sending request to update a merge request")
+	}
+
+	return resp, nil
+}
+
+// LoadMergeRequestNotes loads all notes of the given merge request into its
+// Notes field, oldest first.
+func (c *Client) LoadMergeRequestNotes(ctx context.Context, project *Project, mr *MergeRequest) error {
+	if MockLoadMergeRequestNotes != nil {
+		return MockLoadMergeRequestNotes(c, ctx, project, mr)
+	}
+
+	var notes []*Note
+	err := c.paginate(ctx, fmt.Sprintf("projects/%d/merge_requests/%d/notes", project.ID, mr.IID), url.Values{
+		"sort":     []string{"asc"},
+		"order_by": []string{"created_at"},
+	}, func(page json.RawMessage) error {
+		var ns []*Note
+		if err := json.Unmarshal(page, &ns); err != nil {
+			return err
+		}
+		notes = append(notes, ns...)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "loading merge request notes")
+	}
+
+	mr.Notes = notes
+	return nil
+}
+
+// LoadMergeRequestPipelines loads all pipelines run for the given merge
+// request into its Pipelines field.
+func (c *Client) LoadMergeRequestPipelines(ctx context.Context, project *Project, mr *MergeRequest) error {
+	if MockLoadMergeRequestPipelines != nil {
+		return MockLoadMergeRequestPipelines(c, ctx, project, mr)
+	}
+
+	var pipelines []*Pipeline
+	err := c.paginate(ctx, fmt.Sprintf("projects/%d/merge_requests/%d/pipelines", project.ID, mr.IID), nil, func(page json.RawMessage) error {
+		var ps []*Pipeline
+		if err := json.Unmarshal(page, &ps); err != nil {
+			return err
+		}
+		pipelines = append(pipelines, ps...)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "loading merge request pipelines")
+	}
+
+	mr.Pipelines = pipelines
+	return nil
+}
+
+// paginate requests every page of the list endpoint at path and calls f with
+// the raw JSON of each page. See
+// https://docs.gitlab.com/ee/api/README.html#pagination.
+func (c *Client) paginate(ctx context.Context, path string, values url.Values, f func(json.RawMessage) error) error {
+	if values == nil {
+		values = url.Values{}
+	}
+	values.Set("per_page", "100")
+
+	for page := 1; page != 0; {
+		values.Set("page", strconv.Itoa(page))
+		u := url.URL{Path: path, RawQuery: values.Encode()}
+
+		req, err := http.NewRequest("GET", u.String(), nil)
+		if err != nil {
+			return err
+		}
+
+		var raw json.RawMessage
+		header, err := c.do(ctx, req, &raw)
+		if err != nil {
+			return err
+		}
+
+		if err := f(raw); err != nil {
+			return err
+		}
+
+		// X-Next-Page is empty on the last page.
+		page, _ = strconv.Atoi(header.Get("X-Next-Page"))
+	}
+
+	return nil
+}
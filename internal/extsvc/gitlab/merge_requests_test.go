@@ -0,0 +1,207 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// mockHTTPPages returns one response body per page, as requested by the
+// "page" query parameter, and sets X-Next-Page accordingly.
+type mockHTTPPages struct {
+	pages    []string
+	requests []*http.Request
+}
+
+func (s *mockHTTPPages) Do(req *http.Request) (*http.Response, error) {
+	s.requests = append(s.requests, req)
+
+	page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+	if page == 0 {
+		page = 1
+	}
+
+	header := http.Header{}
+	if page < len(s.pages) {
+		header.Set("X-Next-Page", strconv.Itoa(page+1))
+	}
+
+	return &http.Response{
+		Request:    req,
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(s.pages[page-1])),
+	}, nil
+}
+
+type mockHTTPRecorder struct {
+	mockHTTPResponseBody
+	statusCode int
+	req        *http.Request
+	body       []byte
+}
+
+func (s *mockHTTPRecorder) Do(req *http.Request) (*http.Response, error) {
+	s.req = req
+	if req.Body != nil {
+		s.body, _ = ioutil.ReadAll(req.Body)
+	}
+	resp, err := s.mockHTTPResponseBody.Do(req)
+	if s.statusCode != 0 {
+		resp.StatusCode = s.statusCode
+	}
+	return resp, err
+}
+
+func TestClient_CreateMergeRequest(t *testing.T) {
+	project := &Project{ProjectCommon: ProjectCommon{ID: 42}}
+	opts := CreateMergeRequestOpts{
+		SourceBranch: "campaigns/fix",
+		TargetBranch: "master",
+		Title:        "Fix all the things",
+		Description:  "This fixes all the things",
+	}
+
+	t.Run("created", func(t *testing.T) {
+		mock := &mockHTTPRecorder{
+			statusCode:           http.StatusCreated,
+			mockHTTPResponseBody: mockHTTPResponseBody{responseBody: `{"id": 1, "iid": 2, "project_id": 42, "title": "Fix all the things", "state": "opened"}`},
+		}
+		c := newTestClient(t)
+		c.httpClient = mock
+
+		mr, err := c.CreateMergeRequest(context.Background(), project, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := &MergeRequest{ID: 1, IID: 2, ProjectID: 42, Title: "Fix all the things", State: MergeRequestStateOpened}
+		if diff := cmp.Diff(want, mr); diff != "" {
+			t.Fatal(diff)
+		}
+
+		if have, want := mock.req.Method, "POST"; have != want {
+			t.Errorf("wrong method: have %q, want %q", have, want)
+		}
+		if have, want := mock.req.URL.Path, "/projects/42/merge_requests"; have != want {
+			t.Errorf("wrong path: have %q, want %q", have, want)
+		}
+
+		var sent CreateMergeRequestOpts
+		if err := json.Unmarshal(mock.body, &sent); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(opts, sent); diff != "" {
+			t.Errorf("wrong request body: %s", diff)
+		}
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		c := newTestClient(t)
+		c.httpClient = &mockHTTPEmptyResponse{http.StatusConflict}
+
+		_, err := c.CreateMergeRequest(context.Background(), project, opts)
+		if err != ErrMergeRequestAlreadyExists {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClient_GetOpenMergeRequestByRefs(t *testing.T) {
+	project := &Project{ProjectCommon: ProjectCommon{ID: 42}}
+
+	t.Run("found", func(t *testing.T) {
+		mock := &mockHTTPRecorder{
+			mockHTTPResponseBody: mockHTTPResponseBody{responseBody: `[{"iid": 2}]`},
+		}
+		c := newTestClient(t)
+		c.httpClient = mock
+
+		mr, err := c.GetOpenMergeRequestByRefs(context.Background(), project, "campaigns/fix", "master")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mr.IID != 2 {
+			t.Errorf("wrong merge request: %+v", mr)
+		}
+
+		q := mock.req.URL.Query()
+		for k, want := range map[string]string{"state": "opened", "source_branch": "campaigns/fix", "target_branch": "master"} {
+			if have := q.Get(k); have != want {
+				t.Errorf("wrong %s: have %q, want %q", k, have, want)
+			}
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		c := newTestClient(t)
+		c.httpClient = &mockHTTPResponseBody{responseBody: `[]`}
+
+		_, err := c.GetOpenMergeRequestByRefs(context.Background(), project, "campaigns/fix", "master")
+		if err != ErrMergeRequestNotFound {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestClient_UpdateMergeRequest(t *testing.T) {
+	project := &Project{ProjectCommon: ProjectCommon{ID: 42}}
+	mock := &mockHTTPRecorder{
+		mockHTTPResponseBody: mockHTTPResponseBody{responseBody: `{"iid": 2, "state": "closed"}`},
+	}
+	c := newTestClient(t)
+	c.httpClient = mock
+
+	mr, err := c.UpdateMergeRequest(context.Background(), project, &MergeRequest{IID: 2}, UpdateMergeRequestOpts{
+		StateEvent: UpdateMergeRequestStateEventClose,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mr.State != MergeRequestStateClosed {
+		t.Errorf("wrong state: %q", mr.State)
+	}
+
+	if have, want := mock.req.Method, "PUT"; have != want {
+		t.Errorf("wrong method: have %q, want %q", have, want)
+	}
+	if have, want := mock.req.URL.Path, "/projects/42/merge_requests/2"; have != want {
+		t.Errorf("wrong path: have %q, want %q", have, want)
+	}
+	if have, want := string(mock.body), `{"state_event":"close"}`; have != want {
+		t.Errorf("wrong request body: have %q, want %q", have, want)
+	}
+}
+
+func TestClient_LoadMergeRequestNotes(t *testing.T) {
+	project := &Project{ProjectCommon: ProjectCommon{ID: 42}}
+	mock := &mockHTTPPages{pages: []string{
+		`[{"id": 1, "body": "approved this merge request", "system": true}]`,
+		`[{"id": 2, "body": "looks good"}]`,
+	}}
+	c := newTestClient(t)
+	c.httpClient = mock
+
+	mr := &MergeRequest{IID: 2}
+	if err := c.LoadMergeRequestNotes(context.Background(), project, mr); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*Note{
+		{ID: 1, Body: "approved this merge request", System: true},
+		{ID: 2, Body: "looks good"},
+	}
+	if diff := cmp.Diff(want, mr.Notes); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if have, want := len(mock.requests), 2; have != want {
+		t.Errorf("wrong number of requests: have %d, want %d", have, want)
+	}
+}
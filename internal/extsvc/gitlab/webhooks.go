@@ -0,0 +1,138 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	eventTypeHeader = "X-Gitlab-Event"
+	tokenHeader     = "X-Gitlab-Token"
+)
+
+// WebhookEventType returns the type of the webhook event sent in r.
+func WebhookEventType(r *http.Request) string {
+	return r.Header.Get(eventTypeHeader)
+}
+
+// WebhookToken returns the secret token GitLab sent along with the webhook
+// event in r. GitLab doesn't sign payloads, it sends the configured secret
+// as is.
+func WebhookToken(r *http.Request) string {
+	return r.Header.Get(tokenHeader)
+}
+
+// ParseWebhookEvent parses the payload of a GitLab webhook event of the given
+// type.
+func ParseWebhookEvent(eventType string, payload []byte) (e interface{}, err error) {
+	switch eventType {
+	case "Merge Request Hook":
+		e = &MergeRequestWebhookEvent{}
+		return e, json.Unmarshal(payload, e)
+	case "Pipeline Hook":
+		e = &PipelineWebhookEvent{}
+		return e, json.Unmarshal(payload, e)
+	case "Push Hook", "Tag Push Hook":
+		e = &PushWebhookEvent{}
+		return e, json.Unmarshal(payload, e)
+	default:
+		return nil, fmt.Errorf("unknown webhook event type: %q", eventType)
+	}
+}
+
+// MergeRequestWebhookEvent is sent when a merge request is created, updated,
+// approved, closed, reopened or merged.
+type MergeRequestWebhookEvent struct {
+	User             User          `json:"user"`
+	Project          ProjectCommon `json:"project"`
+	ObjectAttributes struct {
+		IID       int               `json:"iid"`
+		State     MergeRequestState `json:"state"`
+		Action    string            `json:"action"`
+		UpdatedAt WebhookTime       `json:"updated_at"`
+	} `json:"object_attributes"`
+}
+
+// ToEvent returns the merge request event corresponding to the webhook
+// event, or nil if the action isn't one we track.
+func (e *MergeRequestWebhookEvent) ToEvent() interface{} {
+	note := &Note{
+		Author:    e.User,
+		CreatedAt: e.ObjectAttributes.UpdatedAt.Time,
+		System:    true,
+	}
+
+	switch e.ObjectAttributes.Action {
+	case "approved":
+		note.Body = "approved this merge request"
+	case "unapproved":
+		note.Body = "unapproved this merge request"
+	case "close":
+		note.Body = "closed"
+	case "reopen":
+		note.Body = "reopened"
+	case "merge":
+		note.Body = "merged"
+	default:
+		return nil
+	}
+
+	return note.ToEvent()
+}
+
+// PipelineWebhookEvent is sent when the status of a pipeline changes.
+type PipelineWebhookEvent struct {
+	Project          ProjectCommon `json:"project"`
+	ObjectAttributes struct {
+		ID        int            `json:"id"`
+		SHA       string         `json:"sha"`
+		Ref       string         `json:"ref"`
+		Status    PipelineStatus `json:"status"`
+		CreatedAt WebhookTime    `json:"created_at"`
+	} `json:"object_attributes"`
+	// MergeRequest is only set for pipelines run for a merge request.
+	MergeRequest *struct {
+		IID int `json:"iid"`
+	} `json:"merge_request"`
+}
+
+// Pipeline returns the pipeline the webhook event describes.
+func (e *PipelineWebhookEvent) Pipeline() *Pipeline {
+	return &Pipeline{
+		ID:        e.ObjectAttributes.ID,
+		SHA:       e.ObjectAttributes.SHA,
+		Ref:       e.ObjectAttributes.Ref,
+		Status:    e.ObjectAttributes.Status,
+		CreatedAt: e.ObjectAttributes.CreatedAt.Time,
+	}
+}
+
+// PushWebhookEvent is sent when branches or tags are pushed to a project.
+type PushWebhookEvent struct {
+	Project ProjectCommon `json:"project"`
+	Ref     string        `json:"ref"`
+}
+
+// WebhookTime is a timestamp in a webhook payload. Depending on the version,
+// GitLab sends either RFC 3339 timestamps or timestamps of the form
+// "2006-01-02 15:04:05 UTC".
+type WebhookTime struct{ time.Time }
+
+func (t *WebhookTime) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"} {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown timestamp format: %q", s)
+}
@@ -0,0 +1,106 @@
+package gitlab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseWebhookEvent(t *testing.T) {
+	t.Run("merge request approved", func(t *testing.T) {
+		payload := `{
+			"object_kind": "merge_request",
+			"user": {"name": "Jane Doe", "username": "jane-doe"},
+			"project": {"id": 42, "path_with_namespace": "group/project"},
+			"object_attributes": {"iid": 2, "state": "opened", "action": "approved", "updated_at": "2020-06-15 09:04:05 UTC"}
+		}`
+
+		e, err := ParseWebhookEvent("Merge Request Hook", []byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mre, ok := e.(*MergeRequestWebhookEvent)
+		if !ok {
+			t.Fatalf("unexpected event type %T", e)
+		}
+		if mre.Project.ID != 42 || mre.ObjectAttributes.IID != 2 {
+			t.Fatalf("unexpected event: %+v", mre)
+		}
+
+		want := &ReviewApprovedEvent{Note: &Note{
+			Body:      "approved this merge request",
+			Author:    User{Name: "Jane Doe", Username: "jane-doe"},
+			CreatedAt: time.Date(2020, 6, 15, 9, 4, 5, 0, time.UTC),
+			System:    true,
+		}}
+		if diff := cmp.Diff(want, mre.ToEvent()); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("merge request updated", func(t *testing.T) {
+		payload := `{"object_attributes": {"iid": 2, "action": "update", "updated_at": "2020-06-15T09:04:05Z"}}`
+
+		e, err := ParseWebhookEvent("Merge Request Hook", []byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev := e.(*MergeRequestWebhookEvent).ToEvent(); ev != nil {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	})
+
+	t.Run("pipeline", func(t *testing.T) {
+		payload := `{
+			"object_kind": "pipeline",
+			"project": {"id": 42},
+			"object_attributes": {"id": 7, "sha": "deadbeef", "ref": "campaigns/fix", "status": "failed", "created_at": "2020-06-15 09:04:05 UTC"},
+			"merge_request": {"iid": 2}
+		}`
+
+		e, err := ParseWebhookEvent("Pipeline Hook", []byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pe := e.(*PipelineWebhookEvent)
+		if pe.MergeRequest == nil || pe.MergeRequest.IID != 2 {
+			t.Fatalf("unexpected merge request: %+v", pe.MergeRequest)
+		}
+
+		want := &Pipeline{
+			ID:        7,
+			SHA:       "deadbeef",
+			Ref:       "campaigns/fix",
+			Status:    PipelineStatusFailed,
+			CreatedAt: time.Date(2020, 6, 15, 9, 4, 5, 0, time.UTC),
+		}
+		if diff := cmp.Diff(want, pe.Pipeline()); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("push", func(t *testing.T) {
+		payload := `{"object_kind": "push", "ref": "refs/heads/master", "project": {"id": 42}}`
+
+		for _, eventType := range []string{"Push Hook", "Tag Push Hook"} {
+			e, err := ParseWebhookEvent(eventType, []byte(payload))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := &PushWebhookEvent{Project: ProjectCommon{ID: 42}, Ref: "refs/heads/master"}
+			if diff := cmp.Diff(want, e); diff != "" {
+				t.Fatal(diff)
+			}
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		if _, err := ParseWebhookEvent("Issue Hook", []byte(`{}`)); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
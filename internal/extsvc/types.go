@@ -76,6 +76,7 @@ const (
 	KindAWSCodeCommit   = "AWSCODECOMMIT"
 	KindBitbucketServer = "BITBUCKETSERVER"
 	KindBitbucketCloud  = "BITBUCKETCLOUD"
+	KindGerrit          = "GERRIT"
 	KindGitHub          = "GITHUB"
 	KindGitLab          = "GITLAB"
 	KindGitolite        = "GITOLITE"
@@ -99,6 +100,10 @@ const (
 	// ServiceID value is the base URL to the Bitbucket Cloud.
 	TypeBitbucketCloud = "bitbucketCloud"
 
+	// TypeGerrit is the (api.ExternalRepoSpec).ServiceType value for Gerrit projects. The ServiceID
+	// value is the base URL to the Gerrit instance.
+	TypeGerrit = "gerrit"
+
 	// TypeGitHub is the (api.ExternalRepoSpec).ServiceType value for GitHub repositories. The ServiceID value
 	// is the base URL to the GitHub instance (https://github.com or the GitHub Enterprise URL).
 	TypeGitHub = "github"
@@ -127,6 +132,8 @@ func KindToType(kind string) string {
 		return TypeBitbucketServer
 	case KindBitbucketCloud:
 		return TypeBitbucketCloud
+	case KindGerrit:
+		return TypeGerrit
 	case KindGitHub:
 		return TypeGitHub
 	case KindGitLab:
@@ -158,6 +165,8 @@ func ParseServiceType(s string) (string, bool) {
 		return TypeBitbucketServer, true
 	case bbcLower:
 		return TypeBitbucketCloud, true
+	case TypeGerrit:
+		return TypeGerrit, true
 	case TypeGitHub:
 		return TypeGitHub, true
 	case TypeGitLab:
@@ -192,6 +201,8 @@ func ParseConfig(kind, config string) (cfg interface{}, _ error) {
 		cfg = &schema.BitbucketServerConnection{}
 	case KindBitbucketCloud:
 		cfg = &schema.BitbucketCloudConnection{}
+	case KindGerrit:
+		cfg = &schema.GerritConnection{}
 	case KindGitHub:
 		cfg = &schema.GitHubConnection{}
 	case KindGitLab:
@@ -215,6 +226,8 @@ func WebhookURL(kind string, externalServiceID int64, externalURL string) string
 	switch strings.ToUpper(kind) {
 	case KindGitHub:
 		path = "github-webhooks"
+	case KindGitLab:
+		path = "gitlab-webhooks"
 	case KindBitbucketServer:
 		path = "bitbucket-server-webhooks"
 	default:
@@ -255,6 +268,8 @@ func ExtractBaseURL(kind, config string) (*url.URL, error) {
 		return nil, errors.New("BaseURL unavailable for AWSCodeCommit")
 	case *schema.BitbucketServerConnection:
 		rawURL = c.Url
+	case *schema.GerritConnection:
+		rawURL = c.Url
 	case *schema.GitHubConnection:
 		rawURL = c.Url
 	case *schema.GitLabConnection:
@@ -326,6 +341,14 @@ func GetLimitFromConfig(kind string, config interface{}) (rlc RateLimitConfig, e
 			rlc.IsDefault = false
 		}
 		rlc.BaseURL = c.Url
+	case *schema.GerritConnection:
+		// 8/s is the default limit we enforce
+		rlc.Limit = rate.Limit(8)
+		if c != nil && c.RateLimit != nil {
+			rlc.Limit = limitOrInf(c.RateLimit.Enabled, c.RateLimit.RequestsPerHour)
+			rlc.IsDefault = false
+		}
+		rlc.BaseURL = c.Url
 	default:
 		return rlc, ErrRateLimitUnsupported{codehostKind: kind}
 	}
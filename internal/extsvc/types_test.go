@@ -61,6 +61,18 @@ func TestExtractRateLimitConfig(t *testing.T) {
 				IsDefault:   true,
 			},
 		},
+		{
+			name:        "Gerrit default",
+			config:      `{"url": "https://example.com/"}`,
+			kind:        KindGerrit,
+			displayName: "Gerrit 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gerrit 1",
+				Limit:       8.0,
+				IsDefault:   true,
+			},
+		},
 		{
 			name:        "GitLab non-default",
 			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
@@ -109,6 +121,18 @@ func TestExtractRateLimitConfig(t *testing.T) {
 				IsDefault:   false,
 			},
 		},
+		{
+			name:        "Gerrit non-default",
+			config:      `{"url": "https://example.com/", "rateLimit": {"enabled": true, "requestsPerHour": 3600}}`,
+			kind:        KindGerrit,
+			displayName: "Gerrit 1",
+			want: RateLimitConfig{
+				BaseURL:     "https://example.com/",
+				DisplayName: "Gerrit 1",
+				Limit:       1.0,
+				IsDefault:   false,
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			rlc, err := ExtractRateLimitConfig(tc.config, tc.kind, tc.displayName)
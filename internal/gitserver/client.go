@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -60,6 +61,9 @@ func NewClient(cli httpcli.Doer) *Client {
 		Addrs: func(ctx context.Context) []string {
 			return conf.Get().ServiceConnections.GitServers
 		},
+		ReplicationFactor: func() int {
+			return conf.Get().GitserverReplicationFactor
+		},
 		HTTPClient:  cli,
 		HTTPLimiter: parallel.NewRun(500),
 		// Use the binary name for UserAgent. This should effectively identify
@@ -82,9 +86,20 @@ type Client struct {
 	// concurrent use. It may return different results at different times.
 	Addrs func(ctx context.Context) []string
 
+	// ReplicationFactor is a function which should return the number of
+	// gitservers that store each repo. If it is nil or returns less than 1,
+	// each repo is stored on a single gitserver.
+	ReplicationFactor func() int
+
 	// UserAgent is a string identifing who the client is. It will be logged in
 	// the telemetry in gitserver.
 	UserAgent string
+
+	healthMu sync.Mutex
+	health   map[string]addrHealth // cached ping results by gitserver address
+
+	migrationMu sync.Mutex
+	migration   map[string]repoMigration // cached clone states by gitserver address and repo
 }
 
 // AddrForRepo returns the gitserver address to use for the given repo name.
@@ -103,7 +118,235 @@ func (c *Client) addrForKey(ctx context.Context, key string) string {
 	return addrForKey(addrs, key)
 }
 
+// AddrForRepo returns the address among addrs of the gitserver that stores
+// the given repo.
+func AddrForRepo(repo api.RepoName, addrs []string) string {
+	return addrForKey(addrs, string(protocol.NormalizeRepo(repo)))
+}
+
+// AddrsForRepo returns the addresses among addrs of the n gitservers that
+// store the given repo when it is replicated. The first is the one returned
+// by AddrForRepo, which the repo is assigned to, followed by the replicas in
+// order of preference. n is capped at the number of addrs.
+func AddrsForRepo(repo api.RepoName, addrs []string, n int) []string {
+	key := string(protocol.NormalizeRepo(repo))
+	if n <= 1 {
+		return []string{addrForKey(addrs, key)}
+	}
+
+	scores := make([]uint64, len(addrs))
+	ranked := make([]int, len(addrs))
+	for i, addr := range addrs {
+		scores[i] = rendezvousScore(addr, key)
+		ranked[i] = i
+	}
+	// The stable sort breaks ties the same way as addrForKey.
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i]] > scores[ranked[j]]
+	})
+
+	if n > len(addrs) {
+		n = len(addrs)
+	}
+	replicas := make([]string, n)
+	for i := range replicas {
+		replicas[i] = addrs[ranked[i]]
+	}
+	return replicas
+}
+
+// ReplicaAddrsForRepo returns the addresses of the gitservers that store the
+// given repo, starting with the one it is assigned to. See AddrsForRepo.
+func (c *Client) ReplicaAddrsForRepo(ctx context.Context, repo api.RepoName) []string {
+	addrs := c.Addrs(ctx)
+	if len(addrs) == 0 {
+		panic("unexpected state: no gitserver addresses")
+	}
+	return AddrsForRepo(repo, addrs, c.replicationFactor())
+}
+
+func (c *Client) replicationFactor() int {
+	if c.ReplicationFactor == nil {
+		return 1
+	}
+	if n := c.ReplicationFactor(); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// ReadAddrForRepo returns the address of the gitserver to read the given repo
+// from. It is the gitserver the repo is assigned to, unless that gitserver
+// doesn't respond to pings and a replica does. While repos move to the
+// gitservers rendezvous hashing assigns them to, reads are sent to the
+// gitserver that stored the repo before until the new one has cloned it.
+func (c *Client) ReadAddrForRepo(ctx context.Context, repo api.RepoName) string {
+	addr := c.healthyAddrForRepo(ctx, repo)
+	if legacy := c.legacyReadAddr(ctx, repo, addr); legacy != "" {
+		return legacy
+	}
+	return addr
+}
+
+// healthyAddrForRepo returns the first of the gitservers storing the repo that
+// responds to pings.
+func (c *Client) healthyAddrForRepo(ctx context.Context, repo api.RepoName) string {
+	replicas := c.ReplicaAddrsForRepo(ctx, repo)
+	if len(replicas) == 1 {
+		return replicas[0]
+	}
+	for i, addr := range replicas {
+		if c.healthy(ctx, addr) {
+			if i > 0 {
+				replicaFailoverCounter.Inc()
+			}
+			return addr
+		}
+	}
+	// None respond, so the error is reported for the one it is assigned to.
+	return replicas[0]
+}
+
+type repoMigration struct {
+	cloned       bool // the repo is cloned on the gitserver it is read from
+	legacyCloned bool // the repo is cloned on the gitserver it was assigned to by legacyAddrForKey
+	checked      time.Time
+}
+
+// legacyReadAddr returns the address of the gitserver the repo was assigned to
+// before rendezvous hashing (see legacyAddrForKey) if the repo is still cloned
+// there but not yet on addr. Otherwise, it returns "". A repo cloned on addr
+// stays cloned, so this is only checked again for repos that aren't, after
+// healthCheckTTL.
+func (c *Client) legacyReadAddr(ctx context.Context, repo api.RepoName, addr string) string {
+	addrs := c.Addrs(ctx)
+	legacy := legacyAddrForKey(addrs, string(protocol.NormalizeRepo(repo)))
+	if legacy == addr {
+		return ""
+	}
+
+	key := addr + "/" + string(protocol.NormalizeRepo(repo))
+	c.migrationMu.Lock()
+	m, ok := c.migration[key]
+	c.migrationMu.Unlock()
+	if !ok || (!m.cloned && time.Since(m.checked) >= healthCheckTTL) {
+		cloned, err := c.IsRepoClonedOn(ctx, addr, repo)
+		if err != nil {
+			// The error is reported by the read itself.
+			return ""
+		}
+		m = repoMigration{cloned: cloned, checked: time.Now()}
+		if !cloned {
+			m.legacyCloned, _ = c.IsRepoClonedOn(ctx, legacy, repo)
+		}
+
+		c.migrationMu.Lock()
+		if c.migration == nil {
+			c.migration = make(map[string]repoMigration)
+		}
+		c.migration[key] = m
+		c.migrationMu.Unlock()
+	}
+
+	if m.cloned || !m.legacyCloned {
+		return ""
+	}
+	legacyReadCounter.Inc()
+	return legacy
+}
+
+const (
+	// healthCheckTTL is how long the result of a ping of a gitserver is used
+	// to decide whether reads fail over to a replica.
+	healthCheckTTL = 5 * time.Second
+
+	// healthCheckTimeout is how long a gitserver has to respond to a ping
+	// before it is considered unhealthy.
+	healthCheckTimeout = time.Second
+)
+
+type addrHealth struct {
+	ok      bool
+	checked time.Time
+}
+
+// healthy reports whether the gitserver at addr responds to pings. The result
+// is cached for healthCheckTTL.
+func (c *Client) healthy(ctx context.Context, addr string) bool {
+	c.healthMu.Lock()
+	h, ok := c.health[addr]
+	c.healthMu.Unlock()
+	if ok && time.Since(h.checked) < healthCheckTTL {
+		return h.ok
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	err := c.ping(pingCtx, addr)
+	if err != nil && ctx.Err() != nil {
+		// The caller gave up, which says nothing about the gitserver.
+		return true
+	}
+	h = addrHealth{ok: err == nil, checked: time.Now()}
+
+	c.healthMu.Lock()
+	if c.health == nil {
+		c.health = make(map[string]addrHealth)
+	}
+	c.health[addr] = h
+	c.healthMu.Unlock()
+	return h.ok
+}
+
+// PreviousAddrsForRepo returns the addresses among addrs, other than addr, of
+// the gitservers that may have stored the given repo before it was assigned to
+// addr: the one it would be assigned to without addr, and the one it was
+// assigned to before rendezvous hashing was used.
+func PreviousAddrsForRepo(repo api.RepoName, addrs []string, addr string) []string {
+	key := string(protocol.NormalizeRepo(repo))
+
+	others := make([]string, 0, len(addrs))
+	for _, a := range addrs {
+		if a != addr {
+			others = append(others, a)
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+
+	prev := []string{addrForKey(others, key)}
+	if legacy := legacyAddrForKey(addrs, key); legacy != addr && legacy != prev[0] {
+		prev = append(prev, legacy)
+	}
+	return prev
+}
+
+// addrForKey assigns the key to one of addrs with rendezvous hashing: the
+// address with the highest score for the key wins. When an address is added,
+// only the keys it wins move to it, and when one is removed, only its keys
+// move, so that few repos are recloned when gitservers are added or removed.
 func addrForKey(addrs []string, key string) string {
+	var (
+		best      string
+		bestScore uint64
+	)
+	for i, addr := range addrs {
+		if score := rendezvousScore(addr, key); i == 0 || score > bestScore {
+			best, bestScore = addr, score
+		}
+	}
+	return best
+}
+
+func rendezvousScore(addr, key string) uint64 {
+	sum := md5.Sum([]byte(addr + "\x00" + key))
+	return binary.BigEndian.Uint64(sum[:])
+}
+
+// legacyAddrForKey assigns the key to one of addrs the way it was done before
+// rendezvous hashing, which moves almost every key when addrs change.
+func legacyAddrForKey(addrs []string, key string) string {
 	sum := md5.Sum([]byte(key))
 	serverIndex := binary.BigEndian.Uint64(sum[:]) % uint64(len(addrs))
 	return addrs[serverIndex]
@@ -156,7 +399,7 @@ func (c *Client) ArchiveURL(ctx context.Context, repo Repo, opt ArchiveOptions)
 
 	return &url.URL{
 		Scheme:   "http",
-		Host:     c.AddrForRepo(ctx, repo.Name),
+		Host:     c.ReadAddrForRepo(ctx, repo.Name),
 		Path:     "/archive",
 		RawQuery: q.Encode(),
 	}
@@ -278,8 +521,20 @@ var deadlineExceededCounter = prometheus.NewCounter(prometheus.CounterOpts{
 	Help: "Times that Client.sendExec() returned context.DeadlineExceeded",
 })
 
+var replicaFailoverCounter = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "src_gitserver_client_replica_failover",
+	Help: "Times that a read was sent to a replica because the gitserver the repo is assigned to didn't respond to pings",
+})
+
+var legacyReadCounter = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "src_gitserver_client_legacy_read",
+	Help: "Times that a read was sent to the gitserver a repo was assigned to before rendezvous hashing because the one it is assigned to now hasn't cloned it yet",
+})
+
 func init() {
 	prometheus.MustRegister(deadlineExceededCounter)
+	prometheus.MustRegister(replicaFailoverCounter)
+	prometheus.MustRegister(legacyReadCounter)
 }
 
 // Cmd represents a command to be executed remotely.
@@ -560,12 +815,40 @@ func (c *Client) doListOne(ctx context.Context, urlSuffix, addr string) ([]strin
 // recently (within the Since duration specified in the request), the
 // update won't happen.
 func (c *Client) RequestRepoUpdate(ctx context.Context, repo Repo, since time.Duration) (*protocol.RepoUpdateResponse, error) {
+	return c.requestRepoUpdate(ctx, repo, "repo-update", since)
+}
+
+// RequestReplicaRepoUpdate is like RequestRepoUpdate, but requests the update
+// of the replica of the repo on the gitserver at addr. It is used by the
+// gitserver the repo is assigned to to forward updates to its replicas.
+func (c *Client) RequestReplicaRepoUpdate(ctx context.Context, addr string, repo Repo, since time.Duration) (*protocol.RepoUpdateResponse, error) {
+	return c.requestRepoUpdate(ctx, repo, "http://"+addr+"/repo-update", since)
+}
+
+// RequestReplicaRefUpdate requests the gitserver at addr to set a ref of its
+// replica of the repo, fetching the commit from the gitserver at req.Peer. It
+// is used by the gitserver the repo is assigned to to forward refs that
+// aren't fetched from the code host to its replicas.
+func (c *Client) RequestReplicaRefUpdate(ctx context.Context, addr string, req protocol.ReplicaRefUpdateRequest) error {
+	resp, err := c.httpPost(ctx, req.Repo, "http://"+addr+"/replica-ref-update", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
+		return &url.Error{URL: resp.Request.URL.String(), Op: "ReplicaRefUpdate", Err: fmt.Errorf("ReplicaRefUpdate: http status %d: %s", resp.StatusCode, body)}
+	}
+	return nil
+}
+
+func (c *Client) requestRepoUpdate(ctx context.Context, repo Repo, op string, since time.Duration) (*protocol.RepoUpdateResponse, error) {
 	req := &protocol.RepoUpdateRequest{
 		Repo:  repo.Name,
 		URL:   repo.URL,
 		Since: since,
 	}
-	resp, err := c.httpPost(ctx, repo.Name, "repo-update", req)
+	resp, err := c.httpPost(ctx, repo.Name, op, req)
 	if err != nil {
 		return nil, err
 	}
@@ -657,12 +940,24 @@ func (c *Client) IsRepoCloned(ctx context.Context, repo api.RepoName) (bool, err
 	return cloned, nil
 }
 
+// IsRepoClonedOn reports whether the repo is cloned on the gitserver at addr,
+// rather than on the gitserver it is read from.
+func (c *Client) IsRepoClonedOn(ctx context.Context, addr string, repo api.RepoName) (bool, error) {
+	resp, err := c.httpPost(ctx, repo, "http://"+addr+"/is-repo-cloned", &protocol.IsRepoClonedRequest{Repo: repo})
+	if err != nil {
+		return false, err
+	}
+	// no need to defer, we aren't using the body.
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK, nil
+}
+
 func (c *Client) RepoCloneProgress(ctx context.Context, repos ...api.RepoName) (*protocol.RepoCloneProgressResponse, error) {
 	numPossibleShards := len(c.Addrs(ctx))
 	shards := make(map[string]*protocol.RepoCloneProgressRequest, (len(repos)/numPossibleShards)*2) // 2x because it may not be a perfect division
 
 	for _, r := range repos {
-		addr := c.AddrForRepo(ctx, r)
+		addr := c.ReadAddrForRepo(ctx, r)
 		shard := shards[addr]
 
 		if shard == nil {
@@ -674,16 +969,17 @@ func (c *Client) RepoCloneProgress(ctx context.Context, repos ...api.RepoName) (
 	}
 
 	type op struct {
-		req *protocol.RepoCloneProgressRequest
-		res *protocol.RepoCloneProgressResponse
-		err error
+		addr string
+		req  *protocol.RepoCloneProgressRequest
+		res  *protocol.RepoCloneProgressResponse
+		err  error
 	}
 
 	ch := make(chan op, len(shards))
-	for _, req := range shards {
+	for addr, req := range shards {
 		go func(o op) {
 			var resp *http.Response
-			resp, o.err = c.httpPost(ctx, o.req.Repos[0], "repo-clone-progress", o.req)
+			resp, o.err = c.httpPost(ctx, o.req.Repos[0], "http://"+o.addr+"/repo-clone-progress", o.req)
 			if o.err != nil {
 				ch <- o
 				return
@@ -703,7 +999,7 @@ func (c *Client) RepoCloneProgress(ctx context.Context, repos ...api.RepoName) (
 			o.res = new(protocol.RepoCloneProgressResponse)
 			o.err = json.NewDecoder(resp.Body).Decode(o.res)
 			ch <- o
-		}(op{req: req})
+		}(op{addr: addr, req: req})
 	}
 
 	err := new(multierror.Error)
@@ -738,29 +1034,35 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 	numPossibleShards := len(c.Addrs(ctx))
 	shards := make(map[string]*protocol.RepoInfoRequest, (len(repos)/numPossibleShards)*2) // 2x because it may not be a perfect division
 
+	// Each repo is requested from every gitserver that stores it, so that
+	// replica lag can be reported.
+	replicas := make(map[api.RepoName][]string, len(repos))
 	for _, r := range repos {
-		addr := c.AddrForRepo(ctx, r)
-		shard := shards[addr]
+		replicas[r] = c.ReplicaAddrsForRepo(ctx, r)
+		for _, addr := range replicas[r] {
+			shard := shards[addr]
 
-		if shard == nil {
-			shard = new(protocol.RepoInfoRequest)
-			shards[addr] = shard
-		}
+			if shard == nil {
+				shard = new(protocol.RepoInfoRequest)
+				shards[addr] = shard
+			}
 
-		shard.Repos = append(shard.Repos, r)
+			shard.Repos = append(shard.Repos, r)
+		}
 	}
 
 	type op struct {
-		req *protocol.RepoInfoRequest
-		res *protocol.RepoInfoResponse
-		err error
+		addr string
+		req  *protocol.RepoInfoRequest
+		res  *protocol.RepoInfoResponse
+		err  error
 	}
 
 	ch := make(chan op, len(shards))
-	for _, req := range shards {
+	for addr, req := range shards {
 		go func(o op) {
 			var resp *http.Response
-			resp, o.err = c.httpPost(ctx, o.req.Repos[0], "repos", o.req)
+			resp, o.err = c.httpPost(ctx, o.req.Repos[0], "http://"+o.addr+"/repos", o.req)
 			if o.err != nil {
 				ch <- o
 				return
@@ -780,36 +1082,107 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 			o.res = new(protocol.RepoInfoResponse)
 			o.err = json.NewDecoder(resp.Body).Decode(o.res)
 			ch <- o
-		}(op{req: req})
+		}(op{addr: addr, req: req})
+	}
+
+	ops := make(map[string]op, len(shards))
+	for i := 0; i < cap(ch); i++ {
+		o := <-ch
+		ops[o.addr] = o
 	}
 
-	err := new(multierror.Error)
 	res := protocol.RepoInfoResponse{
 		Results: make(map[api.RepoName]*protocol.RepoInfo),
 	}
 
-	for i := 0; i < cap(ch); i++ {
-		o := <-ch
-
-		if o.err != nil {
-			err = multierror.Append(err, o.err)
+	for _, r := range repos {
+		// The information comes from the gitserver the repo is assigned to,
+		// or from the first replica that responded if it didn't.
+		var (
+			info *protocol.RepoInfo
+			src  string
+		)
+		for _, addr := range replicas[r] {
+			if o := ops[addr]; o.err == nil && o.res.Results[r] != nil {
+				info, src = o.res.Results[r], addr
+				break
+			}
+		}
+		if info == nil {
 			continue
 		}
 
-		for repo, info := range o.res.Results {
-			res.Results[repo] = info
+		if len(replicas[r]) > 1 {
+			for _, addr := range replicas[r] {
+				if addr == src {
+					continue
+				}
+				o := ops[addr]
+				info.Replicas = append(info.Replicas, newReplicaInfo(addr, info, o.res, o.err, r))
+			}
+		}
+
+		res.Results[r] = info
+	}
+
+	// Errors are only reported for gitservers whose repos are missing from
+	// the results.
+	err := new(multierror.Error)
+	for _, o := range ops {
+		if o.err == nil {
+			continue
+		}
+		for _, r := range o.req.Repos {
+			if res.Results[r] == nil {
+				err = multierror.Append(err, o.err)
+				break
+			}
 		}
 	}
 
 	return &res, err.ErrorOrNil()
 }
 
-// Remove removes the repository clone from gitserver.
+// newReplicaInfo returns the information about the replica of repo on the
+// gitserver at addr, given its response to the RepoInfo request and the
+// information about the repo returned by RepoInfo.
+func newReplicaInfo(addr string, info *protocol.RepoInfo, res *protocol.RepoInfoResponse, err error, repo api.RepoName) protocol.ReplicaInfo {
+	replica := protocol.ReplicaInfo{Addr: addr}
+	if err != nil {
+		replica.Error = err.Error()
+		return replica
+	}
+
+	r := res.Results[repo]
+	if r == nil {
+		return replica
+	}
+	replica.Cloned = r.Cloned
+	replica.LastFetched = r.LastFetched
+	if info.LastFetched != nil && r.LastFetched != nil && info.LastFetched.After(*r.LastFetched) {
+		replica.Lag = info.LastFetched.Sub(*r.LastFetched)
+	}
+	return replica
+}
+
+// Remove removes the repository clone from gitserver, including its
+// replicas.
 func (c *Client) Remove(ctx context.Context, repo api.RepoName) error {
 	req := &protocol.RepoDeleteRequest{
 		Repo: repo,
 	}
-	resp, err := c.httpPost(ctx, repo, "delete", req)
+
+	err := new(multierror.Error)
+	for _, addr := range c.ReplicaAddrsForRepo(ctx, repo) {
+		if e := c.remove(ctx, addr, req); e != nil {
+			err = multierror.Append(err, e)
+		}
+	}
+	return err.ErrorOrNil()
+}
+
+func (c *Client) remove(ctx context.Context, addr string, req *protocol.RepoDeleteRequest) error {
+	resp, err := c.httpPost(ctx, req.Repo, "http://"+addr+"/delete", req)
 	if err != nil {
 		return err
 	}
@@ -826,8 +1199,17 @@ func (c *Client) httpPost(ctx context.Context, repo api.RepoName, op string, pay
 	return c.do(ctx, repo, "POST", op, payload)
 }
 
+// writeOps are the ops that change a repo. They are sent to the gitserver the
+// repo is assigned to, which forwards updates to the replicas, while other ops
+// may be sent to a replica.
+var writeOps = map[string]bool{
+	"repo-update":              true,
+	"create-commit-from-patch": true,
+}
+
 // do performs a request to a gitserver, sharding based on the given
-// repo name (the repo name is otherwise not used).
+// repo name (the repo name is otherwise not used). op is either the path of
+// the request, or a URL to send it to a specific gitserver.
 func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, payload interface{}) (resp *http.Response, err error) {
 	span, ctx := ot.StartSpanFromContext(ctx, "Client.do")
 	defer func() {
@@ -846,7 +1228,11 @@ func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, p
 
 	uri := op
 	if !strings.HasPrefix(op, "http") {
-		uri = "http://" + c.AddrForRepo(ctx, repo) + "/" + op
+		addr := c.ReadAddrForRepo(ctx, repo)
+		if writeOps[op] {
+			addr = c.AddrForRepo(ctx, repo)
+		}
+		uri = "http://" + addr + "/" + op
 	}
 
 	req, err := http.NewRequest(method, uri, bytes.NewReader(reqBody))
@@ -4,7 +4,10 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/md5"
 	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -14,7 +17,9 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -22,6 +27,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 )
 
@@ -33,7 +39,7 @@ func TestClient_ListCloned(t *testing.T) {
 			switch r.URL.String() {
 			case "http://gitserver-0/list?cloned":
 				return &http.Response{
-					Body: ioutil.NopCloser(bytes.NewBufferString(`["repo0-a", "repo0-b"]`)),
+					Body: ioutil.NopCloser(bytes.NewBufferString(`["repo0-a", "repo0-c"]`)),
 				}, nil
 			case "http://gitserver-1/list?cloned":
 				return &http.Response{
@@ -45,7 +51,8 @@ func TestClient_ListCloned(t *testing.T) {
 		}),
 	}
 
-	want := []string{"repo0-a", "repo1-a", "repo1-b"}
+	// repo0-a and repo1-a are assigned to the other gitserver.
+	want := []string{"repo0-c", "repo1-b"}
 	got, err := cli.ListCloned(context.Background())
 	if err != nil {
 		t.Fatal(err)
@@ -57,6 +64,279 @@ func TestClient_ListCloned(t *testing.T) {
 	}
 }
 
+func TestAddrForRepo(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	added := append(addrs[:len(addrs):len(addrs)], "gitserver-3")
+
+	counts := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		repo := api.RepoName(fmt.Sprintf("github.com/foo/repo%d", i))
+		addr := gitserver.AddrForRepo(repo, addrs)
+		counts[addr]++
+
+		// Adding a gitserver only moves repos to it, and the repos that
+		// moved were stored on their previous address.
+		newAddr := gitserver.AddrForRepo(repo, added)
+		if newAddr != addr {
+			if newAddr != "gitserver-3" {
+				t.Fatalf("%s moved from %s to %s", repo, addr, newAddr)
+			}
+			if prev := gitserver.PreviousAddrsForRepo(repo, added, newAddr); len(prev) == 0 || prev[0] != addr {
+				t.Fatalf("%s: got previous addresses %v, want %s first", repo, prev, addr)
+			}
+		}
+
+		// Names are normalized.
+		if got := gitserver.AddrForRepo(api.RepoName(strings.ToUpper(string(repo))+".git"), addrs); got != addr {
+			t.Fatalf("%s: got %s for the unnormalized name, want %s", repo, got, addr)
+		}
+	}
+
+	// Repos are spread evenly.
+	for _, addr := range addrs {
+		if counts[addr] < 250 {
+			t.Errorf("only %d of 1000 repos are assigned to %s", counts[addr], addr)
+		}
+	}
+}
+
+func TestPreviousAddrsForRepo(t *testing.T) {
+	if got := gitserver.PreviousAddrsForRepo("github.com/foo/bar", []string{"gitserver-0"}, "gitserver-0"); got != nil {
+		t.Errorf("got %v, want no previous addresses", got)
+	}
+
+	addrs := []string{"gitserver-0", "gitserver-1"}
+	for _, addr := range addrs {
+		for _, prev := range gitserver.PreviousAddrsForRepo("github.com/foo/bar", addrs, addr) {
+			if prev == addr {
+				t.Errorf("%s is one of its previous addresses", addr)
+			}
+		}
+	}
+}
+
+func TestAddrsForRepo(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	for i := 0; i < 100; i++ {
+		repo := api.RepoName(fmt.Sprintf("github.com/foo/repo%d", i))
+
+		replicas := gitserver.AddrsForRepo(repo, addrs, 2)
+		if len(replicas) != 2 || replicas[0] == replicas[1] {
+			t.Fatalf("%s: got replicas %v, want 2 distinct addresses", repo, replicas)
+		}
+		if want := gitserver.AddrForRepo(repo, addrs); replicas[0] != want {
+			t.Fatalf("%s: got %s first, want %s", repo, replicas[0], want)
+		}
+
+		// The replica is the gitserver the repo moves to when the one it is
+		// assigned to is removed.
+		if prev := gitserver.PreviousAddrsForRepo(repo, addrs, replicas[0]); prev[0] != replicas[1] {
+			t.Fatalf("%s: got replica %s, want %s", repo, replicas[1], prev[0])
+		}
+
+		if got := gitserver.AddrsForRepo(repo, addrs, 5); len(got) != len(addrs) {
+			t.Fatalf("%s: got %v, want all addresses", repo, got)
+		}
+		if got := gitserver.AddrsForRepo(repo, addrs, 0); len(got) != 1 || got[0] != replicas[0] {
+			t.Fatalf("%s: got %v, want [%s]", repo, got, replicas[0])
+		}
+	}
+}
+
+func TestClient_ReadAddrForRepo(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	repo := api.RepoName("github.com/foo/bar")
+	replicas := gitserver.AddrsForRepo(repo, addrs, 2)
+
+	down := map[string]bool{}
+	pings := map[string]int{}
+	repoUpdates := map[string]int{}
+	cli := &gitserver.Client{
+		Addrs:             func(ctx context.Context) []string { return addrs },
+		ReplicationFactor: func() int { return 2 },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.Path {
+			case "/ping":
+				pings[r.URL.Host]++
+				if down[r.URL.Host] {
+					return nil, errors.New("connection refused")
+				}
+			case "/repo-update":
+				repoUpdates[r.URL.Host]++
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+				Request:    r,
+			}, nil
+		}),
+	}
+
+	if got := cli.ReadAddrForRepo(context.Background(), repo); got != replicas[0] {
+		t.Errorf("got %s, want the gitserver the repo is assigned to, %s", got, replicas[0])
+	}
+
+	// Pings are cached, so a gitserver going down is only noticed later.
+	down[replicas[0]] = true
+	if got := cli.ReadAddrForRepo(context.Background(), repo); got != replicas[0] {
+		t.Errorf("got %s, want the cached choice %s", got, replicas[0])
+	}
+	if pings[replicas[0]] != 1 {
+		t.Errorf("got %d pings of %s, want 1", pings[replicas[0]], replicas[0])
+	}
+
+	// A new client pings again.
+	cli = &gitserver.Client{
+		Addrs:             cli.Addrs,
+		ReplicationFactor: cli.ReplicationFactor,
+		HTTPClient:        cli.HTTPClient,
+	}
+	if got := cli.ReadAddrForRepo(context.Background(), repo); got != replicas[1] {
+		t.Errorf("got %s, want the replica %s", got, replicas[1])
+	}
+
+	// Updates are still sent to the gitserver the repo is assigned to.
+	if _, err := cli.RequestRepoUpdate(context.Background(), gitserver.Repo{Name: repo}, 0); err != nil {
+		t.Fatal(err)
+	}
+	if repoUpdates[replicas[0]] != 1 {
+		t.Errorf("got updates %v, want one to %s", repoUpdates, replicas[0])
+	}
+}
+
+func TestClient_ReadAddrForRepo_legacy(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+
+	// Find a repo that rendezvous hashing moved away from the gitserver it
+	// was assigned to before.
+	var repo api.RepoName
+	var owner, legacy string
+	for i := 0; owner == legacy; i++ {
+		repo = api.RepoName(fmt.Sprintf("github.com/foo/repo%d", i))
+		owner = gitserver.AddrForRepo(repo, addrs)
+		sum := md5.Sum([]byte(repo))
+		legacy = addrs[binary.BigEndian.Uint64(sum[:])%uint64(len(addrs))]
+	}
+
+	cloned := map[string]bool{legacy: true}
+	checks := map[string]int{}
+	newClient := func() *gitserver.Client {
+		return &gitserver.Client{
+			Addrs: func(ctx context.Context) []string { return addrs },
+			HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+				if r.URL.Path != "/is-repo-cloned" {
+					return nil, fmt.Errorf("unexpected url: %s", r.URL.String())
+				}
+				checks[r.URL.Host]++
+				status := http.StatusNotFound
+				if cloned[r.URL.Host] {
+					status = http.StatusOK
+				}
+				return &http.Response{
+					StatusCode: status,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{}`)),
+					Request:    r,
+				}, nil
+			}),
+		}
+	}
+
+	cli := newClient()
+	if got := cli.ReadAddrForRepo(context.Background(), repo); got != legacy {
+		t.Errorf("got %s, want the gitserver that stored the repo before, %s", got, legacy)
+	}
+
+	// Once the gitserver the repo is assigned to has cloned it, reads are
+	// sent there, and this is no longer checked.
+	cloned[owner] = true
+	cli = newClient()
+	for i := 0; i < 2; i++ {
+		if got := cli.ReadAddrForRepo(context.Background(), repo); got != owner {
+			t.Errorf("got %s, want the gitserver the repo is assigned to, %s", got, owner)
+		}
+	}
+	if checks[owner] != 2 {
+		t.Errorf("got %d checks of %s, want 2", checks[owner], owner)
+	}
+
+	// Repos that aren't cloned anywhere are read from the gitserver they are
+	// assigned to, which clones them.
+	cloned = map[string]bool{}
+	cli = newClient()
+	if got := cli.ReadAddrForRepo(context.Background(), repo); got != owner {
+		t.Errorf("got %s, want the gitserver the repo is assigned to, %s", got, owner)
+	}
+}
+
+func TestClient_RepoInfo_replicas(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	repo := api.RepoName("github.com/foo/bar")
+	replicas := gitserver.AddrsForRepo(repo, addrs, 3)
+
+	lastFetched := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetched := map[string]time.Time{
+		replicas[0]: lastFetched,
+		replicas[1]: lastFetched.Add(-time.Hour),
+	}
+	cli := &gitserver.Client{
+		Addrs:             func(ctx context.Context) []string { return addrs },
+		ReplicationFactor: func() int { return 3 },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Path != "/repos" {
+				return nil, fmt.Errorf("unexpected url: %s", r.URL.String())
+			}
+			t, ok := fetched[r.URL.Host]
+			if !ok {
+				return nil, errors.New("connection refused")
+			}
+			body, _ := json.Marshal(protocol.RepoInfoResponse{
+				Results: map[api.RepoName]*protocol.RepoInfo{
+					repo: {Cloned: true, LastFetched: &t},
+				},
+			})
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(body)),
+				Request:    r,
+			}, nil
+		}),
+	}
+
+	res, err := cli.RepoInfo(context.Background(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	behind := lastFetched.Add(-time.Hour)
+	want := &protocol.RepoInfo{
+		Cloned:      true,
+		LastFetched: &lastFetched,
+		Replicas: []protocol.ReplicaInfo{
+			{Addr: replicas[1], Cloned: true, LastFetched: &behind, Lag: time.Hour},
+			{Addr: replicas[2], Error: "connection refused"},
+		},
+	}
+	if diff := cmp.Diff(want, res.Results[repo]); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// The information comes from a replica if the gitserver the repo is
+	// assigned to doesn't respond.
+	delete(fetched, replicas[0])
+	res, err = cli.RepoInfo(context.Background(), repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Results[repo]; got == nil || !got.LastFetched.Equal(behind) {
+		t.Errorf("got %+v, want the information from %s", got, replicas[1])
+	}
+
+	delete(fetched, replicas[1])
+	if _, err := cli.RepoInfo(context.Background(), repo); err == nil {
+		t.Error("got no error when no gitserver responds")
+	}
+}
+
 func TestClient_Archive(t *testing.T) {
 	root, err := ioutil.TempDir("", t.Name())
 	if err != nil {
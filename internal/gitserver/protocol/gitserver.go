@@ -68,6 +68,17 @@ type RepoUpdateResponse struct {
 	Finished *time.Time // time request completed
 }
 
+// ReplicaRefUpdateRequest is a request to set a ref of the replica of a repo
+// to a commit, fetching it from the gitserver the repo is assigned to. It is
+// used to replicate refs that aren't fetched from the code host, such as the
+// ones created by create-commit-from-patch.
+type ReplicaRefUpdateRequest struct {
+	Repo   api.RepoName `json:"repo"`   // identifying URL for repo
+	Ref    string       `json:"ref"`    // the ref to set
+	Commit string       `json:"commit"` // the commit to set the ref to
+	Peer   string       `json:"peer"`   // the address of the gitserver to fetch the commit from
+}
+
 type NotFoundPayload struct {
 	CloneInProgress bool `json:"cloneInProgress"` // If true, exec returned with noop because clone is in progress.
 
@@ -117,11 +128,32 @@ type RepoInfo struct {
 	Cloned          bool       // whether the repository has been cloned successfully
 	LastFetched     *time.Time // when the last `git remote update` or `git fetch` occurred
 	LastChanged     *time.Time // timestamp of the most recent ref in the git repository
+	DiskUsage       int64      // size of the repository on disk in bytes, if it is cloned
+	PartialClone    bool       // whether the repository is a partial clone, which is missing large blobs
 
 	// CloneTime is the time the clone occurred. Note: Repositories may be
 	// recloned automatically, so this time is likely to move forward
 	// periodically.
 	CloneTime *time.Time
+
+	// Replicas is information about the repository on the other gitservers
+	// that store it, if the site configuration's gitserverReplicationFactor
+	// is greater than 1.
+	Replicas []ReplicaInfo
+}
+
+// ReplicaInfo is information about a replica of a repository on a gitserver
+// other than the one a RepoInfo was reported by.
+type ReplicaInfo struct {
+	Addr        string     // the address of the gitserver storing the replica
+	Cloned      bool       // whether the replica has been cloned successfully
+	LastFetched *time.Time // when the replica was last fetched
+	Error       string     // why the gitserver storing the replica couldn't report on it, if it couldn't
+
+	// Lag is how long before the last fetch reported by the RepoInfo the
+	// replica was last fetched. It is zero if the replica is up to date or
+	// either time is unknown.
+	Lag time.Duration
 }
 
 // RepoInfoResponse is the response to a repository information request
@@ -148,11 +148,31 @@ func (c *Client) EnqueueRepoUpdate(ctx context.Context, repo gitserver.Repo) (*p
 		return MockEnqueueRepoUpdate(ctx, repo)
 	}
 
-	req := &protocol.RepoUpdateRequest{
+	return c.enqueueRepoUpdate(ctx, &protocol.RepoUpdateRequest{
 		Repo: repo.Name,
 		URL:  repo.URL,
+	})
+}
+
+// MockEnqueuePushedRepoUpdate mocks (*Client).EnqueuePushedRepoUpdate for tests.
+var MockEnqueuePushedRepoUpdate func(ctx context.Context, repo gitserver.Repo) (*protocol.RepoUpdateResponse, error)
+
+// EnqueuePushedRepoUpdate requests that the named repository be updated as
+// soon as possible because its code host delivered a push webhook for it. The
+// repository is then polled less frequently. It does not wait for the update.
+func (c *Client) EnqueuePushedRepoUpdate(ctx context.Context, repo gitserver.Repo) (*protocol.RepoUpdateResponse, error) {
+	if MockEnqueuePushedRepoUpdate != nil {
+		return MockEnqueuePushedRepoUpdate(ctx, repo)
 	}
 
+	return c.enqueueRepoUpdate(ctx, &protocol.RepoUpdateRequest{
+		Repo:   repo.Name,
+		URL:    repo.URL,
+		Pushed: true,
+	})
+}
+
+func (c *Client) enqueueRepoUpdate(ctx context.Context, req *protocol.RepoUpdateRequest) (*protocol.RepoUpdateResponse, error) {
 	resp, err := c.httpPost(ctx, "enqueue-repo-update", req)
 	if err != nil {
 		return nil, err
@@ -141,10 +141,14 @@ type RepoUpdateRequest struct {
 
 	// URL is the repository's Git remote URL (from which to clone or update).
 	URL string `json:"url"`
+
+	// Pushed is true if the update was requested because the code host
+	// delivered a push webhook for the repo.
+	Pushed bool `json:"pushed,omitempty"`
 }
 
 func (a *RepoUpdateRequest) String() string {
-	return fmt.Sprintf("RepoUpdateRequest{%s, %s}", a.Repo, a.URL)
+	return fmt.Sprintf("RepoUpdateRequest{%s, %s, pushed=%t}", a.Repo, a.URL, a.Pushed)
 }
 
 // RepoUpdateResponse is a response type to a RepoUpdateRequest.
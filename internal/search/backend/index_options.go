@@ -49,7 +49,7 @@ func GetIndexOptions(c *schema.SiteConfiguration, repoName string, getVersion fu
 	if c.ExperimentalFeatures != nil && len(c.ExperimentalFeatures.VersionContexts) > 0 {
 		for _, vc := range c.ExperimentalFeatures.VersionContexts {
 			for _, rev := range vc.Revisions {
-				if rev.Repo == repoName && rev.Rev != "" {
+				if rev.Repo == repoName && rev.Rev != "" && rev.RevAt == "" {
 					branches[rev.Rev] = struct{}{}
 				}
 			}
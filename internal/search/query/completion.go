@@ -0,0 +1,137 @@
+package query
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// CompletionTarget describes the token of a query that is being completed,
+// and the part of the query that a completion of it replaces.
+type CompletionTarget struct {
+	// Field is the canonical name of the field whose value is being
+	// completed (e.g., "repo" for "r:foo"). It is empty if a field name is
+	// being completed.
+	Field string
+
+	// Negated is true if the token starts with "-", as in "-file:foo".
+	Negated bool
+
+	// Prefix is the text of the token before the cursor. For field values,
+	// it excludes the field and an opening quote. For field names, it
+	// excludes the "-" of negated fields.
+	Prefix string
+
+	// Range is the range of the query that a completion replaces: the
+	// field value (including quotes), or the field name and its colon (or
+	// the search pattern being typed), without the "-" of negated fields.
+	// Its columns are byte offsets.
+	Range Range
+}
+
+// CompletionTargetAt returns the completion target for the token of the query
+// that contains the cursor, which is a byte offset in the query. The query is
+// parsed with ParseAndOr. If the query can't be parsed, only the text before
+// the cursor is, closing a quote left open at the cursor. If the cursor is not in a token (e.g., it follows a space),
+// the target is a field name to insert at the cursor.
+func CompletionTargetAt(in string, cursor int) (*CompletionTarget, error) {
+	if cursor < 0 || cursor > len(in) {
+		return nil, errors.New("cursor is outside of the query")
+	}
+	nodes, err := ParseAndOr(in)
+	parsedAll := err == nil
+	if !parsedAll {
+		// Parse the text before the cursor, in which a quoted value may be
+		// unterminated.
+		parsed := false
+		for _, text := range []string{in[:cursor], in[:cursor] + `"`, in[:cursor] + `'`} {
+			var err2 error
+			if nodes, err2 = ParseAndOr(text); err2 == nil {
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			return nil, err
+		}
+	}
+
+	target := &CompletionTarget{Range: newRange(cursor, cursor)}
+	var visit func(nodes []Node) bool
+	visit = func(nodes []Node) bool {
+		for _, node := range nodes {
+			switch n := node.(type) {
+			case Operator:
+				if visit(n.Operands) {
+					return true
+				}
+			case Parameter:
+				r := n.Annotation.Range
+				if n.Field != "" && r.Start.Column < cursor && cursor <= r.End.Column {
+					parameterCompletionTarget(target, in, cursor, n)
+					return true
+				}
+			case Pattern:
+				r := n.Annotation.Range
+				if r.Start.Column < cursor && cursor <= r.End.Column {
+					fieldCompletionTarget(target, in, cursor, r.Start.Column, r.End.Column)
+					return true
+				}
+			}
+		}
+		return false
+	}
+	visit(nodes)
+	if !parsedAll && target.Range.End.Column > cursor {
+		// Don't replace the closing quote that was added.
+		target.Range.End.Column = cursor
+	}
+	return target, nil
+}
+
+// parameterCompletionTarget sets target to complete the field name or value of
+// the parameter, depending on the position of the cursor.
+func parameterCompletionTarget(target *CompletionTarget, in string, cursor int, parameter Parameter) {
+	start, end := parameter.Annotation.Range.Start.Column, parameter.Annotation.Range.End.Column
+	colon := start + len(parameter.Field)
+	if parameter.Negated {
+		colon++
+	}
+	if cursor <= colon {
+		fieldCompletionTarget(target, in, cursor, start, colon+1)
+		return
+	}
+
+	field := strings.ToLower(parameter.Field)
+	if canonical, ok := fieldAliases[field]; ok {
+		field = canonical
+	}
+	target.Field = field
+	target.Negated = parameter.Negated
+	target.Prefix = strings.TrimLeft(in[colon+1:cursor], `"'`)
+	target.Range = newRange(colon+1, end)
+}
+
+// fieldCompletionTarget sets target to complete the field name typed in
+// in[start:end].
+func fieldCompletionTarget(target *CompletionTarget, in string, cursor, start, end int) {
+	if strings.HasPrefix(in[start:], "-") {
+		target.Negated = true
+		start++
+	}
+	if cursor < start {
+		cursor = start
+	}
+	target.Prefix = in[start:cursor]
+	target.Range = newRange(start, end)
+}
+
+// FieldNames returns the sorted names of all fields, including aliases.
+func FieldNames() []string {
+	names := make([]string, 0, len(allFields))
+	for name := range allFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
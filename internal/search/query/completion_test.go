@@ -0,0 +1,59 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompletionTargetAt(t *testing.T) {
+	type want struct {
+		Field      string
+		Negated    bool
+		Prefix     string
+		Start, End int
+	}
+	cases := []struct {
+		// The cursor is at the position of "|" in the query.
+		query string
+		want  want
+	}{
+		{query: "|", want: want{}},
+		{query: "rep|", want: want{Prefix: "rep", Start: 0, End: 3}},
+		{query: "re|p", want: want{Prefix: "re", Start: 0, End: 3}},
+		{query: "foo -fi|", want: want{Negated: true, Prefix: "fi", Start: 5, End: 7}},
+		{query: "foo |", want: want{Start: 4, End: 4}},
+		{query: "re|po:foo", want: want{Prefix: "re", Start: 0, End: 5}},
+		{query: "-fi|le:foo bar", want: want{Negated: true, Prefix: "fi", Start: 1, End: 6}},
+		{query: "repo:|", want: want{Field: "repo", Start: 5, End: 5}},
+		{query: "repo:sourceg| bar", want: want{Field: "repo", Prefix: "sourceg", Start: 5, End: 12}},
+		{query: "r:sourceg|raph", want: want{Field: "repo", Prefix: "sourceg", Start: 2, End: 13}},
+		{query: "-LANG:g|o", want: want{Field: "lang", Negated: true, Prefix: "g", Start: 6, End: 8}},
+		{query: `file:"foo b|ar"`, want: want{Field: "file", Prefix: "foo b", Start: 5, End: 14}},
+		{query: "(type:co| or type:diff) x", want: want{Field: "type", Prefix: "co", Start: 6, End: 8}},
+		{query: "foo or type:diff|", want: want{Field: "type", Prefix: "diff", Start: 12, End: 16}},
+		// Unbalanced quotes are parsed up to the cursor.
+		{query: `repo:foo file:"ba|`, want: want{Field: "file", Prefix: "ba", Start: 14, End: 17}},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			cursor := strings.Index(c.query, "|")
+			in := strings.Replace(c.query, "|", "", 1)
+			target, err := CompletionTargetAt(in, cursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := want{
+				Field:   target.Field,
+				Negated: target.Negated,
+				Prefix:  target.Prefix,
+				Start:   target.Range.Start.Column,
+				End:     target.Range.End.Column,
+			}
+			if diff := cmp.Diff(c.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
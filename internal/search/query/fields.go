@@ -18,8 +18,14 @@ var allFields = map[string]struct{}{
 	FieldType:               empty,
 	FieldPatternType:        empty,
 	FieldContent:            empty,
+	FieldVisibility:         empty,
+	FieldSelect:             empty,
+	FieldSymbolKind:         empty,
+	FieldSymbolParent:       empty,
 	FieldRepoHasFile:        empty,
 	FieldRepoHasCommitAfter: empty,
+	FieldRev:                empty,
+	FieldRevAt:              empty,
 	FieldBefore:             empty,
 	"until":                 empty,
 	FieldAfter:              empty,
@@ -227,6 +227,12 @@ loop:
 		case p.matchKeyword(AND), p.matchKeyword(OR):
 			// Caller advances.
 			break loop
+		case p.matchUnaryKeyword(NOT):
+			node, err := p.parseNot(p.ParsePatternLiteral)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
 		default:
 			parameter, ok, err := p.ParseParameter()
 			if err != nil {
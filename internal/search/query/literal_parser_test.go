@@ -52,6 +52,26 @@ func TestParseAndOrLiteral(t *testing.T) {
 			Want:       `"("`,
 			WantLabels: "HeuristicDanglingParens,Literal",
 		},
+		{
+			Input:      "foo( and not bar(",
+			Want:       `(and "foo(" "NOT bar(")`,
+			WantLabels: "HeuristicHoisted,Literal",
+		},
+		{
+			Input:      "error not found",
+			Want:       `(concat "error" "not" "found")`,
+			WantLabels: "Literal",
+		},
+		{
+			Input:      "error not found or b",
+			Want:       `(or (concat "error" "not" "found") "b")`,
+			WantLabels: "HeuristicHoisted,Literal",
+		},
+		{
+			Input:      "not found",
+			Want:       `(concat "not" "found")`,
+			WantLabels: "Literal",
+		},
 		{
 			Input:      "repo:foo foo( or bar(",
 			Want:       `(and "repo:foo" (or "foo(" "bar("))`,
@@ -383,3 +403,42 @@ func TestParseAndOrLiteral(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessAndOrLiteralNot(t *testing.T) {
+	cases := []struct {
+		Input string
+		Want  string
+	}{
+		{
+			Input: "error not found",
+			Want:  `"error not found"`,
+		},
+		{
+			Input: "not found",
+			Want:  `"not found"`,
+		},
+		{
+			Input: "error not found or timeout",
+			Want:  `(or "error not found" "timeout")`,
+		},
+		{
+			Input: "error and not found",
+			Want:  `(and "error" "NOT found")`,
+		},
+	}
+	for _, tt := range cases {
+		t.Run(tt.Input, func(t *testing.T) {
+			q, err := ProcessAndOr(tt.Input, SearchTypeLiteral)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var resultStr []string
+			for _, node := range q.(*AndOrQuery).Query {
+				resultStr = append(resultStr, node.String())
+			}
+			if diff := cmp.Diff(tt.Want, strings.Join(resultStr, " ")); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
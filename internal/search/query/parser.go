@@ -112,6 +112,7 @@ type keyword string
 const (
 	AND    keyword = "and"
 	OR     keyword = "or"
+	NOT    keyword = "not"
 	LPAREN keyword = "("
 	RPAREN keyword = ")"
 	SQUOTE keyword = "'"
@@ -233,6 +234,51 @@ func (p *parser) matchKeyword(keyword keyword) bool {
 	return strings.EqualFold(v, string(keyword))
 }
 
+// matchUnaryKeyword is like matchKeyword but only matches the keyword in an
+// explicit boolean context: right after an and/or keyword, or at the start of a
+// parenthesized group. Elsewhere, as in "error not found", the keyword is part
+// of a pattern.
+func (p *parser) matchUnaryKeyword(keyword keyword) bool {
+	if p.pos == 0 || (!isSpace(p.buf[p.pos-1:p.pos]) && p.buf[p.pos-1] != '(') {
+		return false
+	}
+	if !p.followsBooleanOperator() {
+		return false
+	}
+	v, err := p.peek(len(string(keyword)))
+	if err != nil {
+		return false
+	}
+	after := p.pos + len(string(keyword))
+	if after >= len(p.buf) || !isSpace(p.buf[after:after+1]) {
+		return false
+	}
+	return strings.EqualFold(v, string(keyword))
+}
+
+// followsBooleanOperator returns true if the input before the current position,
+// ignoring whitespace, ends with an and/or keyword or the opening parenthesis of
+// a group.
+func (p *parser) followsBooleanOperator() bool {
+	end := p.pos
+	for end > 0 && isSpace(p.buf[end-1:end]) {
+		end--
+	}
+	if end == 0 {
+		return false
+	}
+	if p.buf[end-1] == '(' {
+		return p.balanced > 0
+	}
+	for _, keyword := range []keyword{AND, OR} {
+		start := end - len(string(keyword))
+		if start > 0 && isSpace(p.buf[start-1:start]) && strings.EqualFold(string(p.buf[start:end]), string(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
 // skipSpaces advances the input and places the parser position at the next
 // non-space value.
 func (p *parser) skipSpaces() error {
@@ -340,6 +386,11 @@ func ScanField(buf []byte) (string, int) {
 			result = append(result, r)
 			continue
 		}
+		if r == '.' && strings.TrimPrefix(string(result), "-") == "rev" {
+			// Fields in the rev namespace, like rev.at:, contain a dot.
+			result = append(result, r)
+			continue
+		}
 		if r == ':' {
 			// Invariant: len(result) > 0. If len(result) == 1,
 			// check that it is not just a '-'. If len(result) > 1, it is valid.
@@ -575,6 +626,33 @@ func partitionParameters(nodes []Node) []Node {
 	return newOperator(append(unorderedParams, patterns...), And)
 }
 
+// parseNot parses the operand following a not keyword, and returns it negated.
+// Only a single search pattern or parameter can be negated. If the keyword is
+// not followed by one, the keyword itself is parsed as a pattern with
+// parsePattern.
+func (p *parser) parseNot(parsePattern func() Pattern) (Node, error) {
+	start := p.pos
+	p.pos += len(string(NOT))
+	if err := p.skipSpaces(); err != nil {
+		return nil, err
+	}
+	if p.done() || p.match(LPAREN) || p.match(RPAREN) || p.matchKeyword(AND) || p.matchKeyword(OR) {
+		p.pos = start
+		return parsePattern(), nil
+	}
+	parameter, ok, err := p.ParseParameter()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		parameter.Negated = !parameter.Negated
+		return parameter, nil
+	}
+	pattern := parsePattern()
+	pattern.Negated = true
+	return pattern, nil
+}
+
 // parseParameterParameterList scans for consecutive leaf nodes.
 func (p *parser) parseParameterList() ([]Node, error) {
 	var nodes []Node
@@ -653,6 +731,12 @@ loop:
 		case p.matchKeyword(AND), p.matchKeyword(OR):
 			// Caller advances.
 			break loop
+		case p.matchUnaryKeyword(NOT):
+			node, err := p.parseNot(p.ParsePattern)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
 		default:
 			parameter, ok, err := p.ParseParameter()
 			if err != nil {
@@ -168,6 +168,13 @@ func TestScanField(t *testing.T) {
 				Advance: 6,
 			},
 		},
+		{
+			Input: "rev.at:foo",
+			Want: value{
+				Field:   "rev.at",
+				Advance: 7,
+			},
+		},
 		// Invalid field.
 		{
 			Input: "",
@@ -197,6 +204,13 @@ func TestScanField(t *testing.T) {
 				Advance: 0,
 			},
 		},
+		{
+			Input: "fmt.Println:foo",
+			Want: value{
+				Field:   "",
+				Advance: 0,
+			},
+		},
 		{
 			Input: "??:foo",
 			Want: value{
@@ -452,6 +466,49 @@ func TestParse(t *testing.T) {
 			WantGrammar:   `(or (and "and" "andand") "oror")`,
 			WantHeuristic: Same,
 		},
+		{
+			Input:         "a and not b",
+			WantGrammar:   `(and "a" "NOT b")`,
+			WantHeuristic: Same,
+		},
+		{
+			Input:         "(not a and b) or c",
+			WantGrammar:   `(or (and "NOT a" "b") "c")`,
+			WantHeuristic: Same,
+		},
+		{
+			Input:         "a and not file:foo",
+			WantGrammar:   `(and "a" "-file:foo")`,
+			WantHeuristic: Same,
+		},
+		{
+			Name:          "not is a pattern outside of an and/or expression",
+			Input:         "error not found",
+			WantGrammar:   `(concat "error" "not" "found")`,
+			WantHeuristic: Same,
+		},
+		{
+			Name:          "not is a pattern at the start of a query",
+			Input:         "not a or b",
+			WantGrammar:   `(or (concat "not" "a") "b")`,
+			WantHeuristic: Same,
+		},
+		{
+			Name:          "not is a pattern inside an operand",
+			Input:         "error not found or b",
+			WantGrammar:   `(or (concat "error" "not" "found") "b")`,
+			WantHeuristic: Same,
+		},
+		{
+			Input:         "a not",
+			WantGrammar:   `(concat "a" "not")`,
+			WantHeuristic: Same,
+		},
+		{
+			Input:         "not and b",
+			WantGrammar:   `(and "not" "b")`,
+			WantHeuristic: Same,
+		},
 		// Errors.
 		{
 			Name:          "Unbalanced",
@@ -23,9 +23,16 @@ const (
 	FieldType               = "type"
 	FieldRepoHasFile        = "repohasfile"
 	FieldRepoHasCommitAfter = "repohascommitafter"
+	FieldRev                = "rev"
+	FieldRevAt              = "rev.at"
 	FieldPatternType        = "patterntype"
 	FieldContent            = "content"
 	FieldVisibility         = "visibility"
+	FieldSelect             = "select"
+
+	// For symbol search only:
+	FieldSymbolKind   = "symbolkind"
+	FieldSymbolParent = "symbolparent"
 
 	// For diff and commit search only:
 	FieldBefore    = "before"
@@ -62,9 +69,15 @@ var (
 			FieldPatternType: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 			FieldContent:     {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 			FieldVisibility:  {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldSelect:      {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+
+			FieldSymbolKind:   {Literal: types.StringType, Quoted: types.StringType, Negatable: true},
+			FieldSymbolParent: regexpNegatableFieldType,
 
 			FieldRepoHasFile:        regexpNegatableFieldType,
 			FieldRepoHasCommitAfter: {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldRev:                {Literal: types.StringType, Quoted: types.StringType, Singular: true},
+			FieldRevAt:              {Literal: types.StringType, Quoted: types.StringType, Singular: true},
 
 			FieldBefore:    stringFieldType,
 			FieldAfter:     stringFieldType,
@@ -174,6 +187,12 @@ func Validate(q QueryInfo, searchType SearchType) error {
 			return errors.New(`the parameter "type:" is not valid for structural search, search is always performed on file content`)
 		}
 	}
+	symbolKinds, excludeSymbolKinds := q.StringValues(FieldSymbolKind)
+	for _, kind := range append(symbolKinds, excludeSymbolKinds...) {
+		if err := validateSymbolKind(kind); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -163,6 +163,12 @@ func TestQuery_Validate(t *testing.T) {
 			SearchType: SearchTypeStructural,
 			Want:       "",
 		},
+		{
+			Name:       `Unknown symbol kind`,
+			Query:      `type:symbol -symbolkind:functoin foo`,
+			SearchType: SearchTypeLiteral,
+			Want:       `unknown symbol kind "functoin" (examples: "function", "method", "class", "variable")`,
+		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.Name, func(t *testing.T) {
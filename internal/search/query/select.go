@@ -0,0 +1,83 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CtagsKinds maps the symbol kinds that symbolkind: and select:symbol.<kind>
+// accept, which are the names of the LSP symbol kinds (e.g. "function"), to
+// the ctags kinds they correspond to (e.g. "func"). Ctags kinds are
+// determined by the parser and do not (in general) match LSP symbol kinds.
+// Queries accept ctags kinds too, to filter on kinds specific to a language.
+var CtagsKinds = map[string][]string{
+	"file":          {"file"},
+	"module":        {"module"},
+	"namespace":     {"namespace"},
+	"package":       {"package", "packagename", "subprogspec"},
+	"class":         {"class", "type", "service", "typedef", "union", "section", "subtype", "component"},
+	"method":        {"method", "methodspec"},
+	"property":      {"property"},
+	"field":         {"field", "member", "anonmember", "recordfield"},
+	"constructor":   {"constructor"},
+	"enum":          {"enum", "enumerator"},
+	"interface":     {"interface"},
+	"function":      {"function", "func", "subroutine", "macro", "subprogram", "procedure", "command", "singletonmethod"},
+	"variable":      {"variable", "var", "functionvar", "define", "alias", "val"},
+	"constant":      {"constant", "const"},
+	"string":        {"string", "message", "heredoc"},
+	"number":        {"number"},
+	"boolean":       {"bool", "boolean"},
+	"array":         {"array"},
+	"object":        {"object", "literal", "map"},
+	"key":           {"key", "label", "target", "selector", "id", "tag"},
+	"null":          {"null"},
+	"enummember":    {"enum member", "enumconstant"},
+	"struct":        {"struct"},
+	"event":         {"event"},
+	"operator":      {"operator"},
+	"typeparameter": {"type parameter", "annotation"},
+}
+
+// symbolKinds are the symbol kinds and ctags kinds of CtagsKinds.
+var symbolKinds = func() map[string]struct{} {
+	m := make(map[string]struct{})
+	for kind, ctagsKinds := range CtagsKinds {
+		m[kind] = struct{}{}
+		for _, ctagsKind := range ctagsKinds {
+			m[ctagsKind] = struct{}{}
+		}
+	}
+	return m
+}()
+
+// IsSymbolKind reports whether kind is a symbol kind that can be filtered
+// on. Kinds are compared case insensitively.
+func IsSymbolKind(kind string) bool {
+	_, ok := symbolKinds[strings.ToLower(kind)]
+	return ok
+}
+
+// validateSymbolKind returns an error if kind is not a known symbol kind.
+func validateSymbolKind(kind string) error {
+	if !IsSymbolKind(kind) {
+		return fmt.Errorf(`unknown symbol kind %q (examples: "function", "method", "class", "variable")`, kind)
+	}
+	return nil
+}
+
+// SelectedSymbolKind returns the symbol kind selected by the value of a
+// select: field, e.g. "function" for "select:symbol.function". The kind is
+// empty if symbols of any kind are selected ("select:symbol").
+func SelectedSymbolKind(value string) (string, error) {
+	if value == "symbol" {
+		return "", nil
+	}
+	if kind := strings.TrimPrefix(value, "symbol."); kind != value && kind != "" && !strings.Contains(kind, ".") {
+		if err := validateSymbolKind(kind); err != nil {
+			return "", err
+		}
+		return kind, nil
+	}
+	return "", fmt.Errorf(`invalid select: value %q (examples: "select:symbol", "select:symbol.function")`, value)
+}
@@ -154,6 +154,10 @@ func scanText(s *scanner) stateFn {
 			s.emit(TokenColon)
 			return scanValue
 		}
+		if r == '.' && s.input[s.start:s.prevPos] == "rev" {
+			// Fields in the rev namespace, like rev.at:, contain a dot.
+			continue
+		}
 		if !strings.ContainsRune(preColonChars, r) {
 			return scanLiteral
 		}
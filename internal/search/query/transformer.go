@@ -8,24 +8,26 @@ import (
 	"unicode"
 )
 
+// fieldAliases maps field name aliases to their canonical names.
+var fieldAliases = map[string]string{
+	"r":        FieldRepo,
+	"g":        FieldRepoGroup,
+	"f":        FieldFile,
+	"l":        FieldLang,
+	"language": FieldLang,
+	"since":    FieldAfter,
+	"until":    FieldBefore,
+	"m":        FieldMessage,
+	"msg":      FieldMessage,
+}
+
 // SubstituteAliases substitutes field name aliases for their canonical names.
 func SubstituteAliases(nodes []Node) []Node {
-	aliases := map[string]string{
-		"r":        FieldRepo,
-		"g":        FieldRepoGroup,
-		"f":        FieldFile,
-		"l":        FieldLang,
-		"language": FieldLang,
-		"since":    FieldAfter,
-		"until":    FieldBefore,
-		"m":        FieldMessage,
-		"msg":      FieldMessage,
-	}
 	return MapParameter(nodes, func(field, value string, negated bool, annotation Annotation) Node {
 		if field == "content" {
 			return Pattern{Value: value, Negated: negated, Annotation: annotation}
 		}
-		if canonical, ok := aliases[field]; ok {
+		if canonical, ok := fieldAliases[field]; ok {
 			field = canonical
 		}
 		return Parameter{Field: field, Value: value, Negated: negated, Annotation: annotation}
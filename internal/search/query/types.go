@@ -228,14 +228,22 @@ func (q AndOrQuery) valueToTypedValue(field, value string, label labels) []*type
 		FieldLang, "l", "language",
 		FieldType,
 		FieldPatternType,
-		FieldContent:
+		FieldContent,
+		FieldVisibility,
+		FieldSelect,
+		FieldSymbolKind:
 		return []*types.Value{{String: &value}}
 
+	case FieldSymbolParent:
+		return []*types.Value{{Regexp: parseRegexpOrPanic(field, value)}}
+
 	case FieldRepoHasFile:
 		return []*types.Value{{Regexp: parseRegexpOrPanic(field, value)}}
 
 	case
 		FieldRepoHasCommitAfter,
+		FieldRev,
+		FieldRevAt,
 		FieldBefore, "until",
 		FieldAfter, "since":
 		return []*types.Value{{String: &value}}
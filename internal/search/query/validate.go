@@ -232,6 +232,15 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return nil
 	}
 
+	isSelect := func() error {
+		_, err := SelectedSymbolKind(value)
+		return err
+	}
+
+	isSymbolKind := func() error {
+		return validateSymbolKind(value)
+	}
+
 	isUnrecognizedField := func() error {
 		return fmt.Errorf("unrecognized field %q", field)
 	}
@@ -273,13 +282,25 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return satisfies(isNotNegated)
 	case
 		FieldPatternType,
-		FieldContent:
+		FieldContent,
+		FieldVisibility:
 		return satisfies(isSingular, isNotNegated)
+	case
+		FieldSelect:
+		return satisfies(isSingular, isNotNegated, isSelect)
+	case
+		FieldSymbolKind:
+		return satisfies(isSymbolKind)
+	case
+		FieldSymbolParent:
+		return satisfies(isValidRegexp)
 	case
 		FieldRepoHasFile:
 		return satisfies(isValidRegexp)
 	case
-		FieldRepoHasCommitAfter:
+		FieldRepoHasCommitAfter,
+		FieldRev,
+		FieldRevAt:
 		return satisfies(isSingular, isNotNegated)
 	case
 		FieldBefore,
@@ -49,6 +49,18 @@ func TestAndOrQuery_Validation(t *testing.T) {
 			input: "+",
 			want:  "error parsing regexp: missing argument to repetition operator: `+`",
 		},
+		{
+			input: "symbolkind:functoin",
+			want:  `unknown symbol kind "functoin" (examples: "function", "method", "class", "variable")`,
+		},
+		{
+			input: "select:symbol.functoin",
+			want:  `unknown symbol kind "functoin" (examples: "function", "method", "class", "variable")`,
+		},
+		{
+			input: "select:repo",
+			want:  `invalid select: value "repo" (examples: "select:symbol", "select:symbol.function")`,
+		},
 		{
 			input: `\\\`,
 			want:  "error parsing regexp: trailing backslash at end of expression: ``",
@@ -11,7 +11,8 @@ import (
 )
 
 // RevisionSpecifier represents either a revspec or a ref glob. At most one
-// field is set. The default branch is represented by all fields being empty.
+// of RevSpec, RefGlob and ExcludeRefGlob is set. The default branch is
+// represented by all fields being empty.
 type RevisionSpecifier struct {
 	// RevSpec is a revision range specifier suitable for passing to git. See
 	// the manpage gitrevisions(7).
@@ -24,6 +25,12 @@ type RevisionSpecifier struct {
 	// ExcludeRefGlob is a glob for references to exclude. See the
 	// documentation for "--exclude" in git-log.
 	ExcludeRefGlob string
+
+	// RevAt is a date understood by git (such as "2020-01-01" or "1 year
+	// ago"). If set, the revision refers to the last commit on RevSpec (or
+	// the default branch) before that date. It can't be combined with ref
+	// globs.
+	RevAt string
 }
 
 func (r1 RevisionSpecifier) String() string {
@@ -48,7 +55,10 @@ func (r1 RevisionSpecifier) Less(r2 RevisionSpecifier) bool {
 	if r1.RefGlob != r2.RefGlob {
 		return r1.RefGlob < r2.RefGlob
 	}
-	return r1.ExcludeRefGlob < r2.ExcludeRefGlob
+	if r1.ExcludeRefGlob != r2.ExcludeRefGlob {
+		return r1.ExcludeRefGlob < r2.ExcludeRefGlob
+	}
+	return r1.RevAt < r2.RevAt
 }
 
 // RepositoryRevisions specifies a repository and 0 or more revspecs and ref
@@ -97,18 +107,24 @@ func ParseRepositoryRevisions(repoAndOptionalRev string) (string, []RevisionSpec
 		return repoAndOptionalRev, []RevisionSpecifier{}
 	}
 
-	repo := repoAndOptionalRev[:i]
-	var revs []RevisionSpecifier
-	for _, part := range strings.Split(repoAndOptionalRev[i+1:], ":") {
+	return repoAndOptionalRev[:i], ParseRevisionSpecifiers(repoAndOptionalRev[i+1:])
+}
+
+// ParseRevisionSpecifiers parses a ':'-separated list of revspecs and/or ref
+// globs, like the revs of ParseRepositoryRevisions. An empty list refers to
+// the default branch.
+func ParseRevisionSpecifiers(revs string) []RevisionSpecifier {
+	var specifiers []RevisionSpecifier
+	for _, part := range strings.Split(revs, ":") {
 		if part == "" {
 			continue
 		}
-		revs = append(revs, parseRev(part))
+		specifiers = append(specifiers, parseRev(part))
 	}
-	if len(revs) == 0 {
-		revs = []RevisionSpecifier{{RevSpec: ""}} // default branch
+	if len(specifiers) == 0 {
+		specifiers = []RevisionSpecifier{{RevSpec: ""}} // default branch
 	}
-	return repo, revs
+	return specifiers
 }
 
 func parseRev(spec string) RevisionSpecifier {
@@ -160,6 +176,22 @@ func (r *RepositoryRevisions) RevSpecs() []string {
 	return revspecs
 }
 
+// RefGlobs returns the compiled ref include/exclude globs of r. It matches no
+// refs if r has no ref globs. See git.CompileRefGlobs for information on how
+// they are handled.
+func (r *RepositoryRevisions) RefGlobs() (git.RefGlobs, error) {
+	var globs []git.RefGlob
+	for _, rev := range r.Revs {
+		switch {
+		case rev.RefGlob != "":
+			globs = append(globs, git.RefGlob{Include: rev.RefGlob})
+		case rev.ExcludeRefGlob != "":
+			globs = append(globs, git.RefGlob{Exclude: rev.ExcludeRefGlob})
+		}
+	}
+	return git.CompileRefGlobs(globs)
+}
+
 // ExpandedRevSpecs evaluates all of r's ref glob expressions and returns the full, current list of
 // refs matched or resolved by them, plus the explicitly listed Git revspecs. See
 // git.CompileRefGlobs for information on how ref include/exclude globs are handled.
@@ -173,27 +205,17 @@ func (r *RepositoryRevisions) ExpandedRevSpecs(ctx context.Context) ([]string, e
 		listRefs = git.ListRefs
 	}
 
-	var (
-		revSpecs = map[string]struct{}{}
-		globs    []git.RefGlob
-	)
-	for _, rev := range r.Revs {
-		switch {
-		case rev.RefGlob != "":
-			globs = append(globs, git.RefGlob{Include: rev.RefGlob})
-		case rev.ExcludeRefGlob != "":
-			globs = append(globs, git.RefGlob{Exclude: rev.ExcludeRefGlob})
-		default:
-			revSpecs[rev.RevSpec] = struct{}{}
-		}
+	revSpecs := map[string]struct{}{}
+	for _, revSpec := range r.RevSpecs() {
+		revSpecs[revSpec] = struct{}{}
 	}
-	if len(globs) > 0 {
+	if !r.OnlyExplicit() {
 		allRefs, err := listRefs(ctx, r.GitserverRepo())
 		if err != nil {
 			return nil, err
 		}
 
-		rg, err := git.CompileRefGlobs(globs)
+		rg, err := r.RefGlobs()
 		if err != nil {
 			return nil, err
 		}
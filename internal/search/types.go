@@ -64,6 +64,25 @@ type SymbolsParameters struct {
 	// need to match to get included in the result
 	ExcludePattern string
 
+	// Kinds, if set, restricts the result to symbols whose kind (as
+	// reported by ctags, e.g. "function" or "method") is one of Kinds. Kinds
+	// are compared case insensitively.
+	Kinds []string
+
+	// ExcludeKinds is an optional list of kinds that symbols must not have
+	// to get included in the result.
+	ExcludeKinds []string
+
+	// ParentPatterns is a list of regexes that the name of a symbol's
+	// parent (the type, class or namespace containing it) needs to match to
+	// get included in the result. Like IncludePatterns, the patterns are
+	// ANDed together.
+	ParentPatterns []string
+
+	// ExcludeParentPattern is an optional regex that the name of a symbol's
+	// parent must not match to get included in the result.
+	ExcludeParentPattern string
+
 	// First indicates that only the first n symbols should be returned.
 	First int
 }
@@ -119,6 +138,11 @@ type TextPatternInfo struct {
 	IncludePatterns []string
 	ExcludePattern  string
 
+	// IncludePaths, if non-empty, is the list of the paths of the only files
+	// to search. It is set for structural search, where indexed search finds
+	// the files that may contain matches.
+	IncludePaths []string
+
 	FilePatternsReposMustInclude []string
 	FilePatternsReposMustExclude []string
 
@@ -127,11 +151,24 @@ type TextPatternInfo struct {
 	PatternMatchesContent bool
 	PatternMatchesPath    bool
 
+	// PatternExpression, if non-nil, is a boolean expression of patterns
+	// that is searched for instead of Pattern.
+	PatternExpression *PatternExpression
+
 	Languages []string
+
+	// Symbol filters, only used by symbol search. See SymbolsParameters.
+	SymbolKinds                []string
+	ExcludeSymbolKinds         []string
+	SymbolParentPatterns       []string
+	ExcludeSymbolParentPattern string
 }
 
 func (p *TextPatternInfo) String() string {
 	args := []string{fmt.Sprintf("%q", p.Pattern)}
+	if p.PatternExpression != nil {
+		args[0] = p.PatternExpression.String()
+	}
 	if p.IsRegExp {
 		args = append(args, "re")
 	}
@@ -168,6 +205,19 @@ func (p *TextPatternInfo) String() string {
 		args = append(args, fmt.Sprintf("-repositoryPathPattern:%s", dec))
 	}
 
+	for _, kind := range p.SymbolKinds {
+		args = append(args, fmt.Sprintf("symbolkind:%s", kind))
+	}
+	for _, kind := range p.ExcludeSymbolKinds {
+		args = append(args, fmt.Sprintf("-symbolkind:%s", kind))
+	}
+	for _, parent := range p.SymbolParentPatterns {
+		args = append(args, fmt.Sprintf("symbolparent:%q", parent))
+	}
+	if p.ExcludeSymbolParentPattern != "" {
+		args = append(args, fmt.Sprintf("-symbolparent:%q", p.ExcludeSymbolParentPattern))
+	}
+
 	path := "f"
 	if p.PathPatternsAreCaseSensitive {
 		path = "F"
@@ -182,6 +232,48 @@ func (p *TextPatternInfo) String() string {
 	return fmt.Sprintf("TextPatternInfo{%s}", strings.Join(args, ","))
 }
 
+// PatternExpressionKind is the kind of a node in a PatternExpression.
+type PatternExpressionKind string
+
+const (
+	PatternExpressionPattern PatternExpressionKind = "pattern"
+	PatternExpressionAnd     PatternExpressionKind = "and"
+	PatternExpressionOr      PatternExpressionKind = "or"
+	PatternExpressionNot     PatternExpressionKind = "not"
+)
+
+// PatternExpression is a boolean expression of search patterns, such as
+// "foo and not bar". Its patterns are interpreted like TextPatternInfo.Pattern.
+// Keep it in sync with pkg/searcher/protocol.PatternExpression.
+type PatternExpression struct {
+	Kind     PatternExpressionKind `json:"kind"`
+	Pattern  string                `json:"pattern,omitempty"`
+	Operands []*PatternExpression  `json:"operands,omitempty"`
+}
+
+// Patterns returns the patterns of the expression, including negated ones.
+func (e *PatternExpression) Patterns() []string {
+	if e.Kind == PatternExpressionPattern {
+		return []string{e.Pattern}
+	}
+	var patterns []string
+	for _, operand := range e.Operands {
+		patterns = append(patterns, operand.Patterns()...)
+	}
+	return patterns
+}
+
+func (e *PatternExpression) String() string {
+	if e.Kind == PatternExpressionPattern {
+		return fmt.Sprintf("%q", e.Pattern)
+	}
+	operands := make([]string, 0, len(e.Operands))
+	for _, operand := range e.Operands {
+		operands = append(operands, operand.String())
+	}
+	return fmt.Sprintf("(%s %s)", e.Kind, strings.Join(operands, " "))
+}
+
 // CommitPatternInfo is the data type that describes the properties of
 // a pattern used for commit search.
 type CommitPatternInfo struct {
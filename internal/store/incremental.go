@@ -0,0 +1,138 @@
+package store
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// cachedAncestor is the cached zip of an ancestor of the commit being
+// fetched, along with the changes between the two commits.
+type cachedAncestor struct {
+	file    *diskcache.File
+	zr      *zip.Reader
+	changes *git.Changes
+}
+
+// findCachedAncestor returns the cached zip of the nearest ancestor of
+// repo@commit. It returns nil if there is no such ancestor, or if too many
+// files changed since. The returned ancestor must be closed.
+func (s *Store) findCachedAncestor(ctx context.Context, repo gitserver.Repo, commit api.CommitID, largeFilePatterns []string) (*cachedAncestor, error) {
+	if s.FetchTarPaths == nil || s.ListAncestors == nil || s.GitDiff == nil {
+		return nil, nil
+	}
+
+	ancestors, err := s.ListAncestors(ctx, repo, commit, git.MaxAncestorsToCheck)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ancestor := range ancestors {
+		f, err := s.cache.OpenIfExists(zipKey(repo, ancestor, largeFilePatterns))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		a, err := newCachedAncestor(ctx, s, f, repo, ancestor, commit)
+		if err != nil || a == nil {
+			f.Close()
+		}
+		return a, err
+	}
+
+	return nil, nil
+}
+
+func newCachedAncestor(ctx context.Context, s *Store, f *diskcache.File, repo gitserver.Repo, ancestor, commit api.CommitID) (*cachedAncestor, error) {
+	changes, err := s.GitDiff(ctx, repo, ancestor, commit)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes.Added)+len(changes.Modified)+len(changes.Deleted) > git.MaxChangedPaths {
+		return nil, nil
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(f, fi.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	return &cachedAncestor{file: f, zr: zr, changes: changes}, nil
+}
+
+// fetchPaths returns the paths that must be fetched from gitserver because
+// they were added or modified since the ancestor.
+func (a *cachedAncestor) fetchPaths() []string {
+	paths := make([]string, 0, len(a.changes.Added)+len(a.changes.Modified))
+	paths = append(paths, a.changes.Added...)
+	return append(paths, a.changes.Modified...)
+}
+
+// copyUnchanged copies the files of the ancestor's zip that did not change
+// since the ancestor to zw.
+func (a *cachedAncestor) copyUnchanged(zw *zip.Writer) error {
+	changed := make(map[string]struct{}, len(a.changes.Modified)+len(a.changes.Deleted))
+	for _, paths := range [][]string{a.changes.Modified, a.changes.Deleted} {
+		for _, path := range paths {
+			changed[path] = struct{}{}
+		}
+	}
+
+	for _, f := range a.zr.File {
+		if _, ok := changed[f.Name]; ok {
+			continue
+		}
+		if err := copyZipFile(zw, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *cachedAncestor) Close() error {
+	return a.file.Close()
+}
+
+// copyZipFile copies f to zw.
+func copyZipFile(zw *zip.Writer, f *zip.File) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:   f.Name,
+		Method: zip.Store,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// newEmptyTar returns an empty tar archive.
+func newEmptyTar() (io.ReadCloser, error) {
+	var buf bytes.Buffer
+	if err := tar.NewWriter(&buf).Close(); err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(&buf), nil
+}
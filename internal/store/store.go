@@ -24,7 +24,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 
+	"github.com/inconshreveable/log15"
 	"github.com/opentracing/opentracing-go/ext"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -56,6 +58,21 @@ type Store struct {
 	// determine if the error is a bad request (eg invalid repo).
 	FetchTar func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error)
 
+	// FetchTarPaths is like FetchTar, but only includes the given paths in
+	// the archive.
+	//
+	// Together with ListAncestors and GitDiff, it is used to build the zip
+	// of a commit from the cached zip of an ancestor commit, only fetching
+	// the files that changed since. If any of them is nil, the full archive
+	// of every commit is fetched.
+	FetchTarPaths func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, paths []string) (io.ReadCloser, error)
+
+	// ListAncestors returns up to n ancestors of a commit, nearest first.
+	ListAncestors func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, n int) ([]api.CommitID, error)
+
+	// GitDiff returns the paths that changed between two commits.
+	GitDiff func(ctx context.Context, repo gitserver.Repo, base, head api.CommitID) (*git.Changes, error)
+
 	// Path is the directory to store the cache
 	Path string
 
@@ -135,9 +152,7 @@ func (s *Store) PrepareZip(ctx context.Context, repo gitserver.Repo, commit api.
 
 	largeFilePatterns := conf.Get().SearchLargeFiles
 
-	// key is a sha256 hash since we want to use it for the disk name
-	h := sha256.Sum256([]byte(fmt.Sprintf("%q %q %q", repo.Name, commit, largeFilePatterns)))
-	key := hex.EncodeToString(h[:])
+	key := zipKey(repo, commit, largeFilePatterns)
 	span.LogKV("key", key)
 
 	// Our fetch can take a long time, and the frontend aggressively cancels
@@ -176,9 +191,20 @@ func (s *Store) PrepareZip(ctx context.Context, repo gitserver.Repo, commit api.
 	}
 }
 
+// zipKey returns the disk cache key of the zip of repo@commit.
+func zipKey(repo gitserver.Repo, commit api.CommitID, largeFilePatterns []string) string {
+	// key is a sha256 hash since we want to use it for the disk name
+	h := sha256.Sum256([]byte(fmt.Sprintf("%q %q %q", repo.Name, commit, largeFilePatterns)))
+	return hex.EncodeToString(h[:])
+}
+
 // fetch fetches an archive from the network and stores it on disk. It does
 // not populate the in-memory cache. You should probably be calling
 // prepareZip.
+//
+// If the zip of a recent ancestor of commit is cached, only the files that
+// changed since are fetched and the remaining files are copied from the
+// ancestor's zip.
 func (s *Store) fetch(ctx context.Context, repo gitserver.Repo, commit api.CommitID, largeFilePatterns []string) (rc io.ReadCloser, err error) {
 	fetchQueueSize.Inc()
 	ctx, releaseFetchLimiter, err := s.fetchLimiter.Acquire(ctx) // Acquire concurrent fetches semaphore
@@ -223,8 +249,25 @@ func (s *Store) fetch(ctx context.Context, repo gitserver.Repo, commit api.Commi
 		}
 	}()
 
-	r, err := s.FetchTar(ctx, repo, commit)
+	ancestor, err := s.findCachedAncestor(ctx, repo, commit, largeFilePatterns)
+	if err != nil {
+		log15.Warn("Unable to update zip incrementally, fetching full archive", "repo", repo.Name, "commit", commit, "error", err)
+	}
+
+	var r io.ReadCloser
+	switch {
+	case ancestor == nil:
+		r, err = s.FetchTar(ctx, repo, commit)
+	case len(ancestor.fetchPaths()) == 0:
+		// Nothing to fetch. Note that FetchTarPaths would return all paths.
+		r, err = newEmptyTar()
+	default:
+		r, err = s.FetchTarPaths(ctx, repo, commit, ancestor.fetchPaths())
+	}
 	if err != nil {
+		if ancestor != nil {
+			ancestor.Close()
+		}
 		return nil, err
 	}
 
@@ -240,7 +283,14 @@ func (s *Store) fetch(ctx context.Context, repo gitserver.Repo, commit api.Commi
 		defer r.Close()
 		tr := tar.NewReader(r)
 		zw := zip.NewWriter(pw)
-		err := copySearchable(tr, zw, largeFilePatterns)
+		var err error
+		if ancestor != nil {
+			err = ancestor.copyUnchanged(zw)
+			ancestor.Close()
+		}
+		if err == nil {
+			err = copySearchable(tr, zw, largeFilePatterns)
+		}
 		if err1 := zw.Close(); err == nil {
 			err = err1
 		}
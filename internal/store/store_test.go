@@ -2,6 +2,7 @@ package store
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"context"
 	"io"
@@ -11,9 +12,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
 func TestPrepareZip(t *testing.T) {
@@ -117,6 +120,55 @@ func TestPrepareZip_errHeader(t *testing.T) {
 	}
 }
 
+func TestPrepareZip_incremental(t *testing.T) {
+	s, cleanup := tmpStore(t)
+	defer cleanup()
+
+	repo := gitserver.Repo{Name: "foo"}
+	commit1 := api.CommitID("1111111111111111111111111111111111111111")
+	commit2 := api.CommitID("2222222222222222222222222222222222222222")
+	files := map[api.CommitID]map[string]string{
+		commit1: {"a": "a1", "b": "b1", "c": "c1"},
+		commit2: {"a": "a2", "c": "c1", "d": "d2"},
+	}
+
+	var fetchedPaths []string
+	s.FetchTar = func(ctx context.Context, repo gitserver.Repo, commit api.CommitID) (io.ReadCloser, error) {
+		return tarArchive(t, files[commit]), nil
+	}
+	s.FetchTarPaths = func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, paths []string) (io.ReadCloser, error) {
+		fetchedPaths = paths
+		fetched := map[string]string{}
+		for _, path := range paths {
+			fetched[path] = files[commit][path]
+		}
+		return tarArchive(t, fetched), nil
+	}
+	s.ListAncestors = func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, n int) ([]api.CommitID, error) {
+		if commit == commit2 {
+			return []api.CommitID{commit1}, nil
+		}
+		return nil, nil
+	}
+	s.GitDiff = func(ctx context.Context, repo gitserver.Repo, base, head api.CommitID) (*git.Changes, error) {
+		return &git.Changes{Added: []string{"d"}, Modified: []string{"a"}, Deleted: []string{"b"}}, nil
+	}
+
+	for _, commit := range []api.CommitID{commit1, commit2} {
+		path, err := s.PrepareZip(context.Background(), repo, commit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(files[commit], readZip(t, path)); diff != "" {
+			t.Errorf("unexpected zip contents for %s: %s", commit, diff)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"d", "a"}, fetchedPaths); diff != "" {
+		t.Errorf("unexpected fetched paths: %s", diff)
+	}
+}
+
 func TestIngoreSizeMax(t *testing.T) {
 	patterns := []string{
 		"foo",
@@ -158,6 +210,52 @@ func tmpStore(t *testing.T) (*Store, func()) {
 	}, func() { os.RemoveAll(d) }
 }
 
+func tarArchive(t *testing.T, files map[string]string) io.ReadCloser {
+	buf := new(bytes.Buffer)
+	w := tar.NewWriter(buf)
+	for name, body := range files {
+		err := w.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0600,
+			Size:     int64(len(body)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(buf.Bytes()))
+}
+
+func readZip(t *testing.T, path string) map[string]string {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(b)
+	}
+	return files
+}
+
 func emptyTar(t *testing.T) io.ReadCloser {
 	buf := new(bytes.Buffer)
 	w := tar.NewWriter(buf)
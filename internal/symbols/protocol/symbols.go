@@ -33,6 +33,25 @@ type SearchArgs struct {
 	// need to match to get included in the result
 	ExcludePattern string
 
+	// Kinds, if set, restricts the result to symbols whose kind (as
+	// reported by ctags, e.g. "function" or "method") is one of Kinds. Kinds
+	// are compared case insensitively.
+	Kinds []string
+
+	// ExcludeKinds is an optional list of kinds that symbols must not have
+	// to get included in the result.
+	ExcludeKinds []string
+
+	// ParentPatterns is a list of regexes that the name of a symbol's
+	// parent (the type, class or namespace containing it) needs to match to
+	// get included in the result. Like IncludePatterns, the patterns are
+	// ANDed together.
+	ParentPatterns []string
+
+	// ExcludeParentPattern is an optional regex that the name of a symbol's
+	// parent must not match to get included in the result.
+	ExcludeParentPattern string
+
 	// First indicates that only the first n symbols should be returned.
 	First int
 }
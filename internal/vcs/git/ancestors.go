@@ -0,0 +1,37 @@
+package git
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// Limits for services that compute data from the files of a commit (such as
+// searcher archives and symbols databases) incrementally, by updating the
+// cached data of an ancestor commit with the files changed since.
+const (
+	// MaxAncestorsToCheck is the number of ancestors of a commit that are
+	// checked for cached data.
+	MaxAncestorsToCheck = 100
+
+	// MaxChangedPaths is the number of changed paths above which the data of
+	// a commit is computed from scratch rather than updated from the data of
+	// an ancestor. The changed paths are passed to gitserver as arguments of
+	// `git archive`, which must stay reasonably short.
+	MaxChangedPaths = 1000
+)
+
+// Ancestors returns up to n ancestors of commit, nearest first.
+func Ancestors(ctx context.Context, repo gitserver.Repo, commit api.CommitID, n int) ([]api.CommitID, error) {
+	// The first commit of the log is commit itself.
+	commits, err := Commits(ctx, repo, CommitsOptions{Range: string(commit), N: uint(n) + 1, NoEnsureRevision: true})
+	if err != nil || len(commits) == 0 {
+		return nil, err
+	}
+	ancestors := make([]api.CommitID, 0, len(commits)-1)
+	for _, c := range commits[1:] {
+		ancestors = append(ancestors, c.ID)
+	}
+	return ancestors, nil
+}
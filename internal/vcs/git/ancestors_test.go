@@ -0,0 +1,37 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestAncestors(t *testing.T) {
+	repo := MakeGitRepository(t,
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:08Z git commit --allow-empty -m baz --author='a <a@a.com>' --date 2006-01-02T15:04:08Z",
+	)
+	head, err := ResolveRevision(ctx, repo, nil, "HEAD", ResolveRevisionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ancestors, err := Ancestors(ctx, repo, head, MaxAncestorsToCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []api.CommitID{"b266c7e3ca00b1a17ad0b1449825d0854225c007", "ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"}
+	if diff := cmp.Diff(want, ancestors); diff != "" {
+		t.Errorf("unexpected ancestors (-want +got):\n%s", diff)
+	}
+
+	ancestors, err = Ancestors(ctx, repo, head, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want[:1], ancestors); diff != "" {
+		t.Errorf("unexpected ancestors (-want +got):\n%s", diff)
+	}
+}
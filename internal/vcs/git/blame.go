@@ -3,6 +3,7 @@ package git
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -21,6 +22,18 @@ type BlameOptions struct {
 
 	StartLine int `json:",omitempty" url:",omitempty"` // 1-indexed start byte (or 0 for beginning of file)
 	EndLine   int `json:",omitempty" url:",omitempty"` // 1-indexed end byte (or 0 for end of file)
+
+	// IgnoreRevs are commits whose changes are ignored, so that lines they
+	// changed are blamed on the commit that previously changed them (see
+	// BlameIgnoreRevs).
+	IgnoreRevs []api.CommitID `json:",omitempty" url:",omitempty"`
+	// DetectMoves blames lines that were moved or copied within the file on
+	// the commit that originally added them (git blame -M).
+	DetectMoves bool `json:",omitempty" url:",omitempty"`
+	// DetectCopies blames lines that were moved or copied from other files
+	// modified in the same commit on the commit that originally added them
+	// (git blame -C).
+	DetectCopies bool `json:",omitempty" url:",omitempty"`
 }
 
 // A Hunk is a contiguous portion of a file associated with a commit.
@@ -32,6 +45,16 @@ type Hunk struct {
 	api.CommitID
 	Author  Signature
 	Message string
+
+	// Filename is the path of the file in CommitID, which differs from the
+	// blamed path if the file was renamed or the hunk was moved or copied
+	// from another file.
+	Filename string
+	// PreviousCommit and PreviousFilename identify the file that CommitID
+	// changed, which can be blamed to see the history before this hunk. They
+	// are empty if CommitID added the file.
+	PreviousCommit   api.CommitID `json:",omitempty"`
+	PreviousFilename string       `json:",omitempty"`
 }
 
 // BlameFile returns Git blame information about a file.
@@ -44,6 +67,42 @@ func BlameFile(ctx context.Context, repo gitserver.Repo, path string, opt *Blame
 	return blameFileCmd(ctx, gitserverCmdFunc(repo), path, opt)
 }
 
+// blameIgnoreRevsFile is the conventional name of the file that lists the
+// commits that git blame should ignore, such as commits that only reformat
+// code.
+const blameIgnoreRevsFile = ".git-blame-ignore-revs"
+
+// BlameIgnoreRevs returns the commits listed in the .git-blame-ignore-revs
+// file at the root of the tree of commit, or nil if there is no such file.
+// Like git, it expects one full commit ID per line and ignores comments
+// starting with "#".
+func BlameIgnoreRevs(ctx context.Context, repo gitserver.Repo, commit api.CommitID) ([]api.CommitID, error) {
+	data, err := ReadFile(ctx, repo, commit, blameIgnoreRevsFile, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseBlameIgnoreRevs(data), nil
+}
+
+func parseBlameIgnoreRevs(data []byte) []api.CommitID {
+	var revs []api.CommitID
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.IndexByte(line, '#'); i != -1 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		// Skip invalid lines rather than failing the blame. This also ensures
+		// that the revs are safe to pass as arguments.
+		if IsAbsoluteRevision(line) {
+			revs = append(revs, api.CommitID(line))
+		}
+	}
+	return revs
+}
+
 func blameFileCmd(ctx context.Context, command cmdFunc, path string, opt *BlameOptions) ([]*Hunk, error) {
 	if opt == nil {
 		opt = &BlameOptions{}
@@ -62,6 +121,18 @@ func blameFileCmd(ctx context.Context, command cmdFunc, path string, opt *BlameO
 	if opt.StartLine != 0 || opt.EndLine != 0 {
 		args = append(args, fmt.Sprintf("-L%d,%d", opt.StartLine, opt.EndLine))
 	}
+	for _, rev := range opt.IgnoreRevs {
+		if !IsAbsoluteRevision(string(rev)) {
+			return nil, fmt.Errorf("invalid commit to ignore: %q", rev)
+		}
+		args = append(args, "--ignore-rev="+string(rev))
+	}
+	if opt.DetectMoves {
+		args = append(args, "-M")
+	}
+	if opt.DetectCopies {
+		args = append(args, "-C")
+	}
 	args = append(args, string(opt.NewestCommit), "--", filepath.ToSlash(path))
 
 	out, err := command(args).Output(ctx)
@@ -71,89 +142,121 @@ func blameFileCmd(ctx context.Context, command cmdFunc, path string, opt *BlameO
 	if len(out) == 0 {
 		return nil, nil
 	}
+	return parseBlamePorcelain(string(out[:len(out)-1]))
+}
+
+// blameCommit is the information about a commit that git blame --porcelain
+// prints the first time the commit occurs.
+type blameCommit struct {
+	commit Commit
+
+	// The filename information of the last hunk of the commit. It is only
+	// repeated for later hunks if the commit occurs with more than one
+	// filename.
+	filename         string
+	previousCommit   api.CommitID
+	previousFilename string
+}
 
-	commits := make(map[string]Commit)
+// parseBlamePorcelain parses the output of git blame --porcelain. Each line of
+// the file is preceded by a header line "<commit> <original line> <final
+// line>", which also has the number of lines if it starts a hunk. The first
+// time a commit occurs, the header is followed by "<key> <value>" lines with
+// information about the commit. The line itself is prefixed with a tab.
+//
+// The "previous" and "filename" lines describe the hunk rather than the
+// commit: with -M and -C, a commit can occur with more than one filename, and
+// then git repeats them for each of its hunks, omitting "previous" if that
+// filename has no previous one.
+func parseBlamePorcelain(out string) ([]*Hunk, error) {
+	commits := make(map[string]*blameCommit)
 	hunks := make([]*Hunk, 0)
-	remainingLines := strings.Split(string(out[:len(out)-1]), "\n")
+	lines := strings.Split(out, "\n")
 	byteOffset := 0
-	for len(remainingLines) > 0 {
-		// Consume hunk
-		hunkHeader := strings.Split(remainingLines[0], " ")
-		if len(hunkHeader) != 4 {
-			return nil, fmt.Errorf("Expected at least 4 parts to hunkHeader, but got: '%s'", hunkHeader)
+	var hunk *Hunk
+	for i := 0; i < len(lines); {
+		header := strings.Split(lines[i], " ")
+		if len(header) != 3 && len(header) != 4 {
+			return nil, fmt.Errorf("Expected 3 or 4 parts to blame header, but got: %q", lines[i])
 		}
-		commitID := hunkHeader[0]
-		lineNoCur, _ := strconv.Atoi(hunkHeader[2])
-		nLines, _ := strconv.Atoi(hunkHeader[3])
-		hunk := &Hunk{
-			CommitID:  api.CommitID(commitID),
-			StartLine: int(lineNoCur),
-			EndLine:   int(lineNoCur + nLines),
-			StartByte: byteOffset,
+		i++
+		commitID := header[0]
+		c, seen := commits[commitID]
+		if !seen {
+			c = &blameCommit{commit: Commit{ID: api.CommitID(commitID)}}
+			commits[commitID] = c
 		}
 
-		if _, in := commits[commitID]; in {
-			// Already seen commit
-			byteOffset += len(remainingLines[1])
-			remainingLines = remainingLines[2:]
-		} else {
-			// New commit
-			author := strings.Join(strings.Split(remainingLines[1], " ")[1:], " ")
-			email := strings.Join(strings.Split(remainingLines[2], " ")[1:], " ")
-			if len(email) >= 2 && email[0] == '<' && email[len(email)-1] == '>' {
-				email = email[1 : len(email)-1]
-			}
-			authorTime, err := strconv.ParseInt(strings.Join(strings.Split(remainingLines[3], " ")[1:], " "), 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("Failed to parse author-time %q", remainingLines[3])
+		// Consume the information about the commit and the hunk.
+		var (
+			previousCommit   api.CommitID
+			previousFilename string
+		)
+		for ; i < len(lines) && !strings.HasPrefix(lines[i], "\t"); i++ {
+			kv := strings.SplitN(lines[i], " ", 2)
+			if len(kv) != 2 {
+				// A key without a value, such as "boundary".
+				continue
 			}
-			summary := strings.Join(strings.Split(remainingLines[9], " ")[1:], " ")
-			commit := Commit{
-				ID:      api.CommitID(commitID),
-				Message: summary,
-				Author: Signature{
-					Name:  author,
-					Email: email,
-					Date:  time.Unix(authorTime, 0).UTC(),
-				},
-			}
-
-			if len(remainingLines) >= 13 && strings.HasPrefix(remainingLines[10], "previous ") {
-				byteOffset += len(remainingLines[12])
-				remainingLines = remainingLines[13:]
-			} else if len(remainingLines) >= 13 && remainingLines[10] == "boundary" {
-				byteOffset += len(remainingLines[12])
-				remainingLines = remainingLines[13:]
-			} else if len(remainingLines) >= 12 {
-				byteOffset += len(remainingLines[11])
-				remainingLines = remainingLines[12:]
-			} else if len(remainingLines) == 11 {
-				// Empty file
-				remainingLines = remainingLines[11:]
-			} else {
-				return nil, fmt.Errorf("Unexpected number of remaining lines (%d):\n%s", len(remainingLines), "  "+strings.Join(remainingLines, "\n  "))
+			key, value := kv[0], kv[1]
+			switch key {
+			case "author":
+				c.commit.Author.Name = value
+			case "author-mail":
+				if len(value) >= 2 && value[0] == '<' && value[len(value)-1] == '>' {
+					value = value[1 : len(value)-1]
+				}
+				c.commit.Author.Email = value
+			case "author-time":
+				authorTime, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to parse author-time %q", lines[i])
+				}
+				c.commit.Author.Date = time.Unix(authorTime, 0).UTC()
+			case "summary":
+				c.commit.Message = value
+			case "previous":
+				previous := strings.SplitN(value, " ", 2)
+				if len(previous) != 2 {
+					return nil, fmt.Errorf("Failed to parse previous %q", lines[i])
+				}
+				previousCommit, previousFilename = api.CommitID(previous[0]), previous[1]
+			case "filename":
+				// "filename" always follows "previous" (if any).
+				c.filename = value
+				c.previousCommit, c.previousFilename = previousCommit, previousFilename
 			}
-
-			commits[commitID] = commit
 		}
 
-		if commit, present := commits[commitID]; present {
-			// Should always be present, but check just to avoid
-			// panicking in case of a (somewhat likely) bug in our
-			// git-blame parser above.
-			hunk.CommitID = commit.ID
-			hunk.Author = commit.Author
-			hunk.Message = commit.Message
+		if len(header) == 4 {
+			// The line starts a new hunk.
+			lineNoCur, _ := strconv.Atoi(header[2])
+			nLines, _ := strconv.Atoi(header[3])
+			hunk = &Hunk{
+				CommitID:         c.commit.ID,
+				StartLine:        lineNoCur,
+				EndLine:          lineNoCur + nLines,
+				StartByte:        byteOffset,
+				Author:           c.commit.Author,
+				Message:          c.commit.Message,
+				Filename:         c.filename,
+				PreviousCommit:   c.previousCommit,
+				PreviousFilename: c.previousFilename,
+			}
+			hunks = append(hunks, hunk)
+		} else if hunk == nil {
+			return nil, fmt.Errorf("Expected blame hunk header, but got: %q", lines[i-1])
 		}
 
-		// Consume remaining lines in hunk
-		for i := 1; i < nLines; i++ {
-			byteOffset += len(remainingLines[1])
-			remainingLines = remainingLines[2:]
+		if i == len(lines) {
+			// Empty file
+			break
 		}
-
+		// The line is prefixed with a tab instead of suffixed with a
+		// newline, so its length is the number of bytes in the file.
+		byteOffset += len(lines[i])
+		i++
 		hunk.EndByte = byteOffset
-		hunks = append(hunks, hunk)
 	}
 
 	return hunks, nil
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 )
 
@@ -23,10 +24,12 @@ func TestRepository_BlameFile(t *testing.T) {
 		{
 			StartLine: 1, EndLine: 2, StartByte: 0, EndByte: 6, CommitID: "e6093374dcf5725d8517db0dccbbf69df65dbde0",
 			Message: "foo", Author: Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Filename: "f",
 		},
 		{
 			StartLine: 2, EndLine: 3, StartByte: 6, EndByte: 12, CommitID: "fad406f4fe02c358a09df0d03ec7a36c2c8a20f1",
 			Message: "foo", Author: Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")},
+			Filename: "f", PreviousCommit: "e6093374dcf5725d8517db0dccbbf69df65dbde0", PreviousFilename: "f",
 		},
 	}
 	tests := map[string]struct {
@@ -65,3 +68,142 @@ func TestRepository_BlameFile(t *testing.T) {
 		}
 	}
 }
+
+func TestRepository_BlameFile_options(t *testing.T) {
+	t.Parallel()
+
+	commit := func(author string) string {
+		return "GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m " + author + " --author='" + author + " <" + author + "@a.com>' --date 2006-01-02T15:04:05Z"
+	}
+	repo := MakeGitRepository(t,
+		"echo 'this line is certainly long enough to be detected as a copy' > h",
+		"echo x > f",
+		"git add f h",
+		commit("a"),
+		// A commit that only reformats code.
+		"echo X > f",
+		"git add f",
+		commit("b"),
+		"git rev-parse HEAD > .git-blame-ignore-revs",
+		"echo other > h",
+		"echo 'this line is certainly long enough to be detected as a copy' > g",
+		"git add .git-blame-ignore-revs g h",
+		commit("c"),
+	)
+	revs := map[string]api.CommitID{}
+	for author, spec := range map[string]string{"a": "HEAD~2", "b": "HEAD~1", "c": "HEAD"} {
+		rev, err := ResolveRevision(ctx, repo, nil, spec, ResolveRevisionOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		revs[author] = rev
+	}
+
+	ignoreRevs, err := BlameIgnoreRevs(ctx, repo, revs["c"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []api.CommitID{revs["b"]}; !reflect.DeepEqual(ignoreRevs, want) {
+		t.Fatalf("got ignore revs %v, want %v", ignoreRevs, want)
+	}
+	if ignoreRevs, err := BlameIgnoreRevs(ctx, repo, revs["a"]); err != nil || ignoreRevs != nil {
+		t.Errorf("got ignore revs %v (error %v) for commit without .git-blame-ignore-revs, want none", ignoreRevs, err)
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		opt          BlameOptions
+		wantCommit   api.CommitID
+		wantFilename string
+	}{
+		{name: "reformatting commit", path: "f", wantCommit: revs["b"], wantFilename: "f"},
+		{name: "ignore revs", path: "f", opt: BlameOptions{IgnoreRevs: ignoreRevs}, wantCommit: revs["a"], wantFilename: "f"},
+		{name: "copied line", path: "g", wantCommit: revs["c"], wantFilename: "g"},
+		{name: "detect copies", path: "g", opt: BlameOptions{DetectCopies: true}, wantCommit: revs["a"], wantFilename: "h"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.opt.NewestCommit = revs["c"]
+			hunks, err := BlameFile(ctx, repo, test.path, &test.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(hunks) != 1 {
+				t.Fatalf("got %d hunks, want 1", len(hunks))
+			}
+			if hunks[0].CommitID != test.wantCommit || hunks[0].Filename != test.wantFilename {
+				t.Errorf("got hunk of commit %s in file %q, want commit %s in file %q", hunks[0].CommitID, hunks[0].Filename, test.wantCommit, test.wantFilename)
+			}
+		})
+	}
+}
+
+func TestParseBlamePorcelain_copiedFile(t *testing.T) {
+	// The output of git blame -C for a file with lines copied from two files
+	// of the same commit. The filename of the second hunk is repeated without
+	// a previous filename, because the commit added that file.
+	out := `6f78bbe06df9afd22b9e270b4eca3bbb425c1ea7 2 1 1
+author a
+author-mail <a@a.com>
+author-time 1136214245
+author-tz +0000
+committer a
+committer-mail <a@a.com>
+committer-time 1136214245
+committer-tz +0000
+summary a1
+previous 20f0a34b1840a3ae9f2bf86953d11327cfef720a a.txt
+filename a.txt
+	an added line of file a which is also long enough to copy
+6f78bbe06df9afd22b9e270b4eca3bbb425c1ea7 1 2 1
+filename b.txt
+	the only line of file b which is long enough to be detected as a copy
+6f78bbe06df9afd22b9e270b4eca3bbb425c1ea7 2 3 1
+previous 20f0a34b1840a3ae9f2bf86953d11327cfef720a a.txt
+filename a.txt
+	an added line of file a which is also long enough to copy`
+
+	hunks, err := parseBlamePorcelain(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	author := Signature{Name: "a", Email: "a@a.com", Date: MustParseTime(time.RFC3339, "2006-01-02T15:04:05Z")}
+	want := []*Hunk{
+		{
+			StartLine: 1, EndLine: 2, StartByte: 0, EndByte: 58, CommitID: "6f78bbe06df9afd22b9e270b4eca3bbb425c1ea7",
+			Message: "a1", Author: author,
+			Filename: "a.txt", PreviousCommit: "20f0a34b1840a3ae9f2bf86953d11327cfef720a", PreviousFilename: "a.txt",
+		},
+		{
+			StartLine: 2, EndLine: 3, StartByte: 58, EndByte: 128, CommitID: "6f78bbe06df9afd22b9e270b4eca3bbb425c1ea7",
+			Message: "a1", Author: author,
+			Filename: "b.txt",
+		},
+		{
+			StartLine: 3, EndLine: 4, StartByte: 128, EndByte: 186, CommitID: "6f78bbe06df9afd22b9e270b4eca3bbb425c1ea7",
+			Message: "a1", Author: author,
+			Filename: "a.txt", PreviousCommit: "20f0a34b1840a3ae9f2bf86953d11327cfef720a", PreviousFilename: "a.txt",
+		},
+	}
+	if !reflect.DeepEqual(hunks, want) {
+		for _, h := range hunks {
+			t.Logf("%+v", h)
+		}
+		t.Errorf("got hunks %+v, want %+v", hunks, want)
+	}
+}
+
+func TestParseBlameIgnoreRevs(t *testing.T) {
+	data := []byte(`# Reformat with gofmt
+e6093374dcf5725d8517db0dccbbf69df65dbde0
+  fad406f4fe02c358a09df0d03ec7a36c2c8a20f1 # Rename package
+
+e609337 (abbreviated)
+--output=/etc/passwd
+`)
+	want := []api.CommitID{"e6093374dcf5725d8517db0dccbbf69df65dbde0", "fad406f4fe02c358a09df0d03ec7a36c2c8a20f1"}
+	if got := parseBlameIgnoreRevs(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+// Changes are the paths of the files that differ between two commits.
+type Changes struct {
+	Added    []string
+	Modified []string
+	Deleted  []string
+}
+
+// DiffPaths returns the paths of the files that changed between the commits
+// base and head. Renamed files are reported as a deleted and an added path.
+func DiffPaths(ctx context.Context, repo gitserver.Repo, base, head api.CommitID) (*Changes, error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: DiffPaths")
+	span.SetTag("Base", base)
+	span.SetTag("Head", head)
+	defer span.Finish()
+
+	if err := checkSpecArgSafety(string(base)); err != nil {
+		return nil, err
+	}
+	if err := checkSpecArgSafety(string(head)); err != nil {
+		return nil, err
+	}
+
+	rdr, err := ExecReader(ctx, repo, []string{"diff", "--name-status", "--no-renames", "-z", string(base), string(head), "--"})
+	if err != nil {
+		return nil, errors.Wrap(err, "executing git diff")
+	}
+	defer rdr.Close()
+
+	out, err := ioutil.ReadAll(rdr)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading git diff output")
+	}
+
+	return parseDiffNameStatus(out)
+}
+
+// parseDiffNameStatus parses the output of `git diff --name-status -z
+// --no-renames`, which is a NUL-separated list of alternating statuses and
+// paths.
+func parseDiffNameStatus(out []byte) (*Changes, error) {
+	fields := bytes.Split(bytes.TrimRight(out, "\x00"), []byte{0})
+	if len(fields) == 1 && len(fields[0]) == 0 {
+		return &Changes{}, nil
+	}
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("unexpected git diff output: %q", out)
+	}
+
+	changes := &Changes{}
+	for i := 0; i < len(fields); i += 2 {
+		status, path := string(fields[i]), string(fields[i+1])
+		switch status {
+		case "A":
+			changes.Added = append(changes.Added, path)
+		case "M", "T":
+			changes.Modified = append(changes.Modified, path)
+		case "D":
+			changes.Deleted = append(changes.Deleted, path)
+		default:
+			return nil, fmt.Errorf("unexpected git diff status %q for path %q", status, path)
+		}
+	}
+	return changes, nil
+}
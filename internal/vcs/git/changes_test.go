@@ -0,0 +1,64 @@
+package git
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+func TestDiffPaths(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid commits", func(t *testing.T) {
+		if _, err := DiffPaths(ctx, gitserver.Repo{Name: "r"}, "-a", "b"); err == nil {
+			t.Error("unexpected nil error")
+		}
+		if _, err := DiffPaths(ctx, gitserver.Repo{Name: "r"}, "a", "-b"); err == nil {
+			t.Error("unexpected nil error")
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
+			want := []string{"diff", "--name-status", "--no-renames", "-z", "a", "b", "--"}
+			if diff := cmp.Diff(want, args); diff != "" {
+				t.Errorf("unexpected args: %s", diff)
+			}
+			return ioutil.NopCloser(strings.NewReader("A\x00new.go\x00M\x00changed.go\x00T\x00link\x00D\x00old go.go\x00")), nil
+		}
+		defer ResetMocks()
+
+		changes, err := DiffPaths(ctx, gitserver.Repo{Name: "r"}, "a", "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &Changes{
+			Added:    []string{"new.go"},
+			Modified: []string{"changed.go", "link"},
+			Deleted:  []string{"old go.go"},
+		}
+		if diff := cmp.Diff(want, changes); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader("")), nil
+		}
+		defer ResetMocks()
+
+		changes, err := DiffPaths(ctx, gitserver.Repo{Name: "r"}, "a", "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(&Changes{}, changes); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
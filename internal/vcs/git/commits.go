@@ -41,6 +41,7 @@ type CommitsOptions struct {
 
 	Author string // include only commits whose author matches this
 	After  string // include only commits after this date
+	Before string // include only commits before this date
 
 	Path string // only commits modifying the given path are selected (optional)
 
@@ -143,6 +144,50 @@ func HasCommitAfter(ctx context.Context, repo gitserver.Repo, date string, revsp
 	return n > 0, err
 }
 
+// LastCommitBefore returns the ID of the last commit reachable from revspec
+// (or HEAD, if revspec is empty) that was committed before the specified date.
+// It returns a RevisionNotFoundError if there is no such commit.
+func LastCommitBefore(ctx context.Context, repo gitserver.Repo, date string, revspec string) (api.CommitID, error) {
+	if Mocks.LastCommitBefore != nil {
+		return Mocks.LastCommitBefore(repo, date, revspec)
+	}
+
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: LastCommitBefore")
+	span.SetTag("Date", date)
+	span.SetTag("RevSpec", revspec)
+	defer span.Finish()
+
+	if revspec == "" {
+		revspec = "HEAD"
+	}
+
+	commitid, err := ResolveRevision(ctx, repo, nil, revspec, ResolveRevisionOptions{NoEnsureRevision: true})
+	if err != nil {
+		return "", err
+	}
+
+	args, err := commitLogArgs([]string{"rev-list"}, CommitsOptions{
+		N:      1,
+		Before: date,
+		Range:  string(commitid),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", args...)
+	cmd.Repo = repo
+	out, err := cmd.CombinedOutput(ctx)
+	if err != nil {
+		return "", errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+	}
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return "", &gitserver.RevisionNotFoundError{Repo: repo.Name, Spec: fmt.Sprintf("%s (before %s)", revspec, date)}
+	}
+	return api.CommitID(out), nil
+}
+
 func isBadObjectErr(output, obj string) bool {
 	return output == "fatal: bad object "+obj
 }
@@ -226,6 +271,10 @@ func commitLogArgs(initialArgs []string, opt CommitsOptions) (args []string, err
 		args = append(args, "--after="+opt.After)
 	}
 
+	if opt.Before != "" {
+		args = append(args, "--before="+opt.Before)
+	}
+
 	if opt.MessageQuery != "" {
 		args = append(args, "--fixed-strings", "--regexp-ignore-case", "--grep="+opt.MessageQuery)
 	}
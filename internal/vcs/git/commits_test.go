@@ -156,6 +156,39 @@ func TestRepository_HasCommitAfter(t *testing.T) {
 	}
 }
 
+func TestRepository_LastCommitBefore(t *testing.T) {
+	t.Parallel()
+
+	gitCommands := []string{
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m foo --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"GIT_COMMITTER_NAME=c GIT_COMMITTER_EMAIL=c@c.com GIT_COMMITTER_DATE=2006-01-02T15:04:07Z git commit --allow-empty -m bar --author='a <a@a.com>' --date 2006-01-02T15:04:06Z",
+	}
+	repo := MakeGitRepository(t, gitCommands...)
+
+	testCases := []struct {
+		before  string
+		revspec string
+		want    api.CommitID
+	}{
+		{before: "2006-01-02T15:04:06Z", revspec: "", want: "ea167fe3d76b1e5fd3ed8ca44cbd2fe3897684f8"},
+		{before: "2006-01-02T15:04:07Z", revspec: "master", want: "b266c7e3ca00b1a17ad0b1449825d0854225c007"},
+		{before: "2010-01-02T15:04:05Z", revspec: "HEAD", want: "b266c7e3ca00b1a17ad0b1449825d0854225c007"},
+	}
+	for _, tc := range testCases {
+		got, err := LastCommitBefore(ctx, repo, tc.before, tc.revspec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("before %s: got %s, want %s", tc.before, got, tc.want)
+		}
+	}
+
+	if _, err := LastCommitBefore(ctx, repo, "2005-01-02T15:04:05Z", "HEAD"); !gitserver.IsRevisionNotFound(err) {
+		t.Errorf("got err %v, want RevisionNotFoundError", err)
+	}
+}
+
 func TestRepository_Commits(t *testing.T) {
 	t.Parallel()
 
@@ -133,6 +133,7 @@ var (
 		"--find-copies",
 		"--find-renames",
 		"--inter-hunk-context",
+		"--no-renames",
 	}
 )
 
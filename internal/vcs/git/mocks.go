@@ -20,11 +20,14 @@ var Mocks, emptyMocks struct {
 	NewFileReader    func(commit api.CommitID, name string) (io.ReadCloser, error)
 	ReadFile         func(commit api.CommitID, name string) ([]byte, error)
 	ReadDir          func(commit api.CommitID, name string, recurse bool) ([]os.FileInfo, error)
+	ListFiles        func(commit api.CommitID) ([]string, error)
 	ResolveRevision  func(spec string, opt ResolveRevisionOptions) (api.CommitID, error)
 	Stat             func(commit api.CommitID, name string) (os.FileInfo, error)
 	GetObject        func(objectName string) (OID, ObjectType, error)
 	Commits          func(repo gitserver.Repo, opt CommitsOptions) ([]*Commit, error)
 	MergeBase        func(repo gitserver.Repo, a, b api.CommitID) (api.CommitID, error)
+	LastCommitBefore func(repo gitserver.Repo, date, revspec string) (api.CommitID, error)
+	RefsContaining   func(revArgs []string, commits []api.CommitID) (map[api.CommitID][]string, error)
 }
 
 // ResetMocks clears the mock functions set on Mocks (so that subsequent tests don't inadvertently
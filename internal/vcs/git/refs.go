@@ -1,9 +1,11 @@
 package git
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"sort"
 	"strconv"
 	"strings"
@@ -300,6 +302,114 @@ func ListRefs(ctx context.Context, repo gitserver.Repo) ([]Ref, error) {
 	return showRef(ctx, repo)
 }
 
+// RefsContaining returns, for each of the commits, the full names of the refs (e.g.,
+// "refs/heads/mybranch") from which it is reachable, sorted by name. The history reachable from
+// revArgs (e.g., "--glob=refs/heads/*") is walked once for all of the commits, so only the refs
+// pointing into it are returned, and commits that are not reachable from revArgs are omitted.
+func RefsContaining(ctx context.Context, repo gitserver.Repo, revArgs []string, commits []api.CommitID) (map[api.CommitID][]string, error) {
+	if Mocks.RefsContaining != nil {
+		return Mocks.RefsContaining(revArgs, commits)
+	}
+
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: RefsContaining")
+	span.SetTag("RevArgs", revArgs)
+	span.SetTag("Commits", len(commits))
+	defer span.Finish()
+
+	remaining := make(map[api.CommitID]struct{}, len(commits))
+	for _, commit := range commits {
+		if err := ensureAbsoluteCommit(commit); err != nil {
+			return nil, err
+		}
+		remaining[commit] = struct{}{}
+	}
+	if len(remaining) == 0 {
+		return nil, nil
+	}
+
+	// With --topo-order, git lists every commit before its parents, so the refs containing a
+	// commit are known when it is listed: they are the refs pointing to it and the refs
+	// containing its children. This lets us stop reading once all of the commits are listed.
+	args := append([]string{"log", "--topo-order", "--decorate=full", "--format=%H%x00%P%x00%D"}, revArgs...)
+	cmd := gitserver.DefaultClient.Command("git", args...)
+	cmd.Repo = repo
+	rc, err := gitserver.StdoutReader(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	refs := make(map[api.CommitID][]string, len(remaining))
+	pending := map[api.CommitID][]string{} // the refs containing children of unlisted commits
+	r := bufio.NewReader(rc)
+	for len(remaining) > 0 {
+		line, err := r.ReadString('\n')
+		if err == io.EOF && line == "" {
+			break
+		} else if err != nil && err != io.EOF {
+			return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed", cmd.Args))
+		}
+
+		parts := strings.Split(strings.TrimSuffix(line, "\n"), "\x00")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid git log output line: %q", line)
+		}
+		commit := api.CommitID(parts[0])
+		containing := mergeSortedRefs(pending[commit], decorationRefs(parts[2]))
+		delete(pending, commit)
+		if _, ok := remaining[commit]; ok {
+			refs[commit] = containing
+			delete(remaining, commit)
+		}
+		for _, parent := range strings.Fields(parts[1]) {
+			pending[api.CommitID(parent)] = mergeSortedRefs(pending[api.CommitID(parent)], containing)
+		}
+	}
+	return refs, nil
+}
+
+// decorationRefs returns the sorted full ref names in a `git log --decorate=full` %D decoration
+// (e.g., "HEAD -> refs/heads/master, tag: refs/tags/t").
+func decorationRefs(decoration string) []string {
+	if decoration == "" {
+		return nil
+	}
+	var refs []string
+	for _, ref := range strings.Split(decoration, ", ") {
+		ref = strings.TrimPrefix(ref, "HEAD -> ")
+		ref = strings.TrimPrefix(ref, "tag: ")
+		if ref != "HEAD" {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// mergeSortedRefs returns the sorted union of the sorted ref names a and b. It returns a or b
+// itself if the other is empty, so callers must not modify the result.
+func mergeSortedRefs(a, b []string) []string {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+	merged := make([]string, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		switch {
+		case a[0] < b[0]:
+			merged, a = append(merged, a[0]), a[1:]
+		case a[0] > b[0]:
+			merged, b = append(merged, b[0]), b[1:]
+		default:
+			merged, a, b = append(merged, a[0]), a[1:], b[1:]
+		}
+	}
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
+
 // Ref describes a Git ref.
 type Ref struct {
 	Name     string // the full name of the ref (e.g., "refs/heads/mybranch")
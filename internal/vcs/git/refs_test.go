@@ -162,6 +162,88 @@ func TestRepository_Branches_ContainsCommit(t *testing.T) {
 	}
 }
 
+func TestRefsContaining(t *testing.T) {
+	t.Parallel()
+
+	repo := MakeGitRepository(t,
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m base --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git tag t",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m master --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout HEAD^ -b branch2",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m branch2 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	)
+
+	want := map[api.CommitID][]string{
+		"2816a72df28f699722156e545d038a5203b959de": {"refs/heads/branch2", "refs/heads/master", "refs/tags/t"},
+		"1224d334dfe08f4693968ea618ad63ae86ec16ca": {"refs/heads/master"},
+		"920c0e9d7b287b030ac9770fd7ba3ee9dc1760d9": {"refs/heads/branch2"},
+	}
+	commits := []api.CommitID{
+		"2816a72df28f699722156e545d038a5203b959de",
+		"1224d334dfe08f4693968ea618ad63ae86ec16ca",
+		"920c0e9d7b287b030ac9770fd7ba3ee9dc1760d9",
+		"1234567890123456789012345678901234567890",
+	}
+	refs, err := RefsContaining(ctx, repo, []string{"--glob=refs/heads/*"}, commits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("got refs %q, want %q", refs, want)
+	}
+
+	// Only the history reachable from the rev args is walked.
+	refs, err = RefsContaining(ctx, repo, []string{"refs/heads/branch2"}, commits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[api.CommitID][]string{
+		"2816a72df28f699722156e545d038a5203b959de": {"refs/heads/branch2", "refs/tags/t"},
+		"920c0e9d7b287b030ac9770fd7ba3ee9dc1760d9": {"refs/heads/branch2"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("got refs %q, want %q", refs, want)
+	}
+}
+
+func TestRefsContaining_merge(t *testing.T) {
+	t.Parallel()
+
+	repo := MakeGitRepository(t,
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m base --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout -b a",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m a --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout master -b b",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit --allow-empty -m b --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+		"git checkout -b merged",
+		"GIT_AUTHOR_NAME=a GIT_AUTHOR_EMAIL=a@a.com GIT_AUTHOR_DATE=2006-01-02T15:04:05Z GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git merge --no-ff -m merge a",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git tag -a -m tag v1 a",
+	)
+
+	commits := map[string]api.CommitID{}
+	for _, rev := range []string{"master", "a", "b", "merged"} {
+		commit, err := ResolveRevision(ctx, repo, nil, rev, ResolveRevisionOptions{NoEnsureRevision: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits[rev] = commit
+	}
+
+	refs, err := RefsContaining(ctx, repo, []string{"--all"}, []api.CommitID{commits["master"], commits["a"], commits["b"], commits["merged"]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[api.CommitID][]string{
+		commits["master"]: {"refs/heads/a", "refs/heads/b", "refs/heads/master", "refs/heads/merged", "refs/tags/v1"},
+		commits["a"]:      {"refs/heads/a", "refs/heads/merged", "refs/tags/v1"},
+		commits["b"]:      {"refs/heads/b", "refs/heads/merged"},
+		commits["merged"]: {"refs/heads/merged"},
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("got refs %q, want %q", refs, want)
+	}
+}
+
 func TestRepository_Branches_BehindAheadCounts(t *testing.T) {
 	t.Parallel()
 
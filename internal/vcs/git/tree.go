@@ -121,6 +121,51 @@ func ReadDir(ctx context.Context, repo gitserver.Repo, commit api.CommitID, path
 	return lsTree(ctx, repo, commit, path, recurse)
 }
 
+// ListFiles returns the paths of all files (blobs, including symlinks) in the
+// tree of commit. It is cheaper than a recursive ReadDir because it does not
+// compute file sizes or list directories and submodules.
+func ListFiles(ctx context.Context, repo gitserver.Repo, commit api.CommitID) ([]string, error) {
+	if Mocks.ListFiles != nil {
+		return Mocks.ListFiles(commit)
+	}
+
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: ListFiles")
+	span.SetTag("Commit", commit)
+	defer span.Finish()
+
+	if err := ensureAbsoluteCommit(commit); err != nil {
+		return nil, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "ls-tree", "-r", "-z", "--full-tree", string(commit))
+	cmd.Repo = repo
+	out, err := cmd.CombinedOutput(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+	}
+
+	var paths []string
+	for _, line := range bytes.Split(out, []byte{0}) {
+		if len(line) == 0 {
+			continue
+		}
+		// Each line is "<mode> SP <type> SP <object> TAB <path>".
+		tabPos := bytes.IndexByte(line, '\t')
+		if tabPos == -1 {
+			return nil, fmt.Errorf("invalid `git ls-tree` output: %q", line)
+		}
+		info := bytes.SplitN(line[:tabPos], []byte(" "), 3)
+		if len(info) != 3 {
+			return nil, fmt.Errorf("invalid `git ls-tree` output: %q", line)
+		}
+		if string(info[1]) != "blob" {
+			continue
+		}
+		paths = append(paths, string(line[tabPos+1:]))
+	}
+	return paths, nil
+}
+
 // lsTreeRootCache caches the result of running `git ls-tree ...` on a repository's root path
 // (because non-root paths are likely to have a lower cache hit rate). It is intended to improve the
 // perceived performance of large monorepos, where the tree for a given repo+commit (usually the
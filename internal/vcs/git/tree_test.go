@@ -442,3 +442,34 @@ func TestRepository_FileSystem_gitSubmodules(t *testing.T) {
 		}
 	}
 }
+
+func TestListFiles(t *testing.T) {
+	t.Parallel()
+
+	submodDir := InitGitRepository(t,
+		"touch f",
+		"git add f",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m commit1 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	)
+	repo := MakeGitRepository(t,
+		"mkdir -p dir1/dir2",
+		"touch file1 dir1/file2 dir1/dir2/file3 'dir1/file with spaces'",
+		"ln -s file1 link1",
+		"git -c protocol.file.allow=always submodule add "+filepath.ToSlash(submodDir)+" submod",
+		"git add .",
+		"GIT_COMMITTER_NAME=a GIT_COMMITTER_EMAIL=a@a.com GIT_COMMITTER_DATE=2006-01-02T15:04:05Z git commit -m commit1 --author='a <a@a.com>' --date 2006-01-02T15:04:05Z",
+	)
+	commitID, err := ResolveRevision(ctx, repo, nil, "master", ResolveRevisionOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := ListFiles(ctx, repo, commitID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".gitmodules", "dir1/dir2/file3", "dir1/file with spaces", "dir1/file2", "file1", "link1"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("got %q, want %q", paths, want)
+	}
+}
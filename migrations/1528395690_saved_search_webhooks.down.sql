@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE saved_searches DROP COLUMN IF EXISTS webhooks;
+
+COMMIT;
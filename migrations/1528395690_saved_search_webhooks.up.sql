@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS webhooks jsonb NOT NULL DEFAULT '[]';
+
+COMMIT;
@@ -80,6 +80,10 @@
 // 1528395688_add_cloned_column_to_repo.up.sql (154B)
 // 1528395689_lsif_indexable_repositories_enable.down.sql (78B)
 // 1528395689_lsif_indexable_repositories_enable.up.sql (85B)
+// 1528395690_saved_search_webhooks.down.sql (76B)
+// 1528395690_saved_search_webhooks.up.sql (107B)
+// 1528395691_query_runner_state_result_fingerprints.down.sql (91B)
+// 1528395691_query_runner_state_result_fingerprints.up.sql (100B)
 
 package migrations
 
@@ -1748,6 +1752,86 @@ func _1528395689_lsif_indexable_repositories_enableUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395690_saved_search_webhooksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x4c\x00\xb3\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x73\x61\x76\x65\x64\x5f\x73\x65\x61\x72\x63\x68\x65\x73\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x77\x65\x62\x68\x6f\x6f\x6b\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xbf\x1f\x7f\x9d\x4c\x00\x00\x00")
+
+func _1528395690_saved_search_webhooksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395690_saved_search_webhooksDownSql,
+		"1528395690_saved_search_webhooks.down.sql",
+	)
+}
+
+func _1528395690_saved_search_webhooksDownSql() (*asset, error) {
+	bytes, err := _1528395690_saved_search_webhooksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395690_saved_search_webhooks.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1f, 0x54, 0x45, 0xc8, 0x94, 0xe2, 0x75, 0x53, 0xeb, 0xb3, 0xa5, 0xe4, 0xa9, 0x21, 0x71, 0xc3, 0x82, 0xce, 0xb6, 0x3b, 0x28, 0x45, 0x21, 0xe0, 0x66, 0xf9, 0x35, 0x21, 0xcf, 0x76, 0x1c, 0xb4}}
+	return a, nil
+}
+
+var __1528395690_saved_search_webhooksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x6b\x00\x94\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x73\x61\x76\x65\x64\x5f\x73\x65\x61\x72\x63\x68\x65\x73\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x77\x65\x62\x68\x6f\x6f\x6b\x73\x20\x6a\x73\x6f\x6e\x62\x20\x4e\x4f\x54\x20\x4e\x55\x4c\x4c\x20\x44\x45\x46\x41\x55\x4c\x54\x20\x27\x5b\x5d\x27\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x82\x44\xea\x48\x6b\x00\x00\x00")
+
+func _1528395690_saved_search_webhooksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395690_saved_search_webhooksUpSql,
+		"1528395690_saved_search_webhooks.up.sql",
+	)
+}
+
+func _1528395690_saved_search_webhooksUpSql() (*asset, error) {
+	bytes, err := _1528395690_saved_search_webhooksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395690_saved_search_webhooks.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x18, 0xd, 0x1b, 0xca, 0xe0, 0xf, 0x26, 0x1e, 0xf0, 0x9c, 0x69, 0xeb, 0xdc, 0xb6, 0x33, 0xaa, 0xc0, 0x1f, 0x5e, 0x12, 0xd3, 0x47, 0x37, 0x5d, 0x53, 0xca, 0x23, 0x8a, 0xa7, 0x30, 0xe8, 0x6e}}
+	return a, nil
+}
+
+var __1528395691_query_runner_state_result_fingerprintsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x5b\x00\xa4\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x71\x75\x65\x72\x79\x5f\x72\x75\x6e\x6e\x65\x72\x5f\x73\x74\x61\x74\x65\x20\x44\x52\x4f\x50\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x73\x75\x6c\x74\x5f\x66\x69\x6e\x67\x65\x72\x70\x72\x69\x6e\x74\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x3f\xc1\x05\x0b\x5b\x00\x00\x00")
+
+func _1528395691_query_runner_state_result_fingerprintsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395691_query_runner_state_result_fingerprintsDownSql,
+		"1528395691_query_runner_state_result_fingerprints.down.sql",
+	)
+}
+
+func _1528395691_query_runner_state_result_fingerprintsDownSql() (*asset, error) {
+	bytes, err := _1528395691_query_runner_state_result_fingerprintsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395691_query_runner_state_result_fingerprints.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x70, 0x42, 0x71, 0x6d, 0xf2, 0xd1, 0x4, 0x22, 0xfa, 0x99, 0x80, 0xbd, 0x20, 0xc2, 0xcc, 0x67, 0x9a, 0xcc, 0x49, 0xfb, 0x73, 0x75, 0x8a, 0x8a, 0x90, 0xda, 0xc5, 0xd, 0xb5, 0xf, 0x84, 0xc}}
+	return a, nil
+}
+
+var __1528395691_query_runner_state_result_fingerprintsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x64\x00\x9b\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x41\x4c\x54\x45\x52\x20\x54\x41\x42\x4c\x45\x20\x71\x75\x65\x72\x79\x5f\x72\x75\x6e\x6e\x65\x72\x5f\x73\x74\x61\x74\x65\x20\x41\x44\x44\x20\x43\x4f\x4c\x55\x4d\x4e\x20\x49\x46\x20\x4e\x4f\x54\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x73\x75\x6c\x74\x5f\x66\x69\x6e\x67\x65\x72\x70\x72\x69\x6e\x74\x73\x20\x6a\x73\x6f\x6e\x62\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x27\x71\x2a\xb6\x64\x00\x00\x00")
+
+func _1528395691_query_runner_state_result_fingerprintsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395691_query_runner_state_result_fingerprintsUpSql,
+		"1528395691_query_runner_state_result_fingerprints.up.sql",
+	)
+}
+
+func _1528395691_query_runner_state_result_fingerprintsUpSql() (*asset, error) {
+	bytes, err := _1528395691_query_runner_state_result_fingerprintsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395691_query_runner_state_result_fingerprints.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc7, 0x6a, 0xf7, 0x94, 0xf5, 0x21, 0xe0, 0x58, 0x15, 0x96, 0xe0, 0xee, 0x7c, 0xec, 0x42, 0x5b, 0xd0, 0xaf, 0x48, 0x7d, 0xde, 0x0, 0xf0, 0x24, 0x8e, 0xa4, 0x5, 0x4a, 0x70, 0x2d, 0xf9, 0xad}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -1919,6 +2003,10 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395688_add_cloned_column_to_repo.up.sql":                             _1528395688_add_cloned_column_to_repoUpSql,
 	"1528395689_lsif_indexable_repositories_enable.down.sql":                  _1528395689_lsif_indexable_repositories_enableDownSql,
 	"1528395689_lsif_indexable_repositories_enable.up.sql":                    _1528395689_lsif_indexable_repositories_enableUpSql,
+	"1528395690_saved_search_webhooks.down.sql":                               _1528395690_saved_search_webhooksDownSql,
+	"1528395690_saved_search_webhooks.up.sql":                                 _1528395690_saved_search_webhooksUpSql,
+	"1528395691_query_runner_state_result_fingerprints.down.sql":              _1528395691_query_runner_state_result_fingerprintsDownSql,
+	"1528395691_query_runner_state_result_fingerprints.up.sql":                _1528395691_query_runner_state_result_fingerprintsUpSql,
 }
 
 // AssetDebug is true if the assets were built with the debug flag enabled.
@@ -2045,6 +2133,10 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395688_add_cloned_column_to_repo.up.sql":                             {_1528395688_add_cloned_column_to_repoUpSql, map[string]*bintree{}},
 	"1528395689_lsif_indexable_repositories_enable.down.sql":                  {_1528395689_lsif_indexable_repositories_enableDownSql, map[string]*bintree{}},
 	"1528395689_lsif_indexable_repositories_enable.up.sql":                    {_1528395689_lsif_indexable_repositories_enableUpSql, map[string]*bintree{}},
+	"1528395690_saved_search_webhooks.down.sql":                               {_1528395690_saved_search_webhooksDownSql, map[string]*bintree{}},
+	"1528395690_saved_search_webhooks.up.sql":                                 {_1528395690_saved_search_webhooksUpSql, map[string]*bintree{}},
+	"1528395691_query_runner_state_result_fingerprints.down.sql":              {_1528395691_query_runner_state_result_fingerprintsDownSql, map[string]*bintree{}},
+	"1528395691_query_runner_state_result_fingerprints.up.sql":                {_1528395691_query_runner_state_result_fingerprintsUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
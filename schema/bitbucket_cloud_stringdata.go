@@ -73,6 +73,25 @@ const BitbucketCloudSchemaJSON = `{
       "default": "http",
       "examples": ["ssh"]
     },
+    "gitCloneOptions": {
+      "description": "Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.",
+      "title": "BitbucketCloudGitCloneOptions",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "blobSizeLimit": {
+          "description": "If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a \"k\", \"m\" or \"g\" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.",
+          "type": "string",
+          "pattern": "^[0-9]+[kmgKMG]?$",
+          "examples": ["1m", "512k"]
+        },
+        "branchesAndTagsOnly": {
+          "description": "If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.",
+          "type": "boolean",
+          "default": false
+        }
+      }
+    },
     "repositoryPathPattern": {
       "description": "The pattern used to generate the corresponding Sourcegraph repository name for a Bitbucket Cloud repository.\n\n - \"{host}\" is replaced with the Bitbucket Cloud URL's host (such as bitbucket.org),  and \"{nameWithOwner}\" is replaced with the Bitbucket Cloud repository's \"owner/path\" (such as \"myorg/myrepo\").\n\nFor example, if your Bitbucket Cloud is https://bitbucket.org and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of \"{host}/{nameWithOwner}\" would mean that a Bitbucket Cloud repository at https://bitbucket.org/alice/my-repo is available on Sourcegraph at https://src.example.com/bitbucket.org/alice/my-repo.\n\nIt is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.",
       "type": "string",
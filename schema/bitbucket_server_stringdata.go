@@ -81,6 +81,25 @@ const BitbucketServerSchemaJSON = `{
       "default": "http",
       "examples": ["ssh"]
     },
+    "gitCloneOptions": {
+      "description": "Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.",
+      "title": "BitbucketServerGitCloneOptions",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "blobSizeLimit": {
+          "description": "If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a \"k\", \"m\" or \"g\" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.",
+          "type": "string",
+          "pattern": "^[0-9]+[kmgKMG]?$",
+          "examples": ["1m", "512k"]
+        },
+        "branchesAndTagsOnly": {
+          "description": "If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.",
+          "type": "boolean",
+          "default": false
+        }
+      }
+    },
     "certificate": {
       "description": "TLS certificate of the Bitbucket Server instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run ` + "`" + `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM` + "`" + `. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.",
       "type": "string",
@@ -30,6 +30,25 @@ const GitHubSchemaJSON = `{
       "enum": ["http", "ssh"],
       "default": "http"
     },
+    "gitCloneOptions": {
+      "description": "Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.",
+      "title": "GitCloneOptions",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "blobSizeLimit": {
+          "description": "If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a \"k\", \"m\" or \"g\" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.",
+          "type": "string",
+          "pattern": "^[0-9]+[kmgKMG]?$",
+          "examples": ["1m", "512k"]
+        },
+        "branchesAndTagsOnly": {
+          "description": "If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.",
+          "type": "boolean",
+          "default": false
+        }
+      }
+    },
     "token": {
       "description": "A GitHub personal access token. Create one for GitHub.com at https://github.com/settings/tokens/new?description=Sourcegraph (for GitHub Enterprise, replace github.com with your instance's hostname). See https://docs.sourcegraph.com/admin/external_service/github#github-api-token-and-access for which scopes are required for which use cases.",
       "type": "string",
@@ -58,6 +58,25 @@ const GitLabSchemaJSON = `{
       "enum": ["http", "ssh"],
       "default": "http"
     },
+    "gitCloneOptions": {
+      "description": "Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.",
+      "title": "GitLabGitCloneOptions",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "blobSizeLimit": {
+          "description": "If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a \"k\", \"m\" or \"g\" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.",
+          "type": "string",
+          "pattern": "^[0-9]+[kmgKMG]?$",
+          "examples": ["1m", "512k"]
+        },
+        "branchesAndTagsOnly": {
+          "description": "If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.",
+          "type": "boolean",
+          "default": false
+        }
+      }
+    },
     "certificate": {
       "description": "TLS certificate of the GitLab instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run ` + "`" + `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM` + "`" + `. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.",
       "type": "string",
@@ -115,6 +134,23 @@ const GitLabSchemaJSON = `{
         [{ "name": "gitlab-org/gitlab-ee" }, { "name": "gitlab-com/www-gitlab-com" }]
       ]
     },
+    "webhooks": {
+      "description": "An array of configurations defining existing GitLab webhooks that send updates back to Sourcegraph.",
+      "type": "array",
+      "items": {
+        "type": "object",
+        "title": "GitLabWebhook",
+        "required": ["secret"],
+        "properties": {
+          "secret": {
+            "description": "The secret token used when creating the webhook",
+            "type": "string",
+            "minLength": 1
+          }
+        }
+      },
+      "examples": [[{ "secret": "webhook-secret" }]]
+    },
     "projectQuery": {
       "description": "An array of strings specifying which GitLab projects to mirror on Sourcegraph. Each string is a URL path and query that targets a GitLab API endpoint returning a list of projects. If the string only contains a query, then \"projects\" is used as the path. Examples: \"?membership=true&search=foo\", \"groups/mygroup/projects\".\n\nThe special string \"none\" can be used as the only element to disable this feature. Projects matched by multiple query strings are only imported once. Here are a few endpoints that return a list of projects: https://docs.gitlab.com/ee/api/projects.html#list-all-projects, https://docs.gitlab.com/ee/api/groups.html#list-a-groups-projects, https://docs.gitlab.com/ee/api/search.html#scope-projects.",
       "type": "array",
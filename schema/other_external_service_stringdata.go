@@ -24,6 +24,25 @@ const OtherExternalServiceSchemaJSON = `{
       },
       "examples": ["https://github.com/?access_token=secret", "ssh://user@host.xz:2333/", "git://host.xz:2333/"]
     },
+    "gitCloneOptions": {
+      "description": "Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.",
+      "title": "OtherGitCloneOptions",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "blobSizeLimit": {
+          "description": "If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a \"k\", \"m\" or \"g\" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.",
+          "type": "string",
+          "pattern": "^[0-9]+[kmgKMG]?$",
+          "examples": ["1m", "512k"]
+        },
+        "branchesAndTagsOnly": {
+          "description": "If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.",
+          "type": "boolean",
+          "default": false
+        }
+      }
+    },
     "repos": {
       "title": "List of repository clone URLs to be discovered.",
       "type": "array",
@@ -121,6 +121,8 @@ type BitbucketCloudConnection struct {
 	//
 	// Supports excluding by name ({"name": "myorg/myrepo"}) or by UUID ({"uuid": "{fceb73c7-cef6-4abe-956d-e471281126bd}"}).
 	Exclude []*ExcludedBitbucketCloudRepo `json:"exclude,omitempty"`
+	// GitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+	GitCloneOptions *BitbucketCloudGitCloneOptions `json:"gitCloneOptions,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Bitbucket Cloud.
 	//
 	// If "http", Sourcegraph will access Bitbucket Cloud repositories using Git URLs of the form https://bitbucket.org/myteam/myproject.git.
@@ -145,6 +147,14 @@ type BitbucketCloudConnection struct {
 	Username string `json:"username"`
 }
 
+// BitbucketCloudGitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+type BitbucketCloudGitCloneOptions struct {
+	// BlobSizeLimit description: If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a "k", "m" or "g" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// BranchesAndTagsOnly description: If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.
+	BranchesAndTagsOnly bool `json:"branchesAndTagsOnly,omitempty"`
+}
+
 // BitbucketCloudRateLimit description: Rate limit applied when making background API requests to Bitbucket Cloud.
 type BitbucketCloudRateLimit struct {
 	// Enabled description: true if rate limiting is enabled.
@@ -181,6 +191,8 @@ type BitbucketServerConnection struct {
 	Exclude []*ExcludedBitbucketServerRepo `json:"exclude,omitempty"`
 	// ExcludePersonalRepositories description: Whether or not personal repositories should be excluded or not. When true, Sourcegraph will ignore personal repositories it may have access to. See https://docs.sourcegraph.com/integration/bitbucket_server#excluding-personal-repositories for more information.
 	ExcludePersonalRepositories bool `json:"excludePersonalRepositories,omitempty"`
+	// GitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+	GitCloneOptions *BitbucketServerGitCloneOptions `json:"gitCloneOptions,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Bitbucket Server instance.
 	//
 	// If "http", Sourcegraph will access Bitbucket Server repositories using Git URLs of the form http(s)://bitbucket.example.com/scm/myproject/myrepo.git (using https: if the Bitbucket Server instance uses HTTPS).
@@ -225,6 +237,14 @@ type BitbucketServerConnection struct {
 	Webhooks *Webhooks `json:"webhooks,omitempty"`
 }
 
+// BitbucketServerGitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+type BitbucketServerGitCloneOptions struct {
+	// BlobSizeLimit description: If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a "k", "m" or "g" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// BranchesAndTagsOnly description: If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.
+	BranchesAndTagsOnly bool `json:"branchesAndTagsOnly,omitempty"`
+}
+
 // BitbucketServerIdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the Bitbucket Server identity to use for a given Sourcegraph user. When 'username' is used, Sourcegraph assumes usernames are identical in Sourcegraph and Bitbucket Server accounts and `auth.enableUsernameChanges` must be set to false for security reasons.
 type BitbucketServerIdentityProvider struct {
 	Username *BitbucketServerUsernameIdentity
@@ -360,6 +380,12 @@ type ExcludedBitbucketServerRepo struct {
 	// Pattern description: Regular expression which matches against the name of a Bitbucket Server repo.
 	Pattern string `json:"pattern,omitempty"`
 }
+type ExcludedGerritProject struct {
+	// Name description: The name of a Gerrit project ("platform/build") to exclude from mirroring.
+	Name string `json:"name,omitempty"`
+	// Pattern description: Regular expression which matches against the name of a Gerrit project.
+	Pattern string `json:"pattern,omitempty"`
+}
 type ExcludedGitHubRepo struct {
 	// Archived description: If set to true, archived repositories will be excluded.
 	Archived bool `json:"archived,omitempty"`
@@ -430,6 +456,58 @@ type ExternalIdentity struct {
 	Type           string `json:"type"`
 }
 
+// GerritConnection description: Configuration for a connection to Gerrit.
+type GerritConnection struct {
+	// Exclude description: A list of projects to never mirror from Gerrit. Takes precedence over "projects" configuration.
+	//
+	// Supports excluding by name ({"name": "platform/build"}) or by regular expression ({"pattern": "^experimental/.*"}).
+	Exclude []*ExcludedGerritProject `json:"exclude,omitempty"`
+	// GitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+	GitCloneOptions *GerritGitCloneOptions `json:"gitCloneOptions,omitempty"`
+	// Password description: The HTTP password of the user given in "username". This is the password generated under Settings > HTTP Credentials in Gerrit, not the user's account password.
+	Password string `json:"password"`
+	// Projects description: An array of Gerrit project names to mirror on Sourcegraph. If empty, all projects visible to the configured user are mirrored.
+	Projects []string `json:"projects,omitempty"`
+	// RateLimit description: Rate limit applied when making background API requests to Gerrit.
+	RateLimit *GerritRateLimit `json:"rateLimit,omitempty"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Gerrit project.
+	//
+	//  - "{host}" is replaced with the Gerrit URL's host (such as gerrit.example.com), and "{name}" is replaced with the Gerrit project's name (such as "platform/build").
+	//
+	// For example, if your Gerrit is https://gerrit.example.com and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of "{host}/{name}" would mean that a Gerrit project at https://gerrit.example.com/platform/build is available on Sourcegraph at https://src.example.com/gerrit.example.com/platform/build.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+	// Url description: URL of a Gerrit instance, such as https://gerrit.example.com.
+	Url string `json:"url"`
+	// Username description: The username to use when authenticating to the Gerrit instance. Also set the corresponding "password" field.
+	Username string `json:"username"`
+}
+
+// GerritGitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+type GerritGitCloneOptions struct {
+	// BlobSizeLimit description: If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a "k", "m" or "g" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// BranchesAndTagsOnly description: If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.
+	BranchesAndTagsOnly bool `json:"branchesAndTagsOnly,omitempty"`
+}
+
+// GerritRateLimit description: Rate limit applied when making background API requests to Gerrit.
+type GerritRateLimit struct {
+	// Enabled description: true if rate limiting is enabled.
+	Enabled bool `json:"enabled"`
+	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second.
+	RequestsPerHour float64 `json:"requestsPerHour"`
+}
+
+// GitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+type GitCloneOptions struct {
+	// BlobSizeLimit description: If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a "k", "m" or "g" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// BranchesAndTagsOnly description: If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.
+	BranchesAndTagsOnly bool `json:"branchesAndTagsOnly,omitempty"`
+}
+
 // GitHubAuthProvider description: Configures the GitHub (or GitHub Enterprise) OAuth authentication provider for SSO. In addition to specifying this configuration object, you must also create a OAuth App on your GitHub instance: https://developer.github.com/apps/building-oauth-apps/creating-an-oauth-app/. When a user signs into Sourcegraph or links their GitHub account to their existing Sourcegraph account, GitHub will prompt the user for the repo scope.
 type GitHubAuthProvider struct {
 	// AllowOrgs description: Restricts new logins to members of these GitHub organizations. Existing sessions won't be invalidated. Leave empty or unset for no org restrictions.
@@ -470,6 +548,8 @@ type GitHubConnection struct {
 	//
 	// Note: ID is the GitHub GraphQL ID, not the GitHub database ID. eg: "curl https://api.github.com/repos/vuejs/vue | jq .node_id"
 	Exclude []*ExcludedGitHubRepo `json:"exclude,omitempty"`
+	// GitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+	GitCloneOptions *GitCloneOptions `json:"gitCloneOptions,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this GitHub instance.
 	//
 	// If "http", Sourcegraph will access GitHub repositories using Git URLs of the form http(s)://github.com/myteam/myproject.git (using https: if the GitHub instance uses HTTPS).
@@ -563,6 +643,8 @@ type GitLabConnection struct {
 	Certificate string `json:"certificate,omitempty"`
 	// Exclude description: A list of projects to never mirror from this GitLab instance. Takes precedence over "projects" and "projectQuery" configuration. Supports excluding by name ({"name": "group/name"}) or by ID ({"id": 42}).
 	Exclude []*ExcludedGitLabProject `json:"exclude,omitempty"`
+	// GitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+	GitCloneOptions *GitLabGitCloneOptions `json:"gitCloneOptions,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this GitLab instance.
 	//
 	// If "http", Sourcegraph will access GitLab repositories using Git URLs of the form http(s)://gitlab.example.com/myteam/myproject.git (using https: if the GitLab instance uses HTTPS).
@@ -591,6 +673,16 @@ type GitLabConnection struct {
 	Token string `json:"token"`
 	// Url description: URL of a GitLab instance, such as https://gitlab.example.com or (for GitLab.com) https://gitlab.com.
 	Url string `json:"url"`
+	// Webhooks description: An array of configurations defining existing GitLab webhooks that send updates back to Sourcegraph.
+	Webhooks []*GitLabWebhook `json:"webhooks,omitempty"`
+}
+
+// GitLabGitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+type GitLabGitCloneOptions struct {
+	// BlobSizeLimit description: If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a "k", "m" or "g" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// BranchesAndTagsOnly description: If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.
+	BranchesAndTagsOnly bool `json:"branchesAndTagsOnly,omitempty"`
 }
 type GitLabNameTransformation struct {
 	// Regex description: The regex to match for the occurrences of its replacement.
@@ -612,6 +704,10 @@ type GitLabRateLimit struct {
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
+type GitLabWebhook struct {
+	// Secret description: The secret token used when creating the webhook
+	Secret string `json:"secret"`
+}
 
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
@@ -835,7 +931,9 @@ type OpenIDConnectAuthProvider struct {
 
 // OtherExternalServiceConnection description: Configuration for a Connection to Git repositories for which an external service integration isn't yet available.
 type OtherExternalServiceConnection struct {
-	Repos []string `json:"repos"`
+	// GitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+	GitCloneOptions *OtherGitCloneOptions `json:"gitCloneOptions,omitempty"`
+	Repos           []string              `json:"repos"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Git clone base URL host and path, and "{repo}" is replaced with the repository path taken from the `repos` field.
 	//
 	// For example, if your Git clone base URL is https://git.example.com/repos and `repos` contains the value "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that a repository at https://git.example.com/repos/my/repo is available on Sourcegraph at https://sourcegraph.example.com/git.example.com/repos/my/repo.
@@ -845,6 +943,14 @@ type OtherExternalServiceConnection struct {
 	Url                   string `json:"url,omitempty"`
 }
 
+// OtherGitCloneOptions description: Options for how gitserver clones and fetches the repositories of this code host connection. Use them to limit the disk space used by very large repositories.
+type OtherGitCloneOptions struct {
+	// BlobSizeLimit description: If set, repositories are partial clones that leave out files larger than this size until they are needed, when they are fetched from the code host on demand. The size is in bytes, or in KiB, MiB or GiB with a "k", "m" or "g" suffix. This requires a code host that supports Git partial clone (uploadpack.allowFilter), and only applies to repositories cloned after it is set.
+	BlobSizeLimit string `json:"blobSizeLimit,omitempty"`
+	// BranchesAndTagsOnly description: If true, only branches (refs/heads/*) and tags (refs/tags/*) are fetched, not pull request or other refs.
+	BranchesAndTagsOnly bool `json:"branchesAndTagsOnly,omitempty"`
+}
+
 // ParentSourcegraph description: URL to fetch unreachable repository details from. Defaults to "https://sourcegraph.com"
 type ParentSourcegraph struct {
 	Url string `json:"url,omitempty"`
@@ -945,6 +1051,32 @@ type SMTPServerConfig struct {
 	// Username description: The username to use when communicating with the SMTP server.
 	Username string `json:"username,omitempty"`
 }
+
+// SavedQueryWebhook description: An HTTP endpoint that receives a POST request when a saved search has new results.
+type SavedQueryWebhook struct {
+	// Format description: The payload format. "json" posts a generic JSON payload; "teams" and "mattermost" post messages to incoming webhooks of Microsoft Teams and Mattermost.
+	Format string `json:"format,omitempty"`
+	// Secret description: If set, each request carries an X-Sourcegraph-Signature header with the hex-encoded HMAC-SHA256 of the request body keyed by this secret
+	Secret string `json:"secret,omitempty"`
+	// Url description: The URL that notifications are posted to
+	Url string `json:"url"`
+}
+
+// SearchRanking description: Ranks search results by a score computed from signals of their repository and file, instead of ordering them by repository name and file path. Only the results that a search returns are reordered, so this does not change which results are returned. Searches with `stable:yes` are not ranked.
+type SearchRanking struct {
+	// Enabled description: Whether search results are ranked.
+	Enabled bool `json:"enabled,omitempty"`
+	// LowValuePathPatterns description: Regular expressions matching the paths of files that are less relevant than other files, such as tests, vendored dependencies and generated code. Defaults to patterns for common test, vendor and generated file paths.
+	LowValuePathPatterns []string `json:"lowValuePathPatterns,omitempty"`
+	// LowValuePathWeight description: The amount by which the score of a file match decreases if its path matches one of lowValuePathPatterns.
+	LowValuePathWeight *float64 `json:"lowValuePathWeight,omitempty"`
+	// MatchCountWeight description: The weight of the number of matches in a file. The score of a file match increases by the weight times log2(1 + matches).
+	MatchCountWeight *float64 `json:"matchCountWeight,omitempty"`
+	// RepositoryActivityWeight description: The weight of how recently the repository changed, which is when its refs last changed on gitserver. The score of a result increases by the weight times 2^(-days/90), where days is the number of days since the repository last changed.
+	RepositoryActivityWeight *float64 `json:"repositoryActivityWeight,omitempty"`
+	// RepositoryStarsWeight description: The weight of the number of stars of the repository on GitHub or GitLab. The score of a result increases by the weight times log10(1 + stars).
+	RepositoryStarsWeight *float64 `json:"repositoryStarsWeight,omitempty"`
+}
 type SearchSavedQueries struct {
 	// Description description: Description of this saved query
 	Description string `json:"description"`
@@ -958,6 +1090,8 @@ type SearchSavedQueries struct {
 	Query string `json:"query"`
 	// ShowOnHomepage description: DEPRECATED: saved searches are no longer shown on the homepage. This will be removed in a future release.
 	ShowOnHomepage bool `json:"showOnHomepage,omitempty"`
+	// Webhooks description: HTTP webhooks to notify when new results are available
+	Webhooks []*SavedQueryWebhook `json:"webhooks,omitempty"`
 }
 type SearchScope struct {
 	// Description description: A description for this search scope
@@ -1112,6 +1246,8 @@ type SiteConfiguration struct {
 	GithubClientID string `json:"githubClientID,omitempty"`
 	// GithubClientSecret description: Client secret for GitHub. (DEPRECATED)
 	GithubClientSecret string `json:"githubClientSecret,omitempty"`
+	// GitserverReplicationFactor description: Number of gitservers that store each repository. With a value greater than 1, each repository is also kept on the next gitservers chosen by the repository assignment, reads fail over to a replica when the gitserver the repository is assigned to is unreachable, and fetches are forwarded to the replicas. It is capped at the number of gitservers.
+	GitserverReplicationFactor int `json:"gitserverReplicationFactor,omitempty"`
 	// HtmlBodyBottom description: HTML to inject at the bottom of the `<body>` element on each page, for analytics scripts
 	HtmlBodyBottom string `json:"htmlBodyBottom,omitempty"`
 	// HtmlBodyTop description: HTML to inject at the top of the `<body>` element on each page, for analytics scripts
@@ -1154,6 +1290,8 @@ type SiteConfiguration struct {
 	SearchIndexSymbolsEnabled *bool `json:"search.index.symbols.enabled,omitempty"`
 	// SearchLargeFiles description: A list of file glob patterns where matching files will be indexed and searched regardless of their size. The glob pattern syntax can be found here: https://golang.org/pkg/path/filepath/#Match.
 	SearchLargeFiles []string `json:"search.largeFiles,omitempty"`
+	// SearchRanking description: Ranks search results by a score computed from signals of their repository and file, instead of ordering them by repository name and file path. Only the results that a search returns are reordered, so this does not change which results are returned. Searches with `stable:yes` are not ranked.
+	SearchRanking *SearchRanking `json:"search.ranking,omitempty"`
 	// UpdateChannel description: The channel on which to automatically check for Sourcegraph updates.
 	UpdateChannel string `json:"update.channel,omitempty"`
 	// UseJaeger description: DEPRECATED. Use `"observability.tracing": { "sampling": "all" }`, instead. Enables Jaeger tracing.
@@ -1188,6 +1326,8 @@ type VersionContextRevision struct {
 	Repo string `json:"repo"`
 	// Rev description: Branch, tag, or commit hash
 	Rev string `json:"rev"`
+	// RevAt description: If set, the version context uses the last commit on rev (or the default branch, if rev is empty) before this date, such as "2020-01-01" or "1 year ago".
+	RevAt string `json:"revAt,omitempty"`
 }
 
 // Webhooks description: DEPRECATED: Switch to "plugin.webhooks"
@@ -91,6 +91,13 @@ const SettingsSchemaJSON = `{
           "notifySlack": {
             "type": "boolean",
             "description": "Notify Slack via the organization's Slack webhook URL when new results are available"
+          },
+          "webhooks": {
+            "description": "HTTP webhooks to notify when new results are available",
+            "type": "array",
+            "items": {
+              "$ref": "#/definitions/SavedQueryWebhook"
+            }
           }
         },
         "additionalProperties": false,
@@ -231,6 +238,30 @@ const SettingsSchemaJSON = `{
         }
       }
     },
+    "SavedQueryWebhook": {
+      "description": "An HTTP endpoint that receives a POST request when a saved search has new results.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["url"],
+      "properties": {
+        "url": {
+          "type": "string",
+          "description": "The URL that notifications are posted to",
+          "format": "uri",
+          "pattern": "^https?://"
+        },
+        "format": {
+          "type": "string",
+          "description": "The payload format. \"json\" posts a generic JSON payload; \"teams\" and \"mattermost\" post messages to incoming webhooks of Microsoft Teams and Mattermost.",
+          "enum": ["json", "teams", "mattermost"],
+          "default": "json"
+        },
+        "secret": {
+          "type": "string",
+          "description": "If set, each request carries an X-Sourcegraph-Signature header with the hex-encoded HMAC-SHA256 of the request body keyed by this secret"
+        }
+      }
+    },
     "QuickLink": {
       "type": "object",
       "additionalProperties": false,
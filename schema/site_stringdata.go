@@ -43,6 +43,52 @@ const SiteSchemaJSON = `{
       "group": "Search",
       "examples": [["go.sum", "package-lock.json", "*.thrift"]]
     },
+    "search.ranking": {
+      "description": "Ranks search results by a score computed from signals of their repository and file, instead of ordering them by repository name and file path. Only the results that a search returns are reordered, so this does not change which results are returned. Searches with ` + "`" + `stable:yes` + "`" + ` are not ranked.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "description": "Whether search results are ranked.",
+          "type": "boolean",
+          "default": false
+        },
+        "repositoryStarsWeight": {
+          "description": "The weight of the number of stars of the repository on GitHub or GitLab. The score of a result increases by the weight times log10(1 + stars).",
+          "type": "number",
+          "!go": { "pointer": true },
+          "default": 1
+        },
+        "repositoryActivityWeight": {
+          "description": "The weight of how recently the repository changed, which is when its refs last changed on gitserver. The score of a result increases by the weight times 2^(-days/90), where days is the number of days since the repository last changed.",
+          "type": "number",
+          "!go": { "pointer": true },
+          "default": 1
+        },
+        "matchCountWeight": {
+          "description": "The weight of the number of matches in a file. The score of a file match increases by the weight times log2(1 + matches).",
+          "type": "number",
+          "!go": { "pointer": true },
+          "default": 1
+        },
+        "lowValuePathWeight": {
+          "description": "The amount by which the score of a file match decreases if its path matches one of lowValuePathPatterns.",
+          "type": "number",
+          "!go": { "pointer": true },
+          "default": 2
+        },
+        "lowValuePathPatterns": {
+          "description": "Regular expressions matching the paths of files that are less relevant than other files, such as tests, vendored dependencies and generated code. Defaults to patterns for common test, vendor and generated file paths.",
+          "type": "array",
+          "items": {
+            "type": "string"
+          },
+          "examples": [["(^|/)testdata/", "_test\\.go$", "\\.pb\\.go$"]]
+        }
+      },
+      "group": "Search",
+      "examples": [{ "enabled": true, "repositoryStarsWeight": 2 }]
+    },
     "debug.search.symbolsParallelism": {
       "description": "(debug) controls the amount of symbol search parallelism. Defaults to 20. It is not recommended to change this outside of debugging scenarios. This option will be removed in a future version.",
       "type": "integer",
@@ -190,6 +236,11 @@ const SiteSchemaJSON = `{
                     "rev": {
                       "description": "Branch, tag, or commit hash",
                       "type": "string"
+                    },
+                    "revAt": {
+                      "description": "If set, the version context uses the last commit on rev (or the default branch, if rev is empty) before this date, such as \"2020-01-01\" or \"1 year ago\".",
+                      "type": "string",
+                      "examples": ["2020-01-01", "1 year ago"]
                     }
                   }
                 }
@@ -319,6 +370,13 @@ const SiteSchemaJSON = `{
       "default": 5,
       "group": "External services"
     },
+    "gitserverReplicationFactor": {
+      "description": "Number of gitservers that store each repository. With a value greater than 1, each repository is also kept on the next gitservers chosen by the repository assignment, reads fail over to a replica when the gitserver the repository is assigned to is unreachable, and fetches are forwarded to the replicas. It is capped at the number of gitservers.",
+      "type": "integer",
+      "minimum": 1,
+      "default": 1,
+      "group": "External services"
+    },
     "repoListUpdateInterval": {
       "description": "Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.",
       "type": "integer",
@@ -23,6 +23,8 @@ export enum FilterType {
     repogroup = 'repogroup',
     repohasfile = 'repohasfile',
     repohascommitafter = 'repohascommitafter',
+    rev = 'rev',
+    'rev.at' = 'rev.at',
     file = 'file',
     type = 'type',
     case = 'case',
@@ -39,6 +41,9 @@ export enum FilterType {
     content = 'content',
     patterntype = 'patterntype',
     index = 'index',
+    select = 'select',
+    symbolkind = 'symbolkind',
+    symbolparent = 'symbolparent',
 }
 
 export const isFilterType = (filter: string): filter is FilterType => filter in FilterType
@@ -53,10 +58,18 @@ export enum NegatedFilters {
     f = '-f',
     l = '-l',
     repohasfile = '-repohasfile',
+    symbolkind = '-symbolkind',
+    symbolparent = '-symbolparent',
 }
 
 /** The list of filters that are able to be negated. */
-export type NegatableFilter = FilterType.repo | FilterType.file | FilterType.repohasfile | FilterType.lang
+export type NegatableFilter =
+    | FilterType.repo
+    | FilterType.file
+    | FilterType.repohasfile
+    | FilterType.lang
+    | FilterType.symbolkind
+    | FilterType.symbolparent
 
 export const isNegatableFilter = (filter: FilterType): filter is NegatableFilter =>
     Object.keys(NegatedFilters).includes(filter)
@@ -75,6 +88,8 @@ const negatedFilterToNegatableFilter: { [key: string]: NegatableFilter } = {
     '-f': FilterType.file,
     '-l': FilterType.lang,
     '-repohasfile': FilterType.repohasfile,
+    '-symbolkind': FilterType.symbolkind,
+    '-symbolparent': FilterType.symbolparent,
 }
 
 export const resolveNegatedFilter = (filter: NegatedFilters): NegatableFilter => negatedFilterToNegatableFilter[filter]
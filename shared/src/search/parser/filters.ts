@@ -51,6 +51,23 @@ const LANGUAGES: string[] = [
     'typescript',
 ]
 
+const SYMBOL_KINDS: string[] = [
+    'class',
+    'constant',
+    'constructor',
+    'enum',
+    'field',
+    'function',
+    'interface',
+    'method',
+    'module',
+    'namespace',
+    'package',
+    'property',
+    'struct',
+    'variable',
+]
+
 export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
     Record<Exclude<FilterType, NegatableFilter>, BaseFilterDefinition> = {
     [FilterType.after]: {
@@ -127,11 +144,34 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         description: '"string specifying time frame" (filter out stale repositories without recent commits)',
         singular: true,
     },
+    [FilterType.rev]: {
+        description: 'revisions or ref globs (search these revisions of repositories)',
+        singular: true,
+    },
+    [FilterType['rev.at']]: {
+        description: '"string specifying time frame" (search repositories as they were at that time)',
+        singular: true,
+    },
     [FilterType.repohasfile]: {
         negatable: true,
         description: negated =>
             `${negated ? 'Exclude' : 'Include only'} results from repos that contain a matching file`,
     },
+    [FilterType.select]: {
+        description: 'Only return results of the selected type, e.g. symbol or symbol.function.',
+        suggestions: ['symbol', ...SYMBOL_KINDS.map(kind => `symbol.${kind}`)],
+        singular: true,
+    },
+    [FilterType.symbolkind]: {
+        negatable: true,
+        description: negated => `${negated ? 'Exclude' : 'Include only'} symbols of the given kind`,
+        suggestions: SYMBOL_KINDS,
+    },
+    [FilterType.symbolparent]: {
+        negatable: true,
+        description: negated =>
+            `${negated ? 'Exclude' : 'Include only'} symbols whose parent (containing type, class...) matches the given regex pattern`,
+    },
     [FilterType.timeout]: {
         description: 'Duration before timeout',
         singular: true,
@@ -226,7 +226,7 @@ const whitespace = pattern(/\s+/, { type: 'whitespace' as const }, 'whitespace')
 
 const literal = pattern(/[^\s)]+/)
 
-const filterKeyword = pattern(/-?[A-Za-z]+(?=:)/)
+const filterKeyword = pattern(/-?(?:rev\.)?[A-Za-z]+(?=:)/)
 
 const filterDelimiter = character(':')
 
@@ -3654,6 +3654,7 @@ export type RepositoryResult = {
                     | 'AWSCODECOMMIT'
                     | 'BITBUCKETCLOUD'
                     | 'BITBUCKETSERVER'
+                    | 'GERRIT'
                     | 'GITHUB'
                     | 'GITLAB'
                     | 'GITOLITE'
@@ -4241,6 +4242,7 @@ export type addExternalServiceVariables = {
             | 'AWSCODECOMMIT'
             | 'BITBUCKETCLOUD'
             | 'BITBUCKETSERVER'
+            | 'GERRIT'
             | 'GITHUB'
             | 'GITLAB'
             | 'GITOLITE'
@@ -4257,6 +4259,7 @@ export type addExternalServiceResult = {
             | 'AWSCODECOMMIT'
             | 'BITBUCKETCLOUD'
             | 'BITBUCKETSERVER'
+            | 'GERRIT'
             | 'GITHUB'
             | 'GITLAB'
             | 'GITOLITE'
@@ -4272,6 +4275,7 @@ export type externalServiceFields = {
         | 'AWSCODECOMMIT'
         | 'BITBUCKETCLOUD'
         | 'BITBUCKETSERVER'
+        | 'GERRIT'
         | 'GITHUB'
         | 'GITLAB'
         | 'GITOLITE'
@@ -4298,6 +4302,7 @@ export type externalServiceFields1 = {
         | 'AWSCODECOMMIT'
         | 'BITBUCKETCLOUD'
         | 'BITBUCKETSERVER'
+        | 'GERRIT'
         | 'GITHUB'
         | 'GITLAB'
         | 'GITOLITE'
@@ -4324,6 +4329,7 @@ export type externalServiceFields2 = {
         | 'AWSCODECOMMIT'
         | 'BITBUCKETCLOUD'
         | 'BITBUCKETSERVER'
+        | 'GERRIT'
         | 'GITHUB'
         | 'GITLAB'
         | 'GITOLITE'
@@ -4353,6 +4359,7 @@ export type ExternalServicesResult = {
                 | 'AWSCODECOMMIT'
                 | 'BITBUCKETCLOUD'
                 | 'BITBUCKETSERVER'
+                | 'GERRIT'
                 | 'GITHUB'
                 | 'GITLAB'
                 | 'GITOLITE'
@@ -4591,6 +4598,7 @@ export type AllConfigResult = {
                 | 'AWSCODECOMMIT'
                 | 'BITBUCKETCLOUD'
                 | 'BITBUCKETSERVER'
+                | 'GERRIT'
                 | 'GITHUB'
                 | 'GITLAB'
                 | 'GITOLITE'
@@ -78,6 +78,8 @@ export const FilterTypeToProseNames: Record<FilterType, string> = {
     repogroup: 'Repository group',
     repohasfile: 'Repo has file',
     repohascommitafter: 'Repo has commit after',
+    rev: 'Revision',
+    'rev.at': 'Revision at',
     file: 'File',
     lang: 'Language',
     count: 'Count',
@@ -94,4 +96,7 @@ export const FilterTypeToProseNames: Record<FilterType, string> = {
     patterntype: 'Pattern type',
     index: 'Indexed repos',
     visibility: 'Repository visiblity',
+    select: 'Select',
+    symbolkind: 'Symbol kind',
+    symbolparent: 'Symbol parent',
 }